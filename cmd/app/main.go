@@ -22,6 +22,12 @@ import (
 // @securityDefinitions.apikey session_cookie
 // @in cookie
 // @name session_id
+// @securityDefinitions.apikey bearer_token
+// @in header
+// @name Authorization
+// @securityDefinitions.apikey api_key
+// @in header
+// @name X-API-Key
 func main() {
 
 	cfg, err := config.Load()
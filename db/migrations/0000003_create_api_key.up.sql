@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS api_key (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    name TEXT
+        CONSTRAINT api_key_name_length CHECK (LENGTH(name) <= 64) NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash bytea
+        CONSTRAINT api_key_hash_length CHECK (OCTET_LENGTH(key_hash) = 32) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS api_key_user_id_idx ON api_key (user_id);
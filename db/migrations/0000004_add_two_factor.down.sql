@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS recovery_code;
+
+ALTER TABLE uuser
+    DROP COLUMN IF EXISTS totp_secret,
+    DROP COLUMN IF EXISTS totp_enabled,
+    DROP COLUMN IF EXISTS totp_last_step;
@@ -0,0 +1,28 @@
+-- Восстанавливаются только хеши со старыми параметрами без pepper,
+-- остальным пользователям после отката придется сбросить пароль.
+ALTER TABLE uuser
+    ADD COLUMN IF NOT EXISTS password_hashed bytea
+        CONSTRAINT password_hashed_length CHECK (OCTET_LENGTH(password_hashed) <= 32),
+    ADD COLUMN IF NOT EXISTS password_salt bytea
+        CONSTRAINT password_salt_length CHECK (OCTET_LENGTH(password_salt) <= 8);
+
+UPDATE uuser
+SET password_salt = decode(
+        split_part(password_hash, '$', 5) || repeat('=', (4 - length(split_part(password_hash, '$', 5)) % 4) % 4),
+        'base64'
+    ),
+    password_hashed = decode(
+        split_part(password_hash, '$', 6) || repeat('=', (4 - length(split_part(password_hash, '$', 6)) % 4) % 4),
+        'base64'
+    )
+WHERE password_hash LIKE '$argon2id$v=19$m=65536,t=2,p=2$%'
+  AND length(split_part(password_hash, '$', 5)) = 11;
+
+UPDATE uuser
+SET password_salt = '\x00'::bytea, password_hashed = '\x00'::bytea
+WHERE password_salt IS NULL;
+
+ALTER TABLE uuser
+    ALTER COLUMN password_hashed SET NOT NULL,
+    ALTER COLUMN password_salt SET NOT NULL,
+    DROP COLUMN IF EXISTS password_hash;
@@ -0,0 +1,17 @@
+ALTER TABLE uuser
+    ADD COLUMN IF NOT EXISTS password_hash TEXT
+        CONSTRAINT password_hash_length CHECK (LENGTH(password_hash) <= 255);
+
+-- Старые хеши считались с параметрами m=65536,t=2,p=2 без pepper.
+UPDATE uuser
+SET password_hash = format(
+        '$argon2id$v=19$m=65536,t=2,p=2$%s$%s',
+        rtrim(encode(password_salt, 'base64'), '='),
+        rtrim(encode(password_hashed, 'base64'), '=')
+    )
+WHERE password_hash IS NULL;
+
+ALTER TABLE uuser
+    ALTER COLUMN password_hash SET NOT NULL,
+    DROP COLUMN IF EXISTS password_hashed,
+    DROP COLUMN IF EXISTS password_salt;
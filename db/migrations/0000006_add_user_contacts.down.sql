@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS uuser_phone_verified_key;
+DROP INDEX IF EXISTS uuser_email_verified_key;
+
+ALTER TABLE uuser
+    DROP COLUMN IF EXISTS phone_verified,
+    DROP COLUMN IF EXISTS phone,
+    DROP COLUMN IF EXISTS email_verified,
+    DROP COLUMN IF EXISTS email;
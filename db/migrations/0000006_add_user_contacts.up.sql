@@ -0,0 +1,12 @@
+ALTER TABLE uuser
+    ADD COLUMN IF NOT EXISTS email TEXT
+        CONSTRAINT email_length CHECK (LENGTH(email) <= 254),
+    ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS phone TEXT
+        CONSTRAINT phone_format CHECK (phone ~ '^\+[0-9]{10,15}$'),
+    ADD COLUMN IF NOT EXISTS phone_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Уникальны только подтвержденные контакты: неподтвержденный адрес не должен
+-- мешать настоящему владельцу подтвердить его в своем аккаунте.
+CREATE UNIQUE INDEX IF NOT EXISTS uuser_email_verified_key ON uuser (email) WHERE email_verified;
+CREATE UNIQUE INDEX IF NOT EXISTS uuser_phone_verified_key ON uuser (phone) WHERE phone_verified;
@@ -0,0 +1,4 @@
+ALTER TABLE uuser
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS city,
+    DROP COLUMN IF EXISTS bio;
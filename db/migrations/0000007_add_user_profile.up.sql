@@ -0,0 +1,7 @@
+ALTER TABLE uuser
+    ADD COLUMN IF NOT EXISTS bio TEXT
+        CONSTRAINT bio_length CHECK (LENGTH(bio) <= 500),
+    ADD COLUMN IF NOT EXISTS city TEXT
+        CONSTRAINT city_length CHECK (LENGTH(city) <= 100),
+    ADD COLUMN IF NOT EXISTS avatar_url TEXT
+        CONSTRAINT avatar_url_length CHECK (LENGTH(avatar_url) <= 2048);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS uuser_delete_after_idx;
+
+ALTER TABLE uuser
+    DROP COLUMN IF EXISTS delete_after;
@@ -0,0 +1,4 @@
+ALTER TABLE uuser
+    ADD COLUMN IF NOT EXISTS delete_after TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS uuser_delete_after_idx ON uuser (delete_after) WHERE delete_after IS NOT NULL;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS review_report;
+DROP TABLE IF EXISTS review;
+
+ALTER TABLE uuser
+    DROP COLUMN IF EXISTS rating_count,
+    DROP COLUMN IF EXISTS rating_avg;
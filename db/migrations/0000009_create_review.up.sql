@@ -0,0 +1,34 @@
+ALTER TABLE uuser
+    ADD COLUMN IF NOT EXISTS rating_avg NUMERIC(3, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rating_count INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS review (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    -- Отзыв о продавце остается, даже если объявление удалено.
+    advertisement_id INT REFERENCES advertisement(id) ON DELETE SET NULL,
+    seller_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    author_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL
+        CONSTRAINT review_rating_range CHECK (rating BETWEEN 1 AND 5),
+    text TEXT NOT NULL DEFAULT ''
+        CONSTRAINT review_text_length CHECK (LENGTH(text) <= 1000),
+    reply TEXT
+        CONSTRAINT review_reply_length CHECK (LENGTH(reply) <= 1000),
+    replied_at TIMESTAMP WITH TIME ZONE,
+    status TEXT NOT NULL DEFAULT 'published'
+        CONSTRAINT review_status CHECK (status IN ('published', 'hidden')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT review_not_self CHECK (seller_id <> author_id),
+    CONSTRAINT review_advertisement_author_key UNIQUE (advertisement_id, author_id)
+);
+
+CREATE INDEX IF NOT EXISTS review_seller_published_idx
+    ON review (seller_id, created_at DESC) WHERE status = 'published';
+
+CREATE TABLE IF NOT EXISTS review_report (
+    review_id INT NOT NULL REFERENCES review(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (review_id, user_id)
+);
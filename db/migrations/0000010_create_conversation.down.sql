@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS message;
+DROP TABLE IF EXISTS conversation;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS conversation (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    advertisement_id INT REFERENCES advertisement(id) ON DELETE SET NULL,
+    buyer_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    seller_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    -- Отметки о прочтении: id последнего прочитанного сообщения для каждого участника.
+    buyer_last_read_id BIGINT NOT NULL DEFAULT 0,
+    seller_last_read_id BIGINT NOT NULL DEFAULT 0,
+    blocked_by INT REFERENCES uuser(id) ON DELETE SET NULL,
+    last_message_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT conversation_not_self CHECK (buyer_id <> seller_id),
+    CONSTRAINT conversation_advertisement_buyer_key UNIQUE (advertisement_id, buyer_id)
+);
+
+CREATE INDEX IF NOT EXISTS conversation_buyer_idx ON conversation (buyer_id, last_message_at DESC);
+CREATE INDEX IF NOT EXISTS conversation_seller_idx ON conversation (seller_id, last_message_at DESC);
+
+CREATE TABLE IF NOT EXISTS message (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    conversation_id INT NOT NULL REFERENCES conversation(id) ON DELETE CASCADE,
+    sender_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    body TEXT NOT NULL
+        CONSTRAINT message_body_length CHECK (LENGTH(body) BETWEEN 1 AND 2000),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS message_conversation_idx ON message (conversation_id, id DESC);
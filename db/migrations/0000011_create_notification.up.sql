@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS notification (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    -- Пока есть непрочитанное уведомление с тем же ключом, новое не создается.
+    dedup_key TEXT,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS notification_user_idx ON notification (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS notification_user_unread_idx ON notification (user_id) WHERE read_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS notification_unread_dedup_key
+    ON notification (user_id, dedup_key) WHERE read_at IS NULL AND dedup_key IS NOT NULL;
+
+-- Хранятся только явно измененные настройки, по умолчанию все типы включены.
+CREATE TABLE IF NOT EXISTS notification_preference (
+    user_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, type)
+);
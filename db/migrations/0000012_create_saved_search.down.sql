@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS saved_search_cursor;
+DROP TABLE IF EXISTS saved_search_match;
+DROP TABLE IF EXISTS saved_search;
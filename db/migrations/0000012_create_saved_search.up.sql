@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS saved_search (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    name TEXT NOT NULL
+        CONSTRAINT saved_search_name_length CHECK (LENGTH(name) BETWEEN 1 AND 100),
+    min_price NUMERIC(12, 2),
+    max_price NUMERIC(12, 2),
+    sort_by TEXT NOT NULL DEFAULT 'created_at',
+    sort_order TEXT NOT NULL DEFAULT 'desc',
+    alerts_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- Объявления с id не больше start_ad_id существовали до сохранения поиска.
+    start_ad_id INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT saved_search_price_range CHECK (min_price IS NULL OR max_price IS NULL OR min_price <= max_price)
+);
+
+CREATE INDEX IF NOT EXISTS saved_search_user_idx ON saved_search (user_id);
+
+-- Совпадения, еще не отправленные в дайджесте.
+CREATE TABLE IF NOT EXISTS saved_search_match (
+    search_id INT NOT NULL REFERENCES saved_search(id) ON DELETE CASCADE,
+    advertisement_id INT NOT NULL REFERENCES advertisement(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (search_id, advertisement_id)
+);
+
+-- Последнее объявление, проверенное фоновым сопоставлением. Одна строка на всю систему.
+CREATE TABLE IF NOT EXISTS saved_search_cursor (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    last_ad_id INT NOT NULL DEFAULT 0
+);
+
+INSERT INTO saved_search_cursor (last_ad_id)
+SELECT COALESCE(MAX(id), 0) FROM advertisement
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS offer;
+
+ALTER TABLE advertisement DROP COLUMN IF EXISTS status;
@@ -0,0 +1,30 @@
+ALTER TABLE advertisement
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'published'
+        CONSTRAINT advertisement_status CHECK (status IN ('published', 'reserved'));
+
+CREATE TABLE IF NOT EXISTS offer (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    advertisement_id INT NOT NULL REFERENCES advertisement(id) ON DELETE CASCADE,
+    buyer_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    seller_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    -- Встречное предложение ссылается на предложение, в ответ на которое сделано.
+    parent_id INT REFERENCES offer(id) ON DELETE SET NULL,
+    proposed_by INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    amount NUMERIC(12, 2) NOT NULL
+        CONSTRAINT offer_amount_positive CHECK (amount > 0),
+    status TEXT NOT NULL DEFAULT 'pending'
+        CONSTRAINT offer_status CHECK (status IN ('pending', 'accepted', 'declined', 'countered', 'expired')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT offer_not_self CHECK (buyer_id <> seller_id),
+    CONSTRAINT offer_proposed_by_participant CHECK (proposed_by IN (buyer_id, seller_id))
+);
+
+-- У покупателя может быть только одно ожидающее ответа предложение по объявлению.
+CREATE UNIQUE INDEX IF NOT EXISTS offer_pending_buyer_key
+    ON offer (advertisement_id, buyer_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS offer_buyer_idx ON offer (buyer_id, id DESC);
+CREATE INDEX IF NOT EXISTS offer_seller_idx ON offer (seller_id, id DESC);
+CREATE INDEX IF NOT EXISTS offer_pending_expires_idx ON offer (expires_at) WHERE status = 'pending';
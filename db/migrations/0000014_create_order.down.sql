@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS purchase_order_event;
+DROP TABLE IF EXISTS purchase_order;
+
+UPDATE advertisement SET status = 'reserved' WHERE status = 'sold';
+
+ALTER TABLE advertisement
+    DROP CONSTRAINT IF EXISTS advertisement_status,
+    ADD CONSTRAINT advertisement_status CHECK (status IN ('published', 'reserved'));
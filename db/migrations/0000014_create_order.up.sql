@@ -0,0 +1,37 @@
+ALTER TABLE advertisement
+    DROP CONSTRAINT IF EXISTS advertisement_status,
+    ADD CONSTRAINT advertisement_status CHECK (status IN ('published', 'reserved', 'sold'));
+
+-- order — зарезервированное слово, поэтому таблица называется purchase_order.
+CREATE TABLE IF NOT EXISTS purchase_order (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    -- Заказ остается в истории пользователей, даже если объявление удалено.
+    advertisement_id INT REFERENCES advertisement(id) ON DELETE SET NULL,
+    buyer_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    seller_id INT NOT NULL REFERENCES uuser(id) ON DELETE CASCADE,
+    offer_id INT REFERENCES offer(id) ON DELETE SET NULL,
+    amount NUMERIC(12, 2) NOT NULL
+        CONSTRAINT purchase_order_amount_positive CHECK (amount >= 0),
+    status TEXT NOT NULL DEFAULT 'created'
+        CONSTRAINT purchase_order_status
+            CHECK (status IN ('created', 'confirmed', 'shipped', 'completed', 'cancelled', 'disputed')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT purchase_order_not_self CHECK (buyer_id <> seller_id)
+);
+
+-- По объявлению может быть только один неотмененный заказ.
+CREATE UNIQUE INDEX IF NOT EXISTS purchase_order_advertisement_active_key
+    ON purchase_order (advertisement_id) WHERE status <> 'cancelled';
+CREATE INDEX IF NOT EXISTS purchase_order_buyer_idx ON purchase_order (buyer_id, id DESC);
+CREATE INDEX IF NOT EXISTS purchase_order_seller_idx ON purchase_order (seller_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS purchase_order_event (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES purchase_order(id) ON DELETE CASCADE,
+    status TEXT NOT NULL,
+    actor_id INT REFERENCES uuser(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS purchase_order_event_order_idx ON purchase_order_event (order_id, id);
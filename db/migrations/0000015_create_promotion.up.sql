@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS promotion (
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    advertisement_id INT NOT NULL REFERENCES advertisement(id) ON DELETE CASCADE,
+    tier TEXT NOT NULL
+        CONSTRAINT promotion_tier CHECK (tier IN ('basic', 'premium', 'top')),
+    -- priority повторяет tier и используется для сортировки выдачи.
+    priority SMALLINT NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active'
+        CONSTRAINT promotion_status CHECK (status IN ('active', 'expired', 'cancelled')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT promotion_period CHECK (ends_at > starts_at)
+);
+
+-- У объявления может быть только одно запланированное или идущее продвижение.
+CREATE UNIQUE INDEX IF NOT EXISTS promotion_advertisement_active_key
+    ON promotion (advertisement_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS promotion_active_ends_idx ON promotion (ends_at) WHERE status = 'active';
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS advertisement_published_expires_idx;
+
+UPDATE advertisement SET status = 'published' WHERE status = 'archived';
+
+ALTER TABLE advertisement
+    DROP CONSTRAINT IF EXISTS advertisement_status,
+    ADD CONSTRAINT advertisement_status CHECK (status IN ('published', 'reserved', 'sold')),
+    DROP COLUMN IF EXISTS expiry_notified_at,
+    DROP COLUMN IF EXISTS renewals_count,
+    DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,19 @@
+ALTER TABLE advertisement
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS renewals_count INT NOT NULL DEFAULT 0
+        CONSTRAINT advertisement_renewals_count_non_negative CHECK (renewals_count >= 0),
+    -- Когда владельцу отправлено предупреждение о скором истечении; сбрасывается при продлении.
+    ADD COLUMN IF NOT EXISTS expiry_notified_at TIMESTAMP WITH TIME ZONE;
+
+-- Уже опубликованные объявления получают полный срок от момента миграции,
+-- чтобы не уйти в архив все разом.
+UPDATE advertisement SET expires_at = NOW() + INTERVAL '30 days' WHERE expires_at IS NULL;
+
+ALTER TABLE advertisement
+    ALTER COLUMN expires_at SET DEFAULT NOW() + INTERVAL '30 days',
+    ALTER COLUMN expires_at SET NOT NULL,
+    DROP CONSTRAINT IF EXISTS advertisement_status,
+    ADD CONSTRAINT advertisement_status CHECK (status IN ('published', 'reserved', 'sold', 'archived'));
+
+CREATE INDEX IF NOT EXISTS advertisement_published_expires_idx
+    ON advertisement (expires_at) WHERE status = 'published';
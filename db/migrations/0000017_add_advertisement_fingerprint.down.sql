@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS advertisement_duplicate_of_idx;
+DROP INDEX IF EXISTS advertisement_user_fingerprint_key;
+
+ALTER TABLE advertisement
+    DROP COLUMN IF EXISTS duplicate_of,
+    DROP COLUMN IF EXISTS image_hash,
+    DROP COLUMN IF EXISTS content_hash;
+
+ALTER TABLE uuser DROP COLUMN IF EXISTS is_moderator;
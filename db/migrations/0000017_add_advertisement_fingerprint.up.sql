@@ -0,0 +1,16 @@
+ALTER TABLE uuser ADD COLUMN IF NOT EXISTS is_moderator BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Отпечаток содержимого считается в приложении при создании объявления. У объявлений,
+-- созданных до миграции, его нет, и они не участвуют в поиске дубликатов.
+ALTER TABLE advertisement
+    ADD COLUMN IF NOT EXISTS content_hash BIGINT,
+    ADD COLUMN IF NOT EXISTS image_hash TEXT,
+    -- Объявление, копией которого помечено это; заполняется, если дубликаты не отклоняются.
+    ADD COLUMN IF NOT EXISTS duplicate_of INT REFERENCES advertisement(id) ON DELETE SET NULL;
+
+-- Точная копия активного объявления того же автора отклоняется всегда.
+CREATE UNIQUE INDEX IF NOT EXISTS advertisement_user_fingerprint_key
+    ON advertisement (user_id, content_hash, image_hash)
+    WHERE status IN ('published', 'reserved');
+CREATE INDEX IF NOT EXISTS advertisement_duplicate_of_idx
+    ON advertisement (duplicate_of) WHERE duplicate_of IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS advertisement_title_trgm_idx;
+DROP INDEX IF EXISTS advertisement_search_vector_idx;
+
+ALTER TABLE advertisement DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,11 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Поисковый вектор для похожих объявлений: заголовок весомее описания.
+ALTER TABLE advertisement
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS advertisement_search_vector_idx ON advertisement USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS advertisement_title_trgm_idx ON advertisement USING GIN (title gin_trgm_ops);
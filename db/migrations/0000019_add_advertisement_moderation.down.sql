@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS advertisement_moderation_pending_idx;
+
+ALTER TABLE advertisement
+    DROP COLUMN IF EXISTS moderation_note,
+    DROP COLUMN IF EXISTS moderation_status;
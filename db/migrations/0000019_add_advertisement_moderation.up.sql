@@ -0,0 +1,10 @@
+-- Объявления, в которых фильтр содержимого нашел подозрительный текст, ждут решения модератора
+-- и до одобрения не показываются в ленте.
+ALTER TABLE advertisement
+    ADD COLUMN IF NOT EXISTS moderation_status TEXT NOT NULL DEFAULT 'approved'
+        CONSTRAINT advertisement_moderation_status CHECK (moderation_status IN ('approved', 'pending', 'rejected')),
+    -- Причины, по которым объявление отправлено на модерацию.
+    ADD COLUMN IF NOT EXISTS moderation_note TEXT;
+
+CREATE INDEX IF NOT EXISTS advertisement_moderation_pending_idx
+    ON advertisement (id) WHERE moderation_status = 'pending';
@@ -0,0 +1 @@
+ALTER TABLE uuser DROP COLUMN IF EXISTS language;
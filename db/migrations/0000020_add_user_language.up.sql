@@ -0,0 +1,3 @@
+-- Язык сообщений, выбранный пользователем. NULL — язык берется из Accept-Language.
+ALTER TABLE uuser ADD COLUMN IF NOT EXISTS language TEXT
+    CONSTRAINT uuser_language_check CHECK (language IN ('ru', 'en'));
@@ -0,0 +1,2 @@
+ALTER TABLE advertisement
+    DROP COLUMN IF EXISTS version;
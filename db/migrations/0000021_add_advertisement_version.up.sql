@@ -0,0 +1,4 @@
+-- Версия объявления для оптимистичной блокировки: растет при каждом изменении, которое видит
+-- клиент, и отдается в ETag. Изменение и удаление требуют совпадения версии из If-Match.
+ALTER TABLE advertisement
+    ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
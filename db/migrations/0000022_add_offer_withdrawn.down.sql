@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS offer_accepted_expires_idx;
+
+UPDATE offer SET status = 'expired' WHERE status = 'withdrawn';
+
+ALTER TABLE offer
+    DROP CONSTRAINT IF EXISTS offer_status,
+    ADD CONSTRAINT offer_status CHECK (status IN ('pending', 'accepted', 'declined', 'countered', 'expired'));
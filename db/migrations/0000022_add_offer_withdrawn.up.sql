@@ -0,0 +1,9 @@
+-- Принятое предложение резервирует объявление только на offers.acceptedTTL: за это время
+-- покупатель оформляет заказ, иначе резерв снимается. До заказа любой участник может
+-- отказаться от сделки, тогда предложение получает статус withdrawn.
+ALTER TABLE offer
+    DROP CONSTRAINT IF EXISTS offer_status,
+    ADD CONSTRAINT offer_status
+        CHECK (status IN ('pending', 'accepted', 'declined', 'countered', 'expired', 'withdrawn'));
+
+CREATE INDEX IF NOT EXISTS offer_accepted_expires_idx ON offer (expires_at) WHERE status = 'accepted';
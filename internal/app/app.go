@@ -1,15 +1,21 @@
 package app
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/outbox"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/postgres"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/redis"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/server"
 	handler "github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/service"
 	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/connector"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/contentfilter"
 	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
 )
 
@@ -42,29 +48,256 @@ func Init(cfg *config.Config) *server.Server {
 		l.Log.Errorf("Failed to create user repository: %v", err)
 	}
 
+	apiKeyRepo, err := postgres.NewAPIKeyRepository(userConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create api key repository: %v", err)
+	}
+
+	twoFactorRepo, err := postgres.NewTwoFactorRepository(userConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create two factor repository: %v", err)
+	}
+
 	sessionRepo, err := redis.NewSessionRepository(sessionConn, cfg.Redis.TTL)
 	if err != nil {
 		l.Log.Errorf("Failed to create session repository: %v", err)
 	}
 
+	challengeRepo, err := redis.NewChallengeRepository(sessionConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create challenge repository: %v", err)
+	}
+
+	loginAttemptRepo, err := redis.NewLoginAttemptRepository(sessionConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create login attempt repository: %v", err)
+	}
+
+	passwordResetRepo, err := redis.NewPasswordResetRepository(sessionConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create password reset repository: %v", err)
+	}
+
+	contactVerificationRepo, err := redis.NewContactVerificationRepository(sessionConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create contact verification repository: %v", err)
+	}
+
+	similarAdsCacheRepo, err := redis.NewSimilarAdsCacheRepository(sessionConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create similar ads cache repository: %v", err)
+	}
+
+	reviewRepo, err := postgres.NewReviewRepository(adConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create review repository: %v", err)
+	}
+
+	conversationRepo, err := postgres.NewConversationRepository(adConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create conversation repository: %v", err)
+	}
+
+	notificationRepo, err := postgres.NewNotificationRepository(adConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create notification repository: %v", err)
+	}
+
+	savedSearchRepo, err := postgres.NewSavedSearchRepository(adConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create saved search repository: %v", err)
+	}
+
+	offerRepo, err := postgres.NewOfferRepository(adConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create offer repository: %v", err)
+	}
+
+	orderRepo, err := postgres.NewOrderRepository(adConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create order repository: %v", err)
+	}
+
+	promotionRepo, err := postgres.NewPromotionRepository(adConn)
+	if err != nil {
+		l.Log.Errorf("Failed to create promotion repository: %v", err)
+	}
+
+	eventRepo, err := redis.NewEventRepository(sessionConn, cfg.Redis)
+	if err != nil {
+		l.Log.Errorf("Failed to create event repository: %v", err)
+	}
+
+	notifier := outbox.NewNotifier(cfg.Notifier.OutboxPath)
+
 	// Use Cases Init
-	authService := service.NewAuthService(sessionRepo, userRepo)
-	userService := service.NewUserService(userRepo)
-	adService := service.NewAdvertisementService(adRepo, userRepo)
+	pepper := entity.Pepper{ID: cfg.Password.PepperID, Key: []byte(cfg.Password.Pepper)}
+	notificationService := service.NewNotificationService(notificationRepo, eventRepo)
+	authService := service.NewAuthService(sessionRepo, userRepo, apiKeyRepo)
+	userService := service.NewUserService(userRepo, sessionRepo, loginAttemptRepo, notificationService, cfg.LoginProtection, pepper)
+	contentFilter, err := contentfilter.New(cfg.ContentFilter)
+	if err != nil {
+		l.Log.Errorf("Failed to create content filter: %v", err)
+	}
+	adService := service.NewAdvertisementService(
+		adRepo, userRepo, similarAdsCacheRepo, notificationService, contentFilter,
+		cfg.AdExpiry, cfg.Duplicates, cfg.SimilarAds,
+	)
+	moderationService := service.NewModerationService(adRepo, userRepo, notificationService, cfg.Duplicates)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
+	twoFactorService := service.NewTwoFactorService(
+		userRepo, twoFactorRepo, challengeRepo, loginAttemptRepo, cfg.TwoFactor, pepper,
+	)
+	passwordService := service.NewPasswordService(
+		userRepo,
+		sessionRepo,
+		passwordResetRepo,
+		loginAttemptRepo,
+		notifier,
+		notificationService,
+		pepper,
+		cfg.Password,
+	)
+	contactService := service.NewContactService(userRepo, contactVerificationRepo, notifier, cfg.Contacts)
+	accountService := service.NewAccountService(
+		userRepo,
+		adRepo,
+		apiKeyRepo,
+		sessionRepo,
+		twoFactorRepo,
+		pepper,
+		cfg.Account,
+	)
+	reviewService := service.NewReviewService(reviewRepo, adRepo, orderRepo, notificationService, cfg.Reviews)
+	conversationService := service.NewConversationService(conversationRepo, adRepo, eventRepo, notificationService)
+	orderService := service.NewOrderService(orderRepo, adRepo, notificationService)
+	promotionService := service.NewPromotionService(promotionRepo, adRepo, cfg.Promotions)
+	offerService := service.NewOfferService(offerRepo, adRepo, notificationService, cfg.Offers)
+	eventService := service.NewEventService(eventRepo, cfg.Stream)
+	digestSenders, err := service.NewSearchDigestSenders(cfg.SavedSearches.DigestChannels, notificationService, userRepo, notifier)
+	if err != nil {
+		l.Log.Errorf("Failed to create saved search digest senders: %v", err)
+	}
+	savedSearchService := service.NewSavedSearchService(savedSearchRepo, digestSenders, cfg.SavedSearches)
+
 	// Transport Init
-	authHandler := handler.NewAuthHandler(authService, cfg.CSRF)
-	userHandler := handler.NewUserHandler(authService, userService, cfg.CSRF)
-	adHandler := handler.NewAdvertisementHandler(authService, adService, cfg.CSRF)
+	authHandler := handler.NewAuthHandler(authService, userService, cfg.CSRF)
+	userHandler := handler.NewUserHandler(authService, userService, twoFactorService, passwordService, contactService, accountService, cfg.CSRF)
+	adHandler := handler.NewAdvertisementHandler(adService, cfg.CSRF)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService, cfg.CSRF)
+	reviewHandler := handler.NewReviewHandler(reviewService, cfg.CSRF)
+	conversationHandler := handler.NewConversationHandler(conversationService, cfg.CSRF)
+	notificationHandler := handler.NewNotificationHandler(notificationService, cfg.CSRF)
+	offerHandler := handler.NewOfferHandler(offerService, cfg.CSRF)
+	orderHandler := handler.NewOrderHandler(orderService, cfg.CSRF)
+	promotionHandler := handler.NewPromotionHandler(promotionService, cfg.CSRF)
+	savedSearchHandler := handler.NewSavedSearchHandler(savedSearchService, cfg.CSRF)
+	moderationHandler := handler.NewModerationHandler(moderationService, cfg.CSRF)
+	eventHandler := handler.NewEventHandler(eventService, authService, cfg.Stream, cfg.HTTP)
 
 	// Server Init
 	srv := server.NewServer(cfg)
+	srv.Use(middleware.AuthMiddleware(authService))
+	srv.Use(middleware.LanguageMiddleware(userService))
 
 	// Router config
 	srv.SetupRoutes(func(r *http.ServeMux) {
 		authHandler.Configure(r)
 		userHandler.Configure(r)
 		adHandler.Configure(r)
+		apiKeyHandler.Configure(r)
+		reviewHandler.Configure(r)
+		conversationHandler.Configure(r)
+		notificationHandler.Configure(r)
+		offerHandler.Configure(r)
+		orderHandler.Configure(r)
+		promotionHandler.Configure(r)
+		savedSearchHandler.Configure(r)
+		moderationHandler.Configure(r)
+		eventHandler.Configure(r)
 	})
 
+	// Background jobs
+	srv.Go(eventService.Run)
+
+	if cfg.Account.PurgeInterval > 0 {
+		srv.Go(func(ctx context.Context) {
+			runPeriodically(ctx, cfg.Account.PurgeInterval, func(ctx context.Context) {
+				if _, err := accountService.PurgeDeleted(ctx); err != nil {
+					l.Log.Errorf("Failed to purge deleted accounts: %v", err)
+				}
+			})
+		})
+	}
+
+	if cfg.SavedSearches.MatchInterval > 0 {
+		srv.Go(func(ctx context.Context) {
+			runPeriodically(ctx, cfg.SavedSearches.MatchInterval, func(ctx context.Context) {
+				if _, err := savedSearchService.MatchNewAds(ctx); err != nil {
+					l.Log.Errorf("Failed to match new ads with saved searches: %v", err)
+				}
+			})
+		})
+	}
+
+	if cfg.SavedSearches.DigestInterval > 0 {
+		srv.Go(func(ctx context.Context) {
+			runPeriodically(ctx, cfg.SavedSearches.DigestInterval, func(ctx context.Context) {
+				if _, err := savedSearchService.SendDigests(ctx); err != nil {
+					l.Log.Errorf("Failed to send saved search digests: %v", err)
+				}
+			})
+		})
+	}
+
+	if cfg.Offers.ExpireInterval > 0 {
+		srv.Go(func(ctx context.Context) {
+			runPeriodically(ctx, cfg.Offers.ExpireInterval, func(ctx context.Context) {
+				if _, err := offerService.ExpirePending(ctx); err != nil {
+					l.Log.Errorf("Failed to expire pending offers: %v", err)
+				}
+			})
+		})
+	}
+
+	if cfg.AdExpiry.CheckInterval > 0 {
+		srv.Go(func(ctx context.Context) {
+			runPeriodically(ctx, cfg.AdExpiry.CheckInterval, func(ctx context.Context) {
+				if _, err := adService.ArchiveExpired(ctx); err != nil {
+					l.Log.Errorf("Failed to archive expired advertisements: %v", err)
+				}
+				if _, err := adService.NotifyExpiring(ctx); err != nil {
+					l.Log.Errorf("Failed to notify about expiring advertisements: %v", err)
+				}
+			})
+		})
+	}
+
+	if cfg.Promotions.ExpireInterval > 0 {
+		srv.Go(func(ctx context.Context) {
+			runPeriodically(ctx, cfg.Promotions.ExpireInterval, func(ctx context.Context) {
+				if _, err := promotionService.ExpireFinished(ctx); err != nil {
+					l.Log.Errorf("Failed to expire finished promotions: %v", err)
+				}
+			})
+		})
+	}
+
 	return srv
 }
+
+// runPeriodically вызывает job сразу и далее с интервалом interval, пока не отменен ctx.
+func runPeriodically(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		job(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -16,6 +18,11 @@ type HTTPConfig struct {
 	WriteTimeout       time.Duration `yaml:"writeTimeout"`
 	MaxHeaderBytes     int           `yaml:"maxHeaderBytes"`
 	CORSAllowedOrigins []string      `yaml:"corsAllowedOrigins"`
+	// TrustedProxies — адреса и подсети (CIDR) прокси, которым разрешено передавать адрес
+	// клиента в X-Forwarded-For и X-Real-Ip. Пустой список — адрес клиента берется из соединения.
+	TrustedProxies []string `yaml:"trustedProxies"`
+	// TrustedProxyNets заполняется из TrustedProxies при загрузке.
+	TrustedProxyNets []netip.Prefix `yaml:"-"`
 }
 
 type SessionConfig struct {
@@ -36,6 +43,155 @@ type CSRFConfig struct {
 	SameSite   string        `yaml:"sameSite"`
 }
 
+type TwoFactorConfig struct {
+	Issuer               string        `yaml:"issuer"`
+	ChallengeLifetime    time.Duration `yaml:"challengeLifetime"`
+	ChallengeMaxAttempts int           `yaml:"challengeMaxAttempts"`
+	CodeLockout          time.Duration `yaml:"codeLockout"`
+}
+
+type LoginProtectionConfig struct {
+	MaxAttempts   int           `yaml:"maxAttempts"`
+	IPMaxAttempts int           `yaml:"ipMaxAttempts"`
+	Window        time.Duration `yaml:"window"`
+	BaseLockout   time.Duration `yaml:"baseLockout"`
+	MaxLockout    time.Duration `yaml:"maxLockout"`
+}
+
+type PasswordConfig struct {
+	PepperID           string        `yaml:"pepperId"`
+	Pepper             string        `yaml:"-"`
+	ResetTokenLifetime time.Duration `yaml:"resetTokenLifetime"`
+	ResetURL           string        `yaml:"resetUrl"`
+	ResetMaxRequests   int           `yaml:"resetMaxRequests"`
+	ResetWindow        time.Duration `yaml:"resetWindow"`
+}
+
+type ContactsConfig struct {
+	CodeLifetime    time.Duration `yaml:"codeLifetime"`
+	CodeMaxAttempts int           `yaml:"codeMaxAttempts"`
+}
+
+type NotifierConfig struct {
+	OutboxPath string `yaml:"outboxPath"`
+}
+
+type AccountConfig struct {
+	DeletionGracePeriod time.Duration `yaml:"deletionGracePeriod"`
+	PurgeInterval       time.Duration `yaml:"purgeInterval"`
+}
+
+type ReviewsConfig struct {
+	HideAfterReports int `yaml:"hideAfterReports"`
+}
+
+// OfferConfig настраивает предложения цены.
+type OfferConfig struct {
+	// TTL — сколько предложение ждет ответа, прежде чем истечь.
+	TTL time.Duration `yaml:"ttl"`
+	// AcceptedTTL — сколько покупатель может оформить заказ по принятому предложению,
+	// прежде чем резерв с объявления снимется.
+	AcceptedTTL    time.Duration `yaml:"acceptedTTL"`
+	ExpireInterval time.Duration `yaml:"expireInterval"`
+}
+
+// PromotionConfig настраивает продвижение объявлений.
+type PromotionConfig struct {
+	MaxDuration    time.Duration `yaml:"maxDuration"`
+	ExpireInterval time.Duration `yaml:"expireInterval"`
+}
+
+// AdExpiryConfig настраивает срок публикации объявлений.
+type AdExpiryConfig struct {
+	// TTL — срок публикации нового или продленного объявления.
+	TTL         time.Duration `yaml:"ttl"`
+	MaxRenewals int           `yaml:"maxRenewals"`
+	// NotifyBefore — за сколько до истечения владелец получает предупреждение.
+	NotifyBefore  time.Duration `yaml:"notifyBefore"`
+	CheckInterval time.Duration `yaml:"checkInterval"`
+	BatchSize     int           `yaml:"batchSize"`
+}
+
+// DuplicateConfig настраивает поиск повторно размещенных объявлений.
+type DuplicateConfig struct {
+	// Action — что делать с почти дубликатом активного объявления того же автора:
+	// reject отклоняет его, flag публикует с пометкой для модераторов.
+	// Точная копия отклоняется всегда.
+	Action string `yaml:"action"`
+	// MaxDistance — сколько бит simhash текста может отличаться у дубликатов;
+	// при совпадающем изображении допуск удваивается.
+	MaxDistance int `yaml:"maxDistance"`
+}
+
+// SimilarAdsConfig настраивает рекомендации похожих объявлений на странице объявления.
+type SimilarAdsConfig struct {
+	Limit int `yaml:"limit"`
+	// CacheTTL — сколько хранится подборка для объявления; за это время проданные
+	// и снятые объявления могут еще попадаться в ней.
+	CacheTTL time.Duration `yaml:"cacheTTL"`
+}
+
+// ContentFilterConfig настраивает проверку текста объявлений на спам и запрещенное содержимое.
+// Severity у детекторов и правил: block — объявление отклоняется, review — уходит на модерацию,
+// пустое значение отключает проверку.
+type ContentFilterConfig struct {
+	Enabled   bool                      `yaml:"enabled"`
+	Links     LinkFilterConfig          `yaml:"links"`
+	Phones    PhoneFilterConfig         `yaml:"phones"`
+	Profanity ProfanityFilterConfig     `yaml:"profanity"`
+	Rules     []ContentFilterRuleConfig `yaml:"rules"`
+}
+
+type LinkFilterConfig struct {
+	Severity string `yaml:"severity"`
+	// AllowedDomains — домены, ссылки на которые разрешены, вместе с поддоменами.
+	AllowedDomains []string `yaml:"allowedDomains"`
+}
+
+type PhoneFilterConfig struct {
+	Severity string `yaml:"severity"`
+}
+
+type ProfanityFilterConfig struct {
+	Severity string `yaml:"severity"`
+	// Languages — встроенные списки нецензурной лексики: ru, en.
+	Languages []string `yaml:"languages"`
+}
+
+// ContentFilterRuleConfig — правило из списка ключевых слов и регулярных выражений.
+// Ключевое слово со звездочкой на конце совпадает по началу слова, фраза из нескольких слов —
+// с последовательностью слов текста.
+type ContentFilterRuleConfig struct {
+	Name     string   `yaml:"name"`
+	Severity string   `yaml:"severity"`
+	Reason   string   `yaml:"reason"`
+	Keywords []string `yaml:"keywords"`
+	Patterns []string `yaml:"patterns"`
+}
+
+// SavedSearchConfig настраивает сохраненные поиски и оповещения о новых объявлениях.
+type SavedSearchConfig struct {
+	MaxPerUser     int           `yaml:"maxPerUser"`
+	MatchInterval  time.Duration `yaml:"matchInterval"`
+	MatchBatchSize int           `yaml:"matchBatchSize"`
+	// Совпадения копятся между запусками дайджеста и отправляются одним оповещением на пользователя.
+	DigestInterval  time.Duration `yaml:"digestInterval"`
+	DigestBatchSize int           `yaml:"digestBatchSize"`
+	DigestMaxAds    int           `yaml:"digestMaxAds"`
+	// DigestChannels — каналы доставки дайджеста: in_app, outbox.
+	DigestChannels []string `yaml:"digestChannels"`
+}
+
+// StreamConfig настраивает поток событий /events/stream.
+type StreamConfig struct {
+	// HeartbeatInterval не может превышать половину HTTPConfig.WriteTimeout,
+	// иначе соединение оборвется между двумя записями.
+	HeartbeatInterval     time.Duration `yaml:"heartbeatInterval"`
+	BufferSize            int           `yaml:"bufferSize"`
+	MaxConnectionsPerUser int           `yaml:"maxConnectionsPerUser"`
+	ReconnectDelay        time.Duration `yaml:"reconnectDelay"`
+}
+
 type PostgresConfig struct {
 	Host     string `yaml:"host"`
 	Port     string `yaml:"port"`
@@ -55,11 +211,26 @@ type RedisConfig struct {
 }
 
 type Config struct {
-	HTTP     HTTPConfig     `yaml:"http"`
-	Session  SessionConfig  `yaml:"session_id"`
-	CSRF     CSRFConfig     `yaml:"csrf"`
-	Postgres PostgresConfig `yaml:"postgres"`
-	Redis    RedisConfig    `yaml:"redis"`
+	HTTP            HTTPConfig            `yaml:"http"`
+	Session         SessionConfig         `yaml:"session_id"`
+	CSRF            CSRFConfig            `yaml:"csrf"`
+	TwoFactor       TwoFactorConfig       `yaml:"twoFactor"`
+	LoginProtection LoginProtectionConfig `yaml:"loginProtection"`
+	Password        PasswordConfig        `yaml:"password"`
+	Contacts        ContactsConfig        `yaml:"contacts"`
+	Notifier        NotifierConfig        `yaml:"notifier"`
+	Account         AccountConfig         `yaml:"account"`
+	Reviews         ReviewsConfig         `yaml:"reviews"`
+	Stream          StreamConfig          `yaml:"stream"`
+	SavedSearches   SavedSearchConfig     `yaml:"savedSearches"`
+	Offers          OfferConfig           `yaml:"offers"`
+	Promotions      PromotionConfig       `yaml:"promotions"`
+	AdExpiry        AdExpiryConfig        `yaml:"adExpiry"`
+	Duplicates      DuplicateConfig       `yaml:"duplicates"`
+	SimilarAds      SimilarAdsConfig      `yaml:"similarAds"`
+	ContentFilter   ContentFilterConfig   `yaml:"contentFilter"`
+	Postgres        PostgresConfig        `yaml:"postgres"`
+	Redis           RedisConfig           `yaml:"redis"`
 }
 
 func Load() (*Config, error) {
@@ -79,8 +250,18 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("error parsing YAML: %w", err)
 	}
 
+	cfg.HTTP.TrustedProxyNets, err = ParseTrustedProxies(cfg.HTTP.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing http.trustedProxies: %w", err)
+	}
+
+	if err := cfg.validateBatchSizes(); err != nil {
+		return nil, err
+	}
+
 	// Заполнение секретов из .env
 	cfg.CSRF.Secret = os.Getenv("CSRF_SECRET")
+	cfg.Password.Pepper = os.Getenv("PASSWORD_PEPPER")
 
 	// Формирование DSN для PostgreSQL
 	cfg.Postgres = PostgresConfig{
@@ -111,3 +292,46 @@ func Load() (*Config, error) {
 
 	return &cfg, nil
 }
+
+// validateBatchSizes проверяет размеры пачек фоновых задач: задача обрабатывает
+// данные пачками, пока пачка не окажется неполной.
+func (c *Config) validateBatchSizes() error {
+	batches := []struct {
+		name string
+		size int
+	}{
+		{"savedSearches.matchBatchSize", c.SavedSearches.MatchBatchSize},
+		{"savedSearches.digestBatchSize", c.SavedSearches.DigestBatchSize},
+		{"adExpiry.batchSize", c.AdExpiry.BatchSize},
+	}
+	for _, batch := range batches {
+		if batch.size <= 0 {
+			return fmt.Errorf("%s must be positive, got %d", batch.name, batch.size)
+		}
+	}
+	return nil
+}
+
+// ParseTrustedProxies разбирает адреса и подсети доверенных прокси. Одиночный адрес
+// превращается в подсеть из одного адреса.
+func ParseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if strings.Contains(v, "/") {
+			prefix, err := netip.ParsePrefix(v)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
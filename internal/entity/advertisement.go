@@ -2,7 +2,6 @@ package entity
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"image"
 	_ "image/gif"
@@ -20,18 +19,119 @@ import (
 )
 
 type Advertisement struct {
-	ID          int       `json:"id" db:"id"`
-	Title       string    `json:"title" valid:"required,length(3|50)"`
-	Description string    `json:"description" valid:"required,length(10|500)"`
-	ImageURL    string    `json:"image_url" valid:"required,url,imgext"`
-	Price       float64   `json:"price" valid:"required,float"`
-	UserID      int       `json:"user_id" valid:"required"`
-	AuthorLogin string    `json:"author_login"`
-	IsMine      bool      `json:"is_mine"`
+	ID             int       `json:"id" db:"id"`
+	Title          string    `json:"title" valid:"required,length(3|50)"`
+	Description    string    `json:"description" valid:"required,length(10|500)"`
+	ImageURL       string    `json:"image_url" valid:"required,url,imgext"`
+	Price          float64   `json:"price" valid:"required,float"`
+	UserID         int       `json:"user_id" valid:"required"`
+	AuthorLogin    string    `json:"author_login"`
+	AuthorVerified bool      `json:"author_verified"`
+	AuthorRating   float64   `json:"author_rating"`
+	AuthorReviews  int       `json:"author_reviews_count"`
+	IsMine         bool      `json:"is_mine"`
+	Status         AdStatus  `json:"status"`
+	IsPromoted     bool      `json:"is_promoted"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	RenewalsCount  int       `json:"renewals_count"`
+	// Version растет при каждом изменении объявления и отдается клиенту в ETag.
+	Version int `json:"version"`
+	// Fingerprint заполняется сервисом при создании объявления.
+	Fingerprint AdFingerprint `json:"-"`
+	// ModerationStatus — решение по объявлению, которое фильтр содержимого отправил на модерацию.
+	ModerationStatus ModerationStatus `json:"moderation_status"`
+	ModerationNote   string           `json:"moderation_note,omitempty"`
+	// DuplicateOf — объявление того же автора, копией которого помечено это.
+	DuplicateOf *int      `json:"duplicate_of,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+type AdStatus string
+
+const (
+	AdPublished AdStatus = "published"
+	// AdReserved — продавец принял предложение цены или по объявлению оформлен заказ,
+	// новые предложения не принимаются.
+	AdReserved AdStatus = "reserved"
+	// AdSold — заказ по объявлению завершен.
+	AdSold AdStatus = "sold"
+	// AdArchived — срок публикации истек, объявление скрыто из ленты до продления.
+	AdArchived AdStatus = "archived"
+)
+
+type ModerationStatus string
+
+const (
+	ModerationApproved ModerationStatus = "approved"
+	// ModerationPending — объявление ждет модератора и не показывается в ленте.
+	ModerationPending  ModerationStatus = "pending"
+	ModerationRejected ModerationStatus = "rejected"
+)
+
+// IsExpired сообщает, истек ли срок публикации объявления в момент now.
+func (a *Advertisement) IsExpired(now time.Time) bool {
+	return a.Status == AdArchived || !now.Before(a.ExpiresAt)
+}
+
+// CanRenew проверяет, что пользователь может продлить объявление: продлевать можно только
+// свои непроданные объявления и не больше maxRenewals раз.
+func (a *Advertisement) CanRenew(userID, maxRenewals int) error {
+	if a.UserID != userID {
+		return NewError(ErrForbidden, NewLocalizedMessage(MsgAdRenewNotOwner, nil))
+	}
+	if a.Status == AdSold {
+		return NewError(ErrConflict, NewLocalizedMessage(MsgAdRenewSold, MessageParams{"id": a.ID}))
+	}
+	if a.RenewalsCount >= maxRenewals {
+		return NewError(ErrConflict, NewLocalizedMessage(MsgAdRenewLimit, MessageParams{"max": maxRenewals}))
+	}
+	return nil
+}
+
+// CanEdit проверяет, что пользователь может изменить объявление: только свое и только
+// пока по нему не идет сделка.
+func (a *Advertisement) CanEdit(userID int) error {
+	if a.UserID != userID {
+		return NewError(ErrForbidden, NewLocalizedMessage(MsgAdNotOwner, nil))
+	}
+	return a.CheckEditable()
+}
+
+// CheckEditable запрещает менять зарезервированное или проданное объявление.
+func (a *Advertisement) CheckEditable() error {
+	if a.Status == AdReserved || a.Status == AdSold {
+		return NewError(ErrConflict, NewLocalizedMessage(MsgAdLocked, MessageParams{"status": a.Status}))
+	}
+	return nil
+}
+
+// CanDelete проверяет, что пользователь может удалить объявление. Проданное объявление
+// удалить можно, зарезервированное — нет, пока не завершена сделка.
+func (a *Advertisement) CanDelete(userID int) error {
+	if a.UserID != userID {
+		return NewError(ErrForbidden, NewLocalizedMessage(MsgAdNotOwner, nil))
+	}
+	return a.CheckDeletable()
+}
+
+// CheckDeletable запрещает удалять зарезервированное объявление.
+func (a *Advertisement) CheckDeletable() error {
+	if a.Status == AdReserved {
+		return NewError(ErrConflict, NewLocalizedMessage(MsgAdLocked, MessageParams{"status": a.Status}))
+	}
+	return nil
+}
+
+// CheckVersion сверяет версию объявления с версией из If-Match. Версия 0 означает If-Match: *.
+func (a *Advertisement) CheckVersion(version int) error {
+	if version != 0 && a.Version != version {
+		return NewError(ErrPreconditionFailed,
+			NewLocalizedMessage(MsgAdVersionMismatch, MessageParams{"id": a.ID, "version": a.Version}))
+	}
+	return nil
+}
+
 const (
 	AdTitleMinLen       = 3
 	AdTitleMaxLen       = 50
@@ -64,7 +164,8 @@ func init() {
 	})
 }
 
-type FieldErrors map[string]string
+// FieldErrors — ошибки по полям: ключ — имя поля, значение — сообщение для клиента.
+type FieldErrors map[string]LocalizedMessage
 
 type AdvValidationError struct {
 	Fields FieldErrors
@@ -80,7 +181,7 @@ func (e *AdvValidationError) Error() string {
 		b.WriteString("; ")
 		b.WriteString(f)
 		b.WriteString(": ")
-		b.WriteString(m)
+		b.WriteString(m.Error())
 	}
 	return b.String()
 }
@@ -92,7 +193,11 @@ func parseGoValidatorErr(err error) FieldErrors {
 	m := govalidator.ErrorsByField(err)
 	fe := make(FieldErrors, len(m))
 	for k, v := range m {
-		fe[k] = v
+		code := MsgInvalid
+		if strings.Contains(v, "non zero value required") {
+			code = MsgRequired
+		}
+		fe[k] = NewLocalizedMessage(code, nil)
 	}
 	return fe
 }
@@ -109,24 +214,24 @@ func (a *Advertisement) Validate() (bool, error) {
 
 	l := len(strings.TrimSpace(a.Title))
 	if l < AdTitleMinLen || l > AdTitleMaxLen {
-		fe["title"] = fmt.Sprintf("длина должна быть от %d до %d", AdTitleMinLen, AdTitleMaxLen)
+		fe["title"] = NewLocalizedMessage(MsgLengthBetween, MessageParams{"min": AdTitleMinLen, "max": AdTitleMaxLen})
 	}
 
 	l = len(strings.TrimSpace(a.Description))
 	if l < AdDescriptionMinLen || l > AdDescriptionMaxLen {
-		fe["description"] = fmt.Sprintf("длина должна быть от %d до %d", AdDescriptionMinLen, AdDescriptionMaxLen)
+		fe["description"] = NewLocalizedMessage(MsgLengthBetween, MessageParams{"min": AdDescriptionMinLen, "max": AdDescriptionMaxLen})
 	}
 
-	if e := validatePriceRange(a.Price); e != nil {
-		fe["price"] = e.Error()
+	if m := validatePriceRange(a.Price); m != nil {
+		fe["price"] = *m
 	}
 
-	if e := validateImageURLBasic(a.ImageURL); e != nil {
-		fe["image_url"] = e.Error()
+	if m := validateImageURLBasic(a.ImageURL); m != nil {
+		fe["image_url"] = *m
 	}
 
 	if a.UserID <= 0 {
-		fe["user_id"] = "должен быть > 0"
+		fe["user_id"] = NewLocalizedMessage(MsgPositive, nil)
 	}
 
 	if len(fe) > 0 {
@@ -136,31 +241,38 @@ func (a *Advertisement) Validate() (bool, error) {
 }
 
 // validatePriceRange проверяет диапазон цены.
-func validatePriceRange(p float64) error {
+func validatePriceRange(p float64) *LocalizedMessage {
 	if p < AdPriceMin {
-		return fmt.Errorf("цена не может быть меньше %.2f", AdPriceMin)
+		m := NewLocalizedMessage(MsgPriceMin, MessageParams{"min": AdPriceMin})
+		return &m
 	}
 	if p > AdPriceMax {
-		return fmt.Errorf("цена не может превышать %.2f", AdPriceMax)
+		m := NewLocalizedMessage(MsgPriceMax, MessageParams{"max": float64(AdPriceMax)})
+		return &m
 	}
 	return nil
 }
 
 // validateImageURLBasic проверяет базовую корректность URL и допустимое расширение.
-func validateImageURLBasic(raw string) error {
+func validateImageURLBasic(raw string) *LocalizedMessage {
+	var m LocalizedMessage
 	if raw == "" {
-		return errors.New("url не указан")
+		m = NewLocalizedMessage(MsgURLRequired, nil)
+		return &m
 	}
 	if len(raw) > AdImageURLMaxLen {
-		return fmt.Errorf("длина url > %d", AdImageURLMaxLen)
+		m = NewLocalizedMessage(MsgURLTooLong, MessageParams{"max": AdImageURLMaxLen})
+		return &m
 	}
 	u, err := url.ParseRequestURI(raw)
 	if err != nil {
-		return fmt.Errorf("некорректный url: %w", err)
+		m = NewLocalizedMessage(MsgURLInvalid, nil)
+		return &m
 	}
 	ext := strings.ToLower(path.Ext(u.Path))
 	if _, ok := allowedImageExt[ext]; !ok {
-		return fmt.Errorf("недопустимый формат изображения: %s", ext)
+		m = NewLocalizedMessage(MsgImageFormat, MessageParams{"ext": ext})
+		return &m
 	}
 	return nil
 }
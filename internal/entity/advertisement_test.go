@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdvertisementCanRenew(t *testing.T) {
+	t.Parallel()
+
+	const maxRenewals = 3
+
+	tests := []struct {
+		name   string
+		ad     Advertisement
+		userID int
+		want   error
+	}{
+		{"published", Advertisement{UserID: 1, Status: AdPublished}, 1, nil},
+		{"archived", Advertisement{UserID: 1, Status: AdArchived, RenewalsCount: 2}, 1, nil},
+		{"not owner", Advertisement{UserID: 1, Status: AdPublished}, 2, ErrForbidden},
+		{"sold", Advertisement{UserID: 1, Status: AdSold}, 1, ErrConflict},
+		{"limit reached", Advertisement{UserID: 1, Status: AdArchived, RenewalsCount: maxRenewals}, 1, ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.ad.CanRenew(tt.userID, maxRenewals)
+			if tt.want == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, tt.want, err.(Error).ClientErr())
+		})
+	}
+}
+
+func TestAdvertisementCanEditAndDelete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		ad         Advertisement
+		userID     int
+		wantEdit   error
+		wantDelete error
+	}{
+		{"published", Advertisement{UserID: 1, Status: AdPublished}, 1, nil, nil},
+		{"archived", Advertisement{UserID: 1, Status: AdArchived}, 1, nil, nil},
+		{"not owner", Advertisement{UserID: 1, Status: AdPublished}, 2, ErrForbidden, ErrForbidden},
+		{"reserved", Advertisement{UserID: 1, Status: AdReserved}, 1, ErrConflict, ErrConflict},
+		{"sold", Advertisement{UserID: 1, Status: AdSold}, 1, ErrConflict, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, c := range []struct {
+				err  error
+				want error
+			}{
+				{tt.ad.CanEdit(tt.userID), tt.wantEdit},
+				{tt.ad.CanDelete(tt.userID), tt.wantDelete},
+			} {
+				if c.want == nil {
+					require.NoError(t, c.err)
+					continue
+				}
+				require.Error(t, c.err)
+				require.Equal(t, c.want, c.err.(Error).ClientErr())
+			}
+		})
+	}
+}
+
+func TestAdvertisementCheckVersion(t *testing.T) {
+	t.Parallel()
+
+	ad := Advertisement{ID: 7, Version: 3}
+	require.NoError(t, ad.CheckVersion(3))
+	require.NoError(t, ad.CheckVersion(0))
+
+	err := ad.CheckVersion(2)
+	require.ErrorIs(t, err, ErrPreconditionFailed)
+	require.ErrorContains(t, err, "ad.version_mismatch id=7 version=3")
+}
+
+func TestAdvertisementIsExpired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	ad := Advertisement{Status: AdPublished, ExpiresAt: now.Add(time.Hour)}
+	require.False(t, ad.IsExpired(now))
+	require.True(t, ad.IsExpired(now.Add(time.Hour)))
+
+	ad.Status = AdArchived
+	require.True(t, ad.IsExpired(now))
+}
@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ScopeAdsRead  = "ads:read"
+	ScopeAdsWrite = "ads:write"
+)
+
+const (
+	APIKeyPrefix      = "mpk_"
+	APIKeySecretBytes = 32
+	APIKeyDisplayLen  = 12
+	APIKeyNameMaxLen  = 64
+	APIKeyMaxPerUser  = 10
+)
+
+var allowedAPIKeyScopes = map[string]struct{}{
+	ScopeAdsRead:  {},
+	ScopeAdsWrite: {},
+}
+
+type APIKey struct {
+	ID         int
+	UserID     int
+	Name       string
+	Prefix     string
+	KeyHash    []byte
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// GenerateAPIKey возвращает ключ в открытом виде (показывается пользователю один раз),
+// его отображаемый префикс и хеш для хранения в БД.
+func GenerateAPIKey() (raw, prefix string, hash []byte, err error) {
+	secret := make([]byte, APIKeySecretBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", nil, NewError(
+			ErrInternal,
+			fmt.Errorf("ошибка при генерации API-ключа: %w", err),
+		)
+	}
+
+	raw = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return raw, raw[:APIKeyDisplayLen], HashAPIKey(raw), nil
+}
+
+// HashAPIKey хеширует ключ. Ключ содержит 256 бит случайных данных,
+// поэтому медленная функция хеширования здесь не нужна.
+func HashAPIKey(raw string) []byte {
+	sum := sha256.Sum256([]byte(raw))
+	return sum[:]
+}
+
+func ValidateAPIKeyName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return NewError(
+			ErrBadRequest,
+			NewLocalizedMessage(MsgAPIKeyNameEmpty, nil),
+		)
+	}
+	if utf8.RuneCountInString(name) > APIKeyNameMaxLen {
+		return NewError(
+			ErrBadRequest,
+			NewLocalizedMessage(MsgAPIKeyNameTooLong, MessageParams{"max": APIKeyNameMaxLen}),
+		)
+	}
+	return nil
+}
+
+func ValidateAPIKeyScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return NewError(
+			ErrBadRequest,
+			NewLocalizedMessage(MsgAPIKeyScopesEmpty, nil),
+		)
+	}
+	for _, s := range scopes {
+		if _, ok := allowedAPIKeyScopes[s]; !ok {
+			return NewError(
+				ErrBadRequest,
+				NewLocalizedMessage(MsgAPIKeyScopeUnknown, MessageParams{"scope": s}),
+			)
+		}
+	}
+	return nil
+}
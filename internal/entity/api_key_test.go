@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	t.Parallel()
+
+	raw, prefix, hash, err := GenerateAPIKey()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(raw, APIKeyPrefix))
+	require.Equal(t, raw[:APIKeyDisplayLen], prefix)
+	require.Equal(t, HashAPIKey(raw), hash)
+
+	other, _, _, err := GenerateAPIKey()
+	require.NoError(t, err)
+	require.NotEqual(t, raw, other)
+}
+
+func TestAPIKey_IsExpired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	key := APIKey{}
+	require.False(t, key.IsExpired(now))
+
+	key.ExpiresAt = &past
+	require.True(t, key.IsExpired(now))
+}
+
+func TestPrincipal_HasScope(t *testing.T) {
+	t.Parallel()
+
+	apiKey := Principal{Method: AuthMethodAPIKey, Scopes: []string{ScopeAdsRead}}
+	require.True(t, apiKey.HasScope(ScopeAdsRead))
+	require.False(t, apiKey.HasScope(ScopeAdsWrite))
+	require.False(t, apiKey.IsSession())
+
+	session := Principal{Method: AuthMethodSession}
+	require.True(t, session.HasScope(ScopeAdsWrite))
+	require.True(t, session.IsSession())
+}
+
+func TestValidateAPIKeyScopes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		scopes  []string
+		wantErr bool
+	}{
+		{name: "Известные права", scopes: []string{ScopeAdsRead, ScopeAdsWrite}},
+		{name: "Пустой список", scopes: nil, wantErr: true},
+		{name: "Неизвестное право", scopes: []string{"admin"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateAPIKeyScopes(tc.scopes)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -1,39 +1,33 @@
 package entity
 
 import (
-	"bytes"
-	"crypto/rand"
-	"fmt"
 	"regexp"
-
-	"golang.org/x/crypto/argon2"
 )
 
 const (
-	TimeCost        = 2
-	MemoryCost      = 64 * 1024
-	ParallelThreads = 2
-	HashLength      = 32
+	PasswordMinLen = 8
+	PasswordMaxLen = 32
+	LoginMaxLen    = 255
 )
 
 func ValidatePassword(password string) error {
 	switch {
-	case len(password) < 8:
+	case len(password) < PasswordMinLen:
 		return NewError(
 			ErrBadRequest,
-			fmt.Errorf("пароль должен содержать не менее 8 символов"),
+			NewLocalizedMessage(MsgPasswordTooShort, MessageParams{"min": PasswordMinLen}),
 		)
 
-	case len(password) > 32:
+	case len(password) > PasswordMaxLen:
 		return NewError(
 			ErrBadRequest,
-			fmt.Errorf("пароль должен содержать не более 32 символов"),
+			NewLocalizedMessage(MsgPasswordTooLong, MessageParams{"max": PasswordMaxLen}),
 		)
 
 	case !regexp.MustCompile(`^[!@#$%^&*a-zA-Z0-9_]+$`).MatchString(password):
 		return NewError(
 			ErrBadRequest,
-			fmt.Errorf("пароль должен состоять из латинских букв, цифр и специальных символов !@#$%%^&*"),
+			NewLocalizedMessage(MsgPasswordCharset, MessageParams{"specials": "!@#$%^&*"}),
 		)
 
 	default:
@@ -41,55 +35,20 @@ func ValidatePassword(password string) error {
 	}
 }
 
-func HashPassword(password string) (salt []byte, hash []byte, err error) {
-	salt = make([]byte, 8)
-	_, err = rand.Read(salt)
-	if err != nil {
-		return nil, nil, NewError(
-			ErrInternal,
-			fmt.Errorf("ошибка при хешировании пароля"),
-		)
-	}
-
-	hash = argon2.IDKey(
-		[]byte(password),
-		salt,
-		TimeCost,
-		MemoryCost,
-		ParallelThreads,
-		HashLength,
-	)
-	return salt, hash, nil
-}
-
-func CheckPassword(password string, passwordHash, passwordSalt []byte) bool {
-	return bytes.Equal(
-		argon2.IDKey(
-			[]byte(password),
-			passwordSalt,
-			TimeCost,
-			MemoryCost,
-			ParallelThreads,
-			HashLength,
-		),
-		passwordHash,
-	)
-}
-
 func ValidateLogin(login string) error {
 	re := regexp.MustCompile(`^[A-Za-z0-9._-]{3,30}$`)
 
 	if !re.MatchString(login) {
 		return NewError(
 			ErrBadRequest,
-			fmt.Errorf("невалидный логин"),
+			NewLocalizedMessage(MsgLoginInvalid, nil),
 		)
 	}
 
-	if len(login) > 255 {
+	if len(login) > LoginMaxLen {
 		return NewError(
 			ErrBadRequest,
-			fmt.Errorf("логин не может быть длиннее 255 символов"),
+			NewLocalizedMessage(MsgLoginTooLong, MessageParams{"max": LoginMaxLen}),
 		)
 	}
 	return nil
@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+type ContactChannel string
+
+const (
+	ContactEmail ContactChannel = "email"
+	ContactPhone ContactChannel = "phone"
+)
+
+const (
+	EmailMaxLen            = 254
+	VerificationCodeDigits = 6
+)
+
+func ParseContactChannel(s string) (ContactChannel, error) {
+	switch ContactChannel(s) {
+	case ContactEmail, ContactPhone:
+		return ContactChannel(s), nil
+	default:
+		return "", NewError(
+			ErrBadRequest,
+			NewLocalizedMessage(MsgContactChannelUnknown, MessageParams{"channel": s}),
+		)
+	}
+}
+
+// NormalizeContact приводит контакт к каноническому виду, в котором он хранится и сравнивается.
+func NormalizeContact(channel ContactChannel, value string) (string, error) {
+	switch channel {
+	case ContactEmail:
+		return NormalizeEmail(value)
+	case ContactPhone:
+		return NormalizePhone(value)
+	default:
+		return "", NewError(ErrBadRequest, NewLocalizedMessage(MsgContactChannelUnknown, MessageParams{"channel": channel}))
+	}
+}
+
+func NormalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || addr.Name != "" {
+		return "", NewError(ErrBadRequest, NewLocalizedMessage(MsgEmailInvalid, nil))
+	}
+	if len(email) > EmailMaxLen {
+		return "", NewError(
+			ErrBadRequest,
+			NewLocalizedMessage(MsgEmailTooLong, MessageParams{"max": EmailMaxLen}),
+		)
+	}
+
+	return email, nil
+}
+
+// NormalizePhone приводит номер к формату E.164 (+79991234567).
+// Российские номера, начинающиеся с 8, переводятся в +7.
+func NormalizePhone(phone string) (string, error) {
+	var digits strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '(' || r == ')':
+		default:
+			return "", NewError(ErrBadRequest, NewLocalizedMessage(MsgPhoneInvalid, nil))
+		}
+	}
+
+	number := digits.String()
+	if !strings.HasPrefix(strings.TrimSpace(phone), "+") && len(number) == 11 && number[0] == '8' {
+		number = "7" + number[1:]
+	}
+	if len(number) < 10 || len(number) > 15 {
+		return "", NewError(ErrBadRequest, NewLocalizedMessage(MsgPhoneInvalid, nil))
+	}
+
+	return "+" + number, nil
+}
+
+// ContactVerification — ожидающее подтверждения значение контакта и хеш отправленного кода.
+type ContactVerification struct {
+	Value    string
+	CodeHash []byte
+}
+
+func GenerateVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", NewError(
+			ErrInternal,
+			fmt.Errorf("ошибка при генерации кода подтверждения: %w", err),
+		)
+	}
+	return fmt.Sprintf("%0*d", VerificationCodeDigits, n.Int64()), nil
+}
+
+// HashVerificationCode привязывает код к пользователю и контакту,
+// чтобы код от одного адреса не подошел к другому.
+func HashVerificationCode(userID int, value, code string) []byte {
+	sum := sha256.Sum256([]byte(strconv.Itoa(userID) + ":" + value + ":" + strings.TrimSpace(code)))
+	return sum[:]
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: " User@Example.COM ", expected: "user@example.com"},
+		{input: "a.b+tag@mail.ru", expected: "a.b+tag@mail.ru"},
+		{input: "John <john@example.com>", wantErr: true},
+		{input: "not-an-email", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		email, err := NormalizeEmail(tc.input)
+		if tc.wantErr {
+			require.Error(t, err, tc.input)
+			continue
+		}
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.expected, email)
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: "+7 (999) 123-45-67", expected: "+79991234567"},
+		{input: "8 999 123 45 67", expected: "+79991234567"},
+		{input: "+44 20 7946 0958", expected: "+442079460958"},
+		{input: "12345", wantErr: true},
+		{input: "+7 999 abc", wantErr: true},
+		{input: "7+9991234567", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		phone, err := NormalizePhone(tc.input)
+		if tc.wantErr {
+			require.Error(t, err, tc.input)
+			continue
+		}
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.expected, phone)
+	}
+}
+
+func TestVerificationCode(t *testing.T) {
+	t.Parallel()
+
+	code, err := GenerateVerificationCode()
+	require.NoError(t, err)
+	require.Len(t, code, VerificationCodeDigits)
+
+	hash := HashVerificationCode(1, "user@example.com", code)
+	require.Equal(t, hash, HashVerificationCode(1, "user@example.com", " "+code))
+	require.NotEqual(t, hash, HashVerificationCode(2, "user@example.com", code))
+	require.NotEqual(t, hash, HashVerificationCode(1, "other@example.com", code))
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MessageMaxLen            = 2000
+	MessagePreviewLen        = 100
+	MessagesDefaultPage      = 50
+	MessagesMaxPage          = 100
+	ConversationsDefaultPage = 20
+	ConversationsMaxPage     = 100
+)
+
+// Conversation — переписка покупателя с продавцом по объявлению.
+type Conversation struct {
+	ID                 int
+	AdvertisementID    *int
+	AdvertisementTitle string
+	BuyerID            int
+	BuyerLogin         string
+	SellerID           int
+	SellerLogin        string
+	BuyerLastReadID    int64
+	SellerLastReadID   int64
+	BlockedBy          *int
+	LastMessageAt      *time.Time
+	// Unread — число непрочитанных сообщений для пользователя, запросившего переписку.
+	Unread    int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (c *Conversation) IsParticipant(userID int) bool {
+	return userID == c.BuyerID || userID == c.SellerID
+}
+
+// Counterpart возвращает id и логин второго участника.
+func (c *Conversation) Counterpart(userID int) (int, string) {
+	if userID == c.BuyerID {
+		return c.SellerID, c.SellerLogin
+	}
+	return c.BuyerID, c.BuyerLogin
+}
+
+// LastReadID — до какого сообщения переписку прочитал пользователь.
+func (c *Conversation) LastReadID(userID int) int64 {
+	if userID == c.BuyerID {
+		return c.BuyerLastReadID
+	}
+	return c.SellerLastReadID
+}
+
+// CounterpartLastReadID — до какого сообщения переписку прочитал второй участник.
+func (c *Conversation) CounterpartLastReadID(userID int) int64 {
+	if userID == c.BuyerID {
+		return c.SellerLastReadID
+	}
+	return c.BuyerLastReadID
+}
+
+func (c *Conversation) IsBlocked() bool {
+	return c.BlockedBy != nil
+}
+
+type Message struct {
+	ID             int64
+	ConversationID int
+	SenderID       int
+	Body           string
+	CreatedAt      time.Time
+}
+
+// ValidateMessageBody проверяет текст сообщения. Текст должен быть уже очищен от разметки.
+func ValidateMessageBody(body string) error {
+	l := utf8.RuneCountInString(strings.TrimSpace(body))
+	if l == 0 || l > MessageMaxLen {
+		return NewError(ErrBadRequest, &AdvValidationError{Fields: FieldErrors{
+			"body": NewLocalizedMessage(MsgLengthBetween, MessageParams{"min": 1, "max": MessageMaxLen}),
+		}})
+	}
+	return nil
+}
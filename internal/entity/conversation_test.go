@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversation_Participants(t *testing.T) {
+	t.Parallel()
+
+	c := Conversation{
+		BuyerID:          1,
+		BuyerLogin:       "buyer",
+		SellerID:         2,
+		SellerLogin:      "seller",
+		BuyerLastReadID:  10,
+		SellerLastReadID: 7,
+	}
+
+	require.True(t, c.IsParticipant(1))
+	require.True(t, c.IsParticipant(2))
+	require.False(t, c.IsParticipant(3))
+
+	id, login := c.Counterpart(1)
+	require.Equal(t, 2, id)
+	require.Equal(t, "seller", login)
+
+	id, login = c.Counterpart(2)
+	require.Equal(t, 1, id)
+	require.Equal(t, "buyer", login)
+
+	require.Equal(t, int64(10), c.LastReadID(1))
+	require.Equal(t, int64(7), c.CounterpartLastReadID(1))
+	require.Equal(t, int64(10), c.CounterpartLastReadID(2))
+}
+
+func TestValidateMessageBody(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateMessageBody("Здравствуйте, товар еще продается?"))
+	require.Error(t, ValidateMessageBody(""))
+	require.Error(t, ValidateMessageBody(" \n "))
+	require.NoError(t, ValidateMessageBody(strings.Repeat("я", MessageMaxLen)))
+	require.Error(t, ValidateMessageBody(strings.Repeat("я", MessageMaxLen+1)))
+}
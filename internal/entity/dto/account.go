@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+type DeleteAccountResponse struct {
+	DeleteAfter time.Time `json:"delete_after"`
+}
+
+type SessionExport struct {
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Current   bool      `json:"current"`
+}
+
+// AccountExport — все данные пользователя, которые хранит сервис.
+type AccountExport struct {
+	ExportedAt       time.Time               `json:"exported_at"`
+	Profile          UserProfileResponse     `json:"profile"`
+	Advertisements   []AdvertisementResponse `json:"advertisements"`
+	APIKeys          []APIKeyResponse        `json:"api_keys"`
+	Sessions         []SessionExport         `json:"sessions"`
+	TwoFactorEnabled bool                    `json:"two_factor_enabled"`
+}
@@ -9,24 +9,52 @@ type CreateAdvertisementRequest struct {
 	Price       float64 `json:"price"`
 }
 
+// UpdateAdvertisementRequest полностью заменяет содержимое объявления.
+type UpdateAdvertisementRequest struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	ImageURL    string  `json:"image_url"`
+	Price       float64 `json:"price"`
+}
+
 type AdvertisementResponse struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	ImageURL    string    `json:"image_url"`
-	Price       float64   `json:"price"`
-	UserID      int       `json:"user_id"`
-	AuthorLogin string    `json:"author_login"`
-	IsMine      bool      `json:"is_mine"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             int     `json:"id"`
+	Title          string  `json:"title"`
+	Description    string  `json:"description"`
+	ImageURL       string  `json:"image_url"`
+	Price          float64 `json:"price"`
+	UserID         int     `json:"user_id"`
+	AuthorLogin    string  `json:"author_login"`
+	AuthorVerified bool    `json:"author_verified"`
+	AuthorRating   float64 `json:"author_rating"`
+	AuthorReviews  int     `json:"author_reviews_count"`
+	IsMine         bool    `json:"is_mine"`
+	Status         string  `json:"status"`
+	// ModerationStatus заполняется для автора: approved, pending или rejected.
+	ModerationStatus string    `json:"moderation_status,omitempty"`
+	IsPromoted       bool      `json:"is_promoted"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RenewalsCount    int       `json:"renewals_count"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 type AdvertisementShort struct {
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	ImageURL    string    `json:"image_url"`
-	Price       float64   `json:"price"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Title          string  `json:"title"`
+	Description    string  `json:"description"`
+	ImageURL       string  `json:"image_url"`
+	Price          float64 `json:"price"`
+	AuthorVerified bool    `json:"author_verified"`
+	AuthorRating   float64 `json:"author_rating"`
+	AuthorReviews  int     `json:"author_reviews_count"`
+	Status         string  `json:"status"`
+	// ModerationStatus заполняется для автора: approved, pending или rejected.
+	ModerationStatus string    `json:"moderation_status,omitempty"`
+	IsPromoted       bool      `json:"is_promoted"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RenewalsCount    int       `json:"renewals_count"`
+	// Version совпадает со значением заголовка ETag.
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -17,11 +17,14 @@ type Login struct {
 }
 
 type AuthResponse struct {
-	UserId int `json:"user_id"`
+	UserId int                  `json:"user_id"`
+	User   *CurrentUserResponse `json:"user,omitempty"`
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token             string `json:"token,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
 }
 
 type LoginExistsRequest struct {
@@ -0,0 +1,13 @@
+package dto
+
+type SetContactRequest struct {
+	Value string `json:"value"`
+}
+
+type VerifyContactRequest struct {
+	Code string `json:"code"`
+}
+
+type EmailExistsResponse struct {
+	Exists bool `json:"exists"`
+}
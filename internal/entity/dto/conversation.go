@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+type StartConversationRequest struct {
+	Message string `json:"message"`
+}
+
+type SendMessageRequest struct {
+	Body string `json:"body"`
+}
+
+type MarkReadRequest struct {
+	// MessageID — последнее прочитанное сообщение; 0 отмечает прочитанной всю переписку.
+	MessageID int64 `json:"message_id"`
+}
+
+type ConversationResponse struct {
+	ID                    int        `json:"id"`
+	AdvertisementID       *int       `json:"advertisement_id"`
+	AdvertisementTitle    string     `json:"advertisement_title"`
+	Role                  string     `json:"role"`
+	CounterpartID         int        `json:"counterpart_id"`
+	CounterpartLogin      string     `json:"counterpart_login"`
+	CounterpartLastReadID int64      `json:"counterpart_last_read_id"`
+	Unread                int        `json:"unread"`
+	Blocked               bool       `json:"blocked"`
+	BlockedByMe           bool       `json:"blocked_by_me"`
+	LastMessageAt         *time.Time `json:"last_message_at"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+type MessageResponse struct {
+	ID             int64     `json:"id"`
+	ConversationID int       `json:"conversation_id"`
+	SenderID       int       `json:"sender_id"`
+	Body           string    `json:"body"`
+	IsMine         bool      `json:"is_mine"`
+	Read           bool      `json:"read"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type MessagesPage struct {
+	Messages []MessageResponse `json:"messages"`
+	// NextCursor передается в before для получения более старых сообщений.
+	NextCursor *int64 `json:"next_cursor,omitempty"`
+}
+
+type UnreadCountResponse struct {
+	Unread int `json:"unread"`
+}
+
+// MessageReadEvent — данные события message.read для второго участника переписки.
+type MessageReadEvent struct {
+	ConversationID int   `json:"conversation_id"`
+	ReaderID       int   `json:"reader_id"`
+	LastReadID     int64 `json:"last_read_id"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+type SimilarAdResponse struct {
+	ID          int     `json:"id"`
+	UserID      int     `json:"user_id"`
+	AuthorLogin string  `json:"author_login"`
+	Title       string  `json:"title"`
+	ImageURL    string  `json:"image_url"`
+	Price       float64 `json:"price"`
+	Status      string  `json:"status"`
+	// Distance — число различающихся бит отпечатка текста, 0 — тексты совпадают.
+	Distance      int       `json:"distance"`
+	SameImage     bool      `json:"same_image"`
+	NearDuplicate bool      `json:"near_duplicate"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type DuplicateAdResponse struct {
+	ID          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	AuthorLogin string    `json:"author_login"`
+	Title       string    `json:"title"`
+	ImageURL    string    `json:"image_url"`
+	Price       float64   `json:"price"`
+	Status      string    `json:"status"`
+	DuplicateOf int       `json:"duplicate_of"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type PendingAdResponse struct {
+	ID          int     `json:"id"`
+	UserID      int     `json:"user_id"`
+	AuthorLogin string  `json:"author_login"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	ImageURL    string  `json:"image_url"`
+	Price       float64 `json:"price"`
+	Status      string  `json:"status"`
+	// ModerationNote — причины, по которым фильтр отправил объявление на модерацию.
+	ModerationNote string    `json:"moderation_note"`
+	CreatedAt      time.Time `json:"created_at"`
+}
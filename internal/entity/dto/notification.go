@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type NotificationResponse struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data" swaggertype:"object"`
+	Read      bool            `json:"read"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type NotificationPreference struct {
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+	// Configurable = false у уведомлений о безопасности: их нельзя отключить.
+	Configurable bool `json:"configurable"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	// Preferences — тип уведомления и признак, получать ли его.
+	Preferences map[string]bool `json:"preferences"`
+}
+
+// Данные уведомлений по типам.
+
+type AdPublishedNotification struct {
+	AdvertisementID int    `json:"advertisement_id"`
+	Title           string `json:"title"`
+}
+
+// AdExpiryNotification — предупреждение о скором истечении или архивировании объявления.
+type AdExpiryNotification struct {
+	AdvertisementID int       `json:"advertisement_id"`
+	Title           string    `json:"title"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	RenewalsLeft    int       `json:"renewals_left"`
+}
+
+type NewMessageNotification struct {
+	ConversationID int    `json:"conversation_id"`
+	SenderID       int    `json:"sender_id"`
+	Preview        string `json:"preview"`
+}
+
+type ReviewNotification struct {
+	ReviewID int `json:"review_id"`
+	Rating   int `json:"rating"`
+}
+
+type NewLoginNotification struct {
+	IP string `json:"ip"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+type OfferRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+type OfferResponse struct {
+	ID                 int     `json:"id"`
+	AdvertisementID    int     `json:"advertisement_id"`
+	AdvertisementTitle string  `json:"advertisement_title"`
+	BuyerID            int     `json:"buyer_id"`
+	SellerID           int     `json:"seller_id"`
+	ParentID           *int    `json:"parent_id"`
+	ProposedBy         int     `json:"proposed_by"`
+	Role               string  `json:"role"`
+	Amount             float64 `json:"amount"`
+	Status             string  `json:"status"`
+	// CanRespond — предложение ждет ответа именно от запросившего пользователя.
+	CanRespond bool `json:"can_respond"`
+	// CanWithdraw — предложение принято, и участник может отказаться от него, пока нет заказа.
+	CanWithdraw bool `json:"can_withdraw"`
+	// ExpiresAt — срок ответа, а у принятого предложения — срок оформления заказа.
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RespondedAt *time.Time `json:"responded_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// OfferNotification — данные уведомлений new_offer и offer_answered.
+type OfferNotification struct {
+	OfferID         int     `json:"offer_id"`
+	AdvertisementID int     `json:"advertisement_id"`
+	Title           string  `json:"title"`
+	Amount          float64 `json:"amount"`
+	Status          string  `json:"status"`
+}
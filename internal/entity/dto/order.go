@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+type OrderResponse struct {
+	ID                 int     `json:"id"`
+	AdvertisementID    *int    `json:"advertisement_id"`
+	AdvertisementTitle string  `json:"advertisement_title"`
+	BuyerID            int     `json:"buyer_id"`
+	SellerID           int     `json:"seller_id"`
+	OfferID            *int    `json:"offer_id"`
+	Role               string  `json:"role"`
+	Amount             float64 `json:"amount"`
+	Status             string  `json:"status"`
+	// AllowedActions — действия, доступные запросившему пользователю: confirm, ship, complete, dispute, cancel.
+	AllowedActions []string             `json:"allowed_actions"`
+	History        []OrderEventResponse `json:"history,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+type OrderEventResponse struct {
+	Status    string    `json:"status"`
+	ActorID   *int      `json:"actor_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrderNotification — данные уведомления order_updated.
+type OrderNotification struct {
+	OrderID         int    `json:"order_id"`
+	AdvertisementID *int   `json:"advertisement_id"`
+	Title           string `json:"title"`
+	Status          string `json:"status"`
+}
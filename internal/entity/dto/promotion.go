@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+type PromotionRequest struct {
+	// Tier — уровень продвижения: basic, premium, top.
+	Tier string `json:"tier"`
+	// StartsAt по умолчанию — текущий момент.
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   time.Time  `json:"ends_at"`
+}
+
+type PromotionResponse struct {
+	ID              int       `json:"id"`
+	AdvertisementID int       `json:"advertisement_id"`
+	Tier            string    `json:"tier"`
+	StartsAt        time.Time `json:"starts_at"`
+	EndsAt          time.Time `json:"ends_at"`
+	Status          string    `json:"status"`
+	// Running — продвижение поднимает объявление в выдаче прямо сейчас.
+	Running   bool      `json:"running"`
+	CreatedAt time.Time `json:"created_at"`
+}
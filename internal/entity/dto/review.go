@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type CreateReviewRequest struct {
+	Rating int    `json:"rating"`
+	Text   string `json:"text"`
+}
+
+type ReviewReplyRequest struct {
+	Reply string `json:"reply"`
+}
+
+type ReviewResponse struct {
+	ID              int        `json:"id"`
+	AdvertisementID *int       `json:"advertisement_id"`
+	SellerID        int        `json:"seller_id"`
+	AuthorID        int        `json:"author_id"`
+	AuthorLogin     string     `json:"author_login"`
+	Rating          int        `json:"rating"`
+	Text            string     `json:"text"`
+	Reply           string     `json:"reply,omitempty"`
+	RepliedAt       *time.Time `json:"replied_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
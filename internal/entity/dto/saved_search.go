@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+type SavedSearchRequest struct {
+	Name string `json:"name"`
+	// Фильтры и сортировка совпадают с параметрами GET /ad/all.
+	MinPrice *float64 `json:"min_price"`
+	MaxPrice *float64 `json:"max_price"`
+	Sort     string   `json:"sort"`
+	Order    string   `json:"order"`
+	// AlertsEnabled по умолчанию true.
+	AlertsEnabled *bool `json:"alerts_enabled"`
+}
+
+type SavedSearchResponse struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	MinPrice      *float64 `json:"min_price"`
+	MaxPrice      *float64 `json:"max_price"`
+	Sort          string   `json:"sort"`
+	Order         string   `json:"order"`
+	AlertsEnabled bool     `json:"alerts_enabled"`
+	// Query — параметры для GET /ad/all, повторяющие поиск.
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SavedSearchDigestNotification — данные уведомления saved_search_digest.
+type SavedSearchDigestNotification struct {
+	Total    int                     `json:"total"`
+	Searches []SavedSearchDigestItem `json:"searches"`
+}
+
+type SavedSearchDigestItem struct {
+	SearchID int    `json:"search_id"`
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	// AdvertisementIDs — первые из новых объявлений, не больше savedSearches.digestMaxAds.
+	AdvertisementIDs []int `json:"advertisement_ids"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+type TOTPEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type TOTPDisableRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// RecoveryCodesResponse возвращает коды восстановления в открытом виде. Показываются один раз.
+type RecoveryCodesResponse struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// LoginTwoFactorRequest — второй шаг входа: токен из ответа /user/login и код TOTP
+// либо один из кодов восстановления.
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
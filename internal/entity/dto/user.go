@@ -3,10 +3,43 @@ package dto
 import "time"
 
 type UserProfileResponse struct {
-	ID        int       `json:"id"`
-	Login     string    `json:"login"`
-	Name      string    `json:"first_name"`
-	Surname   string    `json:"last_name"`
+	ID            int     `json:"id"`
+	Login         string  `json:"login"`
+	Name          string  `json:"first_name"`
+	Surname       string  `json:"last_name"`
+	Email         string  `json:"email,omitempty"`
+	EmailVerified bool    `json:"email_verified"`
+	Phone         string  `json:"phone,omitempty"`
+	PhoneVerified bool    `json:"phone_verified"`
+	Verified      bool    `json:"verified"`
+	Bio           string  `json:"bio"`
+	City          string  `json:"city"`
+	AvatarURL     string  `json:"avatar_url"`
+	Rating        float64 `json:"rating"`
+	ReviewsCount  int     `json:"reviews_count"`
+	// Language виден только владельцу профиля.
+	Language  string    `json:"language,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+type UpdateProfileRequest struct {
+	Name      *string `json:"first_name,omitempty"`
+	Surname   *string `json:"last_name,omitempty"`
+	Bio       *string `json:"bio,omitempty"`
+	City      *string `json:"city,omitempty"`
+	AvatarURL *string `json:"avatar_url,omitempty"`
+	// Language — язык сообщений: ru или en; пустая строка возвращает выбор по Accept-Language.
+	Language *string `json:"language,omitempty"`
+}
+
+// CurrentUserResponse — профиль текущего пользователя с данными, нужными клиенту при загрузке страницы.
+type CurrentUserResponse struct {
+	UserProfileResponse
+	Roles               []string   `json:"roles"`
+	UnreadNotifications int        `json:"unread_notifications"`
+	AuthMethod          string     `json:"auth_method"`
+	Scopes              []string   `json:"scopes,omitempty"`
+	SessionExpiresAt    *time.Time `json:"session_expires_at,omitempty"`
+	DeleteAfter         *time.Time `json:"delete_after,omitempty"`
+}
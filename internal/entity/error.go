@@ -11,6 +11,14 @@ var (
 	ErrInternal      = errors.New("internal server error")
 	ErrAlreadyExists = errors.New("already exists")
 	ErrNotFound      = errors.New("not found")
+
+	ErrTooManyRequests = errors.New("too many requests")
+	ErrConflict        = errors.New("conflict")
+
+	// ErrPreconditionFailed — версия из If-Match не совпала с текущей.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrPreconditionRequired — изменение без If-Match запрещено.
+	ErrPreconditionRequired = errors.New("precondition required")
 )
 
 const (
@@ -43,3 +51,8 @@ func (e Error) InternalErr() error {
 func (e Error) ClientErr() error {
 	return e.clientErr
 }
+
+// Unwrap позволяет errors.Is и errors.As видеть и клиентскую, и внутреннюю ошибку.
+func (e Error) Unwrap() []error {
+	return []error{e.clientErr, e.internalErr}
+}
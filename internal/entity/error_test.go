@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestError_Unwrap(t *testing.T) {
+	t.Parallel()
+
+	validationErr := &AdvValidationError{Fields: FieldErrors{"title": NewLocalizedMessage(MsgRequired, nil)}}
+	err := fmt.Errorf("сервис: %w", NewError(ErrBadRequest, fmt.Errorf("валидация: %w", validationErr)))
+
+	require.ErrorIs(t, err, ErrBadRequest)
+	require.False(t, errors.Is(err, ErrNotFound))
+
+	var appErr Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrBadRequest, appErr.ClientErr())
+
+	var gotValidation *AdvValidationError
+	require.ErrorAs(t, err, &gotValidation)
+	require.Equal(t, validationErr.Fields, gotValidation.Fields)
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type EventType string
+
+const (
+	// EventMessageNew — новое сообщение в переписке, данные: dto.MessageResponse.
+	EventMessageNew EventType = "message.new"
+	// EventMessageRead — собеседник дочитал переписку, данные: dto.MessageReadEvent.
+	EventMessageRead EventType = "message.read"
+	// EventNotificationNew — новое уведомление, данные: dto.NotificationResponse.
+	EventNotificationNew EventType = "notification.new"
+	// EventSessionExpired отправляется перед закрытием потока, если сессия завершена.
+	EventSessionExpired EventType = "session.expired"
+)
+
+// Event доставляется в поток событий пользователя UserID.
+type Event struct {
+	UserID int             `json:"user_id"`
+	Type   EventType       `json:"type"`
+	Data   json.RawMessage `json:"data"`
+}
+
+func NewEvent(userID int, eventType EventType, data any) (*Event, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, NewError(
+			ErrInternal,
+			fmt.Errorf("не удалось сериализовать событие %s: %w", eventType, err),
+		)
+	}
+
+	return &Event{UserID: userID, Type: eventType, Data: payload}, nil
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEvent(t *testing.T) {
+	t.Parallel()
+
+	event, err := NewEvent(7, EventMessageNew, map[string]any{"id": 42, "body": "Здравствуйте"})
+	require.NoError(t, err)
+	require.Equal(t, 7, event.UserID)
+	require.Equal(t, EventMessageNew, event.Type)
+	require.JSONEq(t, `{"id":42,"body":"Здравствуйте"}`, string(event.Data))
+
+	encoded, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	var decoded Event
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Equal(t, *event, decoded)
+
+	_, err = NewEvent(7, EventMessageNew, make(chan int))
+	require.Error(t, err)
+	require.Equal(t, ErrInternal, err.(Error).ClientErr())
+}
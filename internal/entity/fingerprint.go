@@ -0,0 +1,152 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"math/bits"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+const (
+	DuplicatesDefaultPage = 20
+	DuplicatesMaxPage     = 100
+	ModerationDefaultPage = 20
+	ModerationMaxPage     = 100
+	// SimilarAdsLimit — сколько похожих объявлений показывается модератору.
+	SimilarAdsLimit = 20
+)
+
+// adShingleSize — длина шингла в словах. Три слова устойчивы к перестановке
+// отдельных слов и при этом различают объявления с общей лексикой.
+const adShingleSize = 3
+
+// AdFingerprint — отпечаток содержимого объявления для поиска дубликатов.
+type AdFingerprint struct {
+	// TextHash — simhash шинглов нормализованных заголовка и описания:
+	// у похожих текстов отличается в немногих битах.
+	TextHash uint64
+	// ImageHash — sha256 нормализованного URL изображения.
+	ImageHash string
+}
+
+func NewAdFingerprint(title, description, imageURL string) AdFingerprint {
+	return AdFingerprint{
+		TextHash:  SimHash(NormalizeAdText(title + " " + description)),
+		ImageHash: imageURLHash(imageURL),
+	}
+}
+
+// Distance — число различающихся бит TextHash, от 0 (тексты совпадают) до 64.
+func (f AdFingerprint) Distance(other AdFingerprint) int {
+	return HammingDistance(f.TextHash, other.TextHash)
+}
+
+// NormalizeAdText приводит текст к нижнему регистру, заменяет ё на е, убирает
+// пунктуацию и лишние пробелы, чтобы мелкие правки не меняли отпечаток.
+func NormalizeAdText(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	space := true
+	for _, r := range strings.ToLower(s) {
+		if r == 'ё' {
+			r = 'е'
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		if !space {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Shingles разбивает нормализованный текст на пересекающиеся последовательности
+// по size слов. Текст короче size слов дает один шингл.
+func Shingles(text string, size int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+	if len(words) <= size {
+		return []string{strings.Join(words, " ")}
+	}
+
+	shingles := make([]string, 0, len(words)-size+1)
+	for i := 0; i+size <= len(words); i++ {
+		shingles = append(shingles, strings.Join(words[i:i+size], " "))
+	}
+	return shingles
+}
+
+// SimHash строит 64-битный simhash по шинглам нормализованного текста.
+func SimHash(text string) uint64 {
+	var weights [64]int
+	for _, shingle := range Shingles(text, adShingleSize) {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(shingle))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<i) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	var hash uint64
+	for i, w := range weights {
+		if w > 0 {
+			hash |= 1 << i
+		}
+	}
+	return hash
+}
+
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// imageURLHash хеширует URL без схемы, регистра хоста, query и фрагмента:
+// одна и та же картинка, загруженная через разные ссылки на CDN, дает один хеш.
+func imageURLHash(raw string) string {
+	normalized := strings.TrimSpace(raw)
+	if u, err := url.Parse(normalized); err == nil && u.Host != "" {
+		normalized = strings.ToLower(u.Host) + u.EscapedPath()
+	}
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// SimilarAd — объявление, похожее на проверяемое.
+type SimilarAd struct {
+	Advertisement
+	Distance  int
+	SameImage bool
+}
+
+// SimilarAdsFilter ограничивает поиск похожих объявлений.
+type SimilarAdsFilter struct {
+	// UserID ограничивает поиск объявлениями автора; 0 — все авторы.
+	UserID      int
+	ExcludeID   int
+	MaxDistance int
+	Limit       int
+}
+
+// IsNearDuplicate решает, считать ли похожее объявление дубликатом: тексты отличаются
+// не больше чем на maxDistance бит, а при той же картинке допуск вдвое больше.
+func (s *SimilarAd) IsNearDuplicate(maxDistance int) bool {
+	if s.SameImage {
+		return s.Distance <= 2*maxDistance
+	}
+	return s.Distance <= maxDistance
+}
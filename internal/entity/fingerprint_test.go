@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeAdText(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "продам ежик 100 руб", NormalizeAdText("  ПРОДАМ ёжик!!! — 100 руб. "))
+	require.Empty(t, NormalizeAdText("!!! ..."))
+}
+
+func TestShingles(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"a b c", "b c d"}, Shingles("a b c d", 3))
+	require.Equal(t, []string{"a b"}, Shingles("a b", 3))
+	require.Nil(t, Shingles("", 3))
+}
+
+func TestAdFingerprintDistance(t *testing.T) {
+	t.Parallel()
+
+	const description = "Продаю велосипед в отличном состоянии, пробег небольшой, торг уместен при осмотре"
+
+	original := NewAdFingerprint("Горный велосипед", description, "https://cdn.example.com/bike.jpg")
+	reposted := NewAdFingerprint("горный велосипед!", description+".", "https://CDN.example.com/bike.jpg?v=2")
+	edited := NewAdFingerprint("Горный велосипед", description+" и доставка по городу", "https://cdn.example.com/bike.jpg")
+	other := NewAdFingerprint("Диван угловой", "Почти новый диван, обивка без пятен, самовывоз из центра города", "https://cdn.example.com/sofa.jpg")
+
+	require.Zero(t, original.Distance(reposted))
+	require.Equal(t, original.ImageHash, reposted.ImageHash)
+	require.Less(t, original.Distance(edited), original.Distance(other))
+	require.NotEqual(t, original.ImageHash, other.ImageHash)
+}
+
+func TestSimilarAdIsNearDuplicate(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, (&SimilarAd{Distance: 3}).IsNearDuplicate(3))
+	require.False(t, (&SimilarAd{Distance: 5}).IsNearDuplicate(3))
+	require.True(t, (&SimilarAd{Distance: 5, SameImage: true}).IsNearDuplicate(3))
+	require.False(t, (&SimilarAd{Distance: 7, SameImage: true}).IsNearDuplicate(3))
+}
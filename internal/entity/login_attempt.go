@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// LockoutError описывает временную блокировку входа после серии неудачных попыток
+// или сброса пароля после серии запросов.
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e LockoutError) Error() string {
+	return fmt.Sprintf("слишком много попыток, повторите через %d с", RetryAfterSeconds(e.RetryAfter))
+}
+
+// RetryAfterSeconds округляет длительность вверх до целых секунд для заголовка Retry-After.
+func RetryAfterSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// LoginLockoutDuration возвращает длительность блокировки после failures неудачных попыток.
+// До порога maxAttempts блокировки нет, далее каждая попытка удваивает ее вплоть до maxLockout.
+func LoginLockoutDuration(failures, maxAttempts int, base, maxLockout time.Duration) time.Duration {
+	if failures < maxAttempts {
+		return 0
+	}
+
+	lockout := base
+	for i := maxAttempts; i < failures; i++ {
+		lockout *= 2
+		if lockout >= maxLockout {
+			return maxLockout
+		}
+	}
+	return min(lockout, maxLockout)
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginLockoutDuration(t *testing.T) {
+	t.Parallel()
+
+	base := 30 * time.Second
+	maxLockout := time.Hour
+
+	testCases := []struct {
+		name     string
+		failures int
+		expected time.Duration
+	}{
+		{name: "До порога", failures: 4, expected: 0},
+		{name: "Порог", failures: 5, expected: 30 * time.Second},
+		{name: "Удвоение", failures: 6, expected: time.Minute},
+		{name: "Третья блокировка", failures: 7, expected: 2 * time.Minute},
+		{name: "Ограничение сверху", failures: 100, expected: time.Hour},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, LoginLockoutDuration(tc.failures, 5, base, maxLockout))
+		})
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 1, RetryAfterSeconds(100*time.Millisecond))
+	require.Equal(t, 30, RetryAfterSeconds(30*time.Second))
+}
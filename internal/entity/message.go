@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/i18n"
+)
+
+// Коды сообщений для клиента. Тексты на всех языках лежат в каталогах pkg/i18n/locales,
+// в логи попадают только коды с параметрами.
+const (
+	MsgLengthBetween   = "validation.length_between"
+	MsgMaxLength       = "validation.max_length"
+	MsgRequired        = "validation.required"
+	MsgInvalid         = "validation.invalid"
+	MsgOneOf           = "validation.one_of"
+	MsgPositive        = "validation.positive"
+	MsgNotLessThan     = "validation.not_less_than"
+	MsgPriceMin        = "validation.price_min"
+	MsgPriceMax        = "validation.price_max"
+	MsgAmountRange     = "validation.amount_range"
+	MsgRatingBetween   = "validation.rating_between"
+	MsgURLRequired     = "validation.url_required"
+	MsgURLTooLong      = "validation.url_too_long"
+	MsgURLInvalid      = "validation.url_invalid"
+	MsgImageFormat     = "validation.image_format"
+	MsgStartsInPast    = "validation.starts_in_past"
+	MsgEndsBeforeStart = "validation.ends_before_start"
+	MsgDurationMax     = "validation.duration_max"
+
+	MsgPasswordTooShort = "password.too_short"
+	MsgPasswordTooLong  = "password.too_long"
+	MsgPasswordCharset  = "password.charset"
+	MsgLoginInvalid     = "login.invalid"
+	MsgLoginTooLong     = "login.too_long"
+
+	MsgAPIKeyNameEmpty    = "api_key.name_empty"
+	MsgAPIKeyNameTooLong  = "api_key.name_too_long"
+	MsgAPIKeyScopesEmpty  = "api_key.scopes_empty"
+	MsgAPIKeyScopeUnknown = "api_key.scope_unknown"
+
+	MsgContactChannelUnknown = "contact.channel_unknown"
+	MsgEmailInvalid          = "contact.email_invalid"
+	MsgEmailTooLong          = "contact.email_too_long"
+	MsgPhoneInvalid          = "contact.phone_invalid"
+
+	MsgAdRenewNotOwner = "ad.renew_not_owner"
+	MsgAdRenewSold     = "ad.renew_sold"
+	MsgAdRenewLimit    = "ad.renew_limit"
+
+	MsgAdNotOwner        = "ad.not_owner"
+	MsgAdLocked          = "ad.locked"
+	MsgAdVersionMismatch = "ad.version_mismatch"
+
+	MsgOfferNotFound    = "offer.not_found"
+	MsgOfferOwn         = "offer.own"
+	MsgOfferClosed      = "offer.closed"
+	MsgOfferExpired     = "offer.expired"
+	MsgOfferNotAccepted = "offer.not_accepted"
+
+	MsgOrderNotFound            = "order.not_found"
+	MsgOrderTransitionInvalid   = "order.transition_invalid"
+	MsgOrderTransitionForbidden = "order.transition_forbidden"
+
+	// MsgContentRule дополняется именем правила фильтра: content.rule.drugs.
+	// Для правил без своего перевода показывается общий текст с причиной из конфигурации.
+	MsgContentRule = "content.rule"
+)
+
+// MessageParams — значения для подстановки в текст сообщения.
+type MessageParams map[string]any
+
+// LocalizedMessage — сообщение для клиента: стабильный код и параметры.
+// Текст на нужном языке собирается только при ответе клиенту.
+type LocalizedMessage struct {
+	Code   string
+	Params MessageParams
+}
+
+func NewLocalizedMessage(code string, params MessageParams) LocalizedMessage {
+	return LocalizedMessage{Code: code, Params: params}
+}
+
+// Error возвращает код с параметрами, например validation.length_between max=50 min=3.
+// Так сообщение попадает в логи независимо от языка клиента.
+func (m LocalizedMessage) Error() string {
+	if len(m.Params) == 0 {
+		return m.Code
+	}
+
+	keys := make([]string, 0, len(m.Params))
+	for k := range m.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(m.Code)
+	for _, k := range keys {
+		b.WriteString(" ")
+		b.WriteString(k)
+		b.WriteString("=")
+		b.WriteString(i18n.FormatParam(m.Params[k]))
+	}
+	return b.String()
+}
+
+// Translate возвращает текст сообщения на языке lang.
+func (m LocalizedMessage) Translate(lang i18n.Lang) string {
+	return i18n.Translate(lang, m.Code, m.Params)
+}
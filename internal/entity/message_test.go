@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/i18n"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalizedMessage_Error(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, MsgRequired, NewLocalizedMessage(MsgRequired, nil).Error())
+	require.Equal(t, "validation.length_between max=50 min=3",
+		NewLocalizedMessage(MsgLengthBetween, MessageParams{"min": 3, "max": 50}).Error())
+	require.Equal(t, "validation.price_min min=0.01",
+		NewLocalizedMessage(MsgPriceMin, MessageParams{"min": 0.01}).Error())
+}
+
+// Каждый код, который entity отдает клиенту, должен быть переведен.
+func TestLocalizedMessage_CodesTranslated(t *testing.T) {
+	t.Parallel()
+
+	codes := []string{
+		MsgLengthBetween, MsgMaxLength, MsgRequired, MsgInvalid, MsgOneOf, MsgPositive, MsgNotLessThan,
+		MsgPriceMin, MsgPriceMax, MsgAmountRange, MsgRatingBetween, MsgURLRequired, MsgURLTooLong,
+		MsgURLInvalid, MsgImageFormat, MsgStartsInPast, MsgEndsBeforeStart, MsgDurationMax,
+		MsgPasswordTooShort, MsgPasswordTooLong, MsgPasswordCharset, MsgLoginInvalid, MsgLoginTooLong,
+		MsgAPIKeyNameEmpty, MsgAPIKeyNameTooLong, MsgAPIKeyScopesEmpty, MsgAPIKeyScopeUnknown,
+		MsgContactChannelUnknown, MsgEmailInvalid, MsgEmailTooLong, MsgPhoneInvalid,
+		MsgAdRenewNotOwner, MsgAdRenewSold, MsgAdRenewLimit, MsgAdNotOwner, MsgAdLocked, MsgAdVersionMismatch,
+		MsgOfferNotFound, MsgOfferOwn, MsgOfferClosed, MsgOfferExpired, MsgOfferNotAccepted,
+		MsgOrderNotFound, MsgOrderTransitionInvalid, MsgOrderTransitionForbidden,
+		MsgContentRule,
+	}
+
+	for _, code := range codes {
+		for _, lang := range i18n.Supported {
+			require.NotEqual(t, code, i18n.Translate(lang, code, nil), "нет перевода %s на %s", code, lang)
+		}
+	}
+}
+
+func TestValidatePassword_Message(t *testing.T) {
+	t.Parallel()
+
+	var m LocalizedMessage
+	require.ErrorAs(t, ValidatePassword("short"), &m)
+	require.Equal(t, MsgPasswordTooShort, m.Code)
+	require.Equal(t, "Password must be at least 8 characters long", m.Translate(i18n.English))
+}
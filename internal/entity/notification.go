@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	NotificationsDefaultPage = 20
+	NotificationsMaxPage     = 100
+)
+
+type NotificationType string
+
+const (
+	NotificationAdPublished     NotificationType = "ad_published"
+	NotificationAdExpiring      NotificationType = "ad_expiring"
+	NotificationAdArchived      NotificationType = "ad_archived"
+	NotificationAdRejected      NotificationType = "ad_rejected"
+	NotificationNewMessage      NotificationType = "new_message"
+	NotificationNewOffer        NotificationType = "new_offer"
+	NotificationOfferAnswered   NotificationType = "offer_answered"
+	NotificationOrderUpdated    NotificationType = "order_updated"
+	NotificationNewReview       NotificationType = "new_review"
+	NotificationReviewReply     NotificationType = "review_reply"
+	NotificationSavedSearch     NotificationType = "saved_search_digest"
+	NotificationNewLogin        NotificationType = "new_login"
+	NotificationPasswordChanged NotificationType = "password_changed"
+)
+
+// NotificationTypes перечисляет все типы в порядке вывода настроек.
+var NotificationTypes = []NotificationType{
+	NotificationAdPublished,
+	NotificationAdExpiring,
+	NotificationAdArchived,
+	NotificationAdRejected,
+	NotificationNewMessage,
+	NotificationNewOffer,
+	NotificationOfferAnswered,
+	NotificationOrderUpdated,
+	NotificationNewReview,
+	NotificationReviewReply,
+	NotificationSavedSearch,
+	NotificationNewLogin,
+	NotificationPasswordChanged,
+}
+
+func (t NotificationType) Valid() bool {
+	for _, known := range NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Configurable сообщает, можно ли отключить тип в настройках.
+// Уведомления о безопасности аккаунта доставляются всегда.
+func (t NotificationType) Configurable() bool {
+	return t != NotificationNewLogin && t != NotificationPasswordChanged
+}
+
+type Notification struct {
+	ID     int64
+	UserID int
+	Type   NotificationType
+	Data   json.RawMessage
+	// DedupKey склеивает однотипные события: пока уведомление с этим ключом
+	// не прочитано, новое не создается. Пустой ключ отключает склейку.
+	DedupKey  string
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
+
+func NewNotification(userID int, notificationType NotificationType, data any, dedupKey string) (*Notification, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, NewError(
+			ErrInternal,
+			fmt.Errorf("не удалось сериализовать уведомление %s: %w", notificationType, err),
+		)
+	}
+
+	return &Notification{
+		UserID:   userID,
+		Type:     notificationType,
+		Data:     payload,
+		DedupKey: dedupKey,
+	}, nil
+}
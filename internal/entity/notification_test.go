@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationType(t *testing.T) {
+	t.Parallel()
+
+	for _, notificationType := range NotificationTypes {
+		require.True(t, notificationType.Valid(), notificationType)
+	}
+	require.False(t, NotificationType("price_drop").Valid())
+	require.False(t, NotificationType("").Valid())
+
+	require.True(t, NotificationNewMessage.Configurable())
+	require.False(t, NotificationNewLogin.Configurable())
+	require.False(t, NotificationPasswordChanged.Configurable())
+}
+
+func TestNewNotification(t *testing.T) {
+	t.Parallel()
+
+	n, err := NewNotification(3, NotificationNewMessage, map[string]int{"conversation_id": 12}, "conversation:12")
+	require.NoError(t, err)
+	require.Equal(t, 3, n.UserID)
+	require.Equal(t, NotificationNewMessage, n.Type)
+	require.Equal(t, "conversation:12", n.DedupKey)
+	require.JSONEq(t, `{"conversation_id":12}`, string(n.Data))
+	require.Nil(t, n.ReadAt)
+
+	_, err = NewNotification(3, NotificationNewMessage, func() {}, "")
+	require.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	OffersDefaultPage = 20
+	OffersMaxPage     = 100
+)
+
+type OfferStatus string
+
+const (
+	OfferPending  OfferStatus = "pending"
+	OfferAccepted OfferStatus = "accepted"
+	OfferDeclined OfferStatus = "declined"
+	// OfferCountered — на предложение ответили встречным, торг продолжается в новом предложении.
+	OfferCountered OfferStatus = "countered"
+	// OfferExpired — предложение осталось без ответа или по принятому предложению
+	// не оформили заказ за offers.acceptedTTL.
+	OfferExpired OfferStatus = "expired"
+	// OfferWithdrawn — участник отказался от принятого предложения до оформления заказа.
+	OfferWithdrawn OfferStatus = "withdrawn"
+)
+
+// Offer — предложение цены по объявлению. Первое предложение делает покупатель,
+// дальше стороны по очереди отвечают встречными предложениями.
+type Offer struct {
+	ID                 int
+	AdvertisementID    int
+	AdvertisementTitle string
+	BuyerID            int
+	SellerID           int
+	ParentID           *int
+	// ProposedBy — id участника, сделавшего предложение. Ответить на него может только второй участник.
+	ProposedBy  int
+	Amount      float64
+	Status      OfferStatus
+	ExpiresAt   time.Time
+	RespondedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (o *Offer) IsParticipant(userID int) bool {
+	return userID == o.BuyerID || userID == o.SellerID
+}
+
+// RecipientID — участник, от которого ждут ответа на предложение.
+func (o *Offer) RecipientID() int {
+	if o.ProposedBy == o.BuyerID {
+		return o.SellerID
+	}
+	return o.BuyerID
+}
+
+// CanRespond проверяет, что пользователь может принять, отклонить или перебить предложение.
+func (o *Offer) CanRespond(userID int, now time.Time) error {
+	if !o.IsParticipant(userID) {
+		return NewError(ErrNotFound, NewLocalizedMessage(MsgOfferNotFound, MessageParams{"id": o.ID}))
+	}
+	if o.RecipientID() != userID {
+		return NewError(ErrForbidden, NewLocalizedMessage(MsgOfferOwn, nil))
+	}
+	if o.Status != OfferPending {
+		return NewError(ErrConflict, NewLocalizedMessage(MsgOfferClosed, MessageParams{"status": o.Status}))
+	}
+	if !now.Before(o.ExpiresAt) {
+		return NewError(ErrConflict, NewLocalizedMessage(MsgOfferExpired, nil))
+	}
+	return nil
+}
+
+// CanWithdraw проверяет, что пользователь может отказаться от принятого предложения:
+// покупатель — передумав покупать, продавец — сняв резерв с объявления.
+func (o *Offer) CanWithdraw(userID int) error {
+	if !o.IsParticipant(userID) {
+		return NewError(ErrNotFound, NewLocalizedMessage(MsgOfferNotFound, MessageParams{"id": o.ID}))
+	}
+	if o.Status != OfferAccepted {
+		return NewError(ErrConflict, NewLocalizedMessage(MsgOfferNotAccepted, MessageParams{"status": o.Status}))
+	}
+	return nil
+}
+
+// ValidateOfferAmount проверяет сумму предложения.
+func ValidateOfferAmount(amount float64) error {
+	if amount <= AdPriceMin || amount > AdPriceMax {
+		return NewError(ErrBadRequest, &AdvValidationError{Fields: FieldErrors{
+			"amount": NewLocalizedMessage(MsgAmountRange, MessageParams{"min": AdPriceMin, "max": float64(AdPriceMax)}),
+		}})
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfferCanRespond(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	newOffer := func() *Offer {
+		return &Offer{
+			ID:         1,
+			BuyerID:    10,
+			SellerID:   20,
+			ProposedBy: 10,
+			Status:     OfferPending,
+			ExpiresAt:  now.Add(time.Hour),
+		}
+	}
+
+	require.NoError(t, newOffer().CanRespond(20, now))
+
+	err := newOffer().CanRespond(10, now)
+	require.Error(t, err)
+	require.Equal(t, ErrForbidden, err.(Error).ClientErr())
+
+	err = newOffer().CanRespond(30, now)
+	require.Error(t, err)
+	require.Equal(t, ErrNotFound, err.(Error).ClientErr())
+
+	declined := newOffer()
+	declined.Status = OfferDeclined
+	err = declined.CanRespond(20, now)
+	require.Error(t, err)
+	require.Equal(t, ErrConflict, err.(Error).ClientErr())
+
+	err = newOffer().CanRespond(20, now.Add(2*time.Hour))
+	require.Error(t, err)
+	require.Equal(t, ErrConflict, err.(Error).ClientErr())
+
+	counter := newOffer()
+	counter.ProposedBy = 20
+	require.Equal(t, 10, counter.RecipientID())
+	require.NoError(t, counter.CanRespond(10, now))
+}
+
+func TestOfferCanWithdraw(t *testing.T) {
+	t.Parallel()
+
+	accepted := &Offer{ID: 1, BuyerID: 10, SellerID: 20, ProposedBy: 10, Status: OfferAccepted}
+
+	require.NoError(t, accepted.CanWithdraw(10))
+	require.NoError(t, accepted.CanWithdraw(20))
+
+	err := accepted.CanWithdraw(30)
+	require.Error(t, err)
+	require.Equal(t, ErrNotFound, err.(Error).ClientErr())
+
+	pending := *accepted
+	pending.Status = OfferPending
+	err = pending.CanWithdraw(10)
+	require.Error(t, err)
+	require.Equal(t, ErrConflict, err.(Error).ClientErr())
+}
+
+func TestValidateOfferAmount(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateOfferAmount(1500))
+
+	for _, amount := range []float64{0, -10, AdPriceMax + 1} {
+		err := ValidateOfferAmount(amount)
+		require.Error(t, err, amount)
+		require.Equal(t, ErrBadRequest, err.(Error).ClientErr())
+	}
+}
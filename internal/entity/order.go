@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	OrdersDefaultPage = 20
+	OrdersMaxPage     = 100
+)
+
+type OrderStatus string
+
+const (
+	OrderCreated   OrderStatus = "created"
+	OrderConfirmed OrderStatus = "confirmed"
+	OrderShipped   OrderStatus = "shipped"
+	OrderCompleted OrderStatus = "completed"
+	OrderCancelled OrderStatus = "cancelled"
+	// OrderDisputed — покупатель оспорил отправленный заказ.
+	OrderDisputed OrderStatus = "disputed"
+)
+
+type OrderRole string
+
+const (
+	OrderRoleBuyer  OrderRole = "buyer"
+	OrderRoleSeller OrderRole = "seller"
+)
+
+// orderActions связывает действия из API со статусами, в которые они переводят заказ.
+// Порядок определяет порядок AllowedActions.
+var orderActions = []struct {
+	name string
+	to   OrderStatus
+}{
+	{"confirm", OrderConfirmed},
+	{"ship", OrderShipped},
+	{"complete", OrderCompleted},
+	{"dispute", OrderDisputed},
+	{"cancel", OrderCancelled},
+}
+
+// orderTransitions — из какого статуса в какой и кем может быть переведен заказ.
+var orderTransitions = map[OrderStatus]map[OrderStatus][]OrderRole{
+	OrderCreated: {
+		OrderConfirmed: {OrderRoleSeller},
+		OrderCancelled: {OrderRoleBuyer, OrderRoleSeller},
+	},
+	OrderConfirmed: {
+		OrderShipped:   {OrderRoleSeller},
+		OrderCancelled: {OrderRoleBuyer, OrderRoleSeller},
+	},
+	OrderShipped: {
+		OrderCompleted: {OrderRoleBuyer},
+		OrderDisputed:  {OrderRoleBuyer},
+	},
+	// Спор закрывает покупатель, подтвердив получение, или продавец, отменив заказ.
+	OrderDisputed: {
+		OrderCompleted: {OrderRoleBuyer},
+		OrderCancelled: {OrderRoleSeller},
+	},
+}
+
+// ParseOrderAction возвращает статус, в который переводит заказ действие из API.
+func ParseOrderAction(action string) (OrderStatus, bool) {
+	for _, a := range orderActions {
+		if a.name == action {
+			return a.to, true
+		}
+	}
+	return "", false
+}
+
+// Order — сделка покупателя с продавцом по объявлению.
+type Order struct {
+	ID                 int
+	AdvertisementID    *int
+	AdvertisementTitle string
+	BuyerID            int
+	SellerID           int
+	// OfferID — принятое предложение, по цене которого оформлен заказ.
+	OfferID   *int
+	Amount    float64
+	Status    OrderStatus
+	History   []OrderEvent
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// OrderEvent — запись истории заказа: кто и когда перевел его в статус.
+type OrderEvent struct {
+	Status    OrderStatus
+	ActorID   *int
+	CreatedAt time.Time
+}
+
+func (o *Order) Role(userID int) (OrderRole, bool) {
+	switch userID {
+	case o.BuyerID:
+		return OrderRoleBuyer, true
+	case o.SellerID:
+		return OrderRoleSeller, true
+	}
+	return "", false
+}
+
+// Counterpart — второй участник заказа.
+func (o *Order) Counterpart(userID int) int {
+	if userID == o.BuyerID {
+		return o.SellerID
+	}
+	return o.BuyerID
+}
+
+// CanTransition проверяет, что пользователь может перевести заказ в статус to.
+func (o *Order) CanTransition(userID int, to OrderStatus) error {
+	role, ok := o.Role(userID)
+	if !ok {
+		return NewError(ErrNotFound, NewLocalizedMessage(MsgOrderNotFound, MessageParams{"id": o.ID}))
+	}
+
+	roles, ok := orderTransitions[o.Status][to]
+	if !ok {
+		return NewError(ErrConflict, NewLocalizedMessage(MsgOrderTransitionInvalid, MessageParams{"from": o.Status, "to": to}))
+	}
+	for _, allowed := range roles {
+		if allowed == role {
+			return nil
+		}
+	}
+	return NewError(ErrForbidden, NewLocalizedMessage(MsgOrderTransitionForbidden, MessageParams{"role": role, "to": to}))
+}
+
+// AllowedActions перечисляет действия, доступные пользователю в текущем статусе заказа.
+func (o *Order) AllowedActions(userID int) []string {
+	actions := []string{}
+	for _, a := range orderActions {
+		if o.CanTransition(userID, a.to) == nil {
+			actions = append(actions, a.name)
+		}
+	}
+	return actions
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderCanTransition(t *testing.T) {
+	t.Parallel()
+
+	const buyerID, sellerID = 10, 20
+
+	tests := []struct {
+		name    string
+		from    OrderStatus
+		to      OrderStatus
+		userID  int
+		wantErr error
+	}{
+		{"seller confirms", OrderCreated, OrderConfirmed, sellerID, nil},
+		{"buyer cannot confirm", OrderCreated, OrderConfirmed, buyerID, ErrForbidden},
+		{"buyer cancels created", OrderCreated, OrderCancelled, buyerID, nil},
+		{"seller ships", OrderConfirmed, OrderShipped, sellerID, nil},
+		{"cannot ship unconfirmed", OrderCreated, OrderShipped, sellerID, ErrConflict},
+		{"buyer completes", OrderShipped, OrderCompleted, buyerID, nil},
+		{"seller cannot complete", OrderShipped, OrderCompleted, sellerID, ErrForbidden},
+		{"cannot cancel shipped", OrderShipped, OrderCancelled, buyerID, ErrConflict},
+		{"buyer disputes", OrderShipped, OrderDisputed, buyerID, nil},
+		{"seller cancels disputed", OrderDisputed, OrderCancelled, sellerID, nil},
+		{"completed is final", OrderCompleted, OrderCancelled, sellerID, ErrConflict},
+		{"stranger", OrderCreated, OrderCancelled, 30, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			order := &Order{ID: 1, BuyerID: buyerID, SellerID: sellerID, Status: tt.from}
+			err := order.CanTransition(tt.userID, tt.to)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, tt.wantErr, err.(Error).ClientErr())
+		})
+	}
+}
+
+func TestOrderAllowedActions(t *testing.T) {
+	t.Parallel()
+
+	order := &Order{ID: 1, BuyerID: 10, SellerID: 20, Status: OrderCreated}
+	require.Equal(t, []string{"confirm", "cancel"}, order.AllowedActions(20))
+	require.Equal(t, []string{"cancel"}, order.AllowedActions(10))
+
+	order.Status = OrderShipped
+	require.Equal(t, []string{"complete", "dispute"}, order.AllowedActions(10))
+	require.Empty(t, order.AllowedActions(20))
+
+	order.Status = OrderCompleted
+	require.Empty(t, order.AllowedActions(10))
+}
+
+func TestParseOrderAction(t *testing.T) {
+	t.Parallel()
+
+	status, ok := ParseOrderAction("ship")
+	require.True(t, ok)
+	require.Equal(t, OrderShipped, status)
+
+	_, ok = ParseOrderAction("refund")
+	require.False(t, ok)
+}
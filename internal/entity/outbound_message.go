@@ -0,0 +1,22 @@
+package entity
+
+// OutboundMessage — письмо или сообщение, которое сервис отправляет пользователю
+// через внешний канал (почта, SMS, локальный outbox).
+type OutboundMessage struct {
+	UserID  int            `json:"user_id"`
+	Channel ContactChannel `json:"channel,omitempty"`
+	To      string         `json:"to"`
+	Subject string         `json:"subject"`
+	Body    string         `json:"body"`
+}
+
+func NewOutboundMessage(user *User, subject, body string) *OutboundMessage {
+	channel, to := user.NotificationAddress()
+	return &OutboundMessage{
+		UserID:  user.ID,
+		Channel: channel,
+		To:      to,
+		Subject: subject,
+		Body:    body,
+	}
+}
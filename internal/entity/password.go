@@ -0,0 +1,203 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Текущие параметры argon2id. Хеши с другими параметрами продолжают проверяться
+// и пересчитываются при следующем успешном входе.
+const (
+	TimeCost        = 2
+	MemoryCost      = 64 * 1024
+	ParallelThreads = 2
+	HashLength      = 32
+	SaltLength      = 16
+)
+
+const passwordAlgorithm = "argon2id"
+
+var b64 = base64.RawStdEncoding
+
+// Pepper — серверный секрет, который подмешивается к паролю перед хешированием.
+// ID записывается в хеш (параметр keyid), чтобы секрет можно было сменить.
+type Pepper struct {
+	ID  string
+	Key []byte
+}
+
+func (p Pepper) enabled() bool {
+	return len(p.Key) > 0
+}
+
+type passwordParams struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	keyID   string
+}
+
+// HashPassword возвращает хеш в формате PHC:
+// $argon2id$v=19$m=65536,t=2,p=2[,keyid=...]$<соль>$<хеш>
+func HashPassword(password string, pepper Pepper) (string, error) {
+	salt := make([]byte, SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", NewError(
+			ErrInternal,
+			fmt.Errorf("ошибка при хешировании пароля"),
+		)
+	}
+
+	params := passwordParams{memory: MemoryCost, time: TimeCost, threads: ParallelThreads}
+	if pepper.enabled() {
+		params.keyID = pepper.ID
+	}
+
+	hash := argon2.IDKey(pepperPassword(password, pepper), salt, params.time, params.memory, params.threads, HashLength)
+
+	encodedParams := fmt.Sprintf("m=%d,t=%d,p=%d", params.memory, params.time, params.threads)
+	if params.keyID != "" {
+		encodedParams += ",keyid=" + b64.EncodeToString([]byte(params.keyID))
+	}
+
+	return fmt.Sprintf("$%s$v=%d$%s$%s$%s",
+		passwordAlgorithm,
+		argon2.Version,
+		encodedParams,
+		b64.EncodeToString(salt),
+		b64.EncodeToString(hash),
+	), nil
+}
+
+// CheckPassword сверяет пароль с хешем в формате PHC. needsRehash сообщает, что хеш
+// посчитан с устаревшими параметрами или другим pepper и его стоит пересчитать.
+func CheckPassword(password, encoded string, pepper Pepper) (ok bool, needsRehash bool) {
+	params, salt, hash, err := decodePasswordHash(encoded)
+	if err != nil {
+		return false, false
+	}
+
+	// Хеш посчитан с pepper, которого нет в конфигурации: проверить его невозможно.
+	if params.keyID != "" && (!pepper.enabled() || params.keyID != pepper.ID) {
+		return false, false
+	}
+	hashPepper := pepper
+	if params.keyID == "" {
+		hashPepper = Pepper{}
+	}
+
+	computed := argon2.IDKey(pepperPassword(password, hashPepper), salt, params.time, params.memory, params.threads, uint32(len(hash)))
+	if subtle.ConstantTimeCompare(computed, hash) != 1 {
+		return false, false
+	}
+
+	needsRehash = params.memory != MemoryCost ||
+		params.time != TimeCost ||
+		params.threads != ParallelThreads ||
+		len(hash) != HashLength ||
+		len(salt) != SaltLength ||
+		params.keyID != currentKeyID(pepper)
+
+	return true, needsRehash
+}
+
+var (
+	dummyOnce sync.Once
+	dummyHash string
+)
+
+// CheckDummyPassword выполняет ту же работу, что и CheckPassword, для несуществующего логина,
+// чтобы по времени ответа нельзя было определить, зарегистрирован ли пользователь.
+func CheckDummyPassword(password string, pepper Pepper) {
+	dummyOnce.Do(func() {
+		dummyHash, _ = HashPassword("dummy-password", pepper)
+	})
+	CheckPassword(password, dummyHash, pepper)
+}
+
+func currentKeyID(pepper Pepper) string {
+	if !pepper.enabled() {
+		return ""
+	}
+	return pepper.ID
+}
+
+func pepperPassword(password string, pepper Pepper) []byte {
+	if !pepper.enabled() {
+		return []byte(password)
+	}
+	mac := hmac.New(sha256.New, pepper.Key)
+	mac.Write([]byte(password))
+	return mac.Sum(nil)
+}
+
+func decodePasswordHash(encoded string) (passwordParams, []byte, []byte, error) {
+	var params passwordParams
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != passwordAlgorithm {
+		return params, nil, nil, fmt.Errorf("неподдерживаемый формат хеша пароля")
+	}
+
+	if parts[2] != "v="+strconv.Itoa(argon2.Version) {
+		return params, nil, nil, fmt.Errorf("неподдерживаемая версия argon2: %s", parts[2])
+	}
+
+	for _, param := range strings.Split(parts[3], ",") {
+		key, value, found := strings.Cut(param, "=")
+		if !found {
+			return params, nil, nil, fmt.Errorf("неверный параметр хеша пароля: %s", param)
+		}
+
+		switch key {
+		case "m", "t", "p":
+			bitSize := 32
+			if key == "p" {
+				bitSize = 8
+			}
+			n, err := strconv.ParseUint(value, 10, bitSize)
+			if err != nil || n == 0 {
+				return params, nil, nil, fmt.Errorf("неверный параметр хеша пароля: %s", param)
+			}
+			switch key {
+			case "m":
+				params.memory = uint32(n)
+			case "t":
+				params.time = uint32(n)
+			case "p":
+				params.threads = uint8(n)
+			}
+		case "keyid":
+			keyID, err := b64.DecodeString(value)
+			if err != nil {
+				return params, nil, nil, fmt.Errorf("неверный keyid хеша пароля")
+			}
+			params.keyID = string(keyID)
+		default:
+			return params, nil, nil, fmt.Errorf("неизвестный параметр хеша пароля: %s", key)
+		}
+	}
+	if params.memory == 0 || params.time == 0 || params.threads == 0 {
+		return params, nil, nil, fmt.Errorf("в хеше пароля не хватает параметров")
+	}
+
+	salt, err := b64.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return params, nil, nil, fmt.Errorf("неверная соль хеша пароля")
+	}
+	hash, err := b64.DecodeString(parts[5])
+	if err != nil || len(hash) == 0 {
+		return params, nil, nil, fmt.Errorf("неверное значение хеша пароля")
+	}
+
+	return params, salt, hash, nil
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const PasswordResetTokenBytes = 32
+
+// GeneratePasswordResetToken возвращает одноразовый токен сброса пароля
+// в открытом виде (уходит пользователю) и его хеш для хранения.
+func GeneratePasswordResetToken() (raw string, hash []byte, err error) {
+	secret := make([]byte, PasswordResetTokenBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return "", nil, NewError(
+			ErrInternal,
+			fmt.Errorf("ошибка при генерации токена сброса пароля: %w", err),
+		)
+	}
+
+	raw = base64.RawURLEncoding.EncodeToString(secret)
+	return raw, HashPasswordResetToken(raw), nil
+}
+
+func HashPasswordResetToken(raw string) []byte {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(raw)))
+	return sum[:]
+}
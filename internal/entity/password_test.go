@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+)
+
+func TestHashPassword_PHCFormat(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := HashPassword("password123", Pepper{})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=65536,t=2,p=2$"), encoded)
+
+	ok, needsRehash := CheckPassword("password123", encoded, Pepper{})
+	require.True(t, ok)
+	require.False(t, needsRehash)
+
+	ok, _ = CheckPassword("wrong-password", encoded, Pepper{})
+	require.False(t, ok)
+}
+
+func TestCheckPassword_OutdatedParams(t *testing.T) {
+	t.Parallel()
+
+	// Хеш в формате прежних колонок: 8 байт соли и параметры m=32768,t=1.
+	salt := []byte("12345678")
+	hash := argon2.IDKey([]byte("password123"), salt, 1, 32*1024, ParallelThreads, HashLength)
+	encoded := fmt.Sprintf("$argon2id$v=19$m=32768,t=1,p=2$%s$%s",
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	)
+
+	ok, needsRehash := CheckPassword("password123", encoded, Pepper{})
+	require.True(t, ok)
+	require.True(t, needsRehash)
+}
+
+func TestCheckPassword_Pepper(t *testing.T) {
+	t.Parallel()
+
+	pepper := Pepper{ID: "2024", Key: []byte("server-secret")}
+
+	plain, err := HashPassword("password123", Pepper{})
+	require.NoError(t, err)
+
+	// Старый хеш без pepper проверяется и помечается для пересчета.
+	ok, needsRehash := CheckPassword("password123", plain, pepper)
+	require.True(t, ok)
+	require.True(t, needsRehash)
+
+	peppered, err := HashPassword("password123", pepper)
+	require.NoError(t, err)
+	require.Contains(t, peppered, ",keyid=")
+
+	ok, needsRehash = CheckPassword("password123", peppered, pepper)
+	require.True(t, ok)
+	require.False(t, needsRehash)
+
+	ok, _ = CheckPassword("password123", peppered, Pepper{})
+	require.False(t, ok, "хеш с pepper нельзя проверить без секрета")
+
+	ok, _ = CheckPassword("password123", peppered, Pepper{ID: "2024", Key: []byte("other-secret")})
+	require.False(t, ok)
+}
+
+func TestCheckPassword_InvalidFormat(t *testing.T) {
+	t.Parallel()
+
+	testCases := []string{
+		"",
+		"plain",
+		"$bcrypt$v=19$m=65536,t=2,p=2$c2FsdA$aGFzaA",
+		"$argon2id$v=16$m=65536,t=2,p=2$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=65536,t=2$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=65536,t=2,p=2,x=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=65536,t=2,p=2$!!!$aGFzaA",
+	}
+
+	for _, encoded := range testCases {
+		ok, _ := CheckPassword("password123", encoded, Pepper{})
+		require.False(t, ok, encoded)
+	}
+}
+
+func TestGeneratePasswordResetToken(t *testing.T) {
+	t.Parallel()
+
+	raw, hash, err := GeneratePasswordResetToken()
+	require.NoError(t, err)
+	require.Len(t, hash, 32)
+	require.Equal(t, hash, HashPasswordResetToken(raw))
+	require.Equal(t, hash, HashPasswordResetToken(" "+raw+"\n"))
+
+	other, _, err := GeneratePasswordResetToken()
+	require.NoError(t, err)
+	require.NotEqual(t, raw, other)
+}
@@ -0,0 +1,37 @@
+package entity
+
+type AuthMethod string
+
+const (
+	AuthMethodSession AuthMethod = "session"
+	AuthMethodBearer  AuthMethod = "bearer"
+	AuthMethodAPIKey  AuthMethod = "api_key"
+)
+
+// Principal — аутентифицированный субъект запроса.
+type Principal struct {
+	UserID int
+	Method AuthMethod
+	// SessionID заполнен для входа по cookie или bearer-токену.
+	SessionID string
+	// Scopes ограничивают права только при входе по API-ключу.
+	Scopes []string
+}
+
+// HasScope проверяет право доступа. Сессия пользователя обладает всеми правами.
+func (p *Principal) HasScope(scope string) bool {
+	if p.Method != AuthMethodAPIKey {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSession сообщает, что субъект вошел с паролем, а не через API-ключ.
+func (p *Principal) IsSession() bool {
+	return p.Method == AuthMethodSession || p.Method == AuthMethodBearer
+}
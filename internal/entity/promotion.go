@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// PromotionTier — уровень продвижения. Чем выше приоритет, тем выше объявление в выдаче.
+type PromotionTier string
+
+const (
+	PromotionBasic   PromotionTier = "basic"
+	PromotionPremium PromotionTier = "premium"
+	PromotionTop     PromotionTier = "top"
+)
+
+var promotionPriorities = map[PromotionTier]int{
+	PromotionBasic:   1,
+	PromotionPremium: 2,
+	PromotionTop:     3,
+}
+
+func (t PromotionTier) Valid() bool {
+	_, ok := promotionPriorities[t]
+	return ok
+}
+
+func (t PromotionTier) Priority() int {
+	return promotionPriorities[t]
+}
+
+type PromotionStatus string
+
+const (
+	// PromotionActive — продвижение запланировано или идет: объявление поднимается в выдаче
+	// с StartsAt до EndsAt.
+	PromotionActive    PromotionStatus = "active"
+	PromotionExpired   PromotionStatus = "expired"
+	PromotionCancelled PromotionStatus = "cancelled"
+)
+
+type Promotion struct {
+	ID              int
+	AdvertisementID int
+	Tier            PromotionTier
+	StartsAt        time.Time
+	EndsAt          time.Time
+	Status          PromotionStatus
+	CreatedAt       time.Time
+}
+
+// IsRunning сообщает, поднимает ли продвижение объявление в момент now.
+func (p *Promotion) IsRunning(now time.Time) bool {
+	return p.Status == PromotionActive && !now.Before(p.StartsAt) && now.Before(p.EndsAt)
+}
+
+// Validate проверяет уровень и период продвижения: начало не в прошлом,
+// конец позже начала, длительность не больше maxDuration.
+func (p *Promotion) Validate(now time.Time, maxDuration time.Duration) error {
+	fe := FieldErrors{}
+
+	if !p.Tier.Valid() {
+		fe["tier"] = NewLocalizedMessage(MsgOneOf, MessageParams{"values": strings.Join([]string{string(PromotionBasic), string(PromotionPremium), string(PromotionTop)}, ", ")})
+	}
+	if p.StartsAt.Before(now.Add(-time.Minute)) {
+		fe["starts_at"] = NewLocalizedMessage(MsgStartsInPast, nil)
+	}
+	if !p.EndsAt.After(p.StartsAt) {
+		fe["ends_at"] = NewLocalizedMessage(MsgEndsBeforeStart, nil)
+	} else if p.EndsAt.Sub(p.StartsAt) > maxDuration {
+		fe["ends_at"] = NewLocalizedMessage(MsgDurationMax, MessageParams{"hours": int(maxDuration.Hours())})
+	}
+
+	if len(fe) > 0 {
+		return NewError(ErrBadRequest, &AdvValidationError{Fields: fe})
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromotionValidate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	const maxDuration = 7 * 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		promotion Promotion
+		field     string
+	}{
+		{"valid", Promotion{Tier: PromotionTop, StartsAt: now, EndsAt: now.Add(24 * time.Hour)}, ""},
+		{"unknown tier", Promotion{Tier: "gold", StartsAt: now, EndsAt: now.Add(time.Hour)}, "tier"},
+		{"starts in past", Promotion{Tier: PromotionBasic, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}, "starts_at"},
+		{"ends before start", Promotion{Tier: PromotionBasic, StartsAt: now, EndsAt: now}, "ends_at"},
+		{"too long", Promotion{Tier: PromotionBasic, StartsAt: now, EndsAt: now.Add(maxDuration + time.Hour)}, "ends_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.promotion.Validate(now, maxDuration)
+			if tt.field == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var validationErr *AdvValidationError
+			require.ErrorAs(t, err.(Error).InternalErr(), &validationErr)
+			require.Contains(t, validationErr.Fields, tt.field)
+		})
+	}
+}
+
+func TestPromotionIsRunning(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	p := Promotion{Status: PromotionActive, StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)}
+	require.False(t, p.IsRunning(now))
+	require.True(t, p.IsRunning(now.Add(90*time.Minute)))
+	require.False(t, p.IsRunning(now.Add(2*time.Hour)))
+
+	p.Status = PromotionCancelled
+	require.False(t, p.IsRunning(now.Add(90*time.Minute)))
+}
+
+func TestPromotionTierPriority(t *testing.T) {
+	t.Parallel()
+
+	require.Greater(t, PromotionTop.Priority(), PromotionPremium.Priority())
+	require.Greater(t, PromotionPremium.Priority(), PromotionBasic.Priority())
+	require.Zero(t, PromotionTier("gold").Priority())
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ReviewRatingMin    = 1
+	ReviewRatingMax    = 5
+	ReviewTextMaxLen   = 1000
+	ReviewReplyMinLen  = 1
+	ReviewReplyMaxLen  = 1000
+	ReviewsDefaultPage = 20
+	ReviewsMaxPage     = 100
+)
+
+type ReviewStatus string
+
+const (
+	ReviewPublished ReviewStatus = "published"
+	// ReviewHidden — отзыв скрыт модерацией и не учитывается в рейтинге продавца.
+	ReviewHidden ReviewStatus = "hidden"
+)
+
+type Review struct {
+	ID              int
+	AdvertisementID *int
+	SellerID        int
+	AuthorID        int
+	AuthorLogin     string
+	Rating          int
+	Text            string
+	Reply           string
+	RepliedAt       *time.Time
+	Status          ReviewStatus
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Validate проверяет оценку и текст отзыва. Текст должен быть уже очищен от разметки.
+func (r *Review) Validate() error {
+	fe := FieldErrors{}
+
+	if r.Rating < ReviewRatingMin || r.Rating > ReviewRatingMax {
+		fe["rating"] = NewLocalizedMessage(MsgRatingBetween, MessageParams{"min": ReviewRatingMin, "max": ReviewRatingMax})
+	}
+	if utf8.RuneCountInString(r.Text) > ReviewTextMaxLen {
+		fe["text"] = NewLocalizedMessage(MsgMaxLength, MessageParams{"max": ReviewTextMaxLen})
+	}
+
+	if len(fe) > 0 {
+		return NewError(ErrBadRequest, &AdvValidationError{Fields: fe})
+	}
+	return nil
+}
+
+// ValidateReviewReply проверяет ответ продавца на отзыв.
+func ValidateReviewReply(reply string) error {
+	l := utf8.RuneCountInString(strings.TrimSpace(reply))
+	if l < ReviewReplyMinLen || l > ReviewReplyMaxLen {
+		return NewError(ErrBadRequest, &AdvValidationError{Fields: FieldErrors{
+			"reply": NewLocalizedMessage(MsgLengthBetween, MessageParams{"min": ReviewReplyMinLen, "max": ReviewReplyMaxLen}),
+		}})
+	}
+	return nil
+}
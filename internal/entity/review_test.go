@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReview_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		review Review
+		field  string
+	}{
+		{name: "ok", review: Review{Rating: 5, Text: "Все отлично"}},
+		{name: "без текста", review: Review{Rating: 1}},
+		{name: "оценка ниже минимума", review: Review{Rating: 0}, field: "rating"},
+		{name: "оценка выше максимума", review: Review{Rating: 6}, field: "rating"},
+		{
+			name:   "длинный текст",
+			review: Review{Rating: 3, Text: strings.Repeat("я", ReviewTextMaxLen+1)},
+			field:  "text",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.review.Validate()
+			if tc.field == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *AdvValidationError
+			require.True(t, errors.As(err.(Error).InternalErr(), &validationErr))
+			require.Contains(t, validationErr.Fields, tc.field)
+		})
+	}
+}
+
+func TestValidateReviewReply(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateReviewReply("Спасибо за покупку!"))
+	require.Error(t, ValidateReviewReply("   "))
+	require.Error(t, ValidateReviewReply(strings.Repeat("a", ReviewReplyMaxLen+1)))
+}
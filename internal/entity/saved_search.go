@@ -0,0 +1,163 @@
+package entity
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	SavedSearchNameMinLen = 1
+	SavedSearchNameMaxLen = 100
+)
+
+// SearchCriteria повторяет параметры фильтрации GET /ad/all.
+type SearchCriteria struct {
+	MinPrice *float64
+	MaxPrice *float64
+	SortBy   string
+	Order    string
+}
+
+// Normalize подставляет сортировку по умолчанию так же, как GET /ad/all.
+func (c *SearchCriteria) Normalize() {
+	if c.SortBy == "" {
+		c.SortBy = "created_at"
+	}
+	if c.Order == "" {
+		c.Order = "desc"
+	}
+}
+
+// Query возвращает параметры запроса GET /ad/all, повторяющего сохраненный поиск.
+func (c *SearchCriteria) Query() string {
+	values := url.Values{}
+	values.Set("sort", c.SortBy)
+	values.Set("order", c.Order)
+	if c.MinPrice != nil {
+		values.Set("min_price", strconv.FormatFloat(*c.MinPrice, 'f', -1, 64))
+	}
+	if c.MaxPrice != nil {
+		values.Set("max_price", strconv.FormatFloat(*c.MaxPrice, 'f', -1, 64))
+	}
+	return values.Encode()
+}
+
+type SavedSearch struct {
+	ID            int
+	UserID        int
+	Name          string
+	Criteria      SearchCriteria
+	AlertsEnabled bool
+	// StartAdID — последнее объявление на момент сохранения поиска.
+	// Более старые объявления не попадают в оповещения.
+	StartAdID int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Validate проверяет название и фильтры. Название должно быть уже очищено от разметки.
+func (s *SavedSearch) Validate() error {
+	fe := FieldErrors{}
+
+	if l := utf8.RuneCountInString(strings.TrimSpace(s.Name)); l < SavedSearchNameMinLen || l > SavedSearchNameMaxLen {
+		fe["name"] = NewLocalizedMessage(MsgLengthBetween, MessageParams{"min": SavedSearchNameMinLen, "max": SavedSearchNameMaxLen})
+	}
+
+	c := s.Criteria
+	if c.SortBy != "created_at" && c.SortBy != "price" {
+		fe["sort"] = NewLocalizedMessage(MsgOneOf, MessageParams{"values": "created_at, price"})
+	}
+	if c.Order != "asc" && c.Order != "desc" {
+		fe["order"] = NewLocalizedMessage(MsgOneOf, MessageParams{"values": "asc, desc"})
+	}
+	if c.MinPrice != nil {
+		if m := validatePriceRange(*c.MinPrice); m != nil {
+			fe["min_price"] = *m
+		}
+	}
+	if c.MaxPrice != nil {
+		if m := validatePriceRange(*c.MaxPrice); m != nil {
+			fe["max_price"] = *m
+		}
+	}
+	if c.MinPrice != nil && c.MaxPrice != nil && *c.MinPrice > *c.MaxPrice {
+		fe["max_price"] = NewLocalizedMessage(MsgNotLessThan, MessageParams{"field": "min_price"})
+	}
+
+	if len(fe) > 0 {
+		return NewError(ErrBadRequest, &AdvValidationError{Fields: fe})
+	}
+	return nil
+}
+
+// SavedSearchMatch — новое объявление, подошедшее под сохраненный поиск и ожидающее дайджеста.
+type SavedSearchMatch struct {
+	SearchID        int
+	SearchName      string
+	UserID          int
+	AdvertisementID int
+	AdTitle         string
+	AdPrice         float64
+}
+
+type SearchDigestItem struct {
+	SearchID   int
+	SearchName string
+	// Count — все совпадения, Ads — не больше maxAdsPerSearch первых из них.
+	Count int
+	Ads   []SavedSearchMatch
+}
+
+// SearchDigest собирает совпадения одного пользователя в одно оповещение.
+type SearchDigest struct {
+	UserID int
+	Items  []SearchDigestItem
+}
+
+// TotalCount возвращает число новых объявлений по всем поискам дайджеста.
+func (d *SearchDigest) TotalCount() int {
+	total := 0
+	for _, item := range d.Items {
+		total += item.Count
+	}
+	return total
+}
+
+// BuildSearchDigests группирует совпадения по пользователям и поискам,
+// сохраняя порядок их первого появления.
+func BuildSearchDigests(matches []SavedSearchMatch, maxAdsPerSearch int) []SearchDigest {
+	var digests []SearchDigest
+	digestIdx := make(map[int]int)
+	itemIdx := make(map[int]int)
+
+	for _, match := range matches {
+		di, ok := digestIdx[match.UserID]
+		if !ok {
+			di = len(digests)
+			digestIdx[match.UserID] = di
+			digests = append(digests, SearchDigest{UserID: match.UserID})
+		}
+		digest := &digests[di]
+
+		ii, ok := itemIdx[match.SearchID]
+		if !ok {
+			ii = len(digest.Items)
+			itemIdx[match.SearchID] = ii
+			digest.Items = append(digest.Items, SearchDigestItem{
+				SearchID:   match.SearchID,
+				SearchName: match.SearchName,
+			})
+		}
+		item := &digest.Items[ii]
+
+		item.Count++
+		if len(item.Ads) < maxAdsPerSearch {
+			item.Ads = append(item.Ads, match)
+		}
+	}
+
+	return digests
+}
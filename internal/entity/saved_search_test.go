@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestSavedSearch_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		criteria SearchCriteria
+		search   string
+		field    string
+	}{
+		{name: "без фильтров", search: "Все подряд"},
+		{name: "диапазон цены", search: "Недорого", criteria: SearchCriteria{MinPrice: floatPtr(100), MaxPrice: floatPtr(500)}},
+		{name: "пустое название", search: "  ", field: "name"},
+		{name: "неизвестная сортировка", search: "Поиск", criteria: SearchCriteria{SortBy: "title"}, field: "sort"},
+		{name: "отрицательная цена", search: "Поиск", criteria: SearchCriteria{MinPrice: floatPtr(-1)}, field: "min_price"},
+		{
+			name:     "минимум больше максимума",
+			search:   "Поиск",
+			criteria: SearchCriteria{MinPrice: floatPtr(500), MaxPrice: floatPtr(100)},
+			field:    "max_price",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			search := SavedSearch{Name: tc.search, Criteria: tc.criteria}
+			search.Criteria.Normalize()
+
+			err := search.Validate()
+			if tc.field == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			var validationErr *AdvValidationError
+			require.True(t, errors.As(err.(Error).InternalErr(), &validationErr))
+			require.Contains(t, validationErr.Fields, tc.field)
+		})
+	}
+}
+
+func TestSearchCriteria_Query(t *testing.T) {
+	t.Parallel()
+
+	criteria := SearchCriteria{MinPrice: floatPtr(100), MaxPrice: floatPtr(200.5)}
+	criteria.Normalize()
+
+	require.Equal(t, "max_price=200.5&min_price=100&order=desc&sort=created_at", criteria.Query())
+}
+
+func TestBuildSearchDigests(t *testing.T) {
+	t.Parallel()
+
+	matches := []SavedSearchMatch{
+		{UserID: 1, SearchID: 10, SearchName: "Велосипеды", AdvertisementID: 100},
+		{UserID: 2, SearchID: 20, SearchName: "Диваны", AdvertisementID: 101},
+		{UserID: 1, SearchID: 10, SearchName: "Велосипеды", AdvertisementID: 102},
+		{UserID: 1, SearchID: 11, SearchName: "Самокаты", AdvertisementID: 103},
+		{UserID: 1, SearchID: 10, SearchName: "Велосипеды", AdvertisementID: 104},
+	}
+
+	digests := BuildSearchDigests(matches, 2)
+	require.Len(t, digests, 2)
+
+	require.Equal(t, 1, digests[0].UserID)
+	require.Equal(t, 4, digests[0].TotalCount())
+	require.Len(t, digests[0].Items, 2)
+	require.Equal(t, 10, digests[0].Items[0].SearchID)
+	require.Equal(t, 3, digests[0].Items[0].Count)
+	require.Len(t, digests[0].Items[0].Ads, 2)
+	require.Equal(t, 102, digests[0].Items[0].Ads[1].AdvertisementID)
+	require.Equal(t, 11, digests[0].Items[1].SearchID)
+
+	require.Equal(t, 2, digests[1].UserID)
+	require.Equal(t, 1, digests[1].TotalCount())
+
+	require.Empty(t, BuildSearchDigests(nil, 2))
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+const SessionDisplayLen = 8
+
+type Session struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// DisplayID — начало токена, по которому пользователь может отличить сессии,
+// не раскрывая сам токен.
+func (s *Session) DisplayID() string {
+	if len(s.Token) <= SessionDisplayLen {
+		return s.Token
+	}
+	return s.Token[:SessionDisplayLen]
+}
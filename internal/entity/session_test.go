@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionDisplayID(t *testing.T) {
+	t.Parallel()
+
+	s := Session{Token: "0123456789abcdef"}
+	require.Equal(t, "01234567", s.DisplayID())
+
+	short := Session{Token: "abc"}
+	require.Equal(t, "abc", short.DisplayID())
+}
@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Параметры TOTP по RFC 6238 (совместимы с Google Authenticator и аналогами).
+const (
+	TOTPDigits      = 6
+	TOTPPeriod      = 30
+	TOTPSkew        = 1
+	TOTPSecretBytes = 20
+
+	RecoveryCodesCount = 10
+	RecoveryCodeBytes  = 5
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TwoFactor struct {
+	UserID   int
+	Secret   string
+	Enabled  bool
+	LastStep int64
+}
+
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, TOTPSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", NewError(
+			ErrInternal,
+			fmt.Errorf("ошибка при генерации секрета TOTP: %w", err),
+		)
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TOTPURI формирует otpauth:// ссылку для QR-кода.
+func TOTPURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", TOTPDigits))
+	query.Set("period", fmt.Sprintf("%d", TOTPPeriod))
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// TOTPCode вычисляет код для временного шага step (HOTP из RFC 4226).
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", NewError(
+			ErrInternal,
+			fmt.Errorf("некорректный секрет TOTP: %w", err),
+		)
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < TOTPDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", TOTPDigits, value%mod), nil
+}
+
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / TOTPPeriod
+}
+
+// ValidateTOTP проверяет код с допуском в TOTPSkew шагов. Шаги не новее lastStep
+// отвергаются, чтобы один и тот же код нельзя было использовать повторно.
+func ValidateTOTP(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+
+	current := TOTPStep(now)
+	for step := current - TOTPSkew; step <= current+TOTPSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		expected, err := TOTPCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCodes возвращает одноразовые коды восстановления и их хеши для хранения.
+func GenerateRecoveryCodes() (codes []string, hashes [][]byte, err error) {
+	codes = make([]string, 0, RecoveryCodesCount)
+	hashes = make([][]byte, 0, RecoveryCodesCount)
+
+	for i := 0; i < RecoveryCodesCount; i++ {
+		buf := make([]byte, RecoveryCodeBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, NewError(
+				ErrInternal,
+				fmt.Errorf("ошибка при генерации кодов восстановления: %w", err),
+			)
+		}
+		raw := strings.ToLower(totpEncoding.EncodeToString(buf))
+		code := raw[:4] + "-" + raw[4:]
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+func HashRecoveryCode(code string) []byte {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(normalized))
+	return sum[:]
+}
@@ -1,14 +1,160 @@
 package entity
 
-import "time"
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/i18n"
+)
+
+const (
+	RoleUser           = "user"
+	RoleVerifiedSeller = "verified_seller"
+	RoleModerator      = "moderator"
+)
+
+const (
+	UserNameMinLen = 2
+	UserNameMaxLen = 30
+	UserBioMaxLen  = 500
+	UserCityMaxLen = 100
+)
 
 type User struct {
-	ID           int       `db:"id"`
-	Login        string    `db:"login" valid:"required,alphanum,length(3|50)"`
-	Name         string    `db:"first_name" valid:"required,utfletter,length(1|100)"`
-	Surname      string    `db:"last_name" valid:"required,utfletter,length(1|100)"`
-	PasswordHash []byte    `db:"-" valid:"-"`
-	PasswordSalt []byte    `db:"-" valid:"-"`
-	CreatedAt    time.Time `db:"created_at" valid:"-"`
-	UpdatedAt    time.Time `db:"updated_at" valid:"-"`
+	ID            int     `db:"id"`
+	Login         string  `db:"login" valid:"required,alphanum,length(3|50)"`
+	Name          string  `db:"first_name" valid:"required,utfletter,length(1|100)"`
+	Surname       string  `db:"last_name" valid:"required,utfletter,length(1|100)"`
+	Email         string  `db:"email" valid:"-"`
+	EmailVerified bool    `db:"email_verified" valid:"-"`
+	Phone         string  `db:"phone" valid:"-"`
+	PhoneVerified bool    `db:"phone_verified" valid:"-"`
+	Bio           string  `db:"bio" valid:"-"`
+	City          string  `db:"city" valid:"-"`
+	AvatarURL     string  `db:"avatar_url" valid:"-"`
+	Rating        float64 `db:"rating_avg" valid:"-"`
+	RatingCount   int     `db:"rating_count" valid:"-"`
+	IsModerator   bool    `db:"is_moderator" valid:"-"`
+	// Language — выбранный язык сообщений, пустая строка — не выбран.
+	Language     string     `db:"language" valid:"-"`
+	PasswordHash string     `db:"-" valid:"-"`
+	DeleteAfter  *time.Time `db:"delete_after" valid:"-"`
+	CreatedAt    time.Time  `db:"created_at" valid:"-"`
+	UpdatedAt    time.Time  `db:"updated_at" valid:"-"`
+}
+
+// IsVerified — признак проверенного продавца: подтвержден хотя бы один контакт.
+func (u *User) IsVerified() bool {
+	return u.EmailVerified || u.PhoneVerified
+}
+
+// Roles — роли пользователя для клиента. Отдельной ролевой модели нет,
+// роли выводятся из состояния аккаунта; модераторов назначают напрямую в базе.
+func (u *User) Roles() []string {
+	roles := []string{RoleUser}
+	if u.IsVerified() {
+		roles = append(roles, RoleVerifiedSeller)
+	}
+	if u.IsModerator {
+		roles = append(roles, RoleModerator)
+	}
+	return roles
+}
+
+// NotificationAddress выбирает, куда отправлять служебные сообщения:
+// подтвержденный email, затем подтвержденный телефон, иначе логин (локальный outbox).
+func (u *User) NotificationAddress() (ContactChannel, string) {
+	switch {
+	case u.EmailVerified:
+		return ContactEmail, u.Email
+	case u.PhoneVerified:
+		return ContactPhone, u.Phone
+	default:
+		return "", u.Login
+	}
+}
+
+// ProfileUpdate — частичное изменение профиля: nil означает «не менять»,
+// пустая строка у био, города, аватара и языка — очистить поле.
+type ProfileUpdate struct {
+	Name      *string
+	Surname   *string
+	Bio       *string
+	City      *string
+	AvatarURL *string
+	Language  *string
+}
+
+func (p *ProfileUpdate) IsEmpty() bool {
+	return p.Name == nil && p.Surname == nil && p.Bio == nil && p.City == nil && p.AvatarURL == nil &&
+		p.Language == nil
+}
+
+// Normalize обрезает пробелы и проверяет поля. Аватар проверяется по тем же правилам, что и изображения объявлений.
+func (p *ProfileUpdate) Normalize() error {
+	fe := FieldErrors{}
+
+	trim := func(v *string) {
+		if v != nil {
+			*v = strings.TrimSpace(*v)
+		}
+	}
+	trim(p.Name)
+	trim(p.Surname)
+	trim(p.Bio)
+	trim(p.City)
+	trim(p.AvatarURL)
+	trim(p.Language)
+
+	if m := validateUserName(p.Name); m != nil {
+		fe["first_name"] = *m
+	}
+	if m := validateUserName(p.Surname); m != nil {
+		fe["last_name"] = *m
+	}
+	if p.Bio != nil && utf8.RuneCountInString(*p.Bio) > UserBioMaxLen {
+		fe["bio"] = NewLocalizedMessage(MsgMaxLength, MessageParams{"max": UserBioMaxLen})
+	}
+	if p.City != nil && utf8.RuneCountInString(*p.City) > UserCityMaxLen {
+		fe["city"] = NewLocalizedMessage(MsgMaxLength, MessageParams{"max": UserCityMaxLen})
+	}
+	if p.AvatarURL != nil && *p.AvatarURL != "" {
+		if m := validateImageURLBasic(*p.AvatarURL); m != nil {
+			fe["avatar_url"] = *m
+		}
+	}
+
+	if p.Language != nil && *p.Language != "" {
+		if lang, ok := i18n.ParseLang(*p.Language); ok {
+			*p.Language = string(lang)
+		} else {
+			fe["language"] = NewLocalizedMessage(MsgOneOf, MessageParams{"values": supportedLanguages()})
+		}
+	}
+
+	if len(fe) > 0 {
+		return NewError(ErrBadRequest, &AdvValidationError{Fields: fe})
+	}
+	return nil
+}
+
+func supportedLanguages() string {
+	langs := make([]string, len(i18n.Supported))
+	for i, lang := range i18n.Supported {
+		langs[i] = string(lang)
+	}
+	return strings.Join(langs, ", ")
+}
+
+func validateUserName(name *string) *LocalizedMessage {
+	if name == nil {
+		return nil
+	}
+	l := utf8.RuneCountInString(*name)
+	if l < UserNameMinLen || l > UserNameMaxLen {
+		m := NewLocalizedMessage(MsgLengthBetween, MessageParams{"min": UserNameMinLen, "max": UserNameMaxLen})
+		return &m
+	}
+	return nil
 }
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestProfileUpdate_Normalize(t *testing.T) {
+	t.Parallel()
+
+	update := &ProfileUpdate{
+		Name:      strPtr("  Иван "),
+		City:      strPtr(" Москва "),
+		AvatarURL: strPtr(""),
+		Language:  strPtr(" en-GB "),
+	}
+	require.NoError(t, update.Normalize())
+	require.Equal(t, "Иван", *update.Name)
+	require.Equal(t, "Москва", *update.City)
+	require.Equal(t, "en", *update.Language)
+	require.Nil(t, update.Surname)
+
+	testCases := []struct {
+		name   string
+		update ProfileUpdate
+	}{
+		{name: "Короткое имя", update: ProfileUpdate{Name: strPtr("И")}},
+		{name: "Длинная фамилия", update: ProfileUpdate{Surname: strPtr(strings.Repeat("я", UserNameMaxLen+1))}},
+		{name: "Длинное био", update: ProfileUpdate{Bio: strPtr(strings.Repeat("a", UserBioMaxLen+1))}},
+		{name: "Аватар не изображение", update: ProfileUpdate{AvatarURL: strPtr("https://example.com/avatar.exe")}},
+		{name: "Аватар не url", update: ProfileUpdate{AvatarURL: strPtr("avatar.png")}},
+		{name: "Неподдерживаемый язык", update: ProfileUpdate{Language: strPtr("de")}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Error(t, tc.update.Normalize())
+		})
+	}
+}
+
+func TestUser_Roles(t *testing.T) {
+	t.Parallel()
+
+	u := User{}
+	require.Equal(t, []string{RoleUser}, u.Roles())
+
+	u.PhoneVerified = true
+	require.Equal(t, []string{RoleUser, RoleVerifiedSeller}, u.Roles())
+
+	u.IsModerator = true
+	require.Equal(t, []string{RoleUser, RoleVerifiedSeller, RoleModerator}, u.Roles())
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	httpUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
 	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
 	"github.com/sirupsen/logrus"
@@ -21,6 +22,20 @@ func (c *customResponseWriter) WriteHeader(statusCode int) {
 	}
 }
 
+// Write без явного WriteHeader отвечает статусом 200, как и http.ResponseWriter.
+func (c *customResponseWriter) Write(b []byte) (int, error) {
+	if c.statusCode == 0 {
+		c.WriteHeader(http.StatusOK)
+	}
+	return c.ResponseWriter.Write(b)
+}
+
+// Unwrap нужен http.ResponseController, чтобы потоковые ответы могли
+// сбрасывать буфер и продлевать дедлайн записи.
+func (c *customResponseWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
 func AccessLogMiddleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -45,11 +60,5 @@ func AccessLogMiddleware() func(http.Handler) http.Handler {
 }
 
 func getClientIP(r *http.Request) string {
-	if ip := r.Header.Get("X-Forwarded-For"); ip != "" {
-		return ip
-	}
-	if ip := r.Header.Get("X-Real-Ip"); ip != "" {
-		return ip
-	}
-	return r.RemoteAddr
+	return httpUtils.ClientIP(r)
 }
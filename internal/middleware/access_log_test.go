@@ -10,8 +10,8 @@ import (
 	"testing"
 	// "time"
 
-	"ResuMatch/internal/utils"
-	"ResuMatch/pkg/logger"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
@@ -69,7 +69,9 @@ func TestAccessLogMiddleware(t *testing.T) {
 		// Создаем тестовый запрос
 		req := httptest.NewRequest("GET", "/test", nil)
 		req.Header.Set("User-Agent", "test-agent")
-		req.Header.Set("X-Forwarded-For", "1.2.3.4")
+		req.RemoteAddr = "1.2.3.4:5678"
+		// Без доверенного прокси заголовок подставлен клиентом и в лог не попадает.
+		req.Header.Set("X-Forwarded-For", "6.6.6.6")
 
 		// Добавляем requestID в контекст через utils
 		ctx := utils.SetRequestID(req.Context(), "test-request-id")
@@ -93,7 +95,7 @@ func TestAccessLogMiddleware(t *testing.T) {
 		require.Contains(t, logStr, "status=200")
 		require.Contains(t, logStr, "ip=1.2.3.4")
 		require.Contains(t, logStr, "ua=test-agent")
-		require.Contains(t, logStr, "requestID=test-request-id")
+		require.Contains(t, logStr, "[RID=test-request-id]")
 		require.Contains(t, logStr, "latency=")
 	})
 
@@ -134,14 +136,14 @@ func TestGetClientIP(t *testing.T) {
 		expectedResult string
 	}{
 		{
-			name:           "X-Forwarded-For",
+			name:           "X-Forwarded-For without trusted proxy",
 			headers:        map[string]string{"X-Forwarded-For": "1.2.3.4"},
-			expectedResult: "1.2.3.4",
+			expectedResult: "192.0.2.1",
 		},
 		{
-			name:           "X-Real-Ip",
+			name:           "X-Real-Ip without trusted proxy",
 			headers:        map[string]string{"X-Real-Ip": "5.6.7.8"},
-			expectedResult: "5.6.7.8",
+			expectedResult: "192.0.2.1",
 		},
 		{
 			name:           "RemoteAddr with port",
@@ -154,10 +156,10 @@ func TestGetClientIP(t *testing.T) {
 			expectedResult: "9.10.11.12",
 		},
 		{
-			name:           "Prefer X-Forwarded-For over others",
+			name:           "Prefer RemoteAddr over headers",
 			headers:        map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Real-Ip": "5.6.7.8"},
 			remoteAddr:     "9.10.11.12:1234",
-			expectedResult: "1.2.3.4",
+			expectedResult: "9.10.11.12",
 		},
 	}
 
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const bearerPrefix = "Bearer "
+
+// AuthMiddleware один раз определяет субъект запроса по API-ключу, bearer-токену
+// или сессионной cookie и кладет его в контекст. Сам запрос не отклоняется:
+// это делают обертки RequireAuth, RequireSession и OptionalAuth.
+func AuthMiddleware(auth usecase.AuthUsecase) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			principal, err := resolvePrincipal(r, auth)
+			switch {
+			case err != nil:
+				l.Log.WithFields(logrus.Fields{
+					"requestID": GlobalUtils.GetRequestID(ctx),
+					"error":     err,
+				}).Info("Не удалось аутентифицировать запрос")
+				ctx = GlobalUtils.SetAuthError(ctx, err)
+			case principal != nil:
+				ctx = GlobalUtils.SetPrincipal(ctx, principal)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// resolvePrincipal возвращает ошибку только для явно переданных учетных данных.
+// Просроченная cookie не мешает открывать публичные страницы.
+func resolvePrincipal(r *http.Request, auth usecase.AuthUsecase) (*entity.Principal, error) {
+	ctx := r.Context()
+
+	if key := r.Header.Get(utils.APIKeyHeader); key != "" {
+		return auth.GetPrincipalByAPIKey(ctx, key)
+	}
+
+	if header := r.Header.Get("Authorization"); header != "" {
+		if !strings.HasPrefix(header, bearerPrefix) {
+			return nil, entity.NewError(entity.ErrUnauthorized, fmt.Errorf("неподдерживаемая схема авторизации"))
+		}
+		token := strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix))
+		userID, err := auth.GetUserIDBySession(ctx, token)
+		if err != nil {
+			return nil, entity.NewError(entity.ErrUnauthorized, fmt.Errorf("недействительный токен: %w", err))
+		}
+		return &entity.Principal{UserID: userID, Method: entity.AuthMethodBearer, SessionID: token}, nil
+	}
+
+	cookie, err := r.Cookie("session_id")
+	if err != nil || cookie == nil || cookie.Value == "" {
+		return nil, nil
+	}
+	userID, err := auth.GetUserIDBySession(ctx, cookie.Value)
+	if err != nil {
+		return nil, nil
+	}
+	return &entity.Principal{UserID: userID, Method: entity.AuthMethodSession, SessionID: cookie.Value}, nil
+}
+
+// RequireAuth пропускает только аутентифицированные запросы, обладающие всеми правами scopes.
+func RequireAuth(scopes ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := GlobalUtils.GetPrincipal(r.Context())
+			if !ok {
+				utils.WriteError(w, r, http.StatusUnauthorized, entity.ErrUnauthorized)
+				return
+			}
+			if !checkScopes(w, r, principal, scopes) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession пропускает только пользователей, вошедших с паролем.
+// Используется для действий, недоступных по API-ключу.
+func RequireSession() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := GlobalUtils.GetPrincipal(r.Context())
+			if !ok {
+				utils.WriteError(w, r, http.StatusUnauthorized, entity.ErrUnauthorized)
+				return
+			}
+			if !principal.IsSession() {
+				utils.WriteError(w, r, http.StatusForbidden, entity.ErrForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// OptionalAuth пропускает анонимные запросы, но отклоняет неверные явные учетные данные
+// и аутентифицированные запросы без нужных прав.
+func OptionalAuth(scopes ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := GlobalUtils.GetAuthError(r.Context()); err != nil {
+				utils.WriteError(w, r, http.StatusUnauthorized, entity.ErrUnauthorized)
+				return
+			}
+			if principal, ok := GlobalUtils.GetPrincipal(r.Context()); ok && !checkScopes(w, r, principal, scopes) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func checkScopes(w http.ResponseWriter, r *http.Request, principal *entity.Principal, scopes []string) bool {
+	for _, scope := range scopes {
+		if !principal.HasScope(scope) {
+			utils.WriteError(w, r, http.StatusForbidden, entity.ErrForbidden)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,334 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name              string
+		setupRequest      func(*http.Request)
+		mockSetup         func(*mock.MockAuthUsecase)
+		expectedPrincipal *entity.Principal
+		expectedAuthError bool
+	}{
+		{
+			name:         "Без учетных данных запрос анонимный",
+			setupRequest: func(*http.Request) {},
+			mockSetup:    func(*mock.MockAuthUsecase) {},
+		},
+		{
+			name: "API-ключ дает субъект с правами ключа",
+			setupRequest: func(r *http.Request) {
+				r.Header.Set(utils.APIKeyHeader, "mk_valid")
+				r.AddCookie(&http.Cookie{Name: "session_id", Value: "cookie-session"})
+			},
+			mockSetup: func(auth *mock.MockAuthUsecase) {
+				auth.EXPECT().GetPrincipalByAPIKey(gomock.Any(), "mk_valid").Return(&entity.Principal{
+					UserID: 1,
+					Method: entity.AuthMethodAPIKey,
+					Scopes: []string{entity.ScopeAdsRead},
+				}, nil)
+			},
+			expectedPrincipal: &entity.Principal{
+				UserID: 1,
+				Method: entity.AuthMethodAPIKey,
+				Scopes: []string{entity.ScopeAdsRead},
+			},
+		},
+		{
+			name: "Недействительный API-ключ",
+			setupRequest: func(r *http.Request) {
+				r.Header.Set(utils.APIKeyHeader, "mk_invalid")
+			},
+			mockSetup: func(auth *mock.MockAuthUsecase) {
+				auth.EXPECT().GetPrincipalByAPIKey(gomock.Any(), "mk_invalid").
+					Return(nil, entity.NewError(entity.ErrUnauthorized, fmt.Errorf("недействительный API-ключ")))
+			},
+			expectedAuthError: true,
+		},
+		{
+			name: "Bearer-токен",
+			setupRequest: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer token-123")
+			},
+			mockSetup: func(auth *mock.MockAuthUsecase) {
+				auth.EXPECT().GetUserIDBySession(gomock.Any(), "token-123").Return(2, nil)
+			},
+			expectedPrincipal: &entity.Principal{UserID: 2, Method: entity.AuthMethodBearer, SessionID: "token-123"},
+		},
+		{
+			name: "Неподдерживаемая схема авторизации",
+			setupRequest: func(r *http.Request) {
+				r.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+			},
+			mockSetup:         func(*mock.MockAuthUsecase) {},
+			expectedAuthError: true,
+		},
+		{
+			name: "Недействительный bearer-токен",
+			setupRequest: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer expired")
+			},
+			mockSetup: func(auth *mock.MockAuthUsecase) {
+				auth.EXPECT().GetUserIDBySession(gomock.Any(), "expired").
+					Return(-1, entity.NewError(entity.ErrNotFound, fmt.Errorf("сессия не найдена")))
+			},
+			expectedAuthError: true,
+		},
+		{
+			name: "Сессионная cookie",
+			setupRequest: func(r *http.Request) {
+				r.AddCookie(&http.Cookie{Name: "session_id", Value: "cookie-session"})
+			},
+			mockSetup: func(auth *mock.MockAuthUsecase) {
+				auth.EXPECT().GetUserIDBySession(gomock.Any(), "cookie-session").Return(3, nil)
+			},
+			expectedPrincipal: &entity.Principal{UserID: 3, Method: entity.AuthMethodSession, SessionID: "cookie-session"},
+		},
+		{
+			name: "Просроченная cookie не считается ошибкой",
+			setupRequest: func(r *http.Request) {
+				r.AddCookie(&http.Cookie{Name: "session_id", Value: "expired"})
+			},
+			mockSetup: func(auth *mock.MockAuthUsecase) {
+				auth.EXPECT().GetUserIDBySession(gomock.Any(), "expired").
+					Return(-1, entity.NewError(entity.ErrNotFound, fmt.Errorf("сессия не найдена")))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			auth := mock.NewMockAuthUsecase(ctrl)
+			tc.mockSetup(auth)
+
+			var (
+				principal *entity.Principal
+				authErr   error
+			)
+			handler := AuthMiddleware(auth)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				principal, _ = GlobalUtils.GetPrincipal(r.Context())
+				authErr = GlobalUtils.GetAuthError(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			tc.setupRequest(req)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Equal(t, tc.expectedPrincipal, principal)
+			if tc.expectedAuthError {
+				require.Error(t, authErr)
+			} else {
+				require.NoError(t, authErr)
+			}
+		})
+	}
+}
+
+func TestRequireAuthScopes(t *testing.T) {
+	t.Parallel()
+
+	apiKeyPrincipal := &entity.Principal{
+		UserID: 1,
+		Method: entity.AuthMethodAPIKey,
+		Scopes: []string{entity.ScopeAdsRead},
+	}
+	sessionPrincipal := &entity.Principal{UserID: 1, Method: entity.AuthMethodSession, SessionID: "s"}
+
+	testCases := []struct {
+		name           string
+		middleware     Middleware
+		principal      *entity.Principal
+		authErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "RequireAuth: анонимный запрос",
+			middleware:     RequireAuth(entity.ScopeAdsRead),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "RequireAuth: у ключа есть право",
+			middleware:     RequireAuth(entity.ScopeAdsRead),
+			principal:      apiKeyPrincipal,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "RequireAuth: у ключа нет права",
+			middleware:     RequireAuth(entity.ScopeAdsWrite),
+			principal:      apiKeyPrincipal,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "RequireAuth: сессии доступны все права",
+			middleware:     RequireAuth(entity.ScopeAdsWrite),
+			principal:      sessionPrincipal,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "RequireSession: API-ключ не подходит",
+			middleware:     RequireSession(),
+			principal:      apiKeyPrincipal,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "RequireSession: сессия",
+			middleware:     RequireSession(),
+			principal:      sessionPrincipal,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "OptionalAuth: анонимный запрос",
+			middleware:     OptionalAuth(entity.ScopeAdsRead),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "OptionalAuth: неверные учетные данные",
+			middleware:     OptionalAuth(entity.ScopeAdsRead),
+			authErr:        entity.NewError(entity.ErrUnauthorized, fmt.Errorf("недействительный API-ключ")),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "OptionalAuth: у ключа нет права",
+			middleware:     OptionalAuth(entity.ScopeAdsWrite),
+			principal:      apiKeyPrincipal,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := tc.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			ctx := req.Context()
+			if tc.principal != nil {
+				ctx = GlobalUtils.SetPrincipal(ctx, tc.principal)
+			}
+			if tc.authErr != nil {
+				ctx = GlobalUtils.SetAuthError(ctx, tc.authErr)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req.WithContext(ctx))
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
+
+// TestAPIKeyAccess прогоняет запрос с API-ключом через AuthMiddleware и обертку маршрута,
+// как это происходит в приложении.
+func TestAPIKeyAccess(t *testing.T) {
+	t.Parallel()
+
+	readKey := &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey, Scopes: []string{entity.ScopeAdsRead}}
+
+	testCases := []struct {
+		name           string
+		middleware     Middleware
+		mockSetup      func(*mock.MockAuthUsecase)
+		expectedStatus int
+	}{
+		{
+			name:       "Ключ с правом чтения читает",
+			middleware: RequireAuth(entity.ScopeAdsRead),
+			mockSetup: func(auth *mock.MockAuthUsecase) {
+				auth.EXPECT().GetPrincipalByAPIKey(gomock.Any(), "mk_key").Return(readKey, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "Ключ с правом чтения не пишет",
+			middleware: RequireAuth(entity.ScopeAdsWrite),
+			mockSetup: func(auth *mock.MockAuthUsecase) {
+				auth.EXPECT().GetPrincipalByAPIKey(gomock.Any(), "mk_key").Return(readKey, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Ключ не открывает действия только для сессии",
+			middleware: RequireSession(),
+			mockSetup: func(auth *mock.MockAuthUsecase) {
+				auth.EXPECT().GetPrincipalByAPIKey(gomock.Any(), "mk_key").Return(readKey, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Отозванный ключ",
+			middleware: RequireAuth(entity.ScopeAdsRead),
+			mockSetup: func(auth *mock.MockAuthUsecase) {
+				auth.EXPECT().GetPrincipalByAPIKey(gomock.Any(), "mk_key").
+					Return(nil, entity.NewError(entity.ErrUnauthorized, fmt.Errorf("недействительный API-ключ")))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Просроченный ключ на публичном маршруте",
+			middleware: OptionalAuth(entity.ScopeAdsRead),
+			mockSetup: func(auth *mock.MockAuthUsecase) {
+				auth.EXPECT().GetPrincipalByAPIKey(gomock.Any(), "mk_key").
+					Return(nil, entity.NewError(entity.ErrUnauthorized, fmt.Errorf("срок действия API-ключа истёк")))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Ключ без нужного права на публичном маршруте",
+			middleware: OptionalAuth(entity.ScopeAdsWrite),
+			mockSetup: func(auth *mock.MockAuthUsecase) {
+				auth.EXPECT().GetPrincipalByAPIKey(gomock.Any(), "mk_key").Return(readKey, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			auth := mock.NewMockAuthUsecase(ctrl)
+			tc.mockSetup(auth)
+
+			handler := AuthMiddleware(auth)(tc.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(utils.APIKeyHeader, "mk_key")
+			// Cookie сессии не должна подменять ключ.
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: "cookie-session"})
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+
+	httpUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+// ClientIPMiddleware определяет адрес клиента и сохраняет его в контексте. Заголовки
+// X-Forwarded-For и X-Real-Ip учитываются, только если соединение пришло от доверенного
+// прокси: иначе клиент подставил бы любой адрес и обошел ограничения по IP.
+func ClientIPMiddleware(trusted []netip.Prefix) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := utils.SetClientIP(r.Context(), resolveClientIP(r, trusted))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// resolveClientIP идет по цепочке X-Forwarded-For справа налево, пропуская доверенные
+// прокси, и возвращает первый недоверенный адрес. Левее него значения добавил сам клиент.
+func resolveClientIP(r *http.Request, trusted []netip.Prefix) string {
+	remote := httpUtils.RemoteIP(r)
+	if !isTrustedProxy(remote, trusted) {
+		return remote
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if _, err := netip.ParseAddr(hop); err != nil {
+			// Неразборчивое значение дальше по цепочке не пускает: последний проверенный адрес надежнее.
+			return client
+		}
+		client = hop
+		if !isTrustedProxy(hop, trusted) {
+			return client
+		}
+	}
+
+	if len(hops) == 0 {
+		if realIP := strings.TrimSpace(r.Header.Get("X-Real-Ip")); realIP != "" {
+			if _, err := netip.ParseAddr(realIP); err == nil {
+				return realIP
+			}
+		}
+	}
+	return client
+}
+
+func isTrustedProxy(ip string, trusted []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	httpUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIPMiddleware(t *testing.T) {
+	t.Parallel()
+
+	trusted, err := config.ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		realIP     string
+		expected   string
+	}{
+		{
+			name:       "Untrusted peer ignores headers",
+			remoteAddr: "203.0.113.7:4000",
+			forwarded:  []string{"1.2.3.4"},
+			realIP:     "5.6.7.8",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "Trusted proxy passes client",
+			remoteAddr: "10.0.0.5:4000",
+			forwarded:  []string{"198.51.100.10"},
+			expected:   "198.51.100.10",
+		},
+		{
+			name:       "Spoofed left value is skipped",
+			remoteAddr: "10.0.0.5:4000",
+			forwarded:  []string{"1.1.1.1, 198.51.100.10"},
+			expected:   "198.51.100.10",
+		},
+		{
+			name:       "Chain of trusted proxies",
+			remoteAddr: "10.0.0.5:4000",
+			forwarded:  []string{"1.1.1.1, 198.51.100.10, 192.168.1.1", "10.1.2.3"},
+			expected:   "198.51.100.10",
+		},
+		{
+			name:       "All hops trusted",
+			remoteAddr: "10.0.0.5:4000",
+			forwarded:  []string{"10.9.9.9"},
+			expected:   "10.9.9.9",
+		},
+		{
+			name:       "Garbage hop stops the walk",
+			remoteAddr: "10.0.0.5:4000",
+			forwarded:  []string{"198.51.100.10, not-an-ip"},
+			expected:   "10.0.0.5",
+		},
+		{
+			name:       "X-Real-Ip from trusted proxy",
+			remoteAddr: "192.168.1.1:4000",
+			realIP:     "198.51.100.20",
+			expected:   "198.51.100.20",
+		},
+		{
+			name:       "Trusted proxy without headers",
+			remoteAddr: "10.0.0.5:4000",
+			expected:   "10.0.0.5",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-Ip", tt.realIP)
+			}
+
+			var got string
+			handler := ClientIPMiddleware(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = httpUtils.ClientIP(r)
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	prefixes, err := config.ParseTrustedProxies([]string{"10.0.0.1/8", "::1"})
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.0/8", prefixes[0].String())
+	require.Equal(t, "::1/128", prefixes[1].String())
+
+	_, err = config.ParseTrustedProxies([]string{"proxy.local"})
+	require.Error(t, err)
+}
@@ -21,6 +21,7 @@ func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
 				http.MethodGet,
 				http.MethodPost,
 				http.MethodPut,
+				http.MethodPatch,
 				http.MethodDelete,
 				http.MethodOptions,
 			}, ","))
@@ -29,9 +30,12 @@ func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
 				"Content-Type",
 				"Authorization",
 				"X-CSRF-Token",
+				"X-API-Key",
+				"If-Match",
+				"If-None-Match",
 			}, ","))
 
-			w.Header().Set("Access-Control-Expose-Headers", "X-CSRF-Token")
+			w.Header().Set("Access-Control-Expose-Headers", "X-CSRF-Token,ETag")
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 
 			if r.Method == http.MethodOptions {
@@ -95,11 +95,11 @@ func TestCORS(t *testing.T) {
 
 			// Проверяем стандартные CORS заголовки
 			if tc.expectedAllowOrigin != "" {
-				require.Equal(t, "GET,POST,PUT,DELETE,OPTIONS",
+				require.Equal(t, "GET,POST,PUT,PATCH,DELETE,OPTIONS",
 					resp.Header.Get("Access-Control-Allow-Methods"))
-				require.Equal(t, "Content-Type,Authorization,X-CSRF-Token",
+				require.Equal(t, "Content-Type,Authorization,X-CSRF-Token,X-API-Key,If-Match,If-None-Match",
 					resp.Header.Get("Access-Control-Allow-Headers"))
-				require.Equal(t, "X-CSRF-Token",
+				require.Equal(t, "X-CSRF-Token,ETag",
 					resp.Header.Get("Access-Control-Expose-Headers"))
 				require.Equal(t, "true",
 					resp.Header.Get("Access-Control-Allow-Credentials"))
@@ -5,9 +5,11 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
 )
 
 func generateToken(r *http.Request, sessionID string, cfg config.CSRFConfig) string {
@@ -41,31 +43,45 @@ func SetCSRFToken(w http.ResponseWriter, r *http.Request, cfg config.CSRFConfig)
 	w.Header().Set("X-CSRF-Token", token)
 }
 
+// staticPathPrefix — статические файлы отдаются без выдачи CSRF-токена.
+const staticPathPrefix = "/api/v1/static/"
+
 func CSRFMiddleware(cfg config.CSRFConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
-				SetCSRFToken(w, r, cfg)
+				if !strings.HasPrefix(r.URL.Path, staticPathPrefix) {
+					SetCSRFToken(w, r, cfg)
+				}
 
 				next.ServeHTTP(w, r)
 
 				return
 			}
 
+			// Запросы скриптов с API-ключом или bearer-токеном не несут сессионных cookie,
+			// поэтому подделать их из браузера нельзя.
+			if r.Header.Get(utils.APIKeyHeader) != "" || r.Header.Get("Authorization") != "" {
+				if _, err := r.Cookie("session_id"); err != nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
 			receivedToken := r.Header.Get("X-CSRF-Token")
 			if receivedToken == "" {
-				http.Error(w, "CSRF token missing", http.StatusForbidden)
+				utils.WriteAPIError(w, r, utils.NewAPIError(http.StatusForbidden, utils.CodeCSRFTokenMissing))
 				return
 			}
 
 			cookie, err := r.Cookie(cfg.CookieName)
 			if err != nil {
-				http.Error(w, "CSRF cookie missing", http.StatusForbidden)
+				utils.WriteAPIError(w, r, utils.NewAPIError(http.StatusForbidden, utils.CodeCSRFCookieMissing))
 				return
 			}
 
 			if !hmac.Equal([]byte(receivedToken), []byte(cookie.Value)) {
-				http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+				utils.WriteAPIError(w, r, utils.NewAPIError(http.StatusForbidden, utils.CodeCSRFTokenInvalid))
 				return
 			}
 
@@ -1,13 +1,14 @@
 package middleware
 
 import (
-	"ResuMatch/internal/config"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
 	"github.com/stretchr/testify/require"
 )
 
@@ -149,21 +150,25 @@ func TestCSRFMiddleware(t *testing.T) {
 			tokenHeader string
 			tokenCookie string
 			expected    int
+			code        string
 		}{
 			{
 				name:     "Missing token header",
 				expected: http.StatusForbidden,
+				code:     utils.CodeCSRFTokenMissing,
 			},
 			{
 				name:        "Missing cookie",
 				tokenHeader: "token",
 				expected:    http.StatusForbidden,
+				code:        utils.CodeCSRFCookieMissing,
 			},
 			{
 				name:        "Token mismatch",
 				tokenHeader: "token1",
 				tokenCookie: "token2",
 				expected:    http.StatusForbidden,
+				code:        utils.CodeCSRFTokenInvalid,
 			},
 		}
 
@@ -188,6 +193,8 @@ func TestCSRFMiddleware(t *testing.T) {
 
 				handler.ServeHTTP(w, r)
 				require.Equal(t, tc.expected, w.Code)
+				require.Equal(t, utils.ProblemContentType, w.Header().Get("Content-Type"))
+				require.True(t, strings.Contains(w.Body.String(), `"code":"`+tc.code+`"`))
 			})
 		}
 	})
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/i18n"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// LanguageMiddleware определяет язык сообщений для клиента: язык из профиля
+// аутентифицированного пользователя, иначе лучший вариант из Accept-Language.
+// Профиль читается только когда ответу нужен перевод, поэтому успешные запросы
+// не обращаются к базе. Должен стоять после AuthMiddleware.
+func LanguageMiddleware(users usecase.UserUsecase) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			fallback := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+			principal, ok := GlobalUtils.GetPrincipal(ctx)
+			if !ok {
+				next.ServeHTTP(w, r.WithContext(GlobalUtils.SetLanguage(ctx, fallback)))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(GlobalUtils.SetLanguageResolver(ctx, func() i18n.Lang {
+				preferred, err := users.GetLanguage(ctx, principal.UserID)
+				if err != nil {
+					l.Log.WithFields(logrus.Fields{
+						"requestID": GlobalUtils.GetRequestID(ctx),
+						"userID":    principal.UserID,
+						"error":     err,
+					}).Warn("Не удалось получить язык пользователя")
+					return fallback
+				}
+				if lang, ok := i18n.ParseLang(preferred); ok {
+					return lang
+				}
+				return fallback
+			})))
+		})
+	}
+}
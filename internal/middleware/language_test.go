@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/i18n"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLanguageMiddleware(t *testing.T) {
+	t.Parallel()
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	errorHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Язык запрашивается дважды, а профиль читается один раз.
+		_ = utils.RequestLanguage(r)
+		utils.WriteError(w, r, http.StatusNotFound, entity.ErrNotFound)
+	})
+
+	testCases := []struct {
+		name             string
+		principal        *entity.Principal
+		handler          http.Handler
+		mockSetup        func(*mock.MockUserUsecase)
+		expectedLanguage string
+	}{
+		{
+			name:      "Успешный ответ не читает профиль",
+			principal: &entity.Principal{UserID: 1, Method: entity.AuthMethodSession},
+			handler:   okHandler,
+			mockSetup: func(*mock.MockUserUsecase) {},
+		},
+		{
+			name:      "Ошибка переводится на язык профиля",
+			principal: &entity.Principal{UserID: 1, Method: entity.AuthMethodSession},
+			handler:   errorHandler,
+			mockSetup: func(users *mock.MockUserUsecase) {
+				users.EXPECT().GetLanguage(gomock.Any(), 1).Return("ru", nil).Times(1)
+			},
+			expectedLanguage: string(i18n.Russian),
+		},
+		{
+			name:      "Язык не выбран в профиле",
+			principal: &entity.Principal{UserID: 1, Method: entity.AuthMethodSession},
+			handler:   errorHandler,
+			mockSetup: func(users *mock.MockUserUsecase) {
+				users.EXPECT().GetLanguage(gomock.Any(), 1).Return("", nil)
+			},
+			expectedLanguage: string(i18n.English),
+		},
+		{
+			name:      "Ошибка чтения профиля не мешает ответу",
+			principal: &entity.Principal{UserID: 1, Method: entity.AuthMethodSession},
+			handler:   errorHandler,
+			mockSetup: func(users *mock.MockUserUsecase) {
+				users.EXPECT().GetLanguage(gomock.Any(), 1).
+					Return("", entity.NewError(entity.ErrInternal, fmt.Errorf("соединение потеряно")))
+			},
+			expectedLanguage: string(i18n.English),
+		},
+		{
+			name:             "Аноним получает язык из заголовка",
+			handler:          errorHandler,
+			mockSetup:        func(*mock.MockUserUsecase) {},
+			expectedLanguage: string(i18n.English),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			users := mock.NewMockUserUsecase(ctrl)
+			tc.mockSetup(users)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Language", "de, en;q=0.8")
+			if tc.principal != nil {
+				req = req.WithContext(GlobalUtils.SetPrincipal(req.Context(), tc.principal))
+			}
+			rr := httptest.NewRecorder()
+			LanguageMiddleware(users)(tc.handler).ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedLanguage, rr.Header().Get("Content-Language"))
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
 	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
 	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
@@ -15,10 +16,12 @@ func RecoveryMiddleware() func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
-					utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("%v", err))
+					// Текст паники остается в логах, клиент получает только код ошибки.
+					utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
 					requestID := GlobalUtils.GetRequestID(r.Context())
 					l.Log.WithFields(logrus.Fields{
 						"requestID": requestID,
+						"panic":     fmt.Sprintf("%v", err),
 					}).Fatal("Recovery middleware panic")
 				}
 			}()
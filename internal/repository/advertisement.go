@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 )
@@ -11,4 +12,15 @@ type AdvertisementRepository interface {
 	GetByID(ctx context.Context, id int) (*entity.Advertisement, error)
 	GetAll(ctx context.Context, userID int, page, limit int, sortBy, order string, minPrice, maxPrice *float64) ([]entity.Advertisement, error)
 	GetByUserID(ctx context.Context, userID int) ([]entity.Advertisement, error)
+	// Update и Delete срабатывают, только если версия объявления не изменилась с момента чтения.
+	Update(ctx context.Context, ad *entity.Advertisement) (int, error)
+	Delete(ctx context.Context, id, version int) error
+	Renew(ctx context.Context, id int, expiresAt time.Time, maxRenewals int) error
+	ClaimExpiring(ctx context.Context, deadline time.Time, limit int) ([]entity.Advertisement, error)
+	ArchiveExpired(ctx context.Context, limit int) ([]entity.Advertisement, error)
+	FindSimilar(ctx context.Context, fingerprint entity.AdFingerprint, filter entity.SimilarAdsFilter) ([]entity.SimilarAd, error)
+	GetDuplicates(ctx context.Context, offset, limit int) ([]entity.Advertisement, error)
+	GetSimilar(ctx context.Context, ad *entity.Advertisement, limit int) ([]entity.Advertisement, error)
+	GetPendingModeration(ctx context.Context, offset, limit int) ([]entity.Advertisement, error)
+	Moderate(ctx context.Context, id int, status entity.ModerationStatus) (*entity.Advertisement, error)
 }
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *entity.APIKey) (*entity.APIKey, error)
+	GetByHash(ctx context.Context, keyHash []byte) (*entity.APIKey, error)
+	GetByUserID(ctx context.Context, userID int) ([]entity.APIKey, error)
+	CountByUserID(ctx context.Context, userID int) (int, error)
+	UpdateName(ctx context.Context, userID, id int, name string) (*entity.APIKey, error)
+	Delete(ctx context.Context, userID, id int) error
+	TouchLastUsed(ctx context.Context, id int) error
+}
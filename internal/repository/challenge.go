@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type ChallengeRepository interface {
+	CreateChallenge(ctx context.Context, userID int, ttl time.Duration) (string, error)
+	GetChallenge(ctx context.Context, token string) (int, error)
+	IncrementAttempts(ctx context.Context, token string) (int, error)
+	DeleteChallenge(ctx context.Context, token string) error
+}
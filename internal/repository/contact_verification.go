@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type ContactVerificationRepository interface {
+	Create(ctx context.Context, userID int, channel entity.ContactChannel, v *entity.ContactVerification, ttl time.Duration) error
+	Get(ctx context.Context, userID int, channel entity.ContactChannel) (*entity.ContactVerification, error)
+	IncrementAttempts(ctx context.Context, userID int, channel entity.ContactChannel) (int, error)
+	Delete(ctx context.Context, userID int, channel entity.ContactChannel) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type ConversationRepository interface {
+	GetOrCreate(ctx context.Context, adID, buyerID, sellerID int) (*entity.Conversation, error)
+	GetByID(ctx context.Context, id, viewerID int) (*entity.Conversation, error)
+	GetByUserID(ctx context.Context, userID, offset, limit int) ([]entity.Conversation, error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+	SetBlockedBy(ctx context.Context, id int, blockedBy *int) error
+	CreateMessage(ctx context.Context, msg *entity.Message) (*entity.Message, error)
+	GetMessages(ctx context.Context, conversationID int, beforeID int64, limit int) ([]entity.Message, error)
+	MarkRead(ctx context.Context, conversationID, userID int, upToID int64) (int64, error)
+}
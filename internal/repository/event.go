@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type EventRepository interface {
+	Publish(ctx context.Context, event *entity.Event) error
+	// Subscribe передает в handle события всех пользователей, пока не отменен ctx
+	// или не оборвалось соединение. При отмене ctx возвращает nil.
+	Subscribe(ctx context.Context, handle func(event *entity.Event)) error
+}
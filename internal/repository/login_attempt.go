@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type LoginAttemptRepository interface {
+	// GetLockout возвращает оставшееся время блокировки ключа (0, если блокировки нет).
+	GetLockout(ctx context.Context, key string) (time.Duration, error)
+	RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	Lock(ctx context.Context, key string, duration time.Duration) error
+	Reset(ctx context.Context, key string) error
+}
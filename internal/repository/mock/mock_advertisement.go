@@ -12,6 +12,7 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +42,36 @@ func (m *MockAdvertisementRepository) EXPECT() *MockAdvertisementRepositoryMockR
 	return m.recorder
 }
 
+// ArchiveExpired mocks base method.
+func (m *MockAdvertisementRepository) ArchiveExpired(ctx context.Context, limit int) ([]entity.Advertisement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveExpired", ctx, limit)
+	ret0, _ := ret[0].([]entity.Advertisement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveExpired indicates an expected call of ArchiveExpired.
+func (mr *MockAdvertisementRepositoryMockRecorder) ArchiveExpired(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveExpired", reflect.TypeOf((*MockAdvertisementRepository)(nil).ArchiveExpired), ctx, limit)
+}
+
+// ClaimExpiring mocks base method.
+func (m *MockAdvertisementRepository) ClaimExpiring(ctx context.Context, deadline time.Time, limit int) ([]entity.Advertisement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimExpiring", ctx, deadline, limit)
+	ret0, _ := ret[0].([]entity.Advertisement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimExpiring indicates an expected call of ClaimExpiring.
+func (mr *MockAdvertisementRepositoryMockRecorder) ClaimExpiring(ctx, deadline, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimExpiring", reflect.TypeOf((*MockAdvertisementRepository)(nil).ClaimExpiring), ctx, deadline, limit)
+}
+
 // Create mocks base method.
 func (m *MockAdvertisementRepository) Create(ctx context.Context, ad *entity.Advertisement) (*entity.Advertisement, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +87,35 @@ func (mr *MockAdvertisementRepositoryMockRecorder) Create(ctx, ad any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAdvertisementRepository)(nil).Create), ctx, ad)
 }
 
+// Delete mocks base method.
+func (m *MockAdvertisementRepository) Delete(ctx context.Context, id, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAdvertisementRepositoryMockRecorder) Delete(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAdvertisementRepository)(nil).Delete), ctx, id, version)
+}
+
+// FindSimilar mocks base method.
+func (m *MockAdvertisementRepository) FindSimilar(ctx context.Context, fingerprint entity.AdFingerprint, filter entity.SimilarAdsFilter) ([]entity.SimilarAd, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSimilar", ctx, fingerprint, filter)
+	ret0, _ := ret[0].([]entity.SimilarAd)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSimilar indicates an expected call of FindSimilar.
+func (mr *MockAdvertisementRepositoryMockRecorder) FindSimilar(ctx, fingerprint, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSimilar", reflect.TypeOf((*MockAdvertisementRepository)(nil).FindSimilar), ctx, fingerprint, filter)
+}
+
 // GetAll mocks base method.
 func (m *MockAdvertisementRepository) GetAll(ctx context.Context, userID, page, limit int, sortBy, order string, minPrice, maxPrice *float64) ([]entity.Advertisement, error) {
 	m.ctrl.T.Helper()
@@ -100,3 +160,92 @@ func (mr *MockAdvertisementRepositoryMockRecorder) GetByUserID(ctx, userID any)
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockAdvertisementRepository)(nil).GetByUserID), ctx, userID)
 }
+
+// GetDuplicates mocks base method.
+func (m *MockAdvertisementRepository) GetDuplicates(ctx context.Context, offset, limit int) ([]entity.Advertisement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDuplicates", ctx, offset, limit)
+	ret0, _ := ret[0].([]entity.Advertisement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDuplicates indicates an expected call of GetDuplicates.
+func (mr *MockAdvertisementRepositoryMockRecorder) GetDuplicates(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDuplicates", reflect.TypeOf((*MockAdvertisementRepository)(nil).GetDuplicates), ctx, offset, limit)
+}
+
+// GetPendingModeration mocks base method.
+func (m *MockAdvertisementRepository) GetPendingModeration(ctx context.Context, offset, limit int) ([]entity.Advertisement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingModeration", ctx, offset, limit)
+	ret0, _ := ret[0].([]entity.Advertisement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingModeration indicates an expected call of GetPendingModeration.
+func (mr *MockAdvertisementRepositoryMockRecorder) GetPendingModeration(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingModeration", reflect.TypeOf((*MockAdvertisementRepository)(nil).GetPendingModeration), ctx, offset, limit)
+}
+
+// GetSimilar mocks base method.
+func (m *MockAdvertisementRepository) GetSimilar(ctx context.Context, ad *entity.Advertisement, limit int) ([]entity.Advertisement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSimilar", ctx, ad, limit)
+	ret0, _ := ret[0].([]entity.Advertisement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSimilar indicates an expected call of GetSimilar.
+func (mr *MockAdvertisementRepositoryMockRecorder) GetSimilar(ctx, ad, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimilar", reflect.TypeOf((*MockAdvertisementRepository)(nil).GetSimilar), ctx, ad, limit)
+}
+
+// Moderate mocks base method.
+func (m *MockAdvertisementRepository) Moderate(ctx context.Context, id int, status entity.ModerationStatus) (*entity.Advertisement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Moderate", ctx, id, status)
+	ret0, _ := ret[0].(*entity.Advertisement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Moderate indicates an expected call of Moderate.
+func (mr *MockAdvertisementRepositoryMockRecorder) Moderate(ctx, id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Moderate", reflect.TypeOf((*MockAdvertisementRepository)(nil).Moderate), ctx, id, status)
+}
+
+// Renew mocks base method.
+func (m *MockAdvertisementRepository) Renew(ctx context.Context, id int, expiresAt time.Time, maxRenewals int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, id, expiresAt, maxRenewals)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockAdvertisementRepositoryMockRecorder) Renew(ctx, id, expiresAt, maxRenewals any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockAdvertisementRepository)(nil).Renew), ctx, id, expiresAt, maxRenewals)
+}
+
+// Update mocks base method.
+func (m *MockAdvertisementRepository) Update(ctx context.Context, ad *entity.Advertisement) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, ad)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAdvertisementRepositoryMockRecorder) Update(ctx, ad any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAdvertisementRepository)(nil).Update), ctx, ad)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/repository (interfaces: ChallengeRepository)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/repository/mock/mock_challenge.go github.com/AlexSamarskii/marketplace_vk_intern/internal/repository ChallengeRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockChallengeRepository is a mock of ChallengeRepository interface.
+type MockChallengeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockChallengeRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockChallengeRepositoryMockRecorder is the mock recorder for MockChallengeRepository.
+type MockChallengeRepositoryMockRecorder struct {
+	mock *MockChallengeRepository
+}
+
+// NewMockChallengeRepository creates a new mock instance.
+func NewMockChallengeRepository(ctrl *gomock.Controller) *MockChallengeRepository {
+	mock := &MockChallengeRepository{ctrl: ctrl}
+	mock.recorder = &MockChallengeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChallengeRepository) EXPECT() *MockChallengeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateChallenge mocks base method.
+func (m *MockChallengeRepository) CreateChallenge(ctx context.Context, userID int, ttl time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChallenge", ctx, userID, ttl)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChallenge indicates an expected call of CreateChallenge.
+func (mr *MockChallengeRepositoryMockRecorder) CreateChallenge(ctx, userID, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallenge", reflect.TypeOf((*MockChallengeRepository)(nil).CreateChallenge), ctx, userID, ttl)
+}
+
+// DeleteChallenge mocks base method.
+func (m *MockChallengeRepository) DeleteChallenge(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChallenge", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteChallenge indicates an expected call of DeleteChallenge.
+func (mr *MockChallengeRepositoryMockRecorder) DeleteChallenge(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChallenge", reflect.TypeOf((*MockChallengeRepository)(nil).DeleteChallenge), ctx, token)
+}
+
+// GetChallenge mocks base method.
+func (m *MockChallengeRepository) GetChallenge(ctx context.Context, token string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChallenge", ctx, token)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChallenge indicates an expected call of GetChallenge.
+func (mr *MockChallengeRepositoryMockRecorder) GetChallenge(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChallenge", reflect.TypeOf((*MockChallengeRepository)(nil).GetChallenge), ctx, token)
+}
+
+// IncrementAttempts mocks base method.
+func (m *MockChallengeRepository) IncrementAttempts(ctx context.Context, token string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementAttempts", ctx, token)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementAttempts indicates an expected call of IncrementAttempts.
+func (mr *MockChallengeRepositoryMockRecorder) IncrementAttempts(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementAttempts", reflect.TypeOf((*MockChallengeRepository)(nil).IncrementAttempts), ctx, token)
+}
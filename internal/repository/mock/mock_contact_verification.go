@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/repository (interfaces: ContactVerificationRepository)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/repository/mock/mock_contact_verification.go github.com/AlexSamarskii/marketplace_vk_intern/internal/repository ContactVerificationRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockContactVerificationRepository is a mock of ContactVerificationRepository interface.
+type MockContactVerificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockContactVerificationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockContactVerificationRepositoryMockRecorder is the mock recorder for MockContactVerificationRepository.
+type MockContactVerificationRepositoryMockRecorder struct {
+	mock *MockContactVerificationRepository
+}
+
+// NewMockContactVerificationRepository creates a new mock instance.
+func NewMockContactVerificationRepository(ctrl *gomock.Controller) *MockContactVerificationRepository {
+	mock := &MockContactVerificationRepository{ctrl: ctrl}
+	mock.recorder = &MockContactVerificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContactVerificationRepository) EXPECT() *MockContactVerificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockContactVerificationRepository) Create(ctx context.Context, userID int, channel entity.ContactChannel, v *entity.ContactVerification, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, channel, v, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockContactVerificationRepositoryMockRecorder) Create(ctx, userID, channel, v, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockContactVerificationRepository)(nil).Create), ctx, userID, channel, v, ttl)
+}
+
+// Delete mocks base method.
+func (m *MockContactVerificationRepository) Delete(ctx context.Context, userID int, channel entity.ContactChannel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, channel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockContactVerificationRepositoryMockRecorder) Delete(ctx, userID, channel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockContactVerificationRepository)(nil).Delete), ctx, userID, channel)
+}
+
+// Get mocks base method.
+func (m *MockContactVerificationRepository) Get(ctx context.Context, userID int, channel entity.ContactChannel) (*entity.ContactVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, channel)
+	ret0, _ := ret[0].(*entity.ContactVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockContactVerificationRepositoryMockRecorder) Get(ctx, userID, channel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockContactVerificationRepository)(nil).Get), ctx, userID, channel)
+}
+
+// IncrementAttempts mocks base method.
+func (m *MockContactVerificationRepository) IncrementAttempts(ctx context.Context, userID int, channel entity.ContactChannel) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementAttempts", ctx, userID, channel)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementAttempts indicates an expected call of IncrementAttempts.
+func (mr *MockContactVerificationRepositoryMockRecorder) IncrementAttempts(ctx, userID, channel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementAttempts", reflect.TypeOf((*MockContactVerificationRepository)(nil).IncrementAttempts), ctx, userID, channel)
+}
@@ -0,0 +1,161 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/repository (interfaces: ConversationRepository)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/repository/mock/mock_conversation.go github.com/AlexSamarskii/marketplace_vk_intern/internal/repository ConversationRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConversationRepository is a mock of ConversationRepository interface.
+type MockConversationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConversationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockConversationRepositoryMockRecorder is the mock recorder for MockConversationRepository.
+type MockConversationRepositoryMockRecorder struct {
+	mock *MockConversationRepository
+}
+
+// NewMockConversationRepository creates a new mock instance.
+func NewMockConversationRepository(ctrl *gomock.Controller) *MockConversationRepository {
+	mock := &MockConversationRepository{ctrl: ctrl}
+	mock.recorder = &MockConversationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConversationRepository) EXPECT() *MockConversationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountUnread mocks base method.
+func (m *MockConversationRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnread", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnread indicates an expected call of CountUnread.
+func (mr *MockConversationRepositoryMockRecorder) CountUnread(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnread", reflect.TypeOf((*MockConversationRepository)(nil).CountUnread), ctx, userID)
+}
+
+// CreateMessage mocks base method.
+func (m *MockConversationRepository) CreateMessage(ctx context.Context, msg *entity.Message) (*entity.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessage", ctx, msg)
+	ret0, _ := ret[0].(*entity.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockConversationRepositoryMockRecorder) CreateMessage(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockConversationRepository)(nil).CreateMessage), ctx, msg)
+}
+
+// GetByID mocks base method.
+func (m *MockConversationRepository) GetByID(ctx context.Context, id, viewerID int) (*entity.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id, viewerID)
+	ret0, _ := ret[0].(*entity.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockConversationRepositoryMockRecorder) GetByID(ctx, id, viewerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockConversationRepository)(nil).GetByID), ctx, id, viewerID)
+}
+
+// GetByUserID mocks base method.
+func (m *MockConversationRepository) GetByUserID(ctx context.Context, userID, offset, limit int) ([]entity.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID, offset, limit)
+	ret0, _ := ret[0].([]entity.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockConversationRepositoryMockRecorder) GetByUserID(ctx, userID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockConversationRepository)(nil).GetByUserID), ctx, userID, offset, limit)
+}
+
+// GetMessages mocks base method.
+func (m *MockConversationRepository) GetMessages(ctx context.Context, conversationID int, beforeID int64, limit int) ([]entity.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessages", ctx, conversationID, beforeID, limit)
+	ret0, _ := ret[0].([]entity.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessages indicates an expected call of GetMessages.
+func (mr *MockConversationRepositoryMockRecorder) GetMessages(ctx, conversationID, beforeID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockConversationRepository)(nil).GetMessages), ctx, conversationID, beforeID, limit)
+}
+
+// GetOrCreate mocks base method.
+func (m *MockConversationRepository) GetOrCreate(ctx context.Context, adID, buyerID, sellerID int) (*entity.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreate", ctx, adID, buyerID, sellerID)
+	ret0, _ := ret[0].(*entity.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrCreate indicates an expected call of GetOrCreate.
+func (mr *MockConversationRepositoryMockRecorder) GetOrCreate(ctx, adID, buyerID, sellerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreate", reflect.TypeOf((*MockConversationRepository)(nil).GetOrCreate), ctx, adID, buyerID, sellerID)
+}
+
+// MarkRead mocks base method.
+func (m *MockConversationRepository) MarkRead(ctx context.Context, conversationID, userID int, upToID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, conversationID, userID, upToID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockConversationRepositoryMockRecorder) MarkRead(ctx, conversationID, userID, upToID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockConversationRepository)(nil).MarkRead), ctx, conversationID, userID, upToID)
+}
+
+// SetBlockedBy mocks base method.
+func (m *MockConversationRepository) SetBlockedBy(ctx context.Context, id int, blockedBy *int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBlockedBy", ctx, id, blockedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBlockedBy indicates an expected call of SetBlockedBy.
+func (mr *MockConversationRepositoryMockRecorder) SetBlockedBy(ctx, id, blockedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBlockedBy", reflect.TypeOf((*MockConversationRepository)(nil).SetBlockedBy), ctx, id, blockedBy)
+}
@@ -0,0 +1,194 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/repository (interfaces: OfferRepository)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/repository/mock/mock_offer.go github.com/AlexSamarskii/marketplace_vk_intern/internal/repository OfferRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOfferRepository is a mock of OfferRepository interface.
+type MockOfferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOfferRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOfferRepositoryMockRecorder is the mock recorder for MockOfferRepository.
+type MockOfferRepositoryMockRecorder struct {
+	mock *MockOfferRepository
+}
+
+// NewMockOfferRepository creates a new mock instance.
+func NewMockOfferRepository(ctrl *gomock.Controller) *MockOfferRepository {
+	mock := &MockOfferRepository{ctrl: ctrl}
+	mock.recorder = &MockOfferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOfferRepository) EXPECT() *MockOfferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockOfferRepository) Accept(ctx context.Context, id, userID int, orderDeadline time.Time) (*entity.Offer, []entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", ctx, id, userID, orderDeadline)
+	ret0, _ := ret[0].(*entity.Offer)
+	ret1, _ := ret[1].([]entity.Offer)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockOfferRepositoryMockRecorder) Accept(ctx, id, userID, orderDeadline any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockOfferRepository)(nil).Accept), ctx, id, userID, orderDeadline)
+}
+
+// Counter mocks base method.
+func (m *MockOfferRepository) Counter(ctx context.Context, id, userID int, amount float64, expiresAt time.Time) (*entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Counter", ctx, id, userID, amount, expiresAt)
+	ret0, _ := ret[0].(*entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Counter indicates an expected call of Counter.
+func (mr *MockOfferRepositoryMockRecorder) Counter(ctx, id, userID, amount, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Counter", reflect.TypeOf((*MockOfferRepository)(nil).Counter), ctx, id, userID, amount, expiresAt)
+}
+
+// Create mocks base method.
+func (m *MockOfferRepository) Create(ctx context.Context, offer *entity.Offer) (*entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, offer)
+	ret0, _ := ret[0].(*entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOfferRepositoryMockRecorder) Create(ctx, offer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOfferRepository)(nil).Create), ctx, offer)
+}
+
+// Decline mocks base method.
+func (m *MockOfferRepository) Decline(ctx context.Context, id, userID int) (*entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decline", ctx, id, userID)
+	ret0, _ := ret[0].(*entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decline indicates an expected call of Decline.
+func (mr *MockOfferRepositoryMockRecorder) Decline(ctx, id, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decline", reflect.TypeOf((*MockOfferRepository)(nil).Decline), ctx, id, userID)
+}
+
+// ExpireAccepted mocks base method.
+func (m *MockOfferRepository) ExpireAccepted(ctx context.Context) ([]entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireAccepted", ctx)
+	ret0, _ := ret[0].([]entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireAccepted indicates an expected call of ExpireAccepted.
+func (mr *MockOfferRepositoryMockRecorder) ExpireAccepted(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireAccepted", reflect.TypeOf((*MockOfferRepository)(nil).ExpireAccepted), ctx)
+}
+
+// ExpirePending mocks base method.
+func (m *MockOfferRepository) ExpirePending(ctx context.Context) ([]entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePending", ctx)
+	ret0, _ := ret[0].([]entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePending indicates an expected call of ExpirePending.
+func (mr *MockOfferRepositoryMockRecorder) ExpirePending(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePending", reflect.TypeOf((*MockOfferRepository)(nil).ExpirePending), ctx)
+}
+
+// GetByAdvertisementID mocks base method.
+func (m *MockOfferRepository) GetByAdvertisementID(ctx context.Context, adID, offset, limit int) ([]entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAdvertisementID", ctx, adID, offset, limit)
+	ret0, _ := ret[0].([]entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAdvertisementID indicates an expected call of GetByAdvertisementID.
+func (mr *MockOfferRepositoryMockRecorder) GetByAdvertisementID(ctx, adID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAdvertisementID", reflect.TypeOf((*MockOfferRepository)(nil).GetByAdvertisementID), ctx, adID, offset, limit)
+}
+
+// GetByID mocks base method.
+func (m *MockOfferRepository) GetByID(ctx context.Context, id int) (*entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockOfferRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockOfferRepository)(nil).GetByID), ctx, id)
+}
+
+// GetByUserID mocks base method.
+func (m *MockOfferRepository) GetByUserID(ctx context.Context, userID, offset, limit int) ([]entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID, offset, limit)
+	ret0, _ := ret[0].([]entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockOfferRepositoryMockRecorder) GetByUserID(ctx, userID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockOfferRepository)(nil).GetByUserID), ctx, userID, offset, limit)
+}
+
+// Withdraw mocks base method.
+func (m *MockOfferRepository) Withdraw(ctx context.Context, id, userID int) (*entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdraw", ctx, id, userID)
+	ret0, _ := ret[0].(*entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockOfferRepositoryMockRecorder) Withdraw(ctx, id, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockOfferRepository)(nil).Withdraw), ctx, id, userID)
+}
@@ -12,7 +12,9 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	entity "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -69,6 +71,20 @@ func (mr *MockSessionRepositoryMockRecorder) DeleteAllSessions(ctx, userID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllSessions", reflect.TypeOf((*MockSessionRepository)(nil).DeleteAllSessions), ctx, userID)
 }
 
+// DeleteOtherSessions mocks base method.
+func (m *MockSessionRepository) DeleteOtherSessions(ctx context.Context, userID int, keepToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOtherSessions", ctx, userID, keepToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOtherSessions indicates an expected call of DeleteOtherSessions.
+func (mr *MockSessionRepositoryMockRecorder) DeleteOtherSessions(ctx, userID, keepToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOtherSessions", reflect.TypeOf((*MockSessionRepository)(nil).DeleteOtherSessions), ctx, userID, keepToken)
+}
+
 // DeleteSession mocks base method.
 func (m *MockSessionRepository) DeleteSession(ctx context.Context, sessionToken string) error {
 	m.ctrl.T.Helper()
@@ -97,3 +113,33 @@ func (mr *MockSessionRepositoryMockRecorder) GetSession(ctx, sessionToken any) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockSessionRepository)(nil).GetSession), ctx, sessionToken)
 }
+
+// GetSessionExpiry mocks base method.
+func (m *MockSessionRepository) GetSessionExpiry(ctx context.Context, sessionToken string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionExpiry", ctx, sessionToken)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionExpiry indicates an expected call of GetSessionExpiry.
+func (mr *MockSessionRepositoryMockRecorder) GetSessionExpiry(ctx, sessionToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionExpiry", reflect.TypeOf((*MockSessionRepository)(nil).GetSessionExpiry), ctx, sessionToken)
+}
+
+// ListSessions mocks base method.
+func (m *MockSessionRepository) ListSessions(ctx context.Context, userID int) ([]entity.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx, userID)
+	ret0, _ := ret[0].([]entity.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockSessionRepositoryMockRecorder) ListSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockSessionRepository)(nil).ListSessions), ctx, userID)
+}
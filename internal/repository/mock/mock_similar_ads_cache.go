@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/repository (interfaces: SimilarAdsCacheRepository)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/repository/mock/mock_similar_ads_cache.go github.com/AlexSamarskii/marketplace_vk_intern/internal/repository SimilarAdsCacheRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSimilarAdsCacheRepository is a mock of SimilarAdsCacheRepository interface.
+type MockSimilarAdsCacheRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSimilarAdsCacheRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSimilarAdsCacheRepositoryMockRecorder is the mock recorder for MockSimilarAdsCacheRepository.
+type MockSimilarAdsCacheRepositoryMockRecorder struct {
+	mock *MockSimilarAdsCacheRepository
+}
+
+// NewMockSimilarAdsCacheRepository creates a new mock instance.
+func NewMockSimilarAdsCacheRepository(ctrl *gomock.Controller) *MockSimilarAdsCacheRepository {
+	mock := &MockSimilarAdsCacheRepository{ctrl: ctrl}
+	mock.recorder = &MockSimilarAdsCacheRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSimilarAdsCacheRepository) EXPECT() *MockSimilarAdsCacheRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockSimilarAdsCacheRepository) Get(ctx context.Context, adID int) ([]entity.Advertisement, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, adID)
+	ret0, _ := ret[0].([]entity.Advertisement)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSimilarAdsCacheRepositoryMockRecorder) Get(ctx, adID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSimilarAdsCacheRepository)(nil).Get), ctx, adID)
+}
+
+// Set mocks base method.
+func (m *MockSimilarAdsCacheRepository) Set(ctx context.Context, adID int, ads []entity.Advertisement, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, adID, ads, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockSimilarAdsCacheRepositoryMockRecorder) Set(ctx, adID, ads, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockSimilarAdsCacheRepository)(nil).Set), ctx, adID, ads, ttl)
+}
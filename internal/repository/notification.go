@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type NotificationRepository interface {
+	// Create возвращает nil без ошибки, если тип отключен в настройках пользователя
+	// или уже есть непрочитанное уведомление с тем же DedupKey.
+	Create(ctx context.Context, notification *entity.Notification) (*entity.Notification, error)
+	GetByUserID(ctx context.Context, userID int, unreadOnly bool, offset, limit int) ([]entity.Notification, error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+	MarkRead(ctx context.Context, userID int, id int64) error
+	MarkAllRead(ctx context.Context, userID int) error
+	// GetPreferences возвращает только явно измененные настройки.
+	GetPreferences(ctx context.Context, userID int) (map[entity.NotificationType]bool, error)
+	SetPreferences(ctx context.Context, userID int, preferences map[entity.NotificationType]bool) error
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type Notifier interface {
+	Send(ctx context.Context, msg *entity.OutboundMessage) error
+}
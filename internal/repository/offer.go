@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type OfferRepository interface {
+	// Create сохраняет предложение покупателя, если объявление еще опубликовано.
+	Create(ctx context.Context, offer *entity.Offer) (*entity.Offer, error)
+	GetByID(ctx context.Context, id int) (*entity.Offer, error)
+	GetByUserID(ctx context.Context, userID, offset, limit int) ([]entity.Offer, error)
+	GetByAdvertisementID(ctx context.Context, adID, offset, limit int) ([]entity.Offer, error)
+	// Accept принимает предложение, резервирует объявление до orderDeadline и отклоняет
+	// остальные ожидающие предложения по нему. Возвращает принятое и автоматически отклоненные.
+	Accept(ctx context.Context, id, userID int, orderDeadline time.Time) (*entity.Offer, []entity.Offer, error)
+	Decline(ctx context.Context, id, userID int) (*entity.Offer, error)
+	// Counter закрывает предложение как countered и создает встречное от userID.
+	Counter(ctx context.Context, id, userID int, amount float64, expiresAt time.Time) (*entity.Offer, error)
+	// Withdraw отменяет принятое предложение, по которому еще не оформлен заказ,
+	// и снова публикует объявление.
+	Withdraw(ctx context.Context, id, userID int) (*entity.Offer, error)
+	// ExpirePending переводит просроченные ожидающие предложения в expired и возвращает их.
+	ExpirePending(ctx context.Context) ([]entity.Offer, error)
+	// ExpireAccepted переводит в expired принятые предложения, по которым не оформили
+	// заказ до срока, снимает резерв с их объявлений и возвращает их.
+	ExpireAccepted(ctx context.Context) ([]entity.Offer, error)
+}
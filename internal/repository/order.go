@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type OrderRepository interface {
+	// Create оформляет заказ и резервирует объявление. Если объявление зарезервировано
+	// принятым предложением этого покупателя, заказ оформляется по цене предложения.
+	Create(ctx context.Context, adID, buyerID int) (*entity.Order, error)
+	// GetByID возвращает заказ вместе с историей статусов.
+	GetByID(ctx context.Context, id int) (*entity.Order, error)
+	GetByUserID(ctx context.Context, userID, offset, limit int) ([]entity.Order, error)
+	// UpdateStatus переводит заказ в статус to от имени userID. Завершение заказа помечает
+	// объявление проданным, отмена снова публикует его.
+	UpdateStatus(ctx context.Context, id, userID int, to entity.OrderStatus) (*entity.Order, error)
+	HasCompleted(ctx context.Context, adID, buyerID int) (bool, error)
+}
@@ -0,0 +1,77 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// Notifier — реализация для локального запуска: сообщения не отправляются,
+// а дописываются строками JSON в файл. Без пути к файлу сообщения пишутся в лог.
+type Notifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+type record struct {
+	*entity.OutboundMessage
+	SentAt time.Time `json:"sent_at"`
+}
+
+func NewNotifier(path string) repository.Notifier {
+	return &Notifier{path: path}
+}
+
+func (n *Notifier) Send(ctx context.Context, msg *entity.OutboundMessage) error {
+	fields := logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    msg.UserID,
+		"to":        msg.To,
+		"subject":   msg.Subject,
+	}
+
+	if n.path == "" {
+		fields["body"] = msg.Body
+		l.Log.WithFields(fields).Info("исходящее сообщение")
+		return nil
+	}
+
+	line, err := json.Marshal(record{OutboundMessage: msg, SentAt: time.Now()})
+	if err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось сериализовать исходящее сообщение: %w", err),
+		)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось открыть файл outbox %s: %w", n.path, err),
+		)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось записать исходящее сообщение в %s: %w", n.path, err),
+		)
+	}
+
+	l.Log.WithFields(fields).Info("исходящее сообщение записано в outbox")
+	return nil
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type PasswordResetRepository interface {
+	// CreateToken сохраняет хеш токена; предыдущий токен пользователя перестает действовать.
+	CreateToken(ctx context.Context, userID int, tokenHash []byte, ttl time.Duration) error
+	// ConsumeToken возвращает id пользователя и удаляет токен, так что повторно его использовать нельзя.
+	ConsumeToken(ctx context.Context, tokenHash []byte) (int, error)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
@@ -19,6 +20,13 @@ type AdvertisementRepository struct {
 	DB *sql.DB
 }
 
+// adPromotedCondition — идет ли сейчас продвижение объявления a.
+const adPromotedCondition = `EXISTS (
+	SELECT 1 FROM promotion p
+	WHERE p.advertisement_id = a.id AND p.status = 'active'
+		AND p.starts_at <= NOW() AND p.ends_at > NOW()
+)`
+
 func NewAdvertisementRepository(db *sql.DB) (repository.AdvertisementRepository, error) {
 	return &AdvertisementRepository{DB: db}, nil
 }
@@ -32,9 +40,12 @@ func (r *AdvertisementRepository) Create(ctx context.Context, ad *entity.Adverti
 
 	query := `
 		INSERT INTO advertisement (
-			user_id, title, description, image_url, price, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
-		RETURNING id, user_id, title, description, image_url, price, created_at, updated_at
+			user_id, title, description, image_url, price, expires_at,
+			content_hash, image_hash, duplicate_of, moderation_status, moderation_note,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), NOW(), NOW())
+		RETURNING id, user_id, title, description, image_url, price, status,
+			expires_at, renewals_count, moderation_status, version, created_at, updated_at
 	`
 
 	var createdAd entity.Advertisement
@@ -46,6 +57,12 @@ func (r *AdvertisementRepository) Create(ctx context.Context, ad *entity.Adverti
 		ad.Description,
 		ad.ImageURL,
 		ad.Price,
+		ad.ExpiresAt,
+		int64(ad.Fingerprint.TextHash),
+		ad.Fingerprint.ImageHash,
+		ad.DuplicateOf,
+		ad.ModerationStatus,
+		ad.ModerationNote,
 	).Scan(
 		&createdAd.ID,
 		&createdAd.UserID,
@@ -53,6 +70,11 @@ func (r *AdvertisementRepository) Create(ctx context.Context, ad *entity.Adverti
 		&createdAd.Description,
 		&createdAd.ImageURL,
 		&createdAd.Price,
+		&createdAd.Status,
+		&createdAd.ExpiresAt,
+		&createdAd.RenewalsCount,
+		&createdAd.ModerationStatus,
+		&createdAd.Version,
 		&createdAd.CreatedAt,
 		&createdAd.UpdatedAt,
 	)
@@ -63,7 +85,7 @@ func (r *AdvertisementRepository) Create(ctx context.Context, ad *entity.Adverti
 			switch pqErr.Code {
 			case "23505": // unique_violation
 				return nil, entity.NewError(entity.ErrAlreadyExists,
-					fmt.Errorf("объявление с такими параметрами уже существует: %w", err))
+					fmt.Errorf("у пользователя уже есть активное объявление с таким же содержимым: %w", err))
 			case "23502": // not_null_violation
 				return nil, entity.NewError(entity.ErrBadRequest,
 					fmt.Errorf("обязательное поле отсутствует: %w", err))
@@ -97,9 +119,13 @@ func (r *AdvertisementRepository) GetByID(ctx context.Context, id int) (*entity.
 
 	query := `
 		SELECT 
-			a.id, a.user_id, a.title, a.description, a.image_url, a.price, 
-			a.created_at, a.updated_at
+			a.id, a.user_id, a.title, a.description, a.image_url, a.price, a.status,
+			a.expires_at, a.renewals_count, a.moderation_status, a.version, a.created_at, a.updated_at,
+			(u.email_verified OR u.phone_verified) AS author_verified,
+			u.rating_avg, u.rating_count,
+			` + adPromotedCondition + ` AS is_promoted
 		FROM advertisement a
+		JOIN uuser u ON a.user_id = u.id
 		WHERE a.id = $1
 	`
 
@@ -111,13 +137,25 @@ func (r *AdvertisementRepository) GetByID(ctx context.Context, id int) (*entity.
 		&ad.Description,
 		&ad.ImageURL,
 		&ad.Price,
+		&ad.Status,
+		&ad.ExpiresAt,
+		&ad.RenewalsCount,
+		&ad.ModerationStatus,
+		&ad.Version,
 		&ad.CreatedAt,
 		&ad.UpdatedAt,
+		&ad.AuthorVerified,
+		&ad.AuthorRating,
+		&ad.AuthorReviews,
+		&ad.IsPromoted,
 	)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("объявление с id=%d не найдено: %w", id, err)
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("объявление с id=%d не найдено: %w", id, err),
+			)
 		}
 
 		l.Log.WithFields(logrus.Fields{
@@ -146,7 +184,11 @@ func (r *AdvertisementRepository) GetAll(ctx context.Context, userID int, offset
 		order = "desc"
 	}
 
-	whereParts := []string{"1=1"}
+	// Лента показывает только объявления с неистекшим сроком публикации: истекшие
+	// попадают в архив фоновой задачей, но до ее запуска тоже скрываются.
+	// Объявления на модерации и отклоненные модератором в ленту не попадают, как и
+	// зарезервированные и проданные: купить их уже нельзя.
+	whereParts := []string{"a.status = 'published'", "a.expires_at > NOW()", "a.moderation_status = 'approved'"}
 	args := []interface{}{userID} // userID = $1
 	argPos := 2
 
@@ -167,17 +209,24 @@ func (r *AdvertisementRepository) GetAll(ctx context.Context, userID int, offset
 	offsetPos := len(args) + 2
 	args = append(args, limit, offset)
 
+	// Объявления с идущим продвижением закреплены сверху по приоритету уровня, остальные
+	// идут в выбранном порядке. a.id делает порядок однозначным, чтобы страницы не пересекались.
 	q := fmt.Sprintf(`
         SELECT 
-            a.id, a.user_id, a.title, a.description, a.image_url, a.price,
-            a.created_at, a.updated_at, u.login AS author_login,
-            (a.user_id = $1) AS is_mine
+            a.id, a.user_id, a.title, a.description, a.image_url, a.price, a.status,
+            a.expires_at, a.renewals_count, a.created_at, a.updated_at, u.login AS author_login,
+            (u.email_verified OR u.phone_verified) AS author_verified,
+            u.rating_avg, u.rating_count,
+            (a.user_id = $1) AS is_mine,
+            (p.id IS NOT NULL) AS is_promoted
         FROM advertisement a
         JOIN uuser u ON a.user_id = u.id
+        LEFT JOIN promotion p ON p.advertisement_id = a.id AND p.status = 'active'
+            AND p.starts_at <= NOW() AND p.ends_at > NOW()
         WHERE %s
-        ORDER BY a.%s %s
+        ORDER BY p.priority DESC NULLS LAST, a.%s %s, a.id %s
         LIMIT $%d OFFSET $%d
-    `, whereClause, sortBy, order, limitPos, offsetPos)
+    `, whereClause, sortBy, order, order, limitPos, offsetPos)
 
 	rows, err := r.DB.QueryContext(ctx, q, args...)
 	if err != nil {
@@ -202,10 +251,17 @@ func (r *AdvertisementRepository) GetAll(ctx context.Context, userID int, offset
 			&ad.Description,
 			&ad.ImageURL,
 			&ad.Price,
+			&ad.Status,
+			&ad.ExpiresAt,
+			&ad.RenewalsCount,
 			&ad.CreatedAt,
 			&ad.UpdatedAt,
 			&ad.AuthorLogin,
+			&ad.AuthorVerified,
+			&ad.AuthorRating,
+			&ad.AuthorReviews,
 			&ad.IsMine,
+			&ad.IsPromoted,
 		)
 		if err != nil {
 			l.Log.WithFields(logrus.Fields{
@@ -239,11 +295,15 @@ func (r *AdvertisementRepository) GetByUserID(ctx context.Context, userID int) (
 
 	query := `
 		SELECT 
-			id, user_id, title, description, image_url, price, 
-			created_at, updated_at
-		FROM advertisement
-		WHERE user_id = $1
-		ORDER BY created_at DESC
+			a.id, a.user_id, a.title, a.description, a.image_url, a.price, a.status,
+			a.expires_at, a.renewals_count, a.moderation_status, a.created_at, a.updated_at,
+			(u.email_verified OR u.phone_verified) AS author_verified,
+			u.rating_avg, u.rating_count,
+			` + adPromotedCondition + ` AS is_promoted
+		FROM advertisement a
+		JOIN uuser u ON a.user_id = u.id
+		WHERE a.user_id = $1
+		ORDER BY a.created_at DESC
 	`
 
 	rows, err := r.DB.QueryContext(ctx, query, userID)
@@ -275,8 +335,16 @@ func (r *AdvertisementRepository) GetByUserID(ctx context.Context, userID int) (
 			&ad.Description,
 			&ad.ImageURL,
 			&ad.Price,
+			&ad.Status,
+			&ad.ExpiresAt,
+			&ad.RenewalsCount,
+			&ad.ModerationStatus,
 			&ad.CreatedAt,
 			&ad.UpdatedAt,
+			&ad.AuthorVerified,
+			&ad.AuthorRating,
+			&ad.AuthorReviews,
+			&ad.IsPromoted,
 		)
 		if err != nil {
 			l.Log.WithFields(logrus.Fields{
@@ -300,3 +368,609 @@ func (r *AdvertisementRepository) GetByUserID(ctx context.Context, userID int) (
 
 	return ads, nil
 }
+
+// Update сохраняет новое содержимое объявления, если его версия все еще равна ad.Version,
+// и возвращает новую версию. Условие на статус повторяет проверку сервиса: объявление,
+// по которому началась сделка, не меняется.
+func (r *AdvertisementRepository) Update(ctx context.Context, ad *entity.Advertisement) (int, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"adID":      ad.ID,
+		"version":   ad.Version,
+	}).Info("SQL запрос: изменение объявления")
+
+	var version int
+	err := r.DB.QueryRowContext(ctx, `
+		UPDATE advertisement
+		SET title = $3, description = $4, image_url = $5, price = $6,
+			content_hash = $7, image_hash = $8, duplicate_of = $9,
+			moderation_status = $10, moderation_note = NULLIF($11, ''),
+			version = version + 1,
+			updated_at = NOW()
+		WHERE id = $1 AND version = $2 AND status NOT IN ('reserved', 'sold')
+		RETURNING version
+	`,
+		ad.ID,
+		ad.Version,
+		ad.Title,
+		ad.Description,
+		ad.ImageURL,
+		ad.Price,
+		int64(ad.Fingerprint.TextHash),
+		ad.Fingerprint.ImageHash,
+		ad.DuplicateOf,
+		ad.ModerationStatus,
+		ad.ModerationNote,
+	).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, r.staleWriteError(ctx, ad.ID, (*entity.Advertisement).CheckEditable)
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case entity.PSQLUniqueViolation:
+				return 0, entity.NewError(entity.ErrAlreadyExists,
+					fmt.Errorf("у пользователя уже есть активное объявление с таким же содержимым: %w", err))
+			case entity.PSQLCheckViolation:
+				return 0, entity.NewError(entity.ErrBadRequest,
+					fmt.Errorf("нарушено условие проверки: %w", err))
+			}
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"adID":      ad.ID,
+			"error":     err,
+		}).Error("Ошибка при изменении объявления")
+
+		return 0, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при изменении объявления: %w", err))
+	}
+
+	return version, nil
+}
+
+// Delete удаляет объявление, если его версия все еще равна version.
+// Зарезервированное объявление не удаляется.
+func (r *AdvertisementRepository) Delete(ctx context.Context, id, version int) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"adID":      id,
+		"version":   version,
+	}).Info("SQL запрос: удаление объявления")
+
+	res, err := r.DB.ExecContext(ctx, `
+		DELETE FROM advertisement
+		WHERE id = $1 AND version = $2 AND status <> 'reserved'
+	`, id, version)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"adID":      id,
+			"error":     err,
+		}).Error("Ошибка при удалении объявления")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при удалении объявления: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при удалении объявления: %w", err))
+	}
+	if affected == 0 {
+		return r.staleWriteError(ctx, id, (*entity.Advertisement).CheckDeletable)
+	}
+	return nil
+}
+
+// staleWriteError объясняет, почему условное изменение не затронуло строку: за время
+// между чтением и записью по объявлению могла начаться сделка (409), могла смениться
+// версия (412) или объявление могли удалить (404).
+func (r *AdvertisementRepository) staleWriteError(ctx context.Context, id int, check func(*entity.Advertisement) error) error {
+	current := &entity.Advertisement{ID: id}
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT status, version FROM advertisement WHERE id = $1
+	`, id).Scan(&current.Status, &current.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("объявление с id=%d не найдено: %w", id, err),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"adID":      id,
+			"error":     err,
+		}).Error("Ошибка при перечитывании объявления")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при перечитывании объявления: %w", err))
+	}
+
+	if err := check(current); err != nil {
+		return err
+	}
+	return entity.NewError(
+		entity.ErrPreconditionFailed,
+		entity.NewLocalizedMessage(entity.MsgAdVersionMismatch, entity.MessageParams{"id": id, "version": current.Version}),
+	)
+}
+
+// Renew продлевает публикацию до expiresAt и возвращает архивное объявление в ленту.
+// Условие в WHERE повторяет проверки сервиса, чтобы параллельные продления не превысили лимит.
+func (r *AdvertisementRepository) Renew(ctx context.Context, id int, expiresAt time.Time, maxRenewals int) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"adID":      id,
+	}).Info("SQL запрос: продление объявления")
+
+	res, err := r.DB.ExecContext(ctx, `
+		UPDATE advertisement
+		SET expires_at = $2,
+			renewals_count = renewals_count + 1,
+			expiry_notified_at = NULL,
+			version = version + 1,
+			status = CASE WHEN status = 'archived' THEN 'published' ELSE status END,
+			updated_at = NOW()
+		WHERE id = $1 AND status <> 'sold' AND renewals_count < $3
+	`, id, expiresAt, maxRenewals)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == entity.PSQLUniqueViolation {
+			return entity.NewError(
+				entity.ErrAlreadyExists,
+				fmt.Errorf("у пользователя уже есть активная копия объявления с id=%d", id),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"adID":      id,
+			"error":     err,
+		}).Error("Ошибка при продлении объявления")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при продлении объявления: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при продлении объявления: %w", err))
+	}
+	if affected == 0 {
+		return entity.NewError(
+			entity.ErrConflict,
+			fmt.Errorf("объявление с id=%d продано или продлено максимальное число раз", id),
+		)
+	}
+	return nil
+}
+
+// ClaimExpiring отмечает опубликованные объявления, срок которых истекает до deadline,
+// и возвращает их для предупреждения владельцев. Каждое объявление возвращается
+// один раз за срок публикации.
+func (r *AdvertisementRepository) ClaimExpiring(ctx context.Context, deadline time.Time, limit int) ([]entity.Advertisement, error) {
+	return r.updateExpiring(ctx, `
+		UPDATE advertisement SET expiry_notified_at = NOW()
+		WHERE id IN (
+			SELECT id FROM advertisement
+			WHERE status = 'published' AND expiry_notified_at IS NULL
+				AND expires_at > NOW() AND expires_at <= $1
+			ORDER BY expires_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, title, status, expires_at, renewals_count
+	`, "предупреждение об истечении объявлений", deadline, limit)
+}
+
+// ArchiveExpired переводит в архив опубликованные объявления с истекшим сроком.
+// Зарезервированные и проданные объявления не трогает.
+func (r *AdvertisementRepository) ArchiveExpired(ctx context.Context, limit int) ([]entity.Advertisement, error) {
+	return r.updateExpiring(ctx, `
+		UPDATE advertisement SET status = 'archived', version = version + 1, updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM advertisement
+			WHERE status = 'published' AND expires_at <= NOW()
+			ORDER BY expires_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, title, status, expires_at, renewals_count
+	`, "архивирование истекших объявлений", limit)
+}
+
+func (r *AdvertisementRepository) updateExpiring(ctx context.Context, query, action string, args ...interface{}) ([]entity.Advertisement, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Errorf("Ошибка SQL: %s", action)
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка SQL (%s): %w", action, err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	var ads []entity.Advertisement
+	for rows.Next() {
+		var ad entity.Advertisement
+		if err := rows.Scan(
+			&ad.ID,
+			&ad.UserID,
+			&ad.Title,
+			&ad.Status,
+			&ad.ExpiresAt,
+			&ad.RenewalsCount,
+		); err != nil {
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании объявления: %w", err))
+		}
+		ads = append(ads, ad)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по объявлениям: %w", err))
+	}
+
+	return ads, nil
+}
+
+// FindSimilar ищет активные объявления, текст которых отличается от отпечатка не больше
+// чем на 2*MaxDistance бит, ближайшие первыми. Объявления без отпечатка не участвуют.
+func (r *AdvertisementRepository) FindSimilar(ctx context.Context, fingerprint entity.AdFingerprint, filter entity.SimilarAdsFilter) ([]entity.SimilarAd, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    filter.UserID,
+	}).Info("SQL запрос: поиск похожих объявлений")
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT a.id, a.user_id, u.login, a.title, a.image_url, a.price, a.status, a.created_at,
+			s.distance, a.image_hash = $2 AS same_image
+		FROM advertisement a
+		JOIN uuser u ON a.user_id = u.id
+		CROSS JOIN LATERAL (SELECT bit_count((a.content_hash # $1)::bit(64))::int AS distance) s
+		WHERE a.content_hash IS NOT NULL
+			AND a.status IN ('published', 'reserved')
+			AND a.id <> $3
+			AND ($4 = 0 OR a.user_id = $4)
+			AND s.distance <= 2 * $5
+		ORDER BY s.distance, a.id DESC
+		LIMIT $6
+	`, int64(fingerprint.TextHash), fingerprint.ImageHash, filter.ExcludeID, filter.UserID, filter.MaxDistance, filter.Limit)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при поиске похожих объявлений")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при поиске похожих объявлений: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	var ads []entity.SimilarAd
+	for rows.Next() {
+		var ad entity.SimilarAd
+		if err := rows.Scan(
+			&ad.ID,
+			&ad.UserID,
+			&ad.AuthorLogin,
+			&ad.Title,
+			&ad.ImageURL,
+			&ad.Price,
+			&ad.Status,
+			&ad.CreatedAt,
+			&ad.Distance,
+			&ad.SameImage,
+		); err != nil {
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании объявления: %w", err))
+		}
+		ads = append(ads, ad)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по объявлениям: %w", err))
+	}
+
+	return ads, nil
+}
+
+// GetDuplicates возвращает активные объявления, помеченные как почти дубликаты, новые первыми.
+func (r *AdvertisementRepository) GetDuplicates(ctx context.Context, offset, limit int) ([]entity.Advertisement, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+	}).Info("SQL запрос: получение помеченных дубликатов")
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT a.id, a.user_id, u.login, a.title, a.image_url, a.price, a.status,
+			a.duplicate_of, a.created_at
+		FROM advertisement a
+		JOIN uuser u ON a.user_id = u.id
+		WHERE a.duplicate_of IS NOT NULL AND a.status IN ('published', 'reserved')
+		ORDER BY a.id DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при получении помеченных дубликатов")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении помеченных дубликатов: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	var ads []entity.Advertisement
+	for rows.Next() {
+		var (
+			ad          entity.Advertisement
+			duplicateOf sql.NullInt64
+		)
+		if err := rows.Scan(
+			&ad.ID,
+			&ad.UserID,
+			&ad.AuthorLogin,
+			&ad.Title,
+			&ad.ImageURL,
+			&ad.Price,
+			&ad.Status,
+			&duplicateOf,
+			&ad.CreatedAt,
+		); err != nil {
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании объявления: %w", err))
+		}
+		if duplicateOf.Valid {
+			id := int(duplicateOf.Int64)
+			ad.DuplicateOf = &id
+		}
+		ads = append(ads, ad)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по объявлениям: %w", err))
+	}
+
+	return ads, nil
+}
+
+// GetSimilar подбирает опубликованные объявления других авторов, похожие на ad, лучшие первыми.
+// Кандидаты совпадают хотя бы по одному слову или близки по заголовку в триграммах; оценка
+// складывается из релевантности текста, похожести заголовка и близости цены.
+// Когда у объявлений появятся категории, совпадение категории стоит добавить в оценку.
+func (r *AdvertisementRepository) GetSimilar(ctx context.Context, ad *entity.Advertisement, limit int) ([]entity.Advertisement, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"adID":      ad.ID,
+	}).Info("SQL запрос: подбор похожих объявлений")
+
+	// plainto_tsquery требует всех слов заголовка; замена & на | ищет любое из них.
+	query := `
+		WITH q AS (
+			SELECT replace(plainto_tsquery('russian', $1)::text, '&', '|')::tsquery AS query
+		)
+		SELECT
+			a.id, a.user_id, a.title, a.description, a.image_url, a.price, a.status,
+			a.expires_at, a.renewals_count, a.created_at, a.updated_at, u.login AS author_login,
+			(u.email_verified OR u.phone_verified) AS author_verified,
+			u.rating_avg, u.rating_count,
+			` + adPromotedCondition + ` AS is_promoted
+		FROM advertisement a
+		JOIN uuser u ON a.user_id = u.id
+		CROSS JOIN q
+		WHERE a.status = 'published' AND a.expires_at > NOW() AND a.moderation_status = 'approved'
+			AND a.id <> $2 AND a.user_id <> $3
+			AND (a.search_vector @@ q.query OR a.title % $1)
+		ORDER BY
+			0.5 * ts_rank(a.search_vector, q.query, 32)
+			+ 0.3 * similarity(a.title, $1)
+			+ 0.2 * (1 - abs(a.price - $4) / GREATEST(a.price, $4, 1)) DESC,
+			a.id DESC
+		LIMIT $5
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, ad.Title, ad.ID, ad.UserID, ad.Price, limit)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"adID":      ad.ID,
+			"error":     err,
+		}).Error("Ошибка при подборе похожих объявлений")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при подборе похожих объявлений: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	var ads []entity.Advertisement
+	for rows.Next() {
+		var similar entity.Advertisement
+		if err := rows.Scan(
+			&similar.ID,
+			&similar.UserID,
+			&similar.Title,
+			&similar.Description,
+			&similar.ImageURL,
+			&similar.Price,
+			&similar.Status,
+			&similar.ExpiresAt,
+			&similar.RenewalsCount,
+			&similar.CreatedAt,
+			&similar.UpdatedAt,
+			&similar.AuthorLogin,
+			&similar.AuthorVerified,
+			&similar.AuthorRating,
+			&similar.AuthorReviews,
+			&similar.IsPromoted,
+		); err != nil {
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании объявления: %w", err))
+		}
+		ads = append(ads, similar)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по объявлениям: %w", err))
+	}
+
+	return ads, nil
+}
+
+// GetPendingModeration возвращает объявления, ждущие модератора, старые первыми.
+func (r *AdvertisementRepository) GetPendingModeration(ctx context.Context, offset, limit int) ([]entity.Advertisement, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+	}).Info("SQL запрос: получение объявлений на модерации")
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT a.id, a.user_id, u.login, a.title, a.description, a.image_url, a.price, a.status,
+			a.moderation_status, COALESCE(a.moderation_note, ''), a.created_at
+		FROM advertisement a
+		JOIN uuser u ON a.user_id = u.id
+		WHERE a.moderation_status = 'pending'
+		ORDER BY a.id
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при получении объявлений на модерации")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении объявлений на модерации: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	var ads []entity.Advertisement
+	for rows.Next() {
+		var ad entity.Advertisement
+		if err := rows.Scan(
+			&ad.ID,
+			&ad.UserID,
+			&ad.AuthorLogin,
+			&ad.Title,
+			&ad.Description,
+			&ad.ImageURL,
+			&ad.Price,
+			&ad.Status,
+			&ad.ModerationStatus,
+			&ad.ModerationNote,
+			&ad.CreatedAt,
+		); err != nil {
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании объявления: %w", err))
+		}
+		ads = append(ads, ad)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по объявлениям: %w", err))
+	}
+
+	return ads, nil
+}
+
+// Moderate выносит решение по объявлению, ждущему модератора, и возвращает его автора и заголовок.
+func (r *AdvertisementRepository) Moderate(ctx context.Context, id int, status entity.ModerationStatus) (*entity.Advertisement, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"adID":      id,
+		"status":    status,
+	}).Info("SQL запрос: решение модератора по объявлению")
+
+	var ad entity.Advertisement
+	err := r.DB.QueryRowContext(ctx, `
+		UPDATE advertisement SET moderation_status = $2, version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND moderation_status = 'pending'
+		RETURNING id, user_id, title, moderation_status
+	`, id, status).Scan(&ad.ID, &ad.UserID, &ad.Title, &ad.ModerationStatus)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("объявление с id=%d не ждет модерации", id),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"adID":      id,
+			"error":     err,
+		}).Error("Ошибка при сохранении решения модератора")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при сохранении решения модератора: %w", err))
+	}
+
+	return &ad, nil
+}
@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedResult — ответ базы на очередной запрос: строки для SELECT/RETURNING
+// или число затронутых строк для Exec.
+type scriptedResult struct {
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+}
+
+// scriptedConnector отдает заранее заданные результаты по порядку запросов.
+type scriptedConnector struct {
+	mu      sync.Mutex
+	results []scriptedResult
+	next    int
+}
+
+func newScriptedDB(t *testing.T, results ...scriptedResult) (*sql.DB, *scriptedConnector) {
+	connector := &scriptedConnector{results: results}
+	db := sql.OpenDB(connector)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, connector
+}
+
+func (c *scriptedConnector) pop() (scriptedResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.next == len(c.results) {
+		return scriptedResult{}, errors.New("неожиданный запрос")
+	}
+	result := c.results[c.next]
+	c.next++
+	return result, nil
+}
+
+func (c *scriptedConnector) Connect(context.Context) (driver.Conn, error) {
+	return &scriptedConn{connector: c}, nil
+}
+
+func (c *scriptedConnector) Driver() driver.Driver { return scriptedDriver{} }
+
+type scriptedDriver struct{}
+
+func (scriptedDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("используйте sql.OpenDB")
+}
+
+type scriptedConn struct {
+	connector *scriptedConnector
+}
+
+func (c *scriptedConn) Prepare(string) (driver.Stmt, error) {
+	return &scriptedStmt{connector: c.connector}, nil
+}
+
+func (c *scriptedConn) Close() error { return nil }
+
+func (c *scriptedConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("транзакции не поддерживаются")
+}
+
+type scriptedStmt struct {
+	connector *scriptedConnector
+}
+
+func (s *scriptedStmt) Close() error  { return nil }
+func (s *scriptedStmt) NumInput() int { return -1 }
+
+func (s *scriptedStmt) Exec([]driver.Value) (driver.Result, error) {
+	result, err := s.connector.pop()
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(result.affected), nil
+}
+
+func (s *scriptedStmt) Query([]driver.Value) (driver.Rows, error) {
+	result, err := s.connector.pop()
+	if err != nil {
+		return nil, err
+	}
+	return &scriptedRows{columns: result.columns, rows: result.rows}, nil
+}
+
+type scriptedRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *scriptedRows) Columns() []string { return r.columns }
+func (r *scriptedRows) Close() error      { return nil }
+
+func (r *scriptedRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// currentAd — строка, которую перечитывает репозиторий после условной записи без результата.
+func currentAd(status entity.AdStatus, version int64) scriptedResult {
+	return scriptedResult{
+		columns: []string{"status", "version"},
+		rows:    [][]driver.Value{{string(status), version}},
+	}
+}
+
+var adGone = scriptedResult{columns: []string{"status", "version"}}
+
+func TestAdvertisementRepository_UpdateStale(t *testing.T) {
+	t.Parallel()
+
+	noReturning := scriptedResult{columns: []string{"version"}}
+
+	testCases := []struct {
+		name        string
+		current     scriptedResult
+		expectedErr error
+	}{
+		{
+			name:        "Объявление зарезервировали между чтением и записью",
+			current:     currentAd(entity.AdReserved, 2),
+			expectedErr: entity.ErrConflict,
+		},
+		{
+			name:        "Объявление продали между чтением и записью",
+			current:     currentAd(entity.AdSold, 2),
+			expectedErr: entity.ErrConflict,
+		},
+		{
+			name:        "Версия сменилась",
+			current:     currentAd(entity.AdPublished, 3),
+			expectedErr: entity.ErrPreconditionFailed,
+		},
+		{
+			name:        "Объявление удалили",
+			current:     adGone,
+			expectedErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, connector := newScriptedDB(t, noReturning, tc.current)
+			repo := &AdvertisementRepository{DB: db}
+
+			_, err := repo.Update(context.Background(), &entity.Advertisement{ID: 10, Version: 2})
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, len(connector.results), connector.next)
+		})
+	}
+}
+
+func TestAdvertisementRepository_DeleteStale(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		results     []scriptedResult
+		expectedErr error
+	}{
+		{
+			name:    "Удаление прошло",
+			results: []scriptedResult{{affected: 1}},
+		},
+		{
+			name:        "Объявление зарезервировали между чтением и записью",
+			results:     []scriptedResult{{affected: 0}, currentAd(entity.AdReserved, 2)},
+			expectedErr: entity.ErrConflict,
+		},
+		{
+			name:        "Проданное объявление с новой версией",
+			results:     []scriptedResult{{affected: 0}, currentAd(entity.AdSold, 3)},
+			expectedErr: entity.ErrPreconditionFailed,
+		},
+		{
+			name:        "Объявление уже удалили",
+			results:     []scriptedResult{{affected: 0}, adGone},
+			expectedErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, connector := newScriptedDB(t, tc.results...)
+			repo := &AdvertisementRepository{DB: db}
+
+			err := repo.Delete(context.Background(), 10, 2)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, len(connector.results), connector.next)
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type APIKeyRepository struct {
+	DB *sql.DB
+}
+
+type ScanAPIKey struct {
+	ID         int
+	UserID     int
+	Name       string
+	Prefix     string
+	KeyHash    []byte
+	Scopes     pq.StringArray
+	ExpiresAt  sql.NullTime
+	LastUsedAt sql.NullTime
+	CreatedAt  sql.NullTime
+}
+
+func (k *ScanAPIKey) GetEntity() *entity.APIKey {
+	key := &entity.APIKey{
+		ID:        k.ID,
+		UserID:    k.UserID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		KeyHash:   k.KeyHash,
+		Scopes:    []string(k.Scopes),
+		CreatedAt: k.CreatedAt.Time,
+	}
+	if k.ExpiresAt.Valid {
+		key.ExpiresAt = &k.ExpiresAt.Time
+	}
+	if k.LastUsedAt.Valid {
+		key.LastUsedAt = &k.LastUsedAt.Time
+	}
+	return key
+}
+
+func (k *ScanAPIKey) fields() []interface{} {
+	return []interface{}{
+		&k.ID,
+		&k.UserID,
+		&k.Name,
+		&k.Prefix,
+		&k.KeyHash,
+		&k.Scopes,
+		&k.ExpiresAt,
+		&k.LastUsedAt,
+		&k.CreatedAt,
+	}
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_at`
+
+func NewAPIKeyRepository(db *sql.DB) (repository.APIKeyRepository, error) {
+	return &APIKeyRepository{DB: db}, nil
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *entity.APIKey) (*entity.APIKey, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    key.UserID,
+	}).Info("SQL запрос: создание API-ключа")
+
+	query := `
+		INSERT INTO api_key (user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + apiKeyColumns
+
+	var scanKey ScanAPIKey
+	err := r.DB.QueryRowContext(ctx, query,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		pq.Array(key.Scopes),
+		key.ExpiresAt,
+	).Scan(scanKey.fields()...)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case entity.PSQLUniqueViolation:
+				return nil, entity.NewError(
+					entity.ErrAlreadyExists,
+					fmt.Errorf("такой API-ключ уже существует"),
+				)
+			case entity.PSQLCheckViolation:
+				return nil, entity.NewError(
+					entity.ErrBadRequest,
+					fmt.Errorf("неправильные данные API-ключа"),
+				)
+			}
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при создании API-ключа")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при создании API-ключа: %w", err))
+	}
+
+	return scanKey.GetEntity(), nil
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash []byte) (*entity.APIKey, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+	}).Info("SQL запрос: получение API-ключа по хешу")
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_key WHERE key_hash = $1`
+
+	var scanKey ScanAPIKey
+	err := r.DB.QueryRowContext(ctx, query, keyHash).Scan(scanKey.fields()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("API-ключ не найден"),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при получении API-ключа")
+
+		return nil, entity.NewError(entity.ErrInternal, err)
+	}
+
+	return scanKey.GetEntity(), nil
+}
+
+func (r *APIKeyRepository) GetByUserID(ctx context.Context, userID int) ([]entity.APIKey, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: получение API-ключей пользователя")
+
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_key
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при получении API-ключей пользователя")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении API-ключей пользователя: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	var keys []entity.APIKey
+	for rows.Next() {
+		var scanKey ScanAPIKey
+		if err := rows.Scan(scanKey.fields()...); err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+				"error":     err,
+			}).Error("Ошибка при сканировании API-ключа")
+
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании API-ключа: %w", err))
+		}
+		keys = append(keys, *scanKey.GetEntity())
+	}
+
+	if err := rows.Err(); err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при итерации по API-ключам")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по API-ключам: %w", err))
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) CountByUserID(ctx context.Context, userID int) (int, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: подсчёт API-ключей пользователя")
+
+	var count int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM api_key WHERE user_id = $1`, userID).Scan(&count)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при подсчёте API-ключей пользователя")
+
+		return 0, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при подсчёте API-ключей пользователя: %w", err))
+	}
+
+	return count, nil
+}
+
+func (r *APIKeyRepository) UpdateName(ctx context.Context, userID, id int, name string) (*entity.APIKey, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+		"keyID":     id,
+	}).Info("SQL запрос: переименование API-ключа")
+
+	query := `
+		UPDATE api_key SET name = $1
+		WHERE id = $2 AND user_id = $3
+		RETURNING ` + apiKeyColumns
+
+	var scanKey ScanAPIKey
+	err := r.DB.QueryRowContext(ctx, query, name, id, userID).Scan(scanKey.fields()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("API-ключ с id=%d не найден", id),
+			)
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == entity.PSQLCheckViolation {
+			return nil, entity.NewError(
+				entity.ErrBadRequest,
+				fmt.Errorf("неправильные данные API-ключа"),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"keyID":     id,
+			"error":     err,
+		}).Error("Ошибка при переименовании API-ключа")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при переименовании API-ключа: %w", err))
+	}
+
+	return scanKey.GetEntity(), nil
+}
+
+func (r *APIKeyRepository) Delete(ctx context.Context, userID, id int) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+		"keyID":     id,
+	}).Info("SQL запрос: удаление API-ключа")
+
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM api_key WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"keyID":     id,
+			"error":     err,
+		}).Error("Ошибка при удалении API-ключа")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при удалении API-ключа: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при удалении API-ключа: %w", err))
+	}
+	if affected == 0 {
+		return entity.NewError(
+			entity.ErrNotFound,
+			fmt.Errorf("API-ключ с id=%d не найден", id),
+		)
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id int) error {
+	requestID := utils.GetRequestID(ctx)
+
+	_, err := r.DB.ExecContext(ctx, `UPDATE api_key SET last_used_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"keyID":     id,
+			"error":     err,
+		}).Error("Ошибка при обновлении времени использования API-ключа")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при обновлении времени использования API-ключа: %w", err))
+	}
+
+	return nil
+}
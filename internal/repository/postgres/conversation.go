@@ -0,0 +1,429 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type ConversationRepository struct {
+	DB *sql.DB
+}
+
+type ScanConversation struct {
+	ID                 int
+	AdvertisementID    sql.NullInt64
+	AdvertisementTitle sql.NullString
+	BuyerID            int
+	BuyerLogin         string
+	SellerID           int
+	SellerLogin        string
+	BuyerLastReadID    int64
+	SellerLastReadID   int64
+	BlockedBy          sql.NullInt64
+	LastMessageAt      sql.NullTime
+	Unread             int
+	CreatedAt          sql.NullTime
+	UpdatedAt          sql.NullTime
+}
+
+func (s *ScanConversation) GetEntity() *entity.Conversation {
+	c := &entity.Conversation{
+		ID:                 s.ID,
+		AdvertisementTitle: s.AdvertisementTitle.String,
+		BuyerID:            s.BuyerID,
+		BuyerLogin:         s.BuyerLogin,
+		SellerID:           s.SellerID,
+		SellerLogin:        s.SellerLogin,
+		BuyerLastReadID:    s.BuyerLastReadID,
+		SellerLastReadID:   s.SellerLastReadID,
+		LastMessageAt:      nullTimePtr(s.LastMessageAt),
+		Unread:             s.Unread,
+		CreatedAt:          s.CreatedAt.Time,
+		UpdatedAt:          s.UpdatedAt.Time,
+	}
+	if s.AdvertisementID.Valid {
+		adID := int(s.AdvertisementID.Int64)
+		c.AdvertisementID = &adID
+	}
+	if s.BlockedBy.Valid {
+		blockedBy := int(s.BlockedBy.Int64)
+		c.BlockedBy = &blockedBy
+	}
+	return c
+}
+
+func (s *ScanConversation) fields() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.AdvertisementID,
+		&s.AdvertisementTitle,
+		&s.BuyerID,
+		&s.BuyerLogin,
+		&s.SellerID,
+		&s.SellerLogin,
+		&s.BuyerLastReadID,
+		&s.SellerLastReadID,
+		&s.BlockedBy,
+		&s.LastMessageAt,
+		&s.Unread,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	}
+}
+
+// conversationSelect выбирает переписки вместе с логинами участников и числом
+// непрочитанных сообщений для пользователя $1.
+const conversationSelect = `
+	SELECT
+		c.id, c.advertisement_id, a.title, c.buyer_id, ub.login, c.seller_id, us.login,
+		c.buyer_last_read_id, c.seller_last_read_id, c.blocked_by, c.last_message_at,
+		(
+			SELECT COUNT(*) FROM message m
+			WHERE m.conversation_id = c.id AND m.sender_id <> $1
+				AND m.id > CASE WHEN c.buyer_id = $1 THEN c.buyer_last_read_id ELSE c.seller_last_read_id END
+		) AS unread,
+		c.created_at, c.updated_at
+	FROM conversation c
+	LEFT JOIN advertisement a ON a.id = c.advertisement_id
+	JOIN uuser ub ON ub.id = c.buyer_id
+	JOIN uuser us ON us.id = c.seller_id
+`
+
+func NewConversationRepository(db *sql.DB) (repository.ConversationRepository, error) {
+	return &ConversationRepository{DB: db}, nil
+}
+
+// GetOrCreate возвращает переписку покупателя по объявлению, создавая ее при первом обращении.
+func (r *ConversationRepository) GetOrCreate(ctx context.Context, adID, buyerID, sellerID int) (*entity.Conversation, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"adID":      adID,
+		"buyerID":   buyerID,
+	}).Info("SQL запрос: получение или создание переписки")
+
+	var id int
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO conversation (advertisement_id, buyer_id, seller_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (advertisement_id, buyer_id) DO UPDATE SET advertisement_id = EXCLUDED.advertisement_id
+		RETURNING id
+	`, adID, buyerID, sellerID).Scan(&id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == entity.PSQLCheckViolation {
+			return nil, entity.NewError(
+				entity.ErrBadRequest,
+				fmt.Errorf("нельзя начать переписку с самим собой"),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при создании переписки")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при создании переписки: %w", err))
+	}
+
+	return r.GetByID(ctx, id, buyerID)
+}
+
+func (r *ConversationRepository) GetByID(ctx context.Context, id, viewerID int) (*entity.Conversation, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID":      requestID,
+		"conversationID": id,
+	}).Info("SQL запрос: получение переписки по ID")
+
+	var scanConversation ScanConversation
+	err := r.DB.QueryRowContext(ctx, conversationSelect+` WHERE c.id = $2`, viewerID, id).
+		Scan(scanConversation.fields()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("переписка с id=%d не найдена", id),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID":      requestID,
+			"conversationID": id,
+			"error":          err,
+		}).Error("Ошибка при получении переписки")
+
+		return nil, entity.NewError(entity.ErrInternal, err)
+	}
+
+	return scanConversation.GetEntity(), nil
+}
+
+// GetByUserID возвращает переписки пользователя, сначала с самыми свежими сообщениями.
+func (r *ConversationRepository) GetByUserID(ctx context.Context, userID, offset, limit int) ([]entity.Conversation, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: получение переписок пользователя")
+
+	query := conversationSelect + `
+		WHERE c.buyer_id = $1 OR c.seller_id = $1
+		ORDER BY COALESCE(c.last_message_at, c.created_at) DESC, c.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при получении переписок пользователя")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении переписок пользователя: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	conversations := make([]entity.Conversation, 0, limit)
+	for rows.Next() {
+		var scanConversation ScanConversation
+		if err := rows.Scan(scanConversation.fields()...); err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+				"error":     err,
+			}).Error("Ошибка при сканировании переписки")
+
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании переписки: %w", err))
+		}
+		conversations = append(conversations, *scanConversation.GetEntity())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по перепискам: %w", err))
+	}
+
+	return conversations, nil
+}
+
+// CountUnread считает непрочитанные сообщения пользователя во всех переписках.
+func (r *ConversationRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	var unread int
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM message m
+		JOIN conversation c ON c.id = m.conversation_id
+		WHERE (c.buyer_id = $1 OR c.seller_id = $1) AND m.sender_id <> $1
+			AND m.id > CASE WHEN c.buyer_id = $1 THEN c.buyer_last_read_id ELSE c.seller_last_read_id END
+	`, userID).Scan(&unread)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при подсчете непрочитанных сообщений")
+
+		return 0, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при подсчете непрочитанных сообщений: %w", err))
+	}
+
+	return unread, nil
+}
+
+func (r *ConversationRepository) SetBlockedBy(ctx context.Context, id int, blockedBy *int) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID":      requestID,
+		"conversationID": id,
+	}).Info("SQL запрос: изменение блокировки переписки")
+
+	_, err := r.DB.ExecContext(ctx, `
+		UPDATE conversation SET blocked_by = $1, updated_at = NOW() WHERE id = $2
+	`, blockedBy, id)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID":      requestID,
+			"conversationID": id,
+			"error":          err,
+		}).Error("Ошибка при изменении блокировки переписки")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при изменении блокировки переписки: %w", err))
+	}
+
+	return nil
+}
+
+// CreateMessage сохраняет сообщение. Для отправителя оно сразу считается прочитанным.
+func (r *ConversationRepository) CreateMessage(ctx context.Context, msg *entity.Message) (*entity.Message, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID":      requestID,
+		"conversationID": msg.ConversationID,
+		"senderID":       msg.SenderID,
+	}).Info("SQL запрос: отправка сообщения")
+
+	created := entity.Message{
+		ConversationID: msg.ConversationID,
+		SenderID:       msg.SenderID,
+		Body:           msg.Body,
+	}
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO message (conversation_id, sender_id, body)
+			VALUES ($1, $2, $3)
+			RETURNING id, created_at
+		`, msg.ConversationID, msg.SenderID, msg.Body).Scan(&created.ID, &created.CreatedAt)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == entity.PSQLCheckViolation {
+				return entity.NewError(
+					entity.ErrBadRequest,
+					fmt.Errorf("неправильный текст сообщения"),
+				)
+			}
+			return fmt.Errorf("ошибка при сохранении сообщения: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE conversation SET
+				last_message_at = $2,
+				buyer_last_read_id = CASE WHEN buyer_id = $3 THEN $4 ELSE buyer_last_read_id END,
+				seller_last_read_id = CASE WHEN seller_id = $3 THEN $4 ELSE seller_last_read_id END
+			WHERE id = $1
+		`, msg.ConversationID, created.CreatedAt, msg.SenderID, created.ID)
+		if err != nil {
+			return fmt.Errorf("ошибка при обновлении переписки: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GetMessages возвращает до limit сообщений с id меньше beforeID, новые первыми.
+// beforeID = 0 означает начало с самого нового сообщения.
+func (r *ConversationRepository) GetMessages(ctx context.Context, conversationID int, beforeID int64, limit int) ([]entity.Message, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID":      requestID,
+		"conversationID": conversationID,
+	}).Info("SQL запрос: получение сообщений переписки")
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, conversation_id, sender_id, body, created_at
+		FROM message
+		WHERE conversation_id = $1 AND ($2 = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`, conversationID, beforeID, limit)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID":      requestID,
+			"conversationID": conversationID,
+			"error":          err,
+		}).Error("Ошибка при получении сообщений")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении сообщений: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	messages := make([]entity.Message, 0, limit)
+	for rows.Next() {
+		var msg entity.Message
+		if err := rows.Scan(&msg.ID, &msg.ConversationID, &msg.SenderID, &msg.Body, &msg.CreatedAt); err != nil {
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании сообщения: %w", err))
+		}
+		messages = append(messages, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по сообщениям: %w", err))
+	}
+
+	return messages, nil
+}
+
+// MarkRead сдвигает отметку о прочтении пользователя до upToID, но не дальше последнего сообщения.
+// Отметка никогда не сдвигается назад.
+func (r *ConversationRepository) MarkRead(ctx context.Context, conversationID, userID int, upToID int64) (int64, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID":      requestID,
+		"conversationID": conversationID,
+		"userID":         userID,
+	}).Info("SQL запрос: отметка о прочтении переписки")
+
+	var lastReadID int64
+	err := r.DB.QueryRowContext(ctx, `
+		WITH last AS (
+			SELECT LEAST($3, COALESCE(MAX(id), 0)) AS id FROM message WHERE conversation_id = $1
+		)
+		UPDATE conversation SET
+			buyer_last_read_id = CASE WHEN buyer_id = $2
+				THEN GREATEST(buyer_last_read_id, (SELECT id FROM last)) ELSE buyer_last_read_id END,
+			seller_last_read_id = CASE WHEN seller_id = $2
+				THEN GREATEST(seller_last_read_id, (SELECT id FROM last)) ELSE seller_last_read_id END
+		WHERE id = $1
+		RETURNING CASE WHEN buyer_id = $2 THEN buyer_last_read_id ELSE seller_last_read_id END
+	`, conversationID, userID, upToID).Scan(&lastReadID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("переписка с id=%d не найдена", conversationID),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID":      requestID,
+			"conversationID": conversationID,
+			"error":          err,
+		}).Error("Ошибка при отметке о прочтении")
+
+		return 0, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при отметке о прочтении: %w", err))
+	}
+
+	return lastReadID, nil
+}
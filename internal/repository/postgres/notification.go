@@ -0,0 +1,328 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type NotificationRepository struct {
+	DB *sql.DB
+}
+
+type ScanNotification struct {
+	ID        int64
+	UserID    int
+	Type      string
+	Data      []byte
+	DedupKey  sql.NullString
+	ReadAt    sql.NullTime
+	CreatedAt sql.NullTime
+}
+
+func (s *ScanNotification) GetEntity() *entity.Notification {
+	return &entity.Notification{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		Type:      entity.NotificationType(s.Type),
+		Data:      s.Data,
+		DedupKey:  s.DedupKey.String,
+		ReadAt:    nullTimePtr(s.ReadAt),
+		CreatedAt: s.CreatedAt.Time,
+	}
+}
+
+func (s *ScanNotification) fields() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.UserID,
+		&s.Type,
+		&s.Data,
+		&s.DedupKey,
+		&s.ReadAt,
+		&s.CreatedAt,
+	}
+}
+
+const notificationColumns = `id, user_id, type, data, dedup_key, read_at, created_at`
+
+func NewNotificationRepository(db *sql.DB) (repository.NotificationRepository, error) {
+	return &NotificationRepository{DB: db}, nil
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, notification *entity.Notification) (*entity.Notification, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    notification.UserID,
+		"type":      notification.Type,
+	}).Info("SQL запрос: создание уведомления")
+
+	query := `
+		INSERT INTO notification (user_id, type, data, dedup_key)
+		SELECT $1, $2, $3, $4
+		WHERE NOT EXISTS (
+			SELECT 1 FROM notification_preference
+			WHERE user_id = $1 AND type = $2 AND NOT enabled
+		)
+		ON CONFLICT (user_id, dedup_key) WHERE read_at IS NULL AND dedup_key IS NOT NULL DO NOTHING
+		RETURNING ` + notificationColumns
+
+	dedupKey := sql.NullString{String: notification.DedupKey, Valid: notification.DedupKey != ""}
+
+	var scanNotification ScanNotification
+	err := r.DB.QueryRowContext(ctx, query,
+		notification.UserID,
+		notification.Type,
+		[]byte(notification.Data),
+		dedupKey,
+	).Scan(scanNotification.fields()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    notification.UserID,
+			"error":     err,
+		}).Error("Ошибка при создании уведомления")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при создании уведомления: %w", err))
+	}
+
+	return scanNotification.GetEntity(), nil
+}
+
+// GetByUserID возвращает уведомления пользователя, новые первыми.
+func (r *NotificationRepository) GetByUserID(ctx context.Context, userID int, unreadOnly bool, offset, limit int) ([]entity.Notification, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID":  requestID,
+		"userID":     userID,
+		"unreadOnly": unreadOnly,
+	}).Info("SQL запрос: получение уведомлений пользователя")
+
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notification
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID, unreadOnly, limit, offset)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при получении уведомлений")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении уведомлений: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	notifications := make([]entity.Notification, 0, limit)
+	for rows.Next() {
+		var scanNotification ScanNotification
+		if err := rows.Scan(scanNotification.fields()...); err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+				"error":     err,
+			}).Error("Ошибка при сканировании уведомления")
+
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании уведомления: %w", err))
+		}
+		notifications = append(notifications, *scanNotification.GetEntity())
+	}
+
+	if err := rows.Err(); err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка после сканирования уведомлений")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка после сканирования уведомлений: %w", err))
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	var unread int
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM notification WHERE user_id = $1 AND read_at IS NULL`,
+		userID,
+	).Scan(&unread)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при подсчете непрочитанных уведомлений")
+
+		return 0, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при подсчете непрочитанных уведомлений: %w", err))
+	}
+
+	return unread, nil
+}
+
+// MarkRead не различает чужое и несуществующее уведомление.
+// Повторная отметка уже прочитанного уведомления не считается ошибкой.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID int, id int64) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID":      requestID,
+		"userID":         userID,
+		"notificationID": id,
+	}).Info("SQL запрос: отметка уведомления прочитанным")
+
+	res, err := r.DB.ExecContext(ctx, `
+		UPDATE notification SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+	`, id, userID)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID":      requestID,
+			"notificationID": id,
+			"error":          err,
+		}).Error("Ошибка при отметке уведомления прочитанным")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при отметке уведомления прочитанным: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.NewError(entity.ErrInternal, err)
+	}
+	if affected == 0 {
+		return entity.NewError(
+			entity.ErrNotFound,
+			fmt.Errorf("уведомление с id=%d не найдено", id),
+		)
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID int) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: отметка всех уведомлений прочитанными")
+
+	_, err := r.DB.ExecContext(ctx, `
+		UPDATE notification SET read_at = NOW()
+		WHERE user_id = $1 AND read_at IS NULL
+	`, userID)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при отметке всех уведомлений прочитанными")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при отметке всех уведомлений прочитанными: %w", err))
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID int) (map[entity.NotificationType]bool, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT type, enabled FROM notification_preference WHERE user_id = $1`,
+		userID,
+	)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при получении настроек уведомлений")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении настроек уведомлений: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	preferences := make(map[entity.NotificationType]bool)
+	for rows.Next() {
+		var (
+			notificationType string
+			enabled          bool
+		)
+		if err := rows.Scan(&notificationType, &enabled); err != nil {
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании настроек уведомлений: %w", err))
+		}
+		preferences[entity.NotificationType(notificationType)] = enabled
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка после сканирования настроек уведомлений: %w", err))
+	}
+
+	return preferences, nil
+}
+
+func (r *NotificationRepository) SetPreferences(ctx context.Context, userID int, preferences map[entity.NotificationType]bool) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: сохранение настроек уведомлений")
+
+	return withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		for notificationType, enabled := range preferences {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO notification_preference (user_id, type, enabled)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (user_id, type) DO UPDATE SET enabled = EXCLUDED.enabled
+			`, userID, notificationType, enabled)
+			if err != nil {
+				return fmt.Errorf("ошибка при сохранении настройки уведомлений %s: %w", notificationType, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,684 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type OfferRepository struct {
+	DB *sql.DB
+}
+
+type ScanOffer struct {
+	ID                 int
+	AdvertisementID    int
+	AdvertisementTitle string
+	BuyerID            int
+	SellerID           int
+	ParentID           sql.NullInt64
+	ProposedBy         int
+	Amount             float64
+	Status             string
+	ExpiresAt          time.Time
+	RespondedAt        sql.NullTime
+	CreatedAt          sql.NullTime
+	UpdatedAt          sql.NullTime
+}
+
+func (s *ScanOffer) GetEntity() *entity.Offer {
+	o := &entity.Offer{
+		ID:                 s.ID,
+		AdvertisementID:    s.AdvertisementID,
+		AdvertisementTitle: s.AdvertisementTitle,
+		BuyerID:            s.BuyerID,
+		SellerID:           s.SellerID,
+		ProposedBy:         s.ProposedBy,
+		Amount:             s.Amount,
+		Status:             entity.OfferStatus(s.Status),
+		ExpiresAt:          s.ExpiresAt,
+		RespondedAt:        nullTimePtr(s.RespondedAt),
+		CreatedAt:          s.CreatedAt.Time,
+		UpdatedAt:          s.UpdatedAt.Time,
+	}
+	if s.ParentID.Valid {
+		parentID := int(s.ParentID.Int64)
+		o.ParentID = &parentID
+	}
+	return o
+}
+
+func (s *ScanOffer) fields() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.AdvertisementID,
+		&s.AdvertisementTitle,
+		&s.BuyerID,
+		&s.SellerID,
+		&s.ParentID,
+		&s.ProposedBy,
+		&s.Amount,
+		&s.Status,
+		&s.ExpiresAt,
+		&s.RespondedAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	}
+}
+
+// offerColumns выбирает предложение o вместе с заголовком объявления a.
+const offerColumns = `o.id, o.advertisement_id, a.title, o.buyer_id, o.seller_id, o.parent_id,
+	o.proposed_by, o.amount, o.status, o.expires_at, o.responded_at, o.created_at, o.updated_at`
+
+const offerSelect = `
+	SELECT ` + offerColumns + `
+	FROM offer o
+	JOIN advertisement a ON a.id = o.advertisement_id
+`
+
+func NewOfferRepository(db *sql.DB) (repository.OfferRepository, error) {
+	return &OfferRepository{DB: db}, nil
+}
+
+func (r *OfferRepository) Create(ctx context.Context, offer *entity.Offer) (*entity.Offer, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"adID":      offer.AdvertisementID,
+		"buyerID":   offer.BuyerID,
+	}).Info("SQL запрос: создание предложения цены")
+
+	var id int
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		// Блокировка объявления не дает принять другое предложение, пока создается это.
+		if err := lockPublishedAd(ctx, tx, offer.AdvertisementID); err != nil {
+			return err
+		}
+
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO offer (advertisement_id, buyer_id, seller_id, proposed_by, amount, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`,
+			offer.AdvertisementID,
+			offer.BuyerID,
+			offer.SellerID,
+			offer.BuyerID,
+			offer.Amount,
+			offer.ExpiresAt,
+		).Scan(&id)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) {
+				switch pqErr.Code {
+				case entity.PSQLUniqueViolation:
+					return entity.NewError(
+						entity.ErrAlreadyExists,
+						fmt.Errorf("по этому объявлению уже есть предложение, ожидающее ответа"),
+					)
+				case entity.PSQLCheckViolation:
+					return entity.NewError(
+						entity.ErrBadRequest,
+						fmt.Errorf("неправильные данные предложения"),
+					)
+				}
+			}
+			return fmt.Errorf("ошибка при создании предложения: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *OfferRepository) GetByID(ctx context.Context, id int) (*entity.Offer, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"offerID":   id,
+	}).Info("SQL запрос: получение предложения по ID")
+
+	var scanOffer ScanOffer
+	err := r.DB.QueryRowContext(ctx, offerSelect+` WHERE o.id = $1`, id).Scan(scanOffer.fields()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("предложение с id=%d не найдено", id),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"offerID":   id,
+			"error":     err,
+		}).Error("Ошибка при получении предложения")
+
+		return nil, entity.NewError(entity.ErrInternal, err)
+	}
+
+	return scanOffer.GetEntity(), nil
+}
+
+// GetByUserID возвращает предложения, где пользователь покупатель или продавец, новые первыми.
+func (r *OfferRepository) GetByUserID(ctx context.Context, userID, offset, limit int) ([]entity.Offer, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: получение предложений пользователя")
+
+	rows, err := r.DB.QueryContext(ctx, offerSelect+`
+		WHERE o.buyer_id = $1 OR o.seller_id = $1
+		ORDER BY o.id DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при получении предложений пользователя")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении предложений пользователя: %w", err))
+	}
+
+	return scanOffers(requestID, rows)
+}
+
+func (r *OfferRepository) GetByAdvertisementID(ctx context.Context, adID, offset, limit int) ([]entity.Offer, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"adID":      adID,
+	}).Info("SQL запрос: получение предложений по объявлению")
+
+	rows, err := r.DB.QueryContext(ctx, offerSelect+`
+		WHERE o.advertisement_id = $1
+		ORDER BY o.id DESC
+		LIMIT $2 OFFSET $3
+	`, adID, limit, offset)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"adID":      adID,
+			"error":     err,
+		}).Error("Ошибка при получении предложений по объявлению")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении предложений по объявлению: %w", err))
+	}
+
+	return scanOffers(requestID, rows)
+}
+
+func (r *OfferRepository) Accept(ctx context.Context, id, userID int, orderDeadline time.Time) (*entity.Offer, []entity.Offer, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"offerID":   id,
+		"userID":    userID,
+	}).Info("SQL запрос: принятие предложения")
+
+	var declined []entity.Offer
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		adID, err := offerAdvertisementID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		ad, err := lockAd(ctx, tx, adID, "FOR UPDATE")
+		if err != nil {
+			return err
+		}
+
+		offer, err := lockOfferForResponse(ctx, tx, id, userID)
+		if err != nil {
+			return err
+		}
+
+		// Пока предложение ждало ответа, объявление могли снять с модерации, продать
+		// или оно могло истечь: проверка идет под блокировкой строки до конца транзакции.
+		if err := ad.checkPublished(); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE advertisement SET status = 'reserved', version = version + 1, updated_at = NOW()
+			WHERE id = $1
+		`, offer.AdvertisementID); err != nil {
+			return fmt.Errorf("ошибка при резервировании объявления: %w", err)
+		}
+
+		// У принятого предложения expires_at — срок, до которого покупатель оформляет заказ.
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE offer SET status = 'accepted', expires_at = $2, responded_at = NOW(), updated_at = NOW()
+			WHERE id = $1
+		`, id, orderDeadline); err != nil {
+			return fmt.Errorf("ошибка при принятии предложения: %w", err)
+		}
+
+		rows, err := tx.QueryContext(ctx, `
+			WITH declined AS (
+				UPDATE offer SET status = 'declined', responded_at = NOW(), updated_at = NOW()
+				WHERE advertisement_id = $1 AND status = 'pending'
+				RETURNING *
+			)
+			SELECT `+offerColumns+`
+			FROM declined o
+			JOIN advertisement a ON a.id = o.advertisement_id
+		`, offer.AdvertisementID)
+		if err != nil {
+			return fmt.Errorf("ошибка при отклонении остальных предложений: %w", err)
+		}
+		declined, err = scanOffers(requestID, rows)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accepted, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return accepted, declined, nil
+}
+
+func (r *OfferRepository) Decline(ctx context.Context, id, userID int) (*entity.Offer, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"offerID":   id,
+		"userID":    userID,
+	}).Info("SQL запрос: отклонение предложения")
+
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		adID, err := offerAdvertisementID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := lockAd(ctx, tx, adID, "FOR SHARE"); err != nil {
+			return err
+		}
+
+		if _, err := lockOfferForResponse(ctx, tx, id, userID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE offer SET status = 'declined', responded_at = NOW(), updated_at = NOW()
+			WHERE id = $1
+		`, id); err != nil {
+			return fmt.Errorf("ошибка при отклонении предложения: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *OfferRepository) Counter(ctx context.Context, id, userID int, amount float64, expiresAt time.Time) (*entity.Offer, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"offerID":   id,
+		"userID":    userID,
+	}).Info("SQL запрос: встречное предложение")
+
+	var counterID int
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		adID, err := offerAdvertisementID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		ad, err := lockAd(ctx, tx, adID, "FOR SHARE")
+		if err != nil {
+			return err
+		}
+
+		offer, err := lockOfferForResponse(ctx, tx, id, userID)
+		if err != nil {
+			return err
+		}
+		if err := ad.checkPublished(); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE offer SET status = 'countered', responded_at = NOW(), updated_at = NOW()
+			WHERE id = $1
+		`, id); err != nil {
+			return fmt.Errorf("ошибка при закрытии предложения: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO offer (advertisement_id, buyer_id, seller_id, parent_id, proposed_by, amount, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id
+		`,
+			offer.AdvertisementID,
+			offer.BuyerID,
+			offer.SellerID,
+			offer.ID,
+			userID,
+			amount,
+			expiresAt,
+		).Scan(&counterID)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == entity.PSQLCheckViolation {
+				return entity.NewError(
+					entity.ErrBadRequest,
+					fmt.Errorf("неправильные данные предложения"),
+				)
+			}
+			return fmt.Errorf("ошибка при создании встречного предложения: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, counterID)
+}
+
+func (r *OfferRepository) Withdraw(ctx context.Context, id, userID int) (*entity.Offer, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"offerID":   id,
+		"userID":    userID,
+	}).Info("SQL запрос: отказ от принятого предложения")
+
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		adID, err := offerAdvertisementID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := lockAd(ctx, tx, adID, "FOR UPDATE"); err != nil {
+			return err
+		}
+
+		offer, err := lockOffer(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := offer.CanWithdraw(userID); err != nil {
+			return err
+		}
+
+		var ordered bool
+		err = tx.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM purchase_order WHERE offer_id = $1 AND status <> 'cancelled')
+		`, id).Scan(&ordered)
+		if err != nil {
+			return fmt.Errorf("ошибка при проверке заказа по предложению: %w", err)
+		}
+		if ordered {
+			return entity.NewError(
+				entity.ErrConflict,
+				fmt.Errorf("по предложению с id=%d уже оформлен заказ, отменить можно только заказ", id),
+			)
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE offer SET status = 'withdrawn', updated_at = NOW() WHERE id = $1`, id,
+		); err != nil {
+			return fmt.Errorf("ошибка при отказе от предложения: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE advertisement SET status = 'published', version = version + 1, updated_at = NOW()
+			WHERE id = $1 AND status = 'reserved'
+		`, adID); err != nil {
+			return fmt.Errorf("ошибка при снятии резерва с объявления: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *OfferRepository) ExpirePending(ctx context.Context) ([]entity.Offer, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	rows, err := r.DB.QueryContext(ctx, `
+		WITH expired AS (
+			UPDATE offer SET status = 'expired', updated_at = NOW()
+			WHERE id IN (
+				-- Предложения, на которые сейчас отвечают, закроются при следующем запуске:
+				-- ожидание их блокировок без блокировки объявления грозит взаимоблокировкой.
+				SELECT id FROM offer
+				WHERE status = 'pending' AND expires_at <= NOW()
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT `+offerColumns+`
+		FROM expired o
+		JOIN advertisement a ON a.id = o.advertisement_id
+	`)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при закрытии просроченных предложений")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при закрытии просроченных предложений: %w", err))
+	}
+
+	return scanOffers(requestID, rows)
+}
+
+func (r *OfferRepository) ExpireAccepted(ctx context.Context) ([]entity.Offer, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	// Объявления и предложения, занятые другими транзакциями, пропускаются до следующего
+	// запуска: задача не ждет блокировок и не может встать во взаимоблокировку.
+	rows, err := r.DB.QueryContext(ctx, `
+		WITH overdue AS (
+			SELECT o.id, o.advertisement_id
+			FROM offer o
+			JOIN advertisement a ON a.id = o.advertisement_id
+			WHERE o.status = 'accepted' AND o.expires_at <= NOW()
+				AND NOT EXISTS (
+					SELECT 1 FROM purchase_order po
+					WHERE po.offer_id = o.id AND po.status <> 'cancelled'
+				)
+			FOR UPDATE OF a, o SKIP LOCKED
+		), released AS (
+			UPDATE advertisement a SET status = 'published', version = version + 1, updated_at = NOW()
+			FROM overdue
+			WHERE a.id = overdue.advertisement_id AND a.status = 'reserved'
+		), expired AS (
+			UPDATE offer SET status = 'expired', updated_at = NOW()
+			WHERE id IN (SELECT id FROM overdue)
+			RETURNING *
+		)
+		SELECT `+offerColumns+`
+		FROM expired o
+		JOIN advertisement a ON a.id = o.advertisement_id
+	`)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при снятии просроченных резервов по принятым предложениям")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при снятии просроченных резервов по принятым предложениям: %w", err))
+	}
+
+	return scanOffers(requestID, rows)
+}
+
+// offerAdvertisementID читает объявление предложения без блокировки. Все транзакции
+// с предложениями блокируют сначала объявление, потом предложение, иначе встречные
+// блокировки параллельных ответов и новых предложений по объявлению дают взаимоблокировку.
+// Объявление предложения не меняется, поэтому читать его до блокировок безопасно.
+func offerAdvertisementID(ctx context.Context, tx *sql.Tx, id int) (int, error) {
+	var adID int
+	err := tx.QueryRowContext(ctx, `SELECT advertisement_id FROM offer WHERE id = $1`, id).Scan(&adID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("предложение с id=%d не найдено", id),
+			)
+		}
+		return 0, fmt.Errorf("ошибка при получении предложения: %w", err)
+	}
+	return adID, nil
+}
+
+// lockOfferForResponse блокирует предложение до конца транзакции и проверяет,
+// что userID может на него ответить. Объявление предложения должно быть уже заблокировано.
+func lockOfferForResponse(ctx context.Context, tx *sql.Tx, id, userID int) (*entity.Offer, error) {
+	offer, err := lockOffer(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := offer.CanRespond(userID, time.Now()); err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+// lockOffer блокирует предложение до конца транзакции.
+func lockOffer(ctx context.Context, tx *sql.Tx, id int) (*entity.Offer, error) {
+	var scanOffer ScanOffer
+	err := tx.QueryRowContext(ctx, offerSelect+` WHERE o.id = $1 FOR UPDATE OF o`, id).
+		Scan(scanOffer.fields()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("предложение с id=%d не найдено", id),
+			)
+		}
+		return nil, fmt.Errorf("ошибка при получении предложения: %w", err)
+	}
+	return scanOffer.GetEntity(), nil
+}
+
+// lockedAd — состояние объявления, прочитанное под блокировкой строки.
+type lockedAd struct {
+	id               int
+	status           entity.AdStatus
+	moderationStatus entity.ModerationStatus
+	expired          bool
+}
+
+// lockAd блокирует объявление до конца транзакции: FOR SHARE не дает изменить его статус,
+// FOR UPDATE нужен, если вызывающий меняет объявление сам.
+func lockAd(ctx context.Context, tx *sql.Tx, adID int, lockClause string) (*lockedAd, error) {
+	ad := &lockedAd{id: adID}
+	err := tx.QueryRowContext(ctx,
+		`SELECT status, moderation_status, expires_at <= NOW() FROM advertisement WHERE id = $1 `+lockClause, adID,
+	).Scan(&ad.status, &ad.moderationStatus, &ad.expired)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("объявление с id=%d не найдено", adID),
+			)
+		}
+		return nil, fmt.Errorf("ошибка при получении объявления: %w", err)
+	}
+	return ad, nil
+}
+
+// checkPublished проверяет, что объявление еще опубликовано, не истекло и одобрено модерацией.
+func (ad *lockedAd) checkPublished() error {
+	if ad.status != entity.AdPublished {
+		return entity.NewError(
+			entity.ErrConflict,
+			fmt.Errorf("объявление с id=%d зарезервировано, продано или в архиве", ad.id),
+		)
+	}
+	if ad.expired {
+		return entity.NewError(
+			entity.ErrConflict,
+			fmt.Errorf("срок публикации объявления с id=%d истек", ad.id),
+		)
+	}
+	if ad.moderationStatus != entity.ModerationApproved {
+		return entity.NewError(
+			entity.ErrConflict,
+			fmt.Errorf("объявление с id=%d не прошло модерацию", ad.id),
+		)
+	}
+	return nil
+}
+
+// lockPublishedAd блокирует объявление от изменения статуса до конца транзакции
+// и проверяет, что оно еще опубликовано, не истекло и одобрено модерацией.
+func lockPublishedAd(ctx context.Context, tx *sql.Tx, adID int) error {
+	ad, err := lockAd(ctx, tx, adID, "FOR SHARE")
+	if err != nil {
+		return err
+	}
+	return ad.checkPublished()
+}
+
+func scanOffers(requestID string, rows *sql.Rows) ([]entity.Offer, error) {
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	var offers []entity.Offer
+	for rows.Next() {
+		var scanOffer ScanOffer
+		if err := rows.Scan(scanOffer.fields()...); err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+				"error":     err,
+			}).Error("Ошибка при сканировании предложения")
+
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании предложения: %w", err))
+		}
+		offers = append(offers, *scanOffer.GetEntity())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по предложениям: %w", err))
+	}
+
+	return offers, nil
+}
@@ -0,0 +1,442 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type OrderRepository struct {
+	DB *sql.DB
+}
+
+type ScanOrder struct {
+	ID                 int
+	AdvertisementID    sql.NullInt64
+	AdvertisementTitle sql.NullString
+	BuyerID            int
+	SellerID           int
+	OfferID            sql.NullInt64
+	Amount             float64
+	Status             string
+	CreatedAt          sql.NullTime
+	UpdatedAt          sql.NullTime
+}
+
+func (s *ScanOrder) GetEntity() *entity.Order {
+	o := &entity.Order{
+		ID:                 s.ID,
+		AdvertisementTitle: s.AdvertisementTitle.String,
+		BuyerID:            s.BuyerID,
+		SellerID:           s.SellerID,
+		Amount:             s.Amount,
+		Status:             entity.OrderStatus(s.Status),
+		CreatedAt:          s.CreatedAt.Time,
+		UpdatedAt:          s.UpdatedAt.Time,
+	}
+	if s.AdvertisementID.Valid {
+		adID := int(s.AdvertisementID.Int64)
+		o.AdvertisementID = &adID
+	}
+	if s.OfferID.Valid {
+		offerID := int(s.OfferID.Int64)
+		o.OfferID = &offerID
+	}
+	return o
+}
+
+func (s *ScanOrder) fields() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.AdvertisementID,
+		&s.AdvertisementTitle,
+		&s.BuyerID,
+		&s.SellerID,
+		&s.OfferID,
+		&s.Amount,
+		&s.Status,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	}
+}
+
+const orderSelect = `
+	SELECT
+		o.id, o.advertisement_id, a.title, o.buyer_id, o.seller_id, o.offer_id,
+		o.amount, o.status, o.created_at, o.updated_at
+	FROM purchase_order o
+	LEFT JOIN advertisement a ON a.id = o.advertisement_id
+`
+
+func NewOrderRepository(db *sql.DB) (repository.OrderRepository, error) {
+	return &OrderRepository{DB: db}, nil
+}
+
+func (r *OrderRepository) Create(ctx context.Context, adID, buyerID int) (*entity.Order, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"adID":      adID,
+		"buyerID":   buyerID,
+	}).Info("SQL запрос: оформление заказа")
+
+	var id int
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		var (
+			sellerID         int
+			price            float64
+			status           string
+			moderationStatus string
+		)
+		err := tx.QueryRowContext(ctx,
+			`SELECT user_id, price, status, moderation_status FROM advertisement WHERE id = $1 FOR UPDATE`, adID,
+		).Scan(&sellerID, &price, &status, &moderationStatus)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return entity.NewError(
+					entity.ErrNotFound,
+					fmt.Errorf("объявление с id=%d не найдено", adID),
+				)
+			}
+			return fmt.Errorf("ошибка при получении объявления: %w", err)
+		}
+
+		if entity.ModerationStatus(moderationStatus) != entity.ModerationApproved {
+			return entity.NewError(
+				entity.ErrConflict,
+				fmt.Errorf("объявление с id=%d не прошло модерацию", adID),
+			)
+		}
+
+		var (
+			offerID sql.NullInt64
+			amount  = price
+		)
+		switch entity.AdStatus(status) {
+		case entity.AdPublished:
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE advertisement SET status = 'reserved', version = version + 1, updated_at = NOW() WHERE id = $1`, adID,
+			); err != nil {
+				return fmt.Errorf("ошибка при резервировании объявления: %w", err)
+			}
+		case entity.AdReserved:
+			// Зарезервированное объявление может купить только покупатель с принятым предложением
+			// и только до срока, после которого резерв снимается.
+			var offerBuyerID int
+			err := tx.QueryRowContext(ctx, `
+				SELECT id, buyer_id, amount FROM offer
+				WHERE advertisement_id = $1 AND status = 'accepted' AND expires_at > NOW()
+				ORDER BY id DESC
+				LIMIT 1
+			`, adID).Scan(&offerID, &offerBuyerID, &amount)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("ошибка при получении принятого предложения: %w", err)
+			}
+			if errors.Is(err, sql.ErrNoRows) || offerBuyerID != buyerID {
+				return entity.NewError(
+					entity.ErrConflict,
+					fmt.Errorf("объявление с id=%d зарезервировано другим покупателем", adID),
+				)
+			}
+		default:
+			return entity.NewError(
+				entity.ErrConflict,
+				fmt.Errorf("объявление с id=%d уже продано или в архиве", adID),
+			)
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO purchase_order (advertisement_id, buyer_id, seller_id, offer_id, amount)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, adID, buyerID, sellerID, offerID, amount).Scan(&id)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) {
+				switch pqErr.Code {
+				case entity.PSQLUniqueViolation:
+					return entity.NewError(
+						entity.ErrAlreadyExists,
+						fmt.Errorf("по объявлению уже оформлен заказ"),
+					)
+				case entity.PSQLCheckViolation:
+					return entity.NewError(
+						entity.ErrBadRequest,
+						fmt.Errorf("нельзя оформить заказ на собственное объявление"),
+					)
+				}
+			}
+			return fmt.Errorf("ошибка при оформлении заказа: %w", err)
+		}
+
+		return insertOrderEvent(ctx, tx, id, entity.OrderCreated, buyerID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *OrderRepository) GetByID(ctx context.Context, id int) (*entity.Order, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"orderID":   id,
+	}).Info("SQL запрос: получение заказа по ID")
+
+	var scanOrder ScanOrder
+	err := r.DB.QueryRowContext(ctx, orderSelect+` WHERE o.id = $1`, id).Scan(scanOrder.fields()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("заказ с id=%d не найден", id),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"orderID":   id,
+			"error":     err,
+		}).Error("Ошибка при получении заказа")
+
+		return nil, entity.NewError(entity.ErrInternal, err)
+	}
+	order := scanOrder.GetEntity()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT status, actor_id, created_at
+		FROM purchase_order_event
+		WHERE order_id = $1
+		ORDER BY id
+	`, id)
+	if err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении истории заказа: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	for rows.Next() {
+		var (
+			status    string
+			actorID   sql.NullInt64
+			createdAt sql.NullTime
+		)
+		if err := rows.Scan(&status, &actorID, &createdAt); err != nil {
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании истории заказа: %w", err))
+		}
+
+		event := entity.OrderEvent{Status: entity.OrderStatus(status), CreatedAt: createdAt.Time}
+		if actorID.Valid {
+			actor := int(actorID.Int64)
+			event.ActorID = &actor
+		}
+		order.History = append(order.History, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по истории заказа: %w", err))
+	}
+
+	return order, nil
+}
+
+// GetByUserID возвращает заказы пользователя как покупателя и как продавца, новые первыми.
+func (r *OrderRepository) GetByUserID(ctx context.Context, userID, offset, limit int) ([]entity.Order, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: получение заказов пользователя")
+
+	rows, err := r.DB.QueryContext(ctx, orderSelect+`
+		WHERE o.buyer_id = $1 OR o.seller_id = $1
+		ORDER BY o.id DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при получении заказов пользователя")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении заказов пользователя: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	var orders []entity.Order
+	for rows.Next() {
+		var scanOrder ScanOrder
+		if err := rows.Scan(scanOrder.fields()...); err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+				"error":     err,
+			}).Error("Ошибка при сканировании заказа")
+
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании заказа: %w", err))
+		}
+		orders = append(orders, *scanOrder.GetEntity())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по заказам: %w", err))
+	}
+
+	return orders, nil
+}
+
+func (r *OrderRepository) UpdateStatus(ctx context.Context, id, userID int, to entity.OrderStatus) (*entity.Order, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"orderID":   id,
+		"userID":    userID,
+		"status":    to,
+	}).Info("SQL запрос: изменение статуса заказа")
+
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		// Как и в транзакциях с предложениями, объявление блокируется раньше заказа.
+		var adID sql.NullInt64
+		err := tx.QueryRowContext(ctx, `SELECT advertisement_id FROM purchase_order WHERE id = $1`, id).Scan(&adID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return entity.NewError(
+					entity.ErrNotFound,
+					fmt.Errorf("заказ с id=%d не найден", id),
+				)
+			}
+			return fmt.Errorf("ошибка при получении заказа: %w", err)
+		}
+		if adID.Valid {
+			if _, err := lockAd(ctx, tx, int(adID.Int64), "FOR UPDATE"); err != nil {
+				return err
+			}
+		}
+
+		var scanOrder ScanOrder
+		err = tx.QueryRowContext(ctx, orderSelect+` WHERE o.id = $1 FOR UPDATE OF o`, id).
+			Scan(scanOrder.fields()...)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return entity.NewError(
+					entity.ErrNotFound,
+					fmt.Errorf("заказ с id=%d не найден", id),
+				)
+			}
+			return fmt.Errorf("ошибка при получении заказа: %w", err)
+		}
+
+		order := scanOrder.GetEntity()
+		if err := order.CanTransition(userID, to); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE purchase_order SET status = $2, updated_at = NOW() WHERE id = $1`, id, to,
+		); err != nil {
+			return fmt.Errorf("ошибка при изменении статуса заказа: %w", err)
+		}
+
+		if order.AdvertisementID != nil {
+			var adStatus entity.AdStatus
+			switch to {
+			case entity.OrderCompleted:
+				adStatus = entity.AdSold
+			case entity.OrderCancelled:
+				adStatus = entity.AdPublished
+			}
+			if adStatus != "" {
+				if _, err := tx.ExecContext(ctx,
+					`UPDATE advertisement SET status = $2, version = version + 1, updated_at = NOW() WHERE id = $1`,
+					*order.AdvertisementID, adStatus,
+				); err != nil {
+					return fmt.Errorf("ошибка при изменении статуса объявления: %w", err)
+				}
+			}
+		}
+
+		// Отмененный заказ закрывает и сделку по предложению: иначе покупатель снова
+		// заказал бы уже опубликованное объявление по согласованной цене.
+		if to == entity.OrderCancelled && order.OfferID != nil {
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE offer SET status = 'withdrawn', updated_at = NOW() WHERE id = $1 AND status = 'accepted'`,
+				*order.OfferID,
+			); err != nil {
+				return fmt.Errorf("ошибка при закрытии предложения по заказу: %w", err)
+			}
+		}
+
+		return insertOrderEvent(ctx, tx, id, to, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// HasCompleted проверяет, что покупатель завершил заказ по объявлению.
+func (r *OrderRepository) HasCompleted(ctx context.Context, adID, buyerID int) (bool, error) {
+	var completed bool
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM purchase_order
+			WHERE advertisement_id = $1 AND buyer_id = $2 AND status = 'completed'
+		)
+	`, adID, buyerID).Scan(&completed)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"adID":      adID,
+			"buyerID":   buyerID,
+			"error":     err,
+		}).Error("Ошибка при проверке завершенного заказа")
+
+		return false, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при проверке завершенного заказа: %w", err))
+	}
+	return completed, nil
+}
+
+func insertOrderEvent(ctx context.Context, tx *sql.Tx, orderID int, status entity.OrderStatus, actorID int) error {
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO purchase_order_event (order_id, status, actor_id) VALUES ($1, $2, $3)`,
+		orderID, status, actorID,
+	); err != nil {
+		return fmt.Errorf("ошибка при сохранении истории заказа: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type PromotionRepository struct {
+	DB *sql.DB
+}
+
+type ScanPromotion struct {
+	ID              int
+	AdvertisementID int
+	Tier            string
+	StartsAt        sql.NullTime
+	EndsAt          sql.NullTime
+	Status          string
+	CreatedAt       sql.NullTime
+}
+
+func (s *ScanPromotion) GetEntity() *entity.Promotion {
+	return &entity.Promotion{
+		ID:              s.ID,
+		AdvertisementID: s.AdvertisementID,
+		Tier:            entity.PromotionTier(s.Tier),
+		StartsAt:        s.StartsAt.Time,
+		EndsAt:          s.EndsAt.Time,
+		Status:          entity.PromotionStatus(s.Status),
+		CreatedAt:       s.CreatedAt.Time,
+	}
+}
+
+func (s *ScanPromotion) fields() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.AdvertisementID,
+		&s.Tier,
+		&s.StartsAt,
+		&s.EndsAt,
+		&s.Status,
+		&s.CreatedAt,
+	}
+}
+
+const promotionColumns = `id, advertisement_id, tier, starts_at, ends_at, status, created_at`
+
+func NewPromotionRepository(db *sql.DB) (repository.PromotionRepository, error) {
+	return &PromotionRepository{DB: db}, nil
+}
+
+func (r *PromotionRepository) Create(ctx context.Context, promotion *entity.Promotion) (*entity.Promotion, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"adID":      promotion.AdvertisementID,
+		"tier":      promotion.Tier,
+	}).Info("SQL запрос: создание продвижения")
+
+	var scanPromotion ScanPromotion
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		if err := lockPublishedAd(ctx, tx, promotion.AdvertisementID); err != nil {
+			return err
+		}
+
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO promotion (advertisement_id, tier, priority, starts_at, ends_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING `+promotionColumns,
+			promotion.AdvertisementID,
+			promotion.Tier,
+			promotion.Tier.Priority(),
+			promotion.StartsAt,
+			promotion.EndsAt,
+		).Scan(scanPromotion.fields()...)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) {
+				switch pqErr.Code {
+				case entity.PSQLUniqueViolation:
+					return entity.NewError(
+						entity.ErrAlreadyExists,
+						fmt.Errorf("у объявления уже есть запланированное или идущее продвижение"),
+					)
+				case entity.PSQLCheckViolation:
+					return entity.NewError(
+						entity.ErrBadRequest,
+						fmt.Errorf("неправильные данные продвижения"),
+					)
+				}
+			}
+			return fmt.Errorf("ошибка при создании продвижения: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPromotion.GetEntity(), nil
+}
+
+// GetByAdvertisementID возвращает все продвижения объявления, новые первыми.
+func (r *PromotionRepository) GetByAdvertisementID(ctx context.Context, adID int) ([]entity.Promotion, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"adID":      adID,
+	}).Info("SQL запрос: получение продвижений объявления")
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT `+promotionColumns+`
+		FROM promotion
+		WHERE advertisement_id = $1
+		ORDER BY id DESC
+	`, adID)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"adID":      adID,
+			"error":     err,
+		}).Error("Ошибка при получении продвижений объявления")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении продвижений объявления: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	var promotions []entity.Promotion
+	for rows.Next() {
+		var scanPromotion ScanPromotion
+		if err := rows.Scan(scanPromotion.fields()...); err != nil {
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании продвижения: %w", err))
+		}
+		promotions = append(promotions, *scanPromotion.GetEntity())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по продвижениям: %w", err))
+	}
+
+	return promotions, nil
+}
+
+func (r *PromotionRepository) Cancel(ctx context.Context, id, adID int) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID":   requestID,
+		"promotionID": id,
+	}).Info("SQL запрос: отмена продвижения")
+
+	res, err := r.DB.ExecContext(ctx, `
+		UPDATE promotion SET status = 'cancelled'
+		WHERE id = $1 AND advertisement_id = $2 AND status = 'active'
+	`, id, adID)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID":   requestID,
+			"promotionID": id,
+			"error":       err,
+		}).Error("Ошибка при отмене продвижения")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при отмене продвижения: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при отмене продвижения: %w", err))
+	}
+	if affected == 0 {
+		return entity.NewError(
+			entity.ErrNotFound,
+			fmt.Errorf("активное продвижение с id=%d не найдено", id),
+		)
+	}
+	return nil
+}
+
+func (r *PromotionRepository) ExpireFinished(ctx context.Context) (int64, error) {
+	res, err := r.DB.ExecContext(ctx, `
+		UPDATE promotion SET status = 'expired'
+		WHERE status = 'active' AND ends_at <= NOW()
+	`)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"error":     err,
+		}).Error("Ошибка при закрытии закончившихся продвижений")
+
+		return 0, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при закрытии закончившихся продвижений: %w", err))
+	}
+
+	expired, err := res.RowsAffected()
+	if err != nil {
+		return 0, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при закрытии закончившихся продвижений: %w", err))
+	}
+	return expired, nil
+}
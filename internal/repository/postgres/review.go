@@ -0,0 +1,366 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type ReviewRepository struct {
+	DB *sql.DB
+}
+
+type ScanReview struct {
+	ID              int
+	AdvertisementID sql.NullInt64
+	SellerID        int
+	AuthorID        int
+	AuthorLogin     string
+	Rating          int
+	Text            string
+	Reply           sql.NullString
+	RepliedAt       sql.NullTime
+	Status          string
+	CreatedAt       sql.NullTime
+	UpdatedAt       sql.NullTime
+}
+
+func (s *ScanReview) GetEntity() *entity.Review {
+	review := &entity.Review{
+		ID:          s.ID,
+		SellerID:    s.SellerID,
+		AuthorID:    s.AuthorID,
+		AuthorLogin: s.AuthorLogin,
+		Rating:      s.Rating,
+		Text:        s.Text,
+		Reply:       s.Reply.String,
+		RepliedAt:   nullTimePtr(s.RepliedAt),
+		Status:      entity.ReviewStatus(s.Status),
+		CreatedAt:   s.CreatedAt.Time,
+		UpdatedAt:   s.UpdatedAt.Time,
+	}
+	if s.AdvertisementID.Valid {
+		adID := int(s.AdvertisementID.Int64)
+		review.AdvertisementID = &adID
+	}
+	return review
+}
+
+func (s *ScanReview) fields() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.AdvertisementID,
+		&s.SellerID,
+		&s.AuthorID,
+		&s.AuthorLogin,
+		&s.Rating,
+		&s.Text,
+		&s.Reply,
+		&s.RepliedAt,
+		&s.Status,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	}
+}
+
+// reviewColumns ожидает, что review доступен как r, а автор отзыва — как u.
+const reviewColumns = `r.id, r.advertisement_id, r.seller_id, r.author_id, u.login, r.rating, r.text,
+	r.reply, r.replied_at, r.status, r.created_at, r.updated_at`
+
+func NewReviewRepository(db *sql.DB) (repository.ReviewRepository, error) {
+	return &ReviewRepository{DB: db}, nil
+}
+
+// Create сохраняет отзыв и пересчитывает рейтинг продавца в одной транзакции.
+func (r *ReviewRepository) Create(ctx context.Context, review *entity.Review) (*entity.Review, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"sellerID":  review.SellerID,
+		"authorID":  review.AuthorID,
+	}).Info("SQL запрос: создание отзыва")
+
+	query := `
+		WITH r AS (
+			INSERT INTO review (advertisement_id, seller_id, author_id, rating, text)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING *
+		)
+		SELECT ` + reviewColumns + `
+		FROM r
+		JOIN uuser u ON u.id = r.author_id
+	`
+
+	var scanReview ScanReview
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, query,
+			review.AdvertisementID,
+			review.SellerID,
+			review.AuthorID,
+			review.Rating,
+			review.Text,
+		).Scan(scanReview.fields()...)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) {
+				switch pqErr.Code {
+				case entity.PSQLUniqueViolation:
+					return entity.NewError(
+						entity.ErrAlreadyExists,
+						fmt.Errorf("отзыв по этой сделке уже оставлен"),
+					)
+				case entity.PSQLCheckViolation:
+					return entity.NewError(
+						entity.ErrBadRequest,
+						fmt.Errorf("неправильные данные отзыва"),
+					)
+				}
+			}
+			return fmt.Errorf("ошибка при создании отзыва: %w", err)
+		}
+
+		return refreshSellerRating(ctx, tx, review.SellerID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scanReview.GetEntity(), nil
+}
+
+func (r *ReviewRepository) GetByID(ctx context.Context, id int) (*entity.Review, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"reviewID":  id,
+	}).Info("SQL запрос: получение отзыва по ID")
+
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM review r
+		JOIN uuser u ON u.id = r.author_id
+		WHERE r.id = $1
+	`
+
+	var scanReview ScanReview
+	err := r.DB.QueryRowContext(ctx, query, id).Scan(scanReview.fields()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("отзыв с id=%d не найден", id),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"reviewID":  id,
+			"error":     err,
+		}).Error("Ошибка при получении отзыва")
+
+		return nil, entity.NewError(entity.ErrInternal, err)
+	}
+
+	return scanReview.GetEntity(), nil
+}
+
+// GetBySellerID возвращает опубликованные отзывы о продавце, новые первыми.
+func (r *ReviewRepository) GetBySellerID(ctx context.Context, sellerID, offset, limit int) ([]entity.Review, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"sellerID":  sellerID,
+	}).Info("SQL запрос: получение отзывов о продавце")
+
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM review r
+		JOIN uuser u ON u.id = r.author_id
+		WHERE r.seller_id = $1 AND r.status = 'published'
+		ORDER BY r.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, sellerID, limit, offset)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"sellerID":  sellerID,
+			"error":     err,
+		}).Error("Ошибка при получении отзывов о продавце")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении отзывов о продавце: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	reviews := make([]entity.Review, 0, limit)
+	for rows.Next() {
+		var scanReview ScanReview
+		if err := rows.Scan(scanReview.fields()...); err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+				"error":     err,
+			}).Error("Ошибка при сканировании отзыва")
+
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании отзыва: %w", err))
+		}
+		reviews = append(reviews, *scanReview.GetEntity())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при итерации по отзывам: %w", err))
+	}
+
+	return reviews, nil
+}
+
+// SetReply сохраняет ответ продавца. Ответить можно только один раз.
+func (r *ReviewRepository) SetReply(ctx context.Context, id, sellerID int, reply string) (*entity.Review, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"reviewID":  id,
+	}).Info("SQL запрос: ответ продавца на отзыв")
+
+	query := `
+		WITH r AS (
+			UPDATE review
+			SET reply = $1, replied_at = NOW(), updated_at = NOW()
+			WHERE id = $2 AND seller_id = $3 AND reply IS NULL
+			RETURNING *
+		)
+		SELECT ` + reviewColumns + `
+		FROM r
+		JOIN uuser u ON u.id = r.author_id
+	`
+
+	var scanReview ScanReview
+	err := r.DB.QueryRowContext(ctx, query, reply, id, sellerID).Scan(scanReview.fields()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrAlreadyExists,
+				fmt.Errorf("на отзыв с id=%d уже дан ответ", id),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"reviewID":  id,
+			"error":     err,
+		}).Error("Ошибка при сохранении ответа на отзыв")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при сохранении ответа на отзыв: %w", err))
+	}
+
+	return scanReview.GetEntity(), nil
+}
+
+// Report учитывает жалобу пользователя. Повторная жалоба того же пользователя не считается.
+// Набрав hideAfter жалоб, отзыв скрывается и перестает влиять на рейтинг продавца.
+func (r *ReviewRepository) Report(ctx context.Context, id, userID, hideAfter int) (bool, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"reviewID":  id,
+		"userID":    userID,
+	}).Info("SQL запрос: жалоба на отзыв")
+
+	var hidden bool
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		var (
+			sellerID int
+			status   string
+		)
+		err := tx.QueryRowContext(ctx,
+			`SELECT seller_id, status FROM review WHERE id = $1 FOR UPDATE`, id,
+		).Scan(&sellerID, &status)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return entity.NewError(
+					entity.ErrNotFound,
+					fmt.Errorf("отзыв с id=%d не найден", id),
+				)
+			}
+			return fmt.Errorf("ошибка при получении отзыва: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO review_report (review_id, user_id) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, id, userID); err != nil {
+			return fmt.Errorf("ошибка при сохранении жалобы: %w", err)
+		}
+
+		if entity.ReviewStatus(status) == entity.ReviewHidden {
+			hidden = true
+			return nil
+		}
+
+		var reports int
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM review_report WHERE review_id = $1`, id,
+		).Scan(&reports); err != nil {
+			return fmt.Errorf("ошибка при подсчете жалоб: %w", err)
+		}
+		if reports < hideAfter {
+			return nil
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE review SET status = 'hidden', updated_at = NOW() WHERE id = $1`, id,
+		); err != nil {
+			return fmt.Errorf("ошибка при скрытии отзыва: %w", err)
+		}
+		hidden = true
+
+		return refreshSellerRating(ctx, tx, sellerID)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return hidden, nil
+}
+
+// refreshSellerRating пересчитывает денормализованный рейтинг продавца по опубликованным отзывам.
+func refreshSellerRating(ctx context.Context, tx *sql.Tx, sellerID int) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE uuser
+		SET rating_count = s.cnt, rating_avg = s.avg
+		FROM (
+			SELECT COUNT(*) AS cnt, COALESCE(ROUND(AVG(rating), 2), 0) AS avg
+			FROM review
+			WHERE seller_id = $1 AND status = 'published'
+		) s
+		WHERE uuser.id = $1
+	`, sellerID)
+	if err != nil {
+		return fmt.Errorf("ошибка при пересчете рейтинга продавца: %w", err)
+	}
+	return nil
+}
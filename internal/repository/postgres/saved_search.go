@@ -0,0 +1,410 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type SavedSearchRepository struct {
+	DB *sql.DB
+}
+
+type ScanSavedSearch struct {
+	ID            int
+	UserID        int
+	Name          string
+	MinPrice      sql.NullFloat64
+	MaxPrice      sql.NullFloat64
+	SortBy        string
+	SortOrder     string
+	AlertsEnabled bool
+	StartAdID     int
+	CreatedAt     sql.NullTime
+	UpdatedAt     sql.NullTime
+}
+
+func (s *ScanSavedSearch) GetEntity() *entity.SavedSearch {
+	search := &entity.SavedSearch{
+		ID:     s.ID,
+		UserID: s.UserID,
+		Name:   s.Name,
+		Criteria: entity.SearchCriteria{
+			SortBy: s.SortBy,
+			Order:  s.SortOrder,
+		},
+		AlertsEnabled: s.AlertsEnabled,
+		StartAdID:     s.StartAdID,
+		CreatedAt:     s.CreatedAt.Time,
+		UpdatedAt:     s.UpdatedAt.Time,
+	}
+	if s.MinPrice.Valid {
+		search.Criteria.MinPrice = &s.MinPrice.Float64
+	}
+	if s.MaxPrice.Valid {
+		search.Criteria.MaxPrice = &s.MaxPrice.Float64
+	}
+	return search
+}
+
+func (s *ScanSavedSearch) fields() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.UserID,
+		&s.Name,
+		&s.MinPrice,
+		&s.MaxPrice,
+		&s.SortBy,
+		&s.SortOrder,
+		&s.AlertsEnabled,
+		&s.StartAdID,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	}
+}
+
+const savedSearchColumns = `id, user_id, name, min_price, max_price, sort_by, sort_order,
+	alerts_enabled, start_ad_id, created_at, updated_at`
+
+func NewSavedSearchRepository(db *sql.DB) (repository.SavedSearchRepository, error) {
+	return &SavedSearchRepository{DB: db}, nil
+}
+
+func (r *SavedSearchRepository) Create(ctx context.Context, search *entity.SavedSearch) (*entity.SavedSearch, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    search.UserID,
+	}).Info("SQL запрос: создание сохраненного поиска")
+
+	// Оповещения приходят только об объявлениях, появившихся после сохранения поиска.
+	query := `
+		INSERT INTO saved_search (user_id, name, min_price, max_price, sort_by, sort_order, alerts_enabled, start_ad_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT COALESCE(MAX(id), 0) FROM advertisement))
+		RETURNING ` + savedSearchColumns
+
+	var scanSearch ScanSavedSearch
+	err := r.DB.QueryRowContext(ctx, query,
+		search.UserID,
+		search.Name,
+		search.Criteria.MinPrice,
+		search.Criteria.MaxPrice,
+		search.Criteria.SortBy,
+		search.Criteria.Order,
+		search.AlertsEnabled,
+	).Scan(scanSearch.fields()...)
+	if err != nil {
+		return nil, savedSearchWriteError(requestID, err)
+	}
+
+	return scanSearch.GetEntity(), nil
+}
+
+func (r *SavedSearchRepository) GetByUserID(ctx context.Context, userID int) ([]entity.SavedSearch, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: получение сохраненных поисков пользователя")
+
+	query := `
+		SELECT ` + savedSearchColumns + `
+		FROM saved_search
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при получении сохраненных поисков")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении сохраненных поисков: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	searches := make([]entity.SavedSearch, 0)
+	for rows.Next() {
+		var scanSearch ScanSavedSearch
+		if err := rows.Scan(scanSearch.fields()...); err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+				"error":     err,
+			}).Error("Ошибка при сканировании сохраненного поиска")
+
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании сохраненного поиска: %w", err))
+		}
+		searches = append(searches, *scanSearch.GetEntity())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка после сканирования сохраненных поисков: %w", err))
+	}
+
+	return searches, nil
+}
+
+func (r *SavedSearchRepository) CountByUserID(ctx context.Context, userID int) (int, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	var count int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM saved_search WHERE user_id = $1`, userID).Scan(&count)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при подсчете сохраненных поисков")
+
+		return 0, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при подсчете сохраненных поисков: %w", err))
+	}
+
+	return count, nil
+}
+
+func (r *SavedSearchRepository) Update(ctx context.Context, search *entity.SavedSearch) (*entity.SavedSearch, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"searchID":  search.ID,
+	}).Info("SQL запрос: обновление сохраненного поиска")
+
+	query := `
+		UPDATE saved_search
+		SET name = $1, min_price = $2, max_price = $3, sort_by = $4, sort_order = $5,
+			alerts_enabled = $6, updated_at = NOW()
+		WHERE id = $7 AND user_id = $8
+		RETURNING ` + savedSearchColumns
+
+	var scanSearch ScanSavedSearch
+	err := r.DB.QueryRowContext(ctx, query,
+		search.Name,
+		search.Criteria.MinPrice,
+		search.Criteria.MaxPrice,
+		search.Criteria.SortBy,
+		search.Criteria.Order,
+		search.AlertsEnabled,
+		search.ID,
+		search.UserID,
+	).Scan(scanSearch.fields()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("сохраненный поиск с id=%d не найден", search.ID),
+			)
+		}
+		return nil, savedSearchWriteError(requestID, err)
+	}
+
+	return scanSearch.GetEntity(), nil
+}
+
+func (r *SavedSearchRepository) Delete(ctx context.Context, id, userID int) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"searchID":  id,
+	}).Info("SQL запрос: удаление сохраненного поиска")
+
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM saved_search WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"searchID":  id,
+			"error":     err,
+		}).Error("Ошибка при удалении сохраненного поиска")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при удалении сохраненного поиска: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.NewError(entity.ErrInternal, err)
+	}
+	if affected == 0 {
+		return entity.NewError(
+			entity.ErrNotFound,
+			fmt.Errorf("сохраненный поиск с id=%d не найден", id),
+		)
+	}
+
+	return nil
+}
+
+// MatchNewAds сдвигает общий курсор по id объявлений. Строка курсора блокируется
+// с SKIP LOCKED, поэтому одновременно пачку обрабатывает только один экземпляр.
+func (r *SavedSearchRepository) MatchNewAds(ctx context.Context, batchSize int) (int, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	var processed int
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		var lastAdID int
+		err := tx.QueryRowContext(ctx,
+			`SELECT last_ad_id FROM saved_search_cursor FOR UPDATE SKIP LOCKED`,
+		).Scan(&lastAdID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("ошибка при чтении курсора сохраненных поисков: %w", err)
+		}
+
+		var upToAdID int
+		err = tx.QueryRowContext(ctx, `
+			SELECT COUNT(*), COALESCE(MAX(id), $1)
+			FROM (SELECT id FROM advertisement WHERE id > $1 ORDER BY id LIMIT $2) batch
+		`, lastAdID, batchSize).Scan(&processed, &upToAdID)
+		if err != nil {
+			return fmt.Errorf("ошибка при выборке новых объявлений: %w", err)
+		}
+		if processed == 0 {
+			return nil
+		}
+
+		// Фильтры, добавленные в SearchCriteria, должны появиться и в этом условии.
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO saved_search_match (search_id, advertisement_id)
+			SELECT s.id, a.id
+			FROM advertisement a
+			JOIN saved_search s ON s.alerts_enabled
+				AND a.id > s.start_ad_id
+				AND s.user_id <> a.user_id
+				AND (s.min_price IS NULL OR a.price >= s.min_price)
+				AND (s.max_price IS NULL OR a.price <= s.max_price)
+			WHERE a.id > $1 AND a.id <= $2 AND a.moderation_status = 'approved'
+			ON CONFLICT DO NOTHING
+		`, lastAdID, upToAdID)
+		if err != nil {
+			return fmt.Errorf("ошибка при сопоставлении объявлений с сохраненными поисками: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE saved_search_cursor SET last_ad_id = $1`, upToAdID,
+		); err != nil {
+			return fmt.Errorf("ошибка при обновлении курсора сохраненных поисков: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при сопоставлении сохраненных поисков")
+
+		return 0, err
+	}
+
+	return processed, nil
+}
+
+func (r *SavedSearchRepository) ClaimMatches(ctx context.Context, userLimit int) ([]entity.SavedSearchMatch, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	query := `
+		WITH users AS (
+			SELECT DISTINCT s.user_id
+			FROM saved_search_match m
+			JOIN saved_search s ON s.id = m.search_id
+			LIMIT $1
+		), claimed AS (
+			DELETE FROM saved_search_match m
+			USING saved_search s
+			WHERE s.id = m.search_id AND s.user_id IN (SELECT user_id FROM users)
+			RETURNING m.search_id, m.advertisement_id
+		)
+		SELECT s.id, s.name, s.user_id, a.id, a.title, a.price
+		FROM claimed c
+		JOIN saved_search s ON s.id = c.search_id
+		JOIN advertisement a ON a.id = c.advertisement_id
+		ORDER BY s.user_id, s.id, a.id
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, userLimit)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при выборке совпадений сохраненных поисков")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при выборке совпадений сохраненных поисков: %w", err))
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+			}).Errorf("не удалось закрыть rows: %v", err)
+		}
+	}(rows)
+
+	matches := make([]entity.SavedSearchMatch, 0)
+	for rows.Next() {
+		var match entity.SavedSearchMatch
+		if err := rows.Scan(
+			&match.SearchID,
+			&match.SearchName,
+			&match.UserID,
+			&match.AdvertisementID,
+			&match.AdTitle,
+			&match.AdPrice,
+		); err != nil {
+			return nil, entity.NewError(entity.ErrInternal,
+				fmt.Errorf("ошибка при сканировании совпадения: %w", err))
+		}
+		matches = append(matches, match)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка после сканирования совпадений: %w", err))
+	}
+
+	return matches, nil
+}
+
+func savedSearchWriteError(requestID string, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == entity.PSQLCheckViolation {
+		return entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("неправильные параметры сохраненного поиска"),
+		)
+	}
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"error":     err,
+	}).Error("Ошибка при сохранении поиска")
+
+	return entity.NewError(entity.ErrInternal,
+		fmt.Errorf("ошибка при сохранении поиска: %w", err))
+}
@@ -0,0 +1,239 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type TwoFactorRepository struct {
+	DB *sql.DB
+}
+
+func NewTwoFactorRepository(db *sql.DB) (repository.TwoFactorRepository, error) {
+	return &TwoFactorRepository{DB: db}, nil
+}
+
+func (r *TwoFactorRepository) Get(ctx context.Context, userID int) (*entity.TwoFactor, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: получение настроек двухфакторной аутентификации")
+
+	query := `
+		SELECT id, totp_secret, totp_enabled, totp_last_step
+		FROM uuser
+		WHERE id = $1
+	`
+
+	var (
+		tf     entity.TwoFactor
+		secret sql.NullString
+	)
+	err := r.DB.QueryRowContext(ctx, query, userID).Scan(&tf.UserID, &secret, &tf.Enabled, &tf.LastStep)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("пользователь с id=%d не найден", userID),
+			)
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при получении настроек двухфакторной аутентификации")
+
+		return nil, entity.NewError(entity.ErrInternal, err)
+	}
+	tf.Secret = secret.String
+
+	return &tf, nil
+}
+
+func (r *TwoFactorRepository) SetPendingSecret(ctx context.Context, userID int, secret string) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: сохранение секрета TOTP")
+
+	query := `
+		UPDATE uuser
+		SET totp_secret = $1, totp_enabled = FALSE, totp_last_step = 0, updated_at = NOW()
+		WHERE id = $2 AND totp_enabled = FALSE
+	`
+
+	res, err := r.DB.ExecContext(ctx, query, secret, userID)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при сохранении секрета TOTP")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при сохранении секрета TOTP: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.NewError(entity.ErrInternal, err)
+	}
+	if affected == 0 {
+		return entity.NewError(
+			entity.ErrAlreadyExists,
+			fmt.Errorf("двухфакторная аутентификация уже включена"),
+		)
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) Enable(ctx context.Context, userID int, lastStep int64, recoveryCodeHashes [][]byte) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: включение двухфакторной аутентификации")
+
+	return withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE uuser
+			SET totp_enabled = TRUE, totp_last_step = $1, updated_at = NOW()
+			WHERE id = $2
+		`, lastStep, userID)
+		if err != nil {
+			return fmt.Errorf("ошибка при включении двухфакторной аутентификации: %w", err)
+		}
+		return replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes)
+	})
+}
+
+func (r *TwoFactorRepository) Disable(ctx context.Context, userID int) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: отключение двухфакторной аутентификации")
+
+	return withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE uuser
+			SET totp_secret = NULL, totp_enabled = FALSE, totp_last_step = 0, updated_at = NOW()
+			WHERE id = $1
+		`, userID)
+		if err != nil {
+			return fmt.Errorf("ошибка при отключении двухфакторной аутентификации: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_code WHERE user_id = $1`, userID); err != nil {
+			return fmt.Errorf("ошибка при удалении кодов восстановления: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *TwoFactorRepository) UpdateLastStep(ctx context.Context, userID int, lastStep int64) error {
+	requestID := utils.GetRequestID(ctx)
+
+	// Условие по шагу защищает от гонки двух запросов с одним и тем же кодом.
+	res, err := r.DB.ExecContext(ctx, `
+		UPDATE uuser SET totp_last_step = $1
+		WHERE id = $2 AND totp_last_step < $1
+	`, lastStep, userID)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при обновлении шага TOTP")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при обновлении шага TOTP: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.NewError(entity.ErrInternal, err)
+	}
+	if affected == 0 {
+		return entity.NewError(
+			entity.ErrUnauthorized,
+			fmt.Errorf("код TOTP уже использован"),
+		)
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, recoveryCodeHashes [][]byte) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: перевыпуск кодов восстановления")
+
+	return withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		return replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes)
+	})
+}
+
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash []byte) (bool, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("SQL запрос: использование кода восстановления")
+
+	res, err := r.DB.ExecContext(ctx, `
+		UPDATE recovery_code SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`, userID, codeHash)
+	if err != nil {
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    userID,
+			"error":     err,
+		}).Error("Ошибка при использовании кода восстановления")
+
+		return false, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при использовании кода восстановления: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, entity.NewError(entity.ErrInternal, err)
+	}
+
+	return affected == 1, nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID int, hashes [][]byte) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_code WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("ошибка при удалении кодов восстановления: %w", err)
+	}
+	for _, hash := range hashes {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO recovery_code (user_id, code_hash) VALUES ($1, $2)`,
+			userID, hash,
+		); err != nil {
+			return fmt.Errorf("ошибка при сохранении кода восстановления: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// withTx выполняет fn в транзакции. Ошибки типа entity.Error возвращаются как есть,
+// остальные оборачиваются в ErrInternal.
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	requestID := utils.GetRequestID(ctx)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("не удалось начать транзакцию: %w", err))
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+				"error":     rbErr,
+			}).Error("Не удалось откатить транзакцию")
+		}
+
+		var e entity.Error
+		if errors.As(err, &e) {
+			return err
+		}
+
+		l.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка в транзакции")
+
+		return entity.NewError(entity.ErrInternal, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("не удалось зафиксировать транзакцию: %w", err))
+	}
+	return nil
+}
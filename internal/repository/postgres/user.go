@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
@@ -19,26 +21,97 @@ type UserRepository struct {
 }
 
 type ScanUser struct {
-	ID           int
-	Login        string
-	Name         string
-	Surname      string
-	PasswordHash []byte
-	PasswordSalt []byte
-	CreatedAt    sql.NullTime
-	UpdatedAt    sql.NullTime
+	ID            int
+	Login         string
+	Name          string
+	Surname       string
+	Email         sql.NullString
+	EmailVerified bool
+	Phone         sql.NullString
+	PhoneVerified bool
+	Bio           sql.NullString
+	City          sql.NullString
+	AvatarURL     sql.NullString
+	Rating        float64
+	RatingCount   int
+	IsModerator   bool
+	Language      sql.NullString
+	PasswordHash  string
+	DeleteAfter   sql.NullTime
+	CreatedAt     sql.NullTime
+	UpdatedAt     sql.NullTime
 }
 
 func (u *ScanUser) GetEntity() *entity.User {
 	return &entity.User{
-		ID:           u.ID,
-		Login:        u.Login,
-		Name:         u.Name,
-		Surname:      u.Surname,
-		PasswordHash: u.PasswordHash,
-		PasswordSalt: u.PasswordSalt,
-		CreatedAt:    u.CreatedAt.Time,
-		UpdatedAt:    u.UpdatedAt.Time,
+		ID:            u.ID,
+		Login:         u.Login,
+		Name:          u.Name,
+		Surname:       u.Surname,
+		Email:         u.Email.String,
+		EmailVerified: u.EmailVerified,
+		Phone:         u.Phone.String,
+		PhoneVerified: u.PhoneVerified,
+		Bio:           u.Bio.String,
+		City:          u.City.String,
+		AvatarURL:     u.AvatarURL.String,
+		Rating:        u.Rating,
+		RatingCount:   u.RatingCount,
+		IsModerator:   u.IsModerator,
+		Language:      u.Language.String,
+		PasswordHash:  u.PasswordHash,
+		DeleteAfter:   nullTimePtr(u.DeleteAfter),
+		CreatedAt:     u.CreatedAt.Time,
+		UpdatedAt:     u.UpdatedAt.Time,
+	}
+}
+
+func (u *ScanUser) fields() []interface{} {
+	return []interface{}{
+		&u.ID,
+		&u.Login,
+		&u.Name,
+		&u.Surname,
+		&u.Email,
+		&u.EmailVerified,
+		&u.Phone,
+		&u.PhoneVerified,
+		&u.Bio,
+		&u.City,
+		&u.AvatarURL,
+		&u.Rating,
+		&u.RatingCount,
+		&u.IsModerator,
+		&u.Language,
+		&u.PasswordHash,
+		&u.DeleteAfter,
+		&u.CreatedAt,
+		&u.UpdatedAt,
+	}
+}
+
+const userColumns = `id, login, first_name, last_name, email, email_verified, phone, phone_verified,
+	bio, city, avatar_url, rating_avg, rating_count, is_moderator, language, password_hash, delete_after, created_at, updated_at`
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// contactColumns возвращает колонки значения и признака подтверждения для канала связи.
+func contactColumns(channel entity.ContactChannel) (string, string, error) {
+	switch channel {
+	case entity.ContactEmail:
+		return "email", "email_verified", nil
+	case entity.ContactPhone:
+		return "phone", "phone_verified", nil
+	default:
+		return "", "", entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("неизвестный тип контакта: %s", channel),
+		)
 	}
 }
 
@@ -46,7 +119,7 @@ func NewUserRepository(db *sql.DB) (repository.UserRepository, error) {
 	return &UserRepository{DB: db}, nil
 }
 
-func (r *UserRepository) Create(ctx context.Context, login, name, surname string, passwordHash, passwordSalt []byte) (*entity.User, error) {
+func (r *UserRepository) Create(ctx context.Context, login, name, surname, passwordHash string) (*entity.User, error) {
 	requestID := utils.GetRequestID(ctx)
 
 	logger.Log.WithFields(logrus.Fields{
@@ -54,23 +127,21 @@ func (r *UserRepository) Create(ctx context.Context, login, name, surname string
 	}).Info("SQL запрос: создание пользователя")
 
 	query := `
-		INSERT INTO uuser (login, password_hashed, password_salt, first_name, last_name)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, login, password_hashed, password_salt, first_name, last_name
+		INSERT INTO uuser (login, password_hash, first_name, last_name)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, login, password_hash, first_name, last_name
 	`
 
 	var createdUser entity.User
 	err := r.DB.QueryRowContext(ctx, query,
 		login,
 		passwordHash,
-		passwordSalt,
 		name,
 		surname,
 	).Scan(
 		&createdUser.ID,
 		&createdUser.Login,
 		&createdUser.PasswordHash,
-		&createdUser.PasswordSalt,
 		&createdUser.Name,
 		&createdUser.Surname,
 	)
@@ -120,27 +191,17 @@ func (r *UserRepository) GetByID(ctx context.Context, id int) (*entity.User, err
 		"requestID": requestID,
 	}).Info("SQL запрос: получение пользователя по ID")
 
-	query := `
-		SELECT id, login, first_name, last_name, password_hashed, password_salt, created_at, updated_at
-		FROM uuser
-		WHERE id = $1
-	`
+	query := `SELECT ` + userColumns + ` FROM uuser WHERE id = $1`
 
 	scanUser := ScanUser{}
-	err := r.DB.QueryRowContext(ctx, query, id).Scan(
-		&scanUser.ID,
-		&scanUser.Login,
-		&scanUser.Name,
-		&scanUser.Surname,
-		&scanUser.PasswordHash,
-		&scanUser.PasswordSalt,
-		&scanUser.CreatedAt,
-		&scanUser.UpdatedAt,
-	)
+	err := r.DB.QueryRowContext(ctx, query, id).Scan(scanUser.fields()...)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("пользователь с id=%d не найден: %w", id, err)
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("пользователь с id=%d не найден", id),
+			)
 		}
 
 		logger.Log.WithFields(logrus.Fields{
@@ -149,7 +210,8 @@ func (r *UserRepository) GetByID(ctx context.Context, id int) (*entity.User, err
 			"error":     err,
 		}).Error("Ошибка при получении пользователя")
 
-		return nil, fmt.Errorf("ошибка при получении пользователя: %w", err)
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при получении пользователя: %w", err))
 	}
 
 	return scanUser.GetEntity(), nil
@@ -162,23 +224,10 @@ func (r *UserRepository) GetByLogin(ctx context.Context, login string) (*entity.
 		"requestID": requestID,
 	}).Info("SQL запрос: получение пользователя по логину")
 
-	query := `
-		SELECT id, login, first_name, last_name, password_hashed, password_salt, created_at, updated_at
-		FROM uuser
-		WHERE login = $1
-	`
+	query := `SELECT ` + userColumns + ` FROM uuser WHERE login = $1`
 
 	var scanUser ScanUser
-	err := r.DB.QueryRowContext(ctx, query, login).Scan(
-		&scanUser.ID,
-		&scanUser.Login,
-		&scanUser.Name,
-		&scanUser.Surname,
-		&scanUser.PasswordHash,
-		&scanUser.PasswordSalt,
-		&scanUser.CreatedAt,
-		&scanUser.UpdatedAt,
-	)
+	err := r.DB.QueryRowContext(ctx, query, login).Scan(scanUser.fields()...)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -199,3 +248,326 @@ func (r *UserRepository) GetByLogin(ctx context.Context, login string) (*entity.
 
 	return scanUser.GetEntity(), nil
 }
+
+func (r *UserRepository) UpdatePasswordHash(ctx context.Context, id int, passwordHash string) error {
+	requestID := utils.GetRequestID(ctx)
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    id,
+	}).Info("SQL запрос: обновление хеша пароля")
+
+	query := `
+		UPDATE uuser
+		SET password_hash = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	res, err := r.DB.ExecContext(ctx, query, passwordHash, id)
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    id,
+			"error":     err,
+		}).Error("Ошибка при обновлении хеша пароля")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при обновлении хеша пароля: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.NewError(entity.ErrInternal, err)
+	}
+	if affected == 0 {
+		return entity.NewError(
+			entity.ErrNotFound,
+			fmt.Errorf("пользователь с id=%d не найден", id),
+		)
+	}
+
+	return nil
+}
+
+// GetByEmail ищет пользователя с подтвержденным email.
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+	}).Info("SQL запрос: получение пользователя по email")
+
+	query := `SELECT ` + userColumns + ` FROM uuser WHERE email = $1 AND email_verified`
+
+	var scanUser ScanUser
+	err := r.DB.QueryRowContext(ctx, query, email).Scan(scanUser.fields()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("пользователь с email %s не найден", email),
+			)
+		}
+
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при получении пользователя по email")
+
+		return nil, entity.NewError(entity.ErrInternal, err)
+	}
+
+	return scanUser.GetEntity(), nil
+}
+
+// SetContact сохраняет новый контакт без подтверждения. Контакт, уже подтвержденный
+// другим пользователем, занять нельзя.
+func (r *UserRepository) SetContact(ctx context.Context, id int, channel entity.ContactChannel, value string) error {
+	requestID := utils.GetRequestID(ctx)
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    id,
+		"channel":   channel,
+	}).Info("SQL запрос: изменение контакта пользователя")
+
+	valueColumn, verifiedColumn, err := contactColumns(channel)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE uuser
+		SET %[1]s = $1,
+			%[2]s = (%[1]s IS NOT DISTINCT FROM $1 AND %[2]s),
+			updated_at = NOW()
+		WHERE id = $2 AND NOT EXISTS (
+			SELECT 1 FROM uuser other
+			WHERE other.%[1]s = $1 AND other.%[2]s AND other.id <> $2
+		)
+	`, valueColumn, verifiedColumn)
+
+	res, err := r.DB.ExecContext(ctx, query, value, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == entity.PSQLCheckViolation {
+			return entity.NewError(entity.ErrBadRequest, fmt.Errorf("неправильный формат контакта"))
+		}
+
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    id,
+			"error":     err,
+		}).Error("Ошибка при изменении контакта пользователя")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при изменении контакта пользователя: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.NewError(entity.ErrInternal, err)
+	}
+	if affected == 0 {
+		return entity.NewError(
+			entity.ErrAlreadyExists,
+			fmt.Errorf("этот контакт уже подтвержден другим пользователем"),
+		)
+	}
+
+	return nil
+}
+
+// MarkContactVerified подтверждает контакт, если он не изменился с момента отправки кода.
+func (r *UserRepository) MarkContactVerified(ctx context.Context, id int, channel entity.ContactChannel, value string) error {
+	requestID := utils.GetRequestID(ctx)
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    id,
+		"channel":   channel,
+	}).Info("SQL запрос: подтверждение контакта пользователя")
+
+	valueColumn, verifiedColumn, err := contactColumns(channel)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE uuser
+		SET %[2]s = TRUE, updated_at = NOW()
+		WHERE id = $1 AND %[1]s = $2
+	`, valueColumn, verifiedColumn)
+
+	res, err := r.DB.ExecContext(ctx, query, id, value)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == entity.PSQLUniqueViolation {
+			return entity.NewError(
+				entity.ErrAlreadyExists,
+				fmt.Errorf("этот контакт уже подтвержден другим пользователем"),
+			)
+		}
+
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    id,
+			"error":     err,
+		}).Error("Ошибка при подтверждении контакта пользователя")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при подтверждении контакта пользователя: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.NewError(entity.ErrInternal, err)
+	}
+	if affected == 0 {
+		return entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("контакт был изменен, запросите код заново"),
+		)
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UpdateProfile(ctx context.Context, id int, update *entity.ProfileUpdate) (*entity.User, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    id,
+	}).Info("SQL запрос: изменение профиля пользователя")
+
+	setParts := []string{"updated_at = NOW()"}
+	args := []interface{}{id} // id = $1
+
+	set := func(column string, value *string, nullIfEmpty bool) {
+		if value == nil {
+			return
+		}
+		args = append(args, *value)
+		if nullIfEmpty {
+			setParts = append(setParts, fmt.Sprintf("%s = NULLIF($%d, '')", column, len(args)))
+		} else {
+			setParts = append(setParts, fmt.Sprintf("%s = $%d", column, len(args)))
+		}
+	}
+	set("first_name", update.Name, false)
+	set("last_name", update.Surname, false)
+	set("bio", update.Bio, true)
+	set("city", update.City, true)
+	set("avatar_url", update.AvatarURL, true)
+	set("language", update.Language, true)
+
+	query := fmt.Sprintf(`
+		UPDATE uuser
+		SET %s
+		WHERE id = $1
+		RETURNING `+userColumns, strings.Join(setParts, ", "))
+
+	var scanUser ScanUser
+	err := r.DB.QueryRowContext(ctx, query, args...).Scan(scanUser.fields()...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.NewError(
+				entity.ErrNotFound,
+				fmt.Errorf("пользователь с id=%d не найден", id),
+			)
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == entity.PSQLCheckViolation {
+			return nil, entity.NewError(entity.ErrBadRequest, fmt.Errorf("неправильные данные профиля"))
+		}
+
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"userID":    id,
+			"error":     err,
+		}).Error("Ошибка при изменении профиля пользователя")
+
+		return nil, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при изменении профиля пользователя: %w", err))
+	}
+
+	return scanUser.GetEntity(), nil
+}
+
+// ScheduleDeletion помечает аккаунт на удаление после deleteAfter.
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, id int, deleteAfter time.Time) error {
+	requestID := utils.GetRequestID(ctx)
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID":   requestID,
+		"userID":      id,
+		"deleteAfter": deleteAfter,
+	}).Info("SQL запрос: планирование удаления аккаунта")
+
+	return r.setDeleteAfter(ctx, id, &deleteAfter)
+}
+
+func (r *UserRepository) CancelDeletion(ctx context.Context, id int) error {
+	requestID := utils.GetRequestID(ctx)
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    id,
+	}).Info("SQL запрос: отмена удаления аккаунта")
+
+	return r.setDeleteAfter(ctx, id, nil)
+}
+
+func (r *UserRepository) setDeleteAfter(ctx context.Context, id int, deleteAfter *time.Time) error {
+	res, err := r.DB.ExecContext(ctx, `
+		UPDATE uuser SET delete_after = $1, updated_at = NOW()
+		WHERE id = $2
+	`, deleteAfter, id)
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"userID":    id,
+			"error":     err,
+		}).Error("Ошибка при изменении срока удаления аккаунта")
+
+		return entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при изменении срока удаления аккаунта: %w", err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return entity.NewError(entity.ErrInternal, err)
+	}
+	if affected == 0 {
+		return entity.NewError(
+			entity.ErrNotFound,
+			fmt.Errorf("пользователь с id=%d не найден", id),
+		)
+	}
+
+	return nil
+}
+
+// DeleteScheduled удаляет аккаунты, у которых истек срок ожидания удаления.
+// Объявления, API-ключи и коды восстановления удаляются каскадно.
+func (r *UserRepository) DeleteScheduled(ctx context.Context, now time.Time) (int64, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM uuser WHERE delete_after <= $1`, now)
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"error":     err,
+		}).Error("Ошибка при удалении аккаунтов")
+
+		return 0, entity.NewError(entity.ErrInternal,
+			fmt.Errorf("ошибка при удалении аккаунтов: %w", err))
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, entity.NewError(entity.ErrInternal, err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type PromotionRepository interface {
+	// Create планирует продвижение опубликованного объявления.
+	Create(ctx context.Context, promotion *entity.Promotion) (*entity.Promotion, error)
+	GetByAdvertisementID(ctx context.Context, adID int) ([]entity.Promotion, error)
+	// Cancel отменяет запланированное или идущее продвижение объявления.
+	Cancel(ctx context.Context, id, adID int) error
+	// ExpireFinished закрывает закончившиеся продвижения и возвращает их число.
+	ExpireFinished(ctx context.Context) (int64, error)
+}
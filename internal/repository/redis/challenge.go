@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/gomodule/redigo/redis"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	challengePrefix         = "2fa_challenge:"
+	challengeAttemptsPrefix = "2fa_challenge_attempts:"
+)
+
+// ChallengeRepository хранит короткоживущие токены второго шага входа.
+type ChallengeRepository struct {
+	conn redis.Conn
+}
+
+func NewChallengeRepository(conn redis.Conn) (repository.ChallengeRepository, error) {
+	return &ChallengeRepository{conn: conn}, nil
+}
+
+func (r *ChallengeRepository) CreateChallenge(ctx context.Context, userID int, ttl time.Duration) (string, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"id":        userID,
+	}).Info("создание токена второго шага входа в Redis CreateChallenge")
+
+	token := uuid.NewString()
+	seconds := int(ttl.Seconds())
+
+	_, err := r.conn.Do("SET", challengePrefix+token, fmt.Sprintf("%d", userID), "EX", seconds, "NX")
+	if err != nil {
+		return "", entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось создать токен второго шага для пользователя с id=%d :%w", userID, err),
+		)
+	}
+
+	_, err = r.conn.Do("SET", challengeAttemptsPrefix+token, 0, "EX", seconds)
+	if err != nil {
+		return "", entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось создать счетчик попыток для пользователя с id=%d :%w", userID, err),
+		)
+	}
+
+	return token, nil
+}
+
+func (r *ChallengeRepository) GetChallenge(ctx context.Context, token string) (int, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+	}).Info("получение токена второго шага входа в Redis GetChallenge")
+
+	userID, err := redis.Int(r.conn.Do("GET", challengePrefix+token))
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return 0, entity.NewError(
+				entity.ErrUnauthorized,
+				fmt.Errorf("токен второго шага не найден или истек"),
+			)
+		}
+		return 0, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось получить токен второго шага :%w", err),
+		)
+	}
+
+	return userID, nil
+}
+
+func (r *ChallengeRepository) IncrementAttempts(ctx context.Context, token string) (int, error) {
+	attempts, err := redis.Int(r.conn.Do("INCR", challengeAttemptsPrefix+token))
+	if err != nil {
+		return 0, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось увеличить счетчик попыток :%w", err),
+		)
+	}
+	return attempts, nil
+}
+
+func (r *ChallengeRepository) DeleteChallenge(ctx context.Context, token string) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+	}).Info("удаление токена второго шага входа в Redis DeleteChallenge")
+
+	_, err := r.conn.Do("DEL", challengePrefix+token, challengeAttemptsPrefix+token)
+	if err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось удалить токен второго шага :%w", err),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+)
+
+const contactVerificationPrefix = "contact_verification:"
+
+// ContactVerificationRepository хранит отправленные коды подтверждения контактов.
+// Значение контакта, хеш кода и счетчик попыток лежат в одном hash-ключе.
+type ContactVerificationRepository struct {
+	conn redis.Conn
+}
+
+func NewContactVerificationRepository(conn redis.Conn) (repository.ContactVerificationRepository, error) {
+	return &ContactVerificationRepository{conn: conn}, nil
+}
+
+func contactVerificationKey(userID int, channel entity.ContactChannel) string {
+	return fmt.Sprintf("%s%s:%d", contactVerificationPrefix, channel, userID)
+}
+
+func (r *ContactVerificationRepository) Create(
+	ctx context.Context,
+	userID int,
+	channel entity.ContactChannel,
+	v *entity.ContactVerification,
+	ttl time.Duration,
+) error {
+	l.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"id":        userID,
+		"channel":   channel,
+	}).Info("создание кода подтверждения контакта в Redis Create")
+
+	key := contactVerificationKey(userID, channel)
+
+	// Новый код заменяет предыдущий вместе со счетчиком попыток.
+	if _, err := r.conn.Do("DEL", key); err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось удалить предыдущий код подтверждения пользователя с id=%d :%w", userID, err),
+		)
+	}
+
+	_, err := r.conn.Do("HSET", key, "value", v.Value, "code_hash", v.CodeHash, "attempts", 0)
+	if err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось сохранить код подтверждения пользователя с id=%d :%w", userID, err),
+		)
+	}
+
+	if _, err := r.conn.Do("EXPIRE", key, int(ttl.Seconds())); err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось установить TTL кода подтверждения пользователя с id=%d :%w", userID, err),
+		)
+	}
+
+	return nil
+}
+
+func (r *ContactVerificationRepository) Get(ctx context.Context, userID int, channel entity.ContactChannel) (*entity.ContactVerification, error) {
+	values, err := redis.Values(r.conn.Do("HMGET", contactVerificationKey(userID, channel), "value", "code_hash"))
+	if err != nil {
+		return nil, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось получить код подтверждения пользователя с id=%d :%w", userID, err),
+		)
+	}
+
+	var v entity.ContactVerification
+	if _, err := redis.Scan(values, &v.Value, &v.CodeHash); err != nil {
+		return nil, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось разобрать код подтверждения пользователя с id=%d :%w", userID, err),
+		)
+	}
+	if v.Value == "" || len(v.CodeHash) == 0 {
+		return nil, entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("код подтверждения не найден или истек, запросите новый"),
+		)
+	}
+
+	return &v, nil
+}
+
+func (r *ContactVerificationRepository) IncrementAttempts(ctx context.Context, userID int, channel entity.ContactChannel) (int, error) {
+	attempts, err := redis.Int(r.conn.Do("HINCRBY", contactVerificationKey(userID, channel), "attempts", 1))
+	if err != nil && !errors.Is(err, redis.ErrNil) {
+		return 0, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось увеличить счетчик попыток подтверждения :%w", err),
+		)
+	}
+	return attempts, nil
+}
+
+func (r *ContactVerificationRepository) Delete(ctx context.Context, userID int, channel entity.ContactChannel) error {
+	if _, err := r.conn.Do("DEL", contactVerificationKey(userID, channel)); err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось удалить код подтверждения пользователя с id=%d :%w", userID, err),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/connector"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+)
+
+const eventChannelPrefix = "events:user:"
+
+// EventRepository рассылает события пользователей через Redis Pub/Sub,
+// поэтому событие дойдет до потока на любом экземпляре приложения.
+type EventRepository struct {
+	conn redis.Conn
+	cfg  config.RedisConfig
+}
+
+func NewEventRepository(conn redis.Conn, cfg config.RedisConfig) (repository.EventRepository, error) {
+	return &EventRepository{conn: conn, cfg: cfg}, nil
+}
+
+func (r *EventRepository) Publish(ctx context.Context, event *entity.Event) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    event.UserID,
+		"type":      event.Type,
+	}).Info("публикация события в Redis Publish")
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось сериализовать событие: %w", err),
+		)
+	}
+
+	_, err = r.conn.Do("PUBLISH", eventChannelPrefix+strconv.Itoa(event.UserID), payload)
+	if err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось опубликовать событие для пользователя с id=%d :%w", event.UserID, err),
+		)
+	}
+	return nil
+}
+
+// Subscribe открывает отдельное соединение: в режиме подписки Redis не выполняет других команд.
+func (r *EventRepository) Subscribe(ctx context.Context, handle func(event *entity.Event)) error {
+	conn, err := connector.NewRedisConnection(r.cfg)
+	if err != nil {
+		return err
+	}
+
+	psc := redis.PubSubConn{Conn: conn}
+	defer psc.Close()
+
+	if err := psc.PSubscribe(eventChannelPrefix + "*"); err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось подписаться на события :%w", err),
+		)
+	}
+
+	// Receive блокируется до прихода сообщения, поэтому при отмене контекста закрываем соединение.
+	stop := context.AfterFunc(ctx, func() { _ = psc.Close() })
+	defer stop()
+
+	for {
+		switch msg := psc.Receive().(type) {
+		case redis.Message:
+			var event entity.Event
+			if err := json.Unmarshal(msg.Data, &event); err != nil {
+				l.Log.WithFields(logrus.Fields{
+					"channel": msg.Channel,
+					"error":   err,
+				}).Error("не удалось разобрать событие из Redis")
+				continue
+			}
+			handle(&event)
+		case error:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return entity.NewError(
+				entity.ErrInternal,
+				fmt.Errorf("соединение подписки на события оборвалось :%w", msg),
+			)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	loginFailuresPrefix = "login_failures:"
+	loginLockoutPrefix  = "login_lockout:"
+)
+
+type LoginAttemptRepository struct {
+	conn redis.Conn
+}
+
+func NewLoginAttemptRepository(conn redis.Conn) (repository.LoginAttemptRepository, error) {
+	return &LoginAttemptRepository{conn: conn}, nil
+}
+
+func (r *LoginAttemptRepository) GetLockout(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := redis.Int64(r.conn.Do("PTTL", loginLockoutPrefix+key))
+	if err != nil {
+		return 0, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось получить блокировку входа для ключа=%s :%w", key, err),
+		)
+	}
+	// -2: ключа нет, -1: ключ без TTL (не должен встречаться)
+	if ttl <= 0 {
+		return 0, nil
+	}
+	return time.Duration(ttl) * time.Millisecond, nil
+}
+
+// registerFailureScript увеличивает счетчик и ставит TTL одной командой: счетчик без TTL
+// означал бы вечную блокировку. TTL ставится и счетчику, который остался без него.
+// Окно отсчитывается от первой неудачной попытки.
+var registerFailureScript = redis.NewScript(1, `
+local count = redis.call('INCR', KEYS[1])
+if redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+func (r *LoginAttemptRepository) RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	count, err := redis.Int(registerFailureScript.Do(r.conn, loginFailuresPrefix+key, window.Milliseconds()))
+	if err != nil {
+		return 0, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось увеличить счетчик неудачных входов для ключа=%s :%w", key, err),
+		)
+	}
+	return count, nil
+}
+
+func (r *LoginAttemptRepository) Lock(ctx context.Context, key string, duration time.Duration) error {
+	l.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"key":       key,
+		"duration":  duration.String(),
+	}).Info("временная блокировка входа в Redis Lock")
+
+	_, err := r.conn.Do("SET", loginLockoutPrefix+key, 1, "PX", duration.Milliseconds())
+	if err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось заблокировать вход для ключа=%s :%w", key, err),
+		)
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	_, err := r.conn.Do("DEL", loginFailuresPrefix+key, loginLockoutPrefix+key)
+	if err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось сбросить счетчик неудачных входов для ключа=%s :%w", key, err),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package redis
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	passwordResetPrefix     = "password_reset:"
+	passwordResetUserPrefix = "password_reset_user:"
+)
+
+// PasswordResetRepository хранит хеши одноразовых токенов сброса пароля.
+type PasswordResetRepository struct {
+	conn redis.Conn
+}
+
+func NewPasswordResetRepository(conn redis.Conn) (repository.PasswordResetRepository, error) {
+	return &PasswordResetRepository{conn: conn}, nil
+}
+
+func (r *PasswordResetRepository) CreateToken(ctx context.Context, userID int, tokenHash []byte, ttl time.Duration) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"id":        userID,
+	}).Info("создание токена сброса пароля в Redis CreateToken")
+
+	userKey := passwordResetUserPrefix + strconv.Itoa(userID)
+	seconds := int(ttl.Seconds())
+
+	previous, err := redis.String(r.conn.Do("GET", userKey))
+	if err != nil && !errors.Is(err, redis.ErrNil) {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось получить токен сброса пароля пользователя с id=%d :%w", userID, err),
+		)
+	}
+	if previous != "" {
+		if _, err := r.conn.Do("DEL", passwordResetPrefix+previous); err != nil {
+			return entity.NewError(
+				entity.ErrInternal,
+				fmt.Errorf("не удалось удалить предыдущий токен сброса пароля пользователя с id=%d :%w", userID, err),
+			)
+		}
+	}
+
+	hashHex := hex.EncodeToString(tokenHash)
+
+	_, err = r.conn.Do("SET", passwordResetPrefix+hashHex, userID, "EX", seconds)
+	if err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось создать токен сброса пароля для пользователя с id=%d :%w", userID, err),
+		)
+	}
+
+	_, err = r.conn.Do("SET", userKey, hashHex, "EX", seconds)
+	if err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось сохранить токен сброса пароля пользователя с id=%d :%w", userID, err),
+		)
+	}
+
+	return nil
+}
+
+func (r *PasswordResetRepository) ConsumeToken(ctx context.Context, tokenHash []byte) (int, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+	}).Info("использование токена сброса пароля в Redis ConsumeToken")
+
+	// GETDEL атомарен: два параллельных запроса с одним токеном не пройдут оба.
+	userID, err := redis.Int(r.conn.Do("GETDEL", passwordResetPrefix+hex.EncodeToString(tokenHash)))
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return 0, entity.NewError(
+				entity.ErrBadRequest,
+				fmt.Errorf("ссылка для сброса пароля недействительна или истекла"),
+			)
+		}
+		return 0, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось получить токен сброса пароля :%w", err),
+		)
+	}
+
+	if _, err := r.conn.Do("DEL", passwordResetUserPrefix+strconv.Itoa(userID)); err != nil {
+		return 0, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось удалить токен сброса пароля пользователя с id=%d :%w", userID, err),
+		)
+	}
+
+	return userID, nil
+}
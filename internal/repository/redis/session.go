@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
@@ -205,3 +206,111 @@ func (r *SessionRepository) DeleteAllSessions(ctx context.Context, userID int) e
 
 	return nil
 }
+
+func (r *SessionRepository) DeleteOtherSessions(ctx context.Context, userID int, keepToken string) error {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"id":        userID,
+	}).Info("удаление остальных сессий пользователя в Redis DeleteOtherSessions")
+
+	userSessionsKey := userSessionsPrefix + strconv.Itoa(userID)
+
+	sessions, err := redis.Strings(r.conn.Do("SMEMBERS", userSessionsKey))
+	if err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось получить активные сессии пользователя по ключу=%s :%w", userSessionsKey, err),
+		)
+	}
+
+	for _, session := range sessions {
+		if session == keepToken {
+			continue
+		}
+
+		_, err = r.conn.Do("DEL", session)
+		if err != nil {
+			return entity.NewError(
+				entity.ErrInternal,
+				fmt.Errorf("не удалось удалить сессию из активные сессии пользователя c ключом=%s :%w", session, err),
+			)
+		}
+
+		_, err = r.conn.Do("SREM", userSessionsKey, session)
+		if err != nil {
+			return entity.NewError(
+				entity.ErrInternal,
+				fmt.Errorf("не удалось удалить сессию с ключом=%s из активных сессий пользователя :%w", userSessionsKey, err),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) ListSessions(ctx context.Context, userID int) ([]entity.Session, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"id":        userID,
+	}).Info("получение активных сессий пользователя в Redis ListSessions")
+
+	userSessionsKey := userSessionsPrefix + strconv.Itoa(userID)
+
+	tokens, err := redis.Strings(r.conn.Do("SMEMBERS", userSessionsKey))
+	if err != nil {
+		return nil, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось получить активные сессии пользователя по ключу=%s :%w", userSessionsKey, err),
+		)
+	}
+
+	now := time.Now()
+	sessions := make([]entity.Session, 0, len(tokens))
+	for _, token := range tokens {
+		ttl, err := redis.Int64(r.conn.Do("TTL", token))
+		if err != nil {
+			return nil, entity.NewError(
+				entity.ErrInternal,
+				fmt.Errorf("не удалось получить TTL сессии :%w", err),
+			)
+		}
+		// Сессия уже истекла, но осталась в списке активных.
+		if ttl < 0 {
+			continue
+		}
+		sessions = append(sessions, entity.Session{
+			Token:     token,
+			ExpiresAt: now.Add(time.Duration(ttl) * time.Second),
+		})
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) GetSessionExpiry(ctx context.Context, sessionToken string) (time.Time, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	l.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+	}).Info("получение срока действия сессии в Redis GetSessionExpiry")
+
+	ttl, err := redis.Int64(r.conn.Do("TTL", sessionToken))
+	if err != nil {
+		return time.Time{}, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось получить TTL сессии :%w", err),
+		)
+	}
+	if ttl < 0 {
+		return time.Time{}, entity.NewError(
+			entity.ErrNotFound,
+			fmt.Errorf("сессия не найдена или не имеет срока действия"),
+		)
+	}
+
+	return time.Now().Add(time.Duration(ttl) * time.Second), nil
+}
@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+)
+
+const similarAdsPrefix = "similar_ads:"
+
+type SimilarAdsCacheRepository struct {
+	conn redis.Conn
+}
+
+func NewSimilarAdsCacheRepository(conn redis.Conn) (repository.SimilarAdsCacheRepository, error) {
+	return &SimilarAdsCacheRepository{conn: conn}, nil
+}
+
+func (r *SimilarAdsCacheRepository) Get(ctx context.Context, adID int) ([]entity.Advertisement, bool, error) {
+	l.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"adID":      adID,
+	}).Info("получение похожих объявлений из Redis Get")
+
+	data, err := redis.Bytes(r.conn.Do("GET", similarAdsPrefix+strconv.Itoa(adID)))
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return nil, false, nil
+		}
+		return nil, false, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось получить похожие объявления для id=%d :%w", adID, err),
+		)
+	}
+
+	var ads []entity.Advertisement
+	if err := json.Unmarshal(data, &ads); err != nil {
+		return nil, false, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось разобрать похожие объявления для id=%d :%w", adID, err),
+		)
+	}
+	return ads, true, nil
+}
+
+func (r *SimilarAdsCacheRepository) Set(ctx context.Context, adID int, ads []entity.Advertisement, ttl time.Duration) error {
+	l.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"adID":      adID,
+	}).Info("сохранение похожих объявлений в Redis Set")
+
+	data, err := json.Marshal(ads)
+	if err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось сериализовать похожие объявления для id=%d :%w", adID, err),
+		)
+	}
+
+	if _, err := r.conn.Do("SET", similarAdsPrefix+strconv.Itoa(adID), data, "EX", int(ttl.Seconds())); err != nil {
+		return entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("не удалось сохранить похожие объявления для id=%d :%w", adID, err),
+		)
+	}
+	return nil
+}
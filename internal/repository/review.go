@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type ReviewRepository interface {
+	Create(ctx context.Context, review *entity.Review) (*entity.Review, error)
+	GetByID(ctx context.Context, id int) (*entity.Review, error)
+	GetBySellerID(ctx context.Context, sellerID, offset, limit int) ([]entity.Review, error)
+	SetReply(ctx context.Context, id, sellerID int, reply string) (*entity.Review, error)
+	Report(ctx context.Context, id, userID, hideAfter int) (hidden bool, err error)
+}
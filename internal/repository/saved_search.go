@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search *entity.SavedSearch) (*entity.SavedSearch, error)
+	GetByUserID(ctx context.Context, userID int) ([]entity.SavedSearch, error)
+	CountByUserID(ctx context.Context, userID int) (int, error)
+	Update(ctx context.Context, search *entity.SavedSearch) (*entity.SavedSearch, error)
+	Delete(ctx context.Context, id, userID int) error
+	// MatchNewAds сопоставляет следующие batchSize новых объявлений с поисками
+	// и возвращает число обработанных объявлений. Если сопоставление уже идет
+	// на другом экземпляре, возвращает 0.
+	MatchNewAds(ctx context.Context, batchSize int) (int, error)
+	// ClaimMatches забирает все неотправленные совпадения не более чем userLimit пользователей,
+	// упорядоченные по пользователю и поиску. Забранные совпадения удаляются и повторно не отправляются.
+	ClaimMatches(ctx context.Context, userLimit int) ([]entity.SavedSearchMatch, error)
+}
@@ -1,10 +1,18 @@
 package repository
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
 
 type SessionRepository interface {
 	CreateSession(ctx context.Context, userID int) (string, error)
 	GetSession(ctx context.Context, sessionToken string) (userID int, err error)
 	DeleteSession(ctx context.Context, sessionToken string) error
 	DeleteAllSessions(ctx context.Context, userID int) error
+	DeleteOtherSessions(ctx context.Context, userID int, keepToken string) error
+	ListSessions(ctx context.Context, userID int) ([]entity.Session, error)
+	GetSessionExpiry(ctx context.Context, sessionToken string) (time.Time, error)
 }
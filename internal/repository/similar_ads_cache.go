@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+// SimilarAdsCacheRepository хранит готовые подборки похожих объявлений.
+type SimilarAdsCacheRepository interface {
+	// Get возвращает подборку для объявления; ok=false, если ее нет в кеше.
+	Get(ctx context.Context, adID int) (ads []entity.Advertisement, ok bool, err error)
+	Set(ctx context.Context, adID int, ads []entity.Advertisement, ttl time.Duration) error
+}
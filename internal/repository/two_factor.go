@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type TwoFactorRepository interface {
+	Get(ctx context.Context, userID int) (*entity.TwoFactor, error)
+	SetPendingSecret(ctx context.Context, userID int, secret string) error
+	Enable(ctx context.Context, userID int, lastStep int64, recoveryCodeHashes [][]byte) error
+	Disable(ctx context.Context, userID int) error
+	UpdateLastStep(ctx context.Context, userID int, lastStep int64) error
+	ReplaceRecoveryCodes(ctx context.Context, userID int, recoveryCodeHashes [][]byte) error
+	UseRecoveryCode(ctx context.Context, userID int, codeHash []byte) (bool, error)
+}
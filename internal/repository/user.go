@@ -2,12 +2,21 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 )
 
 type UserRepository interface {
-	Create(ctx context.Context, login, name, surname string, passwordHash, passwordSalt []byte) (*entity.User, error)
+	Create(ctx context.Context, login, name, surname, passwordHash string) (*entity.User, error)
 	GetByID(ctx context.Context, id int) (*entity.User, error)
 	GetByLogin(ctx context.Context, login string) (*entity.User, error)
+	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+	UpdatePasswordHash(ctx context.Context, id int, passwordHash string) error
+	UpdateProfile(ctx context.Context, id int, update *entity.ProfileUpdate) (*entity.User, error)
+	SetContact(ctx context.Context, id int, channel entity.ContactChannel, value string) error
+	MarkContactVerified(ctx context.Context, id int, channel entity.ContactChannel, value string) error
+	ScheduleDeletion(ctx context.Context, id int, deleteAfter time.Time) error
+	CancelDeletion(ctx context.Context, id int) error
+	DeleteScheduled(ctx context.Context, now time.Time) (int64, error)
 }
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 
 	swagger "github.com/swaggo/http-swagger"
@@ -13,13 +14,22 @@ import (
 )
 
 type Server struct {
-	httpServer *http.Server
-	config     *config.Config
+	httpServer  *http.Server
+	config      *config.Config
+	middlewares []middleware.Middleware
+
+	// фоновые задачи живут, пока сервер не остановлен
+	jobsCtx  context.Context
+	stopJobs context.CancelFunc
+	jobsWG   sync.WaitGroup
 }
 
 func NewServer(cfg *config.Config) *Server {
-	return &Server{
-		config: cfg,
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	s := &Server{
+		config:   cfg,
+		jobsCtx:  jobsCtx,
+		stopJobs: stopJobs,
 		httpServer: &http.Server{
 			Addr:           cfg.HTTP.Host + ":" + cfg.HTTP.Port,
 			ReadTimeout:    cfg.HTTP.ReadTimeout,
@@ -27,6 +37,26 @@ func NewServer(cfg *config.Config) *Server {
 			MaxHeaderBytes: cfg.HTTP.MaxHeaderBytes,
 		},
 	}
+	// Потоки событий не завершаются сами, и Shutdown ждал бы их до таймаута.
+	// Отмена фоновых задач в начале остановки закрывает их, остальные запросы дорабатывают.
+	s.httpServer.RegisterOnShutdown(stopJobs)
+	return s
+}
+
+// Use добавляет middleware, выполняемые после базовой цепочки (request ID уже доступен).
+// Вызывается до SetupRoutes.
+func (s *Server) Use(mw ...middleware.Middleware) {
+	s.middlewares = append(s.middlewares, mw...)
+}
+
+// Go запускает фоновую задачу. Контекст задачи отменяется в Stop,
+// и Stop дожидается ее завершения.
+func (s *Server) Go(job func(ctx context.Context)) {
+	s.jobsWG.Add(1)
+	go func() {
+		defer s.jobsWG.Done()
+		job(s.jobsCtx)
+	}()
 }
 
 func (s *Server) SetupRoutes(routeConfig func(*http.ServeMux)) {
@@ -42,13 +72,17 @@ func (s *Server) SetupRoutes(routeConfig func(*http.ServeMux)) {
 	mainRouter.Handle("/swagger/", swagger.WrapHandler)
 	routeConfig(subrouter)
 
-	handler := middleware.CreateMiddlewareChain(
+	chain := append([]middleware.Middleware{
+		// ID запроса выдается первым, чтобы попасть и в ответы recovery и CSRF.
+		middleware.RequestIDMiddleware(),
+		middleware.ClientIPMiddleware(s.config.HTTP.TrustedProxyNets),
 		middleware.RecoveryMiddleware(),
 		middleware.CORS(s.config.HTTP.CORSAllowedOrigins),
 		middleware.CSRFMiddleware(s.config.CSRF),
-		middleware.RequestIDMiddleware(),
 		middleware.AccessLogMiddleware(),
-	)(mainRouter)
+	}, s.middlewares...)
+
+	handler := middleware.CreateMiddlewareChain(chain...)(mainRouter)
 
 	s.httpServer.Handler = handler
 }
@@ -60,5 +94,11 @@ func (s *Server) Run() error {
 func (s *Server) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	return s.httpServer.Shutdown(ctx)
+
+	err := s.httpServer.Shutdown(ctx)
+
+	s.stopJobs()
+	s.jobsWG.Wait()
+
+	return err
 }
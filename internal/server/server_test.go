@@ -1,12 +1,11 @@
 package server
 
 import (
-	"ResuMatch/internal/config"
-	// "context"
 	"net/http"
 	"testing"
 	"time"
 
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -0,0 +1,144 @@
+package http
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// ExportAccount godoc
+// @Tags User
+// @Summary Выгрузка персональных данных
+// @Description Возвращает профиль, объявления, API-ключи и активные сессии пользователя.
+// @Description По умолчанию отдается ZIP-архив с JSON-файлами, с format=json — один JSON-документ.
+// @Description Избранного в сервисе нет, поэтому в выгрузку оно не входит.
+// @Produce application/zip
+// @Produce json
+// @Param format query string false "Формат выгрузки" Enums(zip, json)
+// @Success 200 {object} dto.AccountExport
+// @Failure 400 {object} utils.APIError "Неизвестный формат"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/me/export [get]
+// @Security session_cookie
+func (h *UserHandler) ExportAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "zip" && format != "json" {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	export, err := h.account.Export(ctx, principal.UserID, principal.SessionID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	filename := fmt.Sprintf("account-%d-%s", principal.UserID, export.ExportedAt.Format("20060102"))
+	w.Header().Set("Cache-Control", "no-store")
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		if err := json.NewEncoder(w).Encode(export); err != nil {
+			utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, filename))
+	if err := writeAccountArchive(w, export); err != nil {
+		// Заголовки уже отправлены, поэтому ошибку можно только залогировать.
+		l.Log.WithFields(logrus.Fields{
+			"requestID": GlobalUtils.GetRequestID(ctx),
+			"userID":    principal.UserID,
+			"error":     err,
+		}).Error("Ошибка при формировании архива с данными пользователя")
+	}
+}
+
+func writeAccountArchive(w http.ResponseWriter, export *dto.AccountExport) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", export.Profile},
+		{"advertisements.json", export.Advertisements},
+		{"api_keys.json", export.APIKeys},
+		{"sessions.json", export.Sessions},
+		{"security.json", map[string]any{
+			"two_factor_enabled": export.TwoFactorEnabled,
+			"exported_at":        export.ExportedAt,
+		}},
+	}
+	for _, file := range files {
+		f, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// DeleteAccount godoc
+// @Tags User
+// @Summary Удаление аккаунта
+// @Description Требует текущий пароль. Все сессии завершаются сразу, а данные удаляются
+// @Description после срока ожидания. Вход в аккаунт до этого момента отменяет удаление.
+// @Accept json
+// @Produce json
+// @Param deleteData body dto.DeleteAccountRequest true "Текущий пароль"
+// @Success 202 {object} dto.DeleteAccountResponse
+// @Failure 400 {object} utils.APIError "Неверный формат запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Неверный пароль"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/me [delete]
+// @Security csrf_token
+// @Security session_cookie
+func (h *UserHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	var req dto.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	resp, err := h.account.ScheduleDeletion(ctx, principal.UserID, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	utils.ClearTokenCookies(w)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
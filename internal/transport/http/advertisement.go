@@ -8,27 +8,32 @@ import (
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
 	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/sanitizer"
 )
 
 type AdvertisementHandler struct {
-	auth          usecase.AuthUsecase
 	advertisement usecase.AdvertisementUsecase
 	cfg           config.CSRFConfig
 }
 
-func NewAdvertisementHandler(auth usecase.AuthUsecase, ad usecase.AdvertisementUsecase, cfg config.CSRFConfig) AdvertisementHandler {
-	return AdvertisementHandler{auth: auth, advertisement: ad, cfg: cfg}
+func NewAdvertisementHandler(ad usecase.AdvertisementUsecase, cfg config.CSRFConfig) AdvertisementHandler {
+	return AdvertisementHandler{advertisement: ad, cfg: cfg}
 }
 
 func (h *AdvertisementHandler) Configure(r *http.ServeMux) {
 	adMux := http.NewServeMux()
 
-	adMux.HandleFunc("POST /create", h.CreateAdvertisement)
-	adMux.HandleFunc("GET /{id}", h.GetAdvertisement)
-	adMux.HandleFunc("GET /all", h.GetAllAdvertisements)
+	adMux.Handle("POST /create", middleware.RequireAuth(entity.ScopeAdsWrite)(http.HandlerFunc(h.CreateAdvertisement)))
+	adMux.Handle("GET /{id}", middleware.OptionalAuth(entity.ScopeAdsRead)(http.HandlerFunc(h.GetAdvertisement)))
+	adMux.Handle("PUT /{id}", middleware.RequireAuth(entity.ScopeAdsWrite)(http.HandlerFunc(h.UpdateAdvertisement)))
+	adMux.Handle("DELETE /{id}", middleware.RequireAuth(entity.ScopeAdsWrite)(http.HandlerFunc(h.DeleteAdvertisement)))
+	adMux.Handle("GET /all", middleware.OptionalAuth(entity.ScopeAdsRead)(http.HandlerFunc(h.GetAllAdvertisements)))
+	adMux.Handle("GET /{id}/similar", middleware.OptionalAuth(entity.ScopeAdsRead)(http.HandlerFunc(h.GetSimilarAdvertisements)))
+	adMux.Handle("POST /{id}/renew", middleware.RequireAuth(entity.ScopeAdsWrite)(http.HandlerFunc(h.RenewAdvertisement)))
 
 	r.Handle("/ad/", http.StripPrefix("/ad", adMux))
 }
@@ -36,11 +41,13 @@ func (h *AdvertisementHandler) Configure(r *http.ServeMux) {
 // CreateAdvertisement godoc
 // @Tags Advertisement
 // @Summary Создание нового объявления
-// @Description Создает новое объявления для авторизованного пользователя. Требует авторизации и CSRF-токена.
+// @Description Создает новое объявления для авторизованного пользователя. Требует авторизации и CSRF-токена
+// @Description либо API-ключа с правом ads:write в заголовке X-API-Key.
 // @Accept json
 // @Produce json
 // @Param advertisementData body dto.CreateAdvertisementRequest true "Данные для создания объявления"
 // @Success 201 {object} dto.AdvertisementShort "Созданное объявление"
+// @Header 201 {string} ETag "Версия объявления для If-Match"
 // @Failure 400 {object} utils.APIError "Неверный формат запроса"
 // @Failure 401 {object} utils.APIError "Не авторизован"
 // @Failure 403 {object} utils.APIError "Доступ запрещен (только для соискателей)"
@@ -48,29 +55,16 @@ func (h *AdvertisementHandler) Configure(r *http.ServeMux) {
 // @Router /ad/create [post]
 // @Security csrf_token
 // @Security session_cookie
+// @Security bearer_token
+// @Security api_key
 func (h *AdvertisementHandler) CreateAdvertisement(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	cookie, err := r.Cookie("session_id")
-	if err != nil {
-		utils.WriteError(w, http.StatusUnauthorized, entity.ErrUnauthorized)
-		return
-	}
-
-	if cookie == nil {
-		utils.WriteError(w, http.StatusUnauthorized, entity.ErrUnauthorized)
-		return
-	}
-
-	userID, err := h.auth.GetUserIDBySession(ctx, cookie.Value)
-	if err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
-		return
-	}
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
 
 	var createAdRequest dto.CreateAdvertisementRequest
 	if err := json.NewDecoder(r.Body).Decode(&createAdRequest); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, entity.ErrBadRequest)
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
 		return
 	}
 
@@ -78,33 +72,47 @@ func (h *AdvertisementHandler) CreateAdvertisement(w http.ResponseWriter, r *htt
 	createAdRequest.Description = sanitizer.StrictPolicy.Sanitize(createAdRequest.Description)
 	createAdRequest.ImageURL = sanitizer.StrictPolicy.Sanitize(createAdRequest.ImageURL)
 
-	ad, err := h.advertisement.Create(ctx, userID, &createAdRequest)
+	ad, err := h.advertisement.Create(ctx, principal.UserID, &createAdRequest)
 	if err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(ad); err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, entity.ErrInternal)
+	body, etag, err := encodeAdvertisement(ad)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
 		return
 	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusCreated)
+	_, _ = w.Write(body)
 }
 
 // GetAdvertisement godoc
 // @Tags Advertisement
 // @Summary Получение объявления по ID
-// @Description Возвращает полную информацию об объявлении по его ID. Доступно всем авторизованным пользователям.
+// @Description Возвращает полную информацию об объявлении по его ID. Доступно всем. Объявление на модерации
+// @Description или отклоненное видят только автор и модераторы, остальным отвечает 404. Статус модерации
+// @Description возвращается только автору.
+// @Description В заголовке ETag возвращаются версия объявления и хеш ответа. Если он совпадает с If-None-Match,
+// @Description ответ 304 приходит без тела.
 // @Produce json
 // @Param id path int true "ID объявления"
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
 // @Success 200 {object} dto.AdvertisementShort "Информация об объявлении"
+// @Header 200 {string} ETag "Версия и хеш объявления"
+// @Success 304 "Объявление не изменилось"
 // @Failure 400 {object} utils.APIError "Неверный ID"
-// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 401 {object} utils.APIError "Недействительный API-ключ"
+// @Failure 403 {object} utils.APIError "У API-ключа нет права ads:read"
 // @Failure 404 {object} utils.APIError "Объявление не найдено"
 // @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
 // @Router /ad/{id} [get]
 // @Security session_cookie
+// @Security bearer_token
+// @Security api_key
 func (h *AdvertisementHandler) GetAdvertisement(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -112,28 +120,261 @@ func (h *AdvertisementHandler) GetAdvertisement(w http.ResponseWriter, r *http.R
 	adIDStr := r.PathValue("id")
 	adID, err := strconv.Atoi(adIDStr)
 	if err != nil {
-		utils.WriteError(w, http.StatusBadRequest, entity.ErrBadRequest)
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var userID int
+	if principal, ok := GlobalUtils.GetPrincipal(ctx); ok {
+		userID = principal.UserID
+	}
+
+	ad, err := h.advertisement.GetByID(ctx, userID, adID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	body, etag, err := encodeAdvertisement(ad)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	// Клиент может хранить объявление, но перед показом должен сверить ETag.
+	w.Header().Set("Cache-Control", "no-cache")
+	if utils.NotModified(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// encodeAdvertisement сериализует объявление и считает ETag по получившемуся телу ответа.
+func encodeAdvertisement(ad *dto.AdvertisementShort) ([]byte, string, error) {
+	body, err := json.Marshal(ad)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, utils.ETag(ad.Version, body), nil
+}
+
+// UpdateAdvertisement godoc
+// @Tags Advertisement
+// @Summary Изменение объявления
+// @Description Полностью заменяет заголовок, описание, изображение и цену своего объявления.
+// @Description Заголовок If-Match с ETag из GET /ad/{id} обязателен: если объявление успело измениться,
+// @Description возвращается 412 и правку нужно повторить на свежей версии. Новый текст проходит
+// @Description те же проверки, что и при создании. Зарезервированное или проданное объявление не меняется.
+// @Accept json
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Param If-Match header string true "ETag объявления"
+// @Param advertisementData body dto.UpdateAdvertisementRequest true "Новое содержимое объявления"
+// @Success 200 {object} dto.AdvertisementShort "Измененное объявление"
+// @Header 200 {string} ETag "Новая версия объявления"
+// @Failure 400 {object} utils.APIError "Неверный формат запроса или ошибки полей"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Чужое объявление"
+// @Failure 404 {object} utils.APIError "Объявление не найдено"
+// @Failure 409 {object} utils.APIError "Объявление зарезервировано, продано или повторяет другое"
+// @Failure 412 {object} utils.APIError "Версия из If-Match устарела"
+// @Failure 428 {object} utils.APIError "Не передан If-Match"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /ad/{id} [put]
+// @Security csrf_token
+// @Security session_cookie
+// @Security bearer_token
+// @Security api_key
+func (h *AdvertisementHandler) UpdateAdvertisement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	var updateAdRequest dto.UpdateAdvertisementRequest
+	if err := json.NewDecoder(r.Body).Decode(&updateAdRequest); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	ad, err := h.advertisement.Update(ctx, principal.UserID, adID, version, &updateAdRequest)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	body, etag, err := encodeAdvertisement(ad)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// DeleteAdvertisement godoc
+// @Tags Advertisement
+// @Summary Удаление объявления
+// @Description Удаляет свое объявление. Заголовок If-Match с ETag из GET /ad/{id} обязателен:
+// @Description если объявление успело измениться, возвращается 412. Зарезервированное объявление не удаляется.
+// @Param id path int true "ID объявления"
+// @Param If-Match header string true "ETag объявления"
+// @Success 204 "Объявление удалено"
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Чужое объявление"
+// @Failure 404 {object} utils.APIError "Объявление не найдено"
+// @Failure 409 {object} utils.APIError "Объявление зарезервировано"
+// @Failure 412 {object} utils.APIError "Версия из If-Match устарела"
+// @Failure 428 {object} utils.APIError "Не передан If-Match"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /ad/{id} [delete]
+// @Security csrf_token
+// @Security session_cookie
+// @Security bearer_token
+// @Security api_key
+func (h *AdvertisementHandler) DeleteAdvertisement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	version, err := utils.IfMatchVersion(r)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	if err := h.advertisement.Delete(ctx, principal.UserID, adID, version); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSimilarAdvertisements godoc
+// @Tags Advertisement
+// @Summary Похожие объявления
+// @Description Опубликованные объявления других авторов, похожие на указанное по тексту и цене, лучшие первыми.
+// @Description Объявления текущего пользователя не показываются. Подборка кешируется на несколько минут.
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Success 200 {object} []dto.AdvertisementResponse "Похожие объявления"
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Недействительный API-ключ"
+// @Failure 403 {object} utils.APIError "У API-ключа нет права ads:read"
+// @Failure 404 {object} utils.APIError "Объявление не найдено"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /ad/{id}/similar [get]
+// @Security session_cookie
+// @Security bearer_token
+// @Security api_key
+func (h *AdvertisementHandler) GetSimilarAdvertisements(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var viewerID int
+	if principal, ok := GlobalUtils.GetPrincipal(ctx); ok {
+		viewerID = principal.UserID
+	}
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
 		return
 	}
 
-	ad, err := h.advertisement.GetByID(ctx, adID)
+	ads, err := h.advertisement.GetSimilar(ctx, viewerID, adID)
 	if err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(ad); err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, entity.ErrInternal)
+	if err := json.NewEncoder(w).Encode(ads); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// RenewAdvertisement godoc
+// @Tags Advertisement
+// @Summary Продление объявления
+// @Description Продлевает срок публикации на стандартный срок от текущего момента. Объявление из архива
+// @Description возвращается в ленту. Число продлений ограничено.
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Success 200 {object} dto.AdvertisementShort "Продленное объявление"
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Чужое объявление"
+// @Failure 404 {object} utils.APIError "Объявление не найдено"
+// @Failure 409 {object} utils.APIError "Объявление продано или исчерпан лимит продлений"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /ad/{id}/renew [post]
+// @Security csrf_token
+// @Security session_cookie
+// @Security bearer_token
+// @Security api_key
+func (h *AdvertisementHandler) RenewAdvertisement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	ad, err := h.advertisement.Renew(ctx, principal.UserID, adID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
+
+	body, etag, err := encodeAdvertisement(ad)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
 }
 
 // GetAllAdvertisements godoc
 // @Tags Advertisement
 // @Summary Получение всех объявлений
 // @Description Возвращает список объявлений с поддержкой пагинации, сортировки и фильтрации по цене.
+// @Description В список попадают только опубликованные объявления: зарезервированные и проданные скрыты.
 // @Produce json
 // @Param limit query int false "Количество объявлений на странице (по умолчанию 10)"
 // @Param offset query int false "Смещение от начала списка (по умолчанию 0)"
@@ -143,24 +384,26 @@ func (h *AdvertisementHandler) GetAdvertisement(w http.ResponseWriter, r *http.R
 // @Param max_price query number false "Максимальная цена фильтрации"
 // @Success 200 {object} []dto.AdvertisementResponse "Список объявлений"
 // @Failure 400 {object} utils.APIError "Некорректные параметры запроса"
+// @Failure 401 {object} utils.APIError "Недействительный API-ключ"
+// @Failure 403 {object} utils.APIError "У API-ключа нет права ads:read"
 // @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
 // @Router /ad/all [get]
+// @Security session_cookie
+// @Security bearer_token
+// @Security api_key
 func (h *AdvertisementHandler) GetAllAdvertisements(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Проверяем авторизацию
 	var userID int
-	if cookie, err := r.Cookie("session_id"); err == nil && cookie != nil {
-		if uid, err := h.auth.GetUserIDBySession(ctx, cookie.Value); err == nil {
-			userID = uid
-		}
+	if principal, ok := GlobalUtils.GetPrincipal(ctx); ok {
+		userID = principal.UserID
 	}
 
 	limit := 10
 	if v := r.URL.Query().Get("limit"); v != "" {
 		l, err := strconv.Atoi(v)
 		if err != nil || l <= 0 || l > 100 {
-			utils.WriteError(w, http.StatusBadRequest, entity.ErrBadRequest)
+			utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
 			return
 		}
 		limit = l
@@ -170,7 +413,7 @@ func (h *AdvertisementHandler) GetAllAdvertisements(w http.ResponseWriter, r *ht
 	if v := r.URL.Query().Get("offset"); v != "" {
 		o, err := strconv.Atoi(v)
 		if err != nil || o < 0 {
-			utils.WriteError(w, http.StatusBadRequest, entity.ErrBadRequest)
+			utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
 			return
 		}
 		offset = o
@@ -192,7 +435,7 @@ func (h *AdvertisementHandler) GetAllAdvertisements(w http.ResponseWriter, r *ht
 	if v := r.URL.Query().Get("min_price"); v != "" {
 		f, err := strconv.ParseFloat(v, 64)
 		if err != nil || f < 0 {
-			utils.WriteError(w, http.StatusBadRequest, entity.ErrBadRequest)
+			utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
 			return
 		}
 		minPricePtr = &f
@@ -200,7 +443,7 @@ func (h *AdvertisementHandler) GetAllAdvertisements(w http.ResponseWriter, r *ht
 	if v := r.URL.Query().Get("max_price"); v != "" {
 		f, err := strconv.ParseFloat(v, 64)
 		if err != nil || f < 0 {
-			utils.WriteError(w, http.StatusBadRequest, entity.ErrBadRequest)
+			utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
 			return
 		}
 		maxPricePtr = &f
@@ -208,14 +451,14 @@ func (h *AdvertisementHandler) GetAllAdvertisements(w http.ResponseWriter, r *ht
 
 	ads, err := h.advertisement.GetAll(ctx, userID, offset, limit, sortBy, order, minPricePtr, maxPricePtr)
 	if err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(ads); err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, entity.ErrInternal)
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
 		return
 	}
 }
@@ -0,0 +1,255 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAdvertisementHandler_Get(t *testing.T) {
+	t.Parallel()
+
+	ad := &dto.AdvertisementShort{Title: "Велосипед", Version: 3}
+	body, etag, err := encodeAdvertisement(ad)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		ifNoneMatch    string
+		principal      *entity.Principal
+		viewerID       int
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Аноним получает объявление и ETag",
+			expectedStatus: http.StatusOK,
+			expectedBody:   string(body),
+		},
+		{
+			name:           "Автор получает объявление",
+			principal:      testSessionPrincipal,
+			viewerID:       1,
+			expectedStatus: http.StatusOK,
+			expectedBody:   string(body),
+		},
+		{
+			name:           "Объявление не изменилось",
+			ifNoneMatch:    etag,
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "Слабый ETag тоже совпадает",
+			ifNoneMatch:    `"1-0000000000000000", W/` + etag,
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "Старая версия получает тело",
+			ifNoneMatch:    `"2-0000000000000000"`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   string(body),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ads := mock.NewMockAdvertisementUsecase(ctrl)
+			ads.EXPECT().GetByID(gomock.Any(), tc.viewerID, 10).Return(ad, nil)
+			handler := NewAdvertisementHandler(ads, config.CSRFConfig{})
+
+			req := httptest.NewRequest(http.MethodGet, "/ad/10", nil)
+			if tc.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Equal(t, etag, rr.Header().Get("ETag"))
+			require.Equal(t, tc.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestAdvertisementHandler_Update(t *testing.T) {
+	t.Parallel()
+
+	const body = `{"title":"Велосипед","description":"Почти новый","image_url":"https://example.com/bike.png","price":100}`
+
+	testCases := []struct {
+		name           string
+		ifMatch        string
+		mockSetup      func(*mock.MockAdvertisementUsecase)
+		expectedStatus int
+		expectedETag   bool
+	}{
+		{
+			name:    "Правка с актуальной версией",
+			ifMatch: `"3-1f2e3d4c5b6a7980"`,
+			mockSetup: func(m *mock.MockAdvertisementUsecase) {
+				m.EXPECT().Update(gomock.Any(), 1, 10, 3, gomock.Any()).
+					Return(&dto.AdvertisementShort{Version: 4}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   true,
+		},
+		{
+			name:    "If-Match: * не сверяет версию",
+			ifMatch: "*",
+			mockSetup: func(m *mock.MockAdvertisementUsecase) {
+				m.EXPECT().Update(gomock.Any(), 1, 10, 0, gomock.Any()).
+					Return(&dto.AdvertisementShort{Version: 4}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   true,
+		},
+		{
+			name:           "Без If-Match",
+			mockSetup:      func(*mock.MockAdvertisementUsecase) {},
+			expectedStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name:           "Слабый ETag не подходит для записи",
+			ifMatch:        `W/"3-1f2e3d4c5b6a7980"`,
+			mockSetup:      func(*mock.MockAdvertisementUsecase) {},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "Версия устарела",
+			ifMatch: `"2-1f2e3d4c5b6a7980"`,
+			mockSetup: func(m *mock.MockAdvertisementUsecase) {
+				m.EXPECT().Update(gomock.Any(), 1, 10, 2, gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrPreconditionFailed, fmt.Errorf("версия объявления id=10 изменилась")))
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "Объявление зарезервировано",
+			ifMatch: `"3-1f2e3d4c5b6a7980"`,
+			mockSetup: func(m *mock.MockAdvertisementUsecase) {
+				m.EXPECT().Update(gomock.Any(), 1, 10, 3, gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrConflict, fmt.Errorf("объявление id=10 зарезервировано")))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ads := mock.NewMockAdvertisementUsecase(ctrl)
+			tc.mockSetup(ads)
+			handler := NewAdvertisementHandler(ads, config.CSRFConfig{})
+
+			req := httptest.NewRequest(http.MethodPut, "/ad/10", strings.NewReader(body))
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			rr := serveWithPrincipal(handler.Configure, req, testSessionPrincipal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Equal(t, tc.expectedETag, strings.HasPrefix(rr.Header().Get("ETag"), `"4-`))
+		})
+	}
+}
+
+func TestAdvertisementHandler_Delete(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		ifMatch        string
+		principal      *entity.Principal
+		mockSetup      func(*mock.MockAdvertisementUsecase)
+		expectedStatus int
+	}{
+		{
+			name:      "Удаление с актуальной версией",
+			ifMatch:   `"3-1f2e3d4c5b6a7980"`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockAdvertisementUsecase) {
+				m.EXPECT().Delete(gomock.Any(), 1, 10, 3).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:      "Удаление по API-ключу с правом записи",
+			ifMatch:   "*",
+			principal: &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey, Scopes: []string{entity.ScopeAdsWrite}},
+			mockSetup: func(m *mock.MockAdvertisementUsecase) {
+				m.EXPECT().Delete(gomock.Any(), 1, 10, 0).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Без If-Match",
+			principal:      testSessionPrincipal,
+			mockSetup:      func(*mock.MockAdvertisementUsecase) {},
+			expectedStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name:      "Объявление зарезервировали",
+			ifMatch:   `"3-1f2e3d4c5b6a7980"`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockAdvertisementUsecase) {
+				m.EXPECT().Delete(gomock.Any(), 1, 10, 3).
+					Return(entity.NewError(entity.ErrConflict, fmt.Errorf("объявление id=10 зарезервировано")))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "API-ключ только на чтение",
+			ifMatch:        "*",
+			principal:      &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey, Scopes: []string{entity.ScopeAdsRead}},
+			mockSetup:      func(*mock.MockAdvertisementUsecase) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Без входа",
+			ifMatch:        "*",
+			mockSetup:      func(*mock.MockAdvertisementUsecase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ads := mock.NewMockAdvertisementUsecase(ctrl)
+			tc.mockSetup(ads)
+			handler := NewAdvertisementHandler(ads, config.CSRFConfig{})
+
+			req := httptest.NewRequest(http.MethodDelete, "/ad/10", nil)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
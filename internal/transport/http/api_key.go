@@ -0,0 +1,182 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+type APIKeyHandler struct {
+	apiKey usecase.APIKeyUsecase
+	cfg    config.CSRFConfig
+}
+
+func NewAPIKeyHandler(apiKey usecase.APIKeyUsecase, cfg config.CSRFConfig) APIKeyHandler {
+	return APIKeyHandler{apiKey: apiKey, cfg: cfg}
+}
+
+func (h *APIKeyHandler) Configure(r *http.ServeMux) {
+	apiKeyMux := http.NewServeMux()
+
+	apiKeyMux.HandleFunc("POST /create", h.CreateAPIKey)
+	apiKeyMux.HandleFunc("GET /all", h.GetAPIKeys)
+	apiKeyMux.HandleFunc("PATCH /{id}", h.UpdateAPIKey)
+	apiKeyMux.HandleFunc("DELETE /{id}", h.DeleteAPIKey)
+
+	r.Handle("/apikey/", http.StripPrefix("/apikey", middleware.RequireSession()(apiKeyMux)))
+}
+
+// CreateAPIKey godoc
+// @Tags APIKey
+// @Summary Создание API-ключа
+// @Description Создает персональный API-ключ. Ключ в открытом виде возвращается только в этом ответе,
+// @Description на сервере хранится лишь его хеш. Управлять ключами можно только из сессии.
+// @Accept json
+// @Produce json
+// @Param apiKeyData body dto.CreateAPIKeyRequest true "Название, права и срок действия ключа"
+// @Success 201 {object} dto.APIKeyCreatedResponse "Созданный ключ"
+// @Failure 400 {object} utils.APIError "Неверный формат запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Превышено количество ключей"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /apikey/create [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	var createRequest dto.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&createRequest); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	key, err := h.apiKey.Create(ctx, principal.UserID, &createRequest)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(key); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetAPIKeys godoc
+// @Tags APIKey
+// @Summary Список API-ключей
+// @Description Возвращает API-ключи текущего пользователя без секретной части.
+// @Produce json
+// @Success 200 {object} []dto.APIKeyResponse "Список ключей"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /apikey/all [get]
+// @Security session_cookie
+func (h *APIKeyHandler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	keys, err := h.apiKey.GetByUserID(ctx, principal.UserID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(keys); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// UpdateAPIKey godoc
+// @Tags APIKey
+// @Summary Переименование API-ключа
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ключа"
+// @Param apiKeyData body dto.UpdateAPIKeyRequest true "Новое название ключа"
+// @Success 200 {object} dto.APIKeyResponse "Обновленный ключ"
+// @Failure 400 {object} utils.APIError "Неверный формат запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Ключ не найден"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /apikey/{id} [patch]
+// @Security csrf_token
+// @Security session_cookie
+func (h *APIKeyHandler) UpdateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	keyID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var updateRequest dto.UpdateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&updateRequest); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	key, err := h.apiKey.Update(ctx, principal.UserID, keyID, &updateRequest)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(key); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// DeleteAPIKey godoc
+// @Tags APIKey
+// @Summary Отзыв API-ключа
+// @Param id path int true "ID ключа"
+// @Success 204
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Ключ не найден"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /apikey/{id} [delete]
+// @Security csrf_token
+// @Security session_cookie
+func (h *APIKeyHandler) DeleteAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	keyID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.apiKey.Delete(ctx, principal.UserID, keyID); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIKeyHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		principal      *entity.Principal
+		mockSetup      func(*mock.MockAPIKeyUsecase)
+		expectedStatus int
+		expectedKey    string
+	}{
+		{
+			name:      "Создание ключа возвращает его один раз",
+			method:    http.MethodPost,
+			url:       "/apikey/create",
+			body:      `{"name":"Импорт","scopes":["ads:read"]}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockAPIKeyUsecase) {
+				m.EXPECT().Create(gomock.Any(), 1, &dto.CreateAPIKeyRequest{Name: "Импорт", Scopes: []string{entity.ScopeAdsRead}}).
+					Return(&dto.APIKeyCreatedResponse{
+						APIKeyResponse: dto.APIKeyResponse{ID: 5, Prefix: "mk_abc"},
+						Key:            "mk_abcdef",
+					}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedKey:    "mk_abcdef",
+		},
+		{
+			name:      "Лимит ключей",
+			method:    http.MethodPost,
+			url:       "/apikey/create",
+			body:      `{"name":"Импорт","scopes":["ads:read"]}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockAPIKeyUsecase) {
+				m.EXPECT().Create(gomock.Any(), 1, gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrForbidden, fmt.Errorf("достигнуто максимальное количество API-ключей")))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Список ключей",
+			method:    http.MethodGet,
+			url:       "/apikey/all",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockAPIKeyUsecase) {
+				m.EXPECT().GetByUserID(gomock.Any(), 1).Return([]dto.APIKeyResponse{{ID: 5}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Отзыв ключа",
+			method:    http.MethodDelete,
+			url:       "/apikey/5",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockAPIKeyUsecase) {
+				m.EXPECT().Delete(gomock.Any(), 1, 5).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:      "Отзыв чужого ключа",
+			method:    http.MethodDelete,
+			url:       "/apikey/6",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockAPIKeyUsecase) {
+				m.EXPECT().Delete(gomock.Any(), 1, 6).
+					Return(entity.NewError(entity.ErrNotFound, fmt.Errorf("ключ не найден")))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Ключом нельзя управлять ключами",
+			method:         http.MethodGet,
+			url:            "/apikey/all",
+			principal:      &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey, Scopes: []string{entity.ScopeAdsWrite}},
+			mockSetup:      func(*mock.MockAPIKeyUsecase) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Без входа",
+			method:         http.MethodGet,
+			url:            "/apikey/all",
+			mockSetup:      func(*mock.MockAPIKeyUsecase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			apiKeys := mock.NewMockAPIKeyUsecase(ctrl)
+			tc.mockSetup(apiKeys)
+			handler := NewAPIKeyHandler(apiKeys, config.CSRFConfig{})
+
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			if tc.expectedKey != "" {
+				var resp dto.APIKeyCreatedResponse
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+				require.Equal(t, tc.expectedKey, resp.Key)
+			}
+		})
+	}
+}
@@ -10,20 +10,22 @@ import (
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
 )
 
 type AuthHandler struct {
 	auth usecase.AuthUsecase
+	user usecase.UserUsecase
 	cfg  config.CSRFConfig
 }
 
-func NewAuthHandler(auth usecase.AuthUsecase, cfg config.CSRFConfig) AuthHandler {
-	return AuthHandler{auth: auth, cfg: cfg}
+func NewAuthHandler(auth usecase.AuthUsecase, user usecase.UserUsecase, cfg config.CSRFConfig) AuthHandler {
+	return AuthHandler{auth: auth, user: user, cfg: cfg}
 }
 
 func (h *AuthHandler) Configure(r *http.ServeMux) {
 	authMux := http.NewServeMux()
-	authMux.HandleFunc("GET /isAuth", h.IsAuth)
+	authMux.Handle("GET /isAuth", middleware.RequireAuth()(http.HandlerFunc(h.IsAuth)))
 	authMux.HandleFunc("POST /logout", h.Logout)
 	authMux.HandleFunc("POST /logoutAll", h.LogoutAll)
 
@@ -33,9 +35,13 @@ func (h *AuthHandler) Configure(r *http.ServeMux) {
 // IsAuth godoc
 // @Tags Auth
 // @Summary Проверка авторизации
-// @Description Проверяет авторизован пользователь или нет.
+// @Description Проверяет авторизован пользователь или нет (по cookie, bearer-токену или API-ключу).
+// @Description С include=user дополнительно возвращает те же данные, что и GET /user/me.
 // @Security session_cookie
+// @Security bearer_token
+// @Security api_key
 // @Produce json
+// @Param include query string false "Дополнительные данные" Enums(user)
 // @Success 200 {object} dto.AuthResponse
 // @Failure 401 {object} utils.APIError
 // @Failure 500 {object} utils.APIError
@@ -43,22 +49,22 @@ func (h *AuthHandler) Configure(r *http.ServeMux) {
 func (h *AuthHandler) IsAuth(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	cookie, err := r.Cookie("session_id")
-	if err != nil || cookie == nil {
-		utils.WriteError(w, http.StatusUnauthorized, entity.ErrUnauthorized)
-		return
-	}
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
 
-	userID, err := h.auth.GetUserIDBySession(ctx, cookie.Value)
-	if err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
-		return
+	response := dto.AuthResponse{UserId: principal.UserID}
+	if r.URL.Query().Get("include") == "user" {
+		current, err := h.user.GetCurrentUser(ctx, principal)
+		if err != nil {
+			utils.WriteAPIError(w, r, utils.ToAPIError(err))
+			return
+		}
+		response.User = current
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if err = json.NewEncoder(w).Encode(dto.AuthResponse{UserId: userID}); err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, entity.ErrInternal)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
 		return
 	}
 }
@@ -75,15 +81,14 @@ func (h *AuthHandler) IsAuth(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	cookie, err := r.Cookie("session_id")
-	if err != nil || cookie == nil {
+	principal, ok := GlobalUtils.GetPrincipal(ctx)
+	if !ok || !principal.IsSession() {
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	err = h.auth.Logout(ctx, cookie.Value)
-	if err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
+	if err := h.auth.Logout(ctx, principal.SessionID); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
 
@@ -107,20 +112,14 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	cookie, err := r.Cookie("session_id")
-	if err != nil || cookie == nil {
+	principal, ok := GlobalUtils.GetPrincipal(ctx)
+	if !ok || !principal.IsSession() {
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	userID, err := h.auth.GetUserIDBySession(ctx, cookie.Value)
-	if err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
-		return
-	}
-
-	if err := h.auth.LogoutAll(ctx, userID); err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
+	if err := h.auth.LogoutAll(ctx, principal.UserID); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
 
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthHandler_IsAuth(t *testing.T) {
+	t.Parallel()
+
+	current := &dto.CurrentUserResponse{
+		UserProfileResponse: dto.UserProfileResponse{ID: 1, Login: "user.test"},
+		Roles:               []string{entity.RoleUser},
+		AuthMethod:          string(entity.AuthMethodSession),
+	}
+
+	testCases := []struct {
+		name             string
+		url              string
+		principal        *entity.Principal
+		mockSetup        func(*mock.MockUserUsecase)
+		expectedStatus   int
+		expectedResponse dto.AuthResponse
+	}{
+		{
+			name:             "Без include только id",
+			url:              "/auth/isAuth",
+			principal:        testSessionPrincipal,
+			mockSetup:        func(*mock.MockUserUsecase) {},
+			expectedStatus:   http.StatusOK,
+			expectedResponse: dto.AuthResponse{UserId: 1},
+		},
+		{
+			name:      "include=user возвращает данные /user/me",
+			url:       "/auth/isAuth?include=user",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockUserUsecase) {
+				m.EXPECT().GetCurrentUser(gomock.Any(), testSessionPrincipal).Return(current, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedResponse: dto.AuthResponse{UserId: 1, User: current},
+		},
+		{
+			name:           "Без входа",
+			url:            "/auth/isAuth?include=user",
+			mockSetup:      func(*mock.MockUserUsecase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			users := mock.NewMockUserUsecase(ctrl)
+			tc.mockSetup(users)
+			handler := NewAuthHandler(mock.NewMockAuthUsecase(ctrl), users, config.CSRFConfig{})
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			if tc.expectedStatus == http.StatusOK {
+				var resp dto.AuthResponse
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+				require.Equal(t, tc.expectedResponse, resp)
+			}
+		})
+	}
+}
+
+func TestUserHandler_GetCurrentUser(t *testing.T) {
+	t.Parallel()
+
+	apiKeyPrincipal := &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey, Scopes: []string{entity.ScopeAdsRead}}
+
+	testCases := []struct {
+		name           string
+		principal      *entity.Principal
+		mockSetup      func(userMocks)
+		expectedStatus int
+	}{
+		{
+			name:      "Сессия",
+			principal: testSessionPrincipal,
+			mockSetup: func(m userMocks) {
+				m.user.EXPECT().GetCurrentUser(gomock.Any(), testSessionPrincipal).
+					Return(&dto.CurrentUserResponse{AuthMethod: string(entity.AuthMethodSession)}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "API-ключ с любыми правами",
+			principal: apiKeyPrincipal,
+			mockSetup: func(m userMocks) {
+				m.user.EXPECT().GetCurrentUser(gomock.Any(), apiKeyPrincipal).
+					Return(&dto.CurrentUserResponse{AuthMethod: string(entity.AuthMethodAPIKey)}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Без входа",
+			mockSetup:      func(userMocks) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler, m := newTestUserHandler(ctrl)
+			tc.mockSetup(m)
+
+			req := httptest.NewRequest(http.MethodGet, "/user/me", nil)
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+// SetContact godoc
+// @Tags User
+// @Summary Указание email или телефона
+// @Description Сохраняет контакт в нормализованном виде и отправляет на него код подтверждения.
+// @Description До подтверждения контакт виден только владельцу. Повторный запрос отправляет новый код.
+// @Accept json
+// @Param channel path string true "Тип контакта" Enums(email, phone)
+// @Param contactData body dto.SetContactRequest true "Email или номер телефона"
+// @Success 202
+// @Failure 400 {object} utils.APIError "Неверный формат контакта"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 409 {object} utils.APIError "Контакт уже подтвержден другим пользователем"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/contacts/{channel} [put]
+// @Security csrf_token
+// @Security session_cookie
+func (h *UserHandler) SetContact(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	channel, err := entity.ParseContactChannel(r.PathValue("channel"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var req dto.SetContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.contact.SetContact(ctx, principal.UserID, channel, req.Value); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// VerifyContact godoc
+// @Tags User
+// @Summary Подтверждение email или телефона
+// @Description Проверяет код, отправленный на контакт. Подтвержденный контакт дает отметку «проверенный продавец».
+// @Accept json
+// @Param channel path string true "Тип контакта" Enums(email, phone)
+// @Param codeData body dto.VerifyContactRequest true "Код подтверждения"
+// @Success 204
+// @Failure 400 {object} utils.APIError "Неверный или истекший код"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 409 {object} utils.APIError "Контакт уже подтвержден другим пользователем"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/contacts/{channel}/verify [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *UserHandler) VerifyContact(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	channel, err := entity.ParseContactChannel(r.PathValue("channel"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var req dto.VerifyContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.contact.VerifyContact(ctx, principal.UserID, channel, req.Code); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
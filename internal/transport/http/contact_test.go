@@ -0,0 +1,98 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUserHandler_Contacts(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		principal      *entity.Principal
+		mockSetup      func(userMocks)
+		expectedStatus int
+	}{
+		{
+			name:      "Новый email получает код",
+			method:    http.MethodPut,
+			url:       "/user/contacts/email",
+			body:      `{"value":"user@example.com"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m userMocks) {
+				m.contact.EXPECT().SetContact(gomock.Any(), 1, entity.ContactEmail, "user@example.com").Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:      "Подтверждение телефона",
+			method:    http.MethodPost,
+			url:       "/user/contacts/phone/verify",
+			body:      `{"code":"123456"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m userMocks) {
+				m.contact.EXPECT().VerifyContact(gomock.Any(), 1, entity.ContactPhone, "123456").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:      "Неверный или истекший код",
+			method:    http.MethodPost,
+			url:       "/user/contacts/email/verify",
+			body:      `{"code":"000000"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m userMocks) {
+				m.contact.EXPECT().VerifyContact(gomock.Any(), 1, entity.ContactEmail, "000000").
+					Return(entity.NewError(entity.ErrBadRequest, fmt.Errorf("неверный код подтверждения")))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Неизвестный канал",
+			method:         http.MethodPut,
+			url:            "/user/contacts/fax",
+			body:           `{"value":"123"}`,
+			principal:      testSessionPrincipal,
+			mockSetup:      func(userMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "API-ключ не меняет контакты",
+			method:         http.MethodPut,
+			url:            "/user/contacts/email",
+			body:           `{"value":"user@example.com"}`,
+			principal:      &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey},
+			mockSetup:      func(userMocks) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler, m := newTestUserHandler(ctrl)
+			tc.mockSetup(m)
+
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+type ConversationHandler struct {
+	conversation usecase.ConversationUsecase
+	cfg          config.CSRFConfig
+}
+
+func NewConversationHandler(conversation usecase.ConversationUsecase, cfg config.CSRFConfig) ConversationHandler {
+	return ConversationHandler{conversation: conversation, cfg: cfg}
+}
+
+func (h *ConversationHandler) Configure(r *http.ServeMux) {
+	// Более конкретный шаблон имеет приоритет над поддеревом /ad/ обработчика объявлений.
+	r.Handle("POST /ad/{id}/conversations", middleware.RequireSession()(http.HandlerFunc(h.StartConversation)))
+
+	conversationMux := http.NewServeMux()
+	conversationMux.HandleFunc("GET /all", h.GetConversations)
+	conversationMux.HandleFunc("GET /unread", h.GetUnreadCount)
+	conversationMux.HandleFunc("GET /{id}/messages", h.GetMessages)
+	conversationMux.HandleFunc("POST /{id}/messages", h.SendMessage)
+	conversationMux.HandleFunc("POST /{id}/read", h.MarkRead)
+	conversationMux.HandleFunc("POST /{id}/block", h.BlockConversation)
+	conversationMux.HandleFunc("DELETE /{id}/block", h.UnblockConversation)
+
+	r.Handle("/conversations/", http.StripPrefix("/conversations", middleware.RequireSession()(conversationMux)))
+}
+
+// StartConversation godoc
+// @Tags Conversation
+// @Summary Написать продавцу
+// @Description Открывает переписку с продавцом по объявлению или возвращает уже существующую.
+// @Description Если передано сообщение, оно отправляется сразу.
+// @Accept json
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Param conversationData body dto.StartConversationRequest false "Первое сообщение"
+// @Success 200 {object} dto.ConversationResponse
+// @Failure 400 {object} utils.APIError "Собственное объявление или неверное сообщение"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Переписка заблокирована"
+// @Failure 404 {object} utils.APIError "Объявление не найдено"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /ad/{id}/conversations [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *ConversationHandler) StartConversation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var req dto.StartConversationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	conversation, err := h.conversation.Start(ctx, principal.UserID, adID, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(conversation); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetConversations godoc
+// @Tags Conversation
+// @Summary Список переписок
+// @Description Переписки пользователя как покупателя и как продавца, сначала самые свежие.
+// @Produce json
+// @Param limit query int false "Количество переписок на странице (по умолчанию 20)"
+// @Param offset query int false "Смещение от начала списка (по умолчанию 0)"
+// @Success 200 {object} []dto.ConversationResponse
+// @Failure 400 {object} utils.APIError "Некорректные параметры запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /conversations/all [get]
+// @Security session_cookie
+func (h *ConversationHandler) GetConversations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	offset, limit, err := utils.ParsePagination(r, entity.ConversationsDefaultPage, entity.ConversationsMaxPage)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	conversations, err := h.conversation.GetByUserID(ctx, principal.UserID, offset, limit)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(conversations); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetUnreadCount godoc
+// @Tags Conversation
+// @Summary Число непрочитанных сообщений
+// @Description Суммарное число непрочитанных входящих сообщений во всех переписках.
+// @Produce json
+// @Success 200 {object} dto.UnreadCountResponse
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /conversations/unread [get]
+// @Security session_cookie
+func (h *ConversationHandler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	unread, err := h.conversation.CountUnread(ctx, principal.UserID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(unread); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetMessages godoc
+// @Tags Conversation
+// @Summary Сообщения переписки
+// @Description Возвращает сообщения, новые первыми. Для следующей страницы передайте next_cursor в before.
+// @Produce json
+// @Param id path int true "ID переписки"
+// @Param before query int false "Вернуть сообщения с id меньше указанного"
+// @Param limit query int false "Количество сообщений (по умолчанию 50)"
+// @Success 200 {object} dto.MessagesPage
+// @Failure 400 {object} utils.APIError "Некорректные параметры запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Переписка не найдена"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /conversations/{id}/messages [get]
+// @Security session_cookie
+func (h *ConversationHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	conversationID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var before int64
+	if v := r.URL.Query().Get("before"); v != "" {
+		before, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || before <= 0 {
+			utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+			return
+		}
+	}
+
+	_, limit, err := utils.ParsePagination(r, entity.MessagesDefaultPage, entity.MessagesMaxPage)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	page, err := h.conversation.GetMessages(ctx, principal.UserID, conversationID, before, limit)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// SendMessage godoc
+// @Tags Conversation
+// @Summary Отправка сообщения
+// @Description Текст очищается от разметки. В заблокированную переписку писать нельзя.
+// @Accept json
+// @Produce json
+// @Param id path int true "ID переписки"
+// @Param messageData body dto.SendMessageRequest true "Текст сообщения"
+// @Success 201 {object} dto.MessageResponse
+// @Failure 400 {object} utils.APIError "Пустое или слишком длинное сообщение"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Переписка заблокирована"
+// @Failure 404 {object} utils.APIError "Переписка не найдена"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /conversations/{id}/messages [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *ConversationHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	conversationID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var req dto.SendMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	msg, err := h.conversation.SendMessage(ctx, principal.UserID, conversationID, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(msg); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// MarkRead godoc
+// @Tags Conversation
+// @Summary Отметка о прочтении
+// @Description Отмечает прочитанными сообщения до message_id включительно, без тела запроса — всю переписку.
+// @Accept json
+// @Param id path int true "ID переписки"
+// @Param readData body dto.MarkReadRequest false "Последнее прочитанное сообщение"
+// @Success 204
+// @Failure 400 {object} utils.APIError "Неверный формат запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Переписка не найдена"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /conversations/{id}/read [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *ConversationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	conversationID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var req dto.MarkReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.conversation.MarkRead(ctx, principal.UserID, conversationID, &req); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BlockConversation godoc
+// @Tags Conversation
+// @Summary Блокировка переписки
+// @Description После блокировки ни один из участников не может отправлять сообщения.
+// @Param id path int true "ID переписки"
+// @Success 204
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Переписка не найдена"
+// @Failure 409 {object} utils.APIError "Переписка уже заблокирована"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /conversations/{id}/block [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *ConversationHandler) BlockConversation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	conversationID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.conversation.Block(ctx, principal.UserID, conversationID); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnblockConversation godoc
+// @Tags Conversation
+// @Summary Снятие блокировки переписки
+// @Description Снять блокировку может только участник, который ее поставил.
+// @Param id path int true "ID переписки"
+// @Success 204
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Блокировку поставил другой участник"
+// @Failure 404 {object} utils.APIError "Переписка не найдена"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /conversations/{id}/block [delete]
+// @Security csrf_token
+// @Security session_cookie
+func (h *ConversationHandler) UnblockConversation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	conversationID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.conversation.Unblock(ctx, principal.UserID, conversationID); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
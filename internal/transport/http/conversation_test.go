@@ -0,0 +1,150 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestConversationHandler(t *testing.T) {
+	t.Parallel()
+
+	notFound := entity.NewError(entity.ErrNotFound, fmt.Errorf("переписка с id=7 не найдена"))
+
+	testCases := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		principal      *entity.Principal
+		mockSetup      func(*mock.MockConversationUsecase)
+		expectedStatus int
+	}{
+		{
+			name:      "Начало переписки по объявлению",
+			method:    http.MethodPost,
+			url:       "/ad/10/conversations",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockConversationUsecase) {
+				m.EXPECT().Start(gomock.Any(), 1, 10, &dto.StartConversationRequest{}).
+					Return(&dto.ConversationResponse{ID: 7}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Сообщения своей переписки",
+			method:    http.MethodGet,
+			url:       "/conversations/7/messages?before=20&limit=10",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockConversationUsecase) {
+				m.EXPECT().GetMessages(gomock.Any(), 1, 7, int64(20), 10).Return(&dto.MessagesPage{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Сообщения чужой переписки",
+			method:    http.MethodGet,
+			url:       "/conversations/7/messages",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockConversationUsecase) {
+				m.EXPECT().GetMessages(gomock.Any(), 1, 7, int64(0), entity.MessagesDefaultPage).Return(nil, notFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:      "Отправка в чужую переписку",
+			method:    http.MethodPost,
+			url:       "/conversations/7/messages",
+			body:      `{"body":"Привет"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockConversationUsecase) {
+				m.EXPECT().SendMessage(gomock.Any(), 1, 7, &dto.SendMessageRequest{Body: "Привет"}).Return(nil, notFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:      "Отправка в заблокированную переписку",
+			method:    http.MethodPost,
+			url:       "/conversations/7/messages",
+			body:      `{"body":"Привет"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockConversationUsecase) {
+				m.EXPECT().SendMessage(gomock.Any(), 1, 7, gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrForbidden, fmt.Errorf("переписка заблокирована")))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Отметка о прочтении без тела",
+			method:    http.MethodPost,
+			url:       "/conversations/7/read",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockConversationUsecase) {
+				m.EXPECT().MarkRead(gomock.Any(), 1, 7, &dto.MarkReadRequest{}).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:      "Снятие чужой блокировки",
+			method:    http.MethodDelete,
+			url:       "/conversations/7/block",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockConversationUsecase) {
+				m.EXPECT().Unblock(gomock.Any(), 1, 7).
+					Return(entity.NewError(entity.ErrForbidden, fmt.Errorf("снять блокировку может только заблокировавший участник")))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Некорректный курсор",
+			method:         http.MethodGet,
+			url:            "/conversations/7/messages?before=-1",
+			principal:      testSessionPrincipal,
+			mockSetup:      func(*mock.MockConversationUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Переписки недоступны по API-ключу",
+			method:         http.MethodGet,
+			url:            "/conversations/all",
+			principal:      &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey, Scopes: []string{entity.ScopeAdsRead}},
+			mockSetup:      func(*mock.MockConversationUsecase) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Без входа",
+			method:         http.MethodGet,
+			url:            "/conversations/unread",
+			mockSetup:      func(*mock.MockConversationUsecase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			conversations := mock.NewMockConversationUsecase(ctrl)
+			tc.mockSetup(conversations)
+			handler := NewConversationHandler(conversations, config.CSRFConfig{})
+
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	l "github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultHeartbeatInterval = 15 * time.Second
+
+type EventHandler struct {
+	events       usecase.EventUsecase
+	auth         usecase.AuthUsecase
+	cfg          config.StreamConfig
+	writeTimeout time.Duration
+}
+
+func NewEventHandler(
+	events usecase.EventUsecase,
+	auth usecase.AuthUsecase,
+	cfg config.StreamConfig,
+	httpCfg config.HTTPConfig,
+) EventHandler {
+	return EventHandler{
+		events:       events,
+		auth:         auth,
+		cfg:          cfg,
+		writeTimeout: httpCfg.WriteTimeout,
+	}
+}
+
+func (h *EventHandler) Configure(r *http.ServeMux) {
+	eventMux := http.NewServeMux()
+	eventMux.HandleFunc("GET /stream", h.Stream)
+
+	r.Handle("/events/", http.StripPrefix("/events", middleware.RequireSession()(eventMux)))
+}
+
+// heartbeatInterval подбирает интервал так, чтобы между двумя записями
+// не истек дедлайн записи HTTPConfig.WriteTimeout.
+func (h *EventHandler) heartbeatInterval() time.Duration {
+	interval := h.cfg.HeartbeatInterval
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	if h.writeTimeout > 0 && interval > h.writeTimeout/2 {
+		interval = h.writeTimeout / 2
+	}
+	return interval
+}
+
+// Stream godoc
+// @Tags Event
+// @Summary Поток событий
+// @Description Server-Sent Events: новые сообщения (message.new) и отметки о прочтении (message.read).
+// @Description Раз в heartbeatInterval приходит комментарий-пинг. Если сессия завершена, приходит
+// @Description session.expired и поток закрывается. Пропущенные за время переподключения события
+// @Description не повторяются, после переподключения клиенту следует перечитать счетчики.
+// @Produce text/event-stream
+// @Success 200 {string} string "Поток событий"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Требуется сессия"
+// @Failure 429 {object} utils.APIError "Слишком много открытых потоков"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /events/stream [get]
+// @Security session_cookie
+func (h *EventHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestID := GlobalUtils.GetRequestID(ctx)
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	events, unsubscribe, err := h.events.Subscribe(principal.UserID)
+	if err != nil {
+		var appErr entity.Error
+		if errors.As(err, &appErr) && errors.Is(appErr.ClientErr(), entity.ErrTooManyRequests) {
+			utils.WriteError(w, r, http.StatusTooManyRequests, entity.ErrTooManyRequests)
+			return
+		}
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+	defer unsubscribe()
+
+	rc := http.NewResponseController(w)
+	write := func(frame string) error {
+		// Дедлайн записи сервера отсчитывается от начала запроса, продлеваем его перед каждой записью.
+		if h.writeTimeout > 0 {
+			if err := rc.SetWriteDeadline(time.Now().Add(h.writeTimeout)); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, frame); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := write(fmt.Sprintf("retry: %d\n\n", h.cfg.ReconnectDelay.Milliseconds())); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeatInterval())
+	defer heartbeat.Stop()
+
+	for {
+		var frame string
+
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// Поток закрыт сервером: остановка или медленный клиент.
+				return
+			}
+			frame = fmt.Sprintf("event: %s\ndata: %s\n\n", event.Type, event.Data)
+		case <-heartbeat.C:
+			if _, err := h.auth.GetUserIDBySession(ctx, principal.SessionID); err != nil {
+				_ = write(fmt.Sprintf("event: %s\ndata: {}\n\n", entity.EventSessionExpired))
+				return
+			}
+			frame = ": ping\n\n"
+		}
+
+		if err := write(frame); err != nil {
+			l.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+				"userID":    principal.UserID,
+				"error":     err,
+			}).Info("Клиент отключился от потока событий")
+			return
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEventHandler_Stream(t *testing.T) {
+	t.Parallel()
+
+	sessionEnded := entity.NewError(entity.ErrUnauthorized, fmt.Errorf("сессия не найдена"))
+
+	testCases := []struct {
+		name           string
+		principal      *entity.Principal
+		mockSetup      func(*mock.MockEventUsecase, *mock.MockAuthUsecase)
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			name:      "События доставляются до закрытия потока сервером",
+			principal: testSessionPrincipal,
+			mockSetup: func(events *mock.MockEventUsecase, _ *mock.MockAuthUsecase) {
+				ch := make(chan *entity.Event, 1)
+				ch <- &entity.Event{UserID: 1, Type: entity.EventMessageNew, Data: []byte(`{"id":11}`)}
+				close(ch)
+				events.EXPECT().Subscribe(1).Return((<-chan *entity.Event)(ch), func() {}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{"retry: 1000\n\n", "event: message.new\ndata: {\"id\":11}\n\n"},
+		},
+		{
+			name:      "Завершенная сессия закрывает поток",
+			principal: testSessionPrincipal,
+			mockSetup: func(events *mock.MockEventUsecase, auth *mock.MockAuthUsecase) {
+				events.EXPECT().Subscribe(1).Return(make(<-chan *entity.Event), func() {}, nil)
+				gomock.InOrder(
+					auth.EXPECT().GetUserIDBySession(gomock.Any(), "session").Return(1, nil),
+					auth.EXPECT().GetUserIDBySession(gomock.Any(), "session").Return(0, sessionEnded),
+				)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{": ping\n\n", "event: session.expired\ndata: {}\n\n"},
+		},
+		{
+			name:      "Превышено число потоков",
+			principal: testSessionPrincipal,
+			mockSetup: func(events *mock.MockEventUsecase, _ *mock.MockAuthUsecase) {
+				events.EXPECT().Subscribe(1).
+					Return(nil, nil, entity.NewError(entity.ErrTooManyRequests, fmt.Errorf("превышено число открытых потоков")))
+			},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:           "Поток недоступен по API-ключу",
+			principal:      &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey, Scopes: []string{entity.ScopeAdsRead}},
+			mockSetup:      func(*mock.MockEventUsecase, *mock.MockAuthUsecase) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Без входа",
+			mockSetup:      func(*mock.MockEventUsecase, *mock.MockAuthUsecase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			events := mock.NewMockEventUsecase(ctrl)
+			auth := mock.NewMockAuthUsecase(ctrl)
+			tc.mockSetup(events, auth)
+			handler := NewEventHandler(events, auth, config.StreamConfig{
+				HeartbeatInterval: 5 * time.Millisecond,
+				ReconnectDelay:    time.Second,
+			}, config.HTTPConfig{})
+
+			req := httptest.NewRequest(http.MethodGet, "/events/stream", nil)
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			for _, frame := range tc.expectedBody {
+				require.Contains(t, rr.Body.String(), frame)
+			}
+		})
+	}
+}
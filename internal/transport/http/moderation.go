@@ -0,0 +1,209 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+type ModerationHandler struct {
+	moderation usecase.ModerationUsecase
+	cfg        config.CSRFConfig
+}
+
+func NewModerationHandler(moderation usecase.ModerationUsecase, cfg config.CSRFConfig) ModerationHandler {
+	return ModerationHandler{moderation: moderation, cfg: cfg}
+}
+
+func (h *ModerationHandler) Configure(r *http.ServeMux) {
+	r.Handle("GET /moderation/duplicates", middleware.RequireSession()(http.HandlerFunc(h.GetDuplicates)))
+	r.Handle("GET /moderation/ads/{id}/similar", middleware.RequireSession()(http.HandlerFunc(h.GetSimilarAds)))
+	r.Handle("GET /moderation/pending", middleware.RequireSession()(http.HandlerFunc(h.GetPending)))
+	r.Handle("POST /moderation/ads/{id}/approve", middleware.RequireSession()(http.HandlerFunc(h.ApproveAd)))
+	r.Handle("POST /moderation/ads/{id}/reject", middleware.RequireSession()(http.HandlerFunc(h.RejectAd)))
+}
+
+// GetDuplicates godoc
+// @Tags Moderation
+// @Summary Помеченные дубликаты
+// @Description Активные объявления, опубликованные с пометкой почти дубликата, новые первыми. Только для модераторов.
+// @Produce json
+// @Param limit query int false "Количество на странице (по умолчанию 20, максимум 100)"
+// @Param offset query int false "Смещение от начала списка"
+// @Success 200 {object} []dto.DuplicateAdResponse
+// @Failure 400 {object} utils.APIError "Некорректные параметры запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Пользователь не модератор"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /moderation/duplicates [get]
+// @Security session_cookie
+func (h *ModerationHandler) GetDuplicates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	offset, limit, err := utils.ParsePagination(r, entity.DuplicatesDefaultPage, entity.DuplicatesMaxPage)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	duplicates, err := h.moderation.GetDuplicates(ctx, principal.UserID, offset, limit)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(duplicates); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetSimilarAds godoc
+// @Tags Moderation
+// @Summary Похожие объявления
+// @Description Активные объявления всех авторов, похожие на указанное по тексту, ближайшие первыми.
+// @Description near_duplicate отмечает объявления, которые при создании считались бы дубликатом. Только для модераторов.
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Success 200 {object} []dto.SimilarAdResponse
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Пользователь не модератор"
+// @Failure 404 {object} utils.APIError "Объявление не найдено"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /moderation/ads/{id}/similar [get]
+// @Security session_cookie
+func (h *ModerationHandler) GetSimilarAds(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	similar, err := h.moderation.GetSimilar(ctx, principal.UserID, adID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(similar); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetPending godoc
+// @Tags Moderation
+// @Summary Объявления на модерации
+// @Description Объявления, которые фильтр содержимого отправил на модерацию, старые первыми. Только для модераторов.
+// @Produce json
+// @Param limit query int false "Количество на странице (по умолчанию 20, максимум 100)"
+// @Param offset query int false "Смещение от начала списка"
+// @Success 200 {object} []dto.PendingAdResponse
+// @Failure 400 {object} utils.APIError "Некорректные параметры запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Пользователь не модератор"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /moderation/pending [get]
+// @Security session_cookie
+func (h *ModerationHandler) GetPending(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	offset, limit, err := utils.ParsePagination(r, entity.ModerationDefaultPage, entity.ModerationMaxPage)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	pending, err := h.moderation.GetPending(ctx, principal.UserID, offset, limit)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(pending); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// ApproveAd godoc
+// @Tags Moderation
+// @Summary Одобрить объявление
+// @Description Объявление появляется в ленте, автор получает уведомление ad_published. Только для модераторов.
+// @Param id path int true "ID объявления"
+// @Success 204
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Пользователь не модератор"
+// @Failure 404 {object} utils.APIError "Объявление не ждет модерации"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /moderation/ads/{id}/approve [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *ModerationHandler) ApproveAd(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, h.moderation.Approve)
+}
+
+// RejectAd godoc
+// @Tags Moderation
+// @Summary Отклонить объявление
+// @Description Объявление остается скрытым, автор получает уведомление ad_rejected. Только для модераторов.
+// @Param id path int true "ID объявления"
+// @Success 204
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Пользователь не модератор"
+// @Failure 404 {object} utils.APIError "Объявление не ждет модерации"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /moderation/ads/{id}/reject [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *ModerationHandler) RejectAd(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, h.moderation.Reject)
+}
+
+func (h *ModerationHandler) moderate(
+	w http.ResponseWriter,
+	r *http.Request,
+	decide func(ctx context.Context, userID, adID int) error,
+) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := decide(ctx, principal.UserID, adID); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestModerationHandler(t *testing.T) {
+	t.Parallel()
+
+	notModerator := entity.NewError(entity.ErrForbidden, fmt.Errorf("пользователь id=1 не модератор"))
+
+	testCases := []struct {
+		name           string
+		method         string
+		url            string
+		principal      *entity.Principal
+		mockSetup      func(*mock.MockModerationUsecase)
+		expectedStatus int
+	}{
+		{
+			name:      "Список дубликатов",
+			method:    http.MethodGet,
+			url:       "/moderation/duplicates?offset=20&limit=10",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockModerationUsecase) {
+				m.EXPECT().GetDuplicates(gomock.Any(), 1, 20, 10).Return([]dto.DuplicateAdResponse{{ID: 9, DuplicateOf: 5}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Дубликаты доступны только модератору",
+			method:    http.MethodGet,
+			url:       "/moderation/duplicates",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockModerationUsecase) {
+				m.EXPECT().GetDuplicates(gomock.Any(), 1, 0, entity.DuplicatesDefaultPage).Return(nil, notModerator)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Похожие объявления",
+			method:    http.MethodGet,
+			url:       "/moderation/ads/10/similar",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockModerationUsecase) {
+				m.EXPECT().GetSimilar(gomock.Any(), 1, 10).Return([]dto.SimilarAdResponse{{ID: 11, NearDuplicate: true}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Похожие доступны только модератору",
+			method:    http.MethodGet,
+			url:       "/moderation/ads/10/similar",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockModerationUsecase) {
+				m.EXPECT().GetSimilar(gomock.Any(), 1, 10).Return(nil, notModerator)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Очередь модерации",
+			method:    http.MethodGet,
+			url:       "/moderation/pending",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockModerationUsecase) {
+				m.EXPECT().GetPending(gomock.Any(), 1, 0, entity.ModerationDefaultPage).Return([]dto.PendingAdResponse{{ID: 10}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Одобрение объявления",
+			method:    http.MethodPost,
+			url:       "/moderation/ads/10/approve",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockModerationUsecase) {
+				m.EXPECT().Approve(gomock.Any(), 1, 10).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:      "Отклонение объявления не модератором",
+			method:    http.MethodPost,
+			url:       "/moderation/ads/10/reject",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockModerationUsecase) {
+				m.EXPECT().Reject(gomock.Any(), 1, 10).Return(notModerator)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Решение по объявлению не из очереди",
+			method:    http.MethodPost,
+			url:       "/moderation/ads/10/approve",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockModerationUsecase) {
+				m.EXPECT().Approve(gomock.Any(), 1, 10).
+					Return(entity.NewError(entity.ErrNotFound, fmt.Errorf("объявление с id=10 не ожидает модерации")))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Модерация недоступна по API-ключу",
+			method:         http.MethodGet,
+			url:            "/moderation/duplicates",
+			principal:      &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey, Scopes: []string{entity.ScopeAdsRead}},
+			mockSetup:      func(*mock.MockModerationUsecase) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Без входа",
+			method:         http.MethodGet,
+			url:            "/moderation/duplicates",
+			mockSetup:      func(*mock.MockModerationUsecase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			moderation := mock.NewMockModerationUsecase(ctrl)
+			tc.mockSetup(moderation)
+			handler := NewModerationHandler(moderation, config.CSRFConfig{})
+
+			req := httptest.NewRequest(tc.method, tc.url, nil)
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
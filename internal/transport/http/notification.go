@@ -0,0 +1,235 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+type NotificationHandler struct {
+	notification usecase.NotificationUsecase
+	cfg          config.CSRFConfig
+}
+
+func NewNotificationHandler(notification usecase.NotificationUsecase, cfg config.CSRFConfig) NotificationHandler {
+	return NotificationHandler{notification: notification, cfg: cfg}
+}
+
+func (h *NotificationHandler) Configure(r *http.ServeMux) {
+	r.Handle("GET /notifications", middleware.RequireSession()(http.HandlerFunc(h.GetNotifications)))
+
+	notificationMux := http.NewServeMux()
+	notificationMux.HandleFunc("GET /unread", h.GetUnreadCount)
+	notificationMux.HandleFunc("POST /{id}/read", h.MarkRead)
+	notificationMux.HandleFunc("POST /read-all", h.MarkAllRead)
+	notificationMux.HandleFunc("GET /preferences", h.GetPreferences)
+	notificationMux.HandleFunc("PUT /preferences", h.UpdatePreferences)
+
+	r.Handle("/notifications/", http.StripPrefix("/notifications", middleware.RequireSession()(notificationMux)))
+}
+
+// GetNotifications godoc
+// @Tags Notification
+// @Summary Список уведомлений
+// @Description Уведомления пользователя, новые первыми. С unread=true возвращаются только непрочитанные.
+// @Produce json
+// @Param unread query bool false "Только непрочитанные"
+// @Param limit query int false "Количество уведомлений на странице (по умолчанию 20)"
+// @Param offset query int false "Смещение от начала списка (по умолчанию 0)"
+// @Success 200 {object} []dto.NotificationResponse
+// @Failure 400 {object} utils.APIError "Некорректные параметры запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /notifications [get]
+// @Security session_cookie
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	var unreadOnly bool
+	if v := r.URL.Query().Get("unread"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+			return
+		}
+		unreadOnly = parsed
+	}
+
+	offset, limit, err := utils.ParsePagination(r, entity.NotificationsDefaultPage, entity.NotificationsMaxPage)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	notifications, err := h.notification.GetByUserID(ctx, principal.UserID, unreadOnly, offset, limit)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(notifications); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetUnreadCount godoc
+// @Tags Notification
+// @Summary Число непрочитанных уведомлений
+// @Produce json
+// @Success 200 {object} dto.UnreadCountResponse
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /notifications/unread [get]
+// @Security session_cookie
+func (h *NotificationHandler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	unread, err := h.notification.CountUnread(ctx, principal.UserID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(unread); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// MarkRead godoc
+// @Tags Notification
+// @Summary Отметка уведомления прочитанным
+// @Param id path int true "ID уведомления"
+// @Success 204
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Уведомление не найдено"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /notifications/{id}/read [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	notificationID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.notification.MarkRead(ctx, principal.UserID, notificationID); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllRead godoc
+// @Tags Notification
+// @Summary Отметка всех уведомлений прочитанными
+// @Success 204
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /notifications/read-all [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	if err := h.notification.MarkAllRead(ctx, principal.UserID); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPreferences godoc
+// @Tags Notification
+// @Summary Настройки уведомлений
+// @Description Все типы уведомлений с признаком, получает ли их пользователь.
+// @Produce json
+// @Success 200 {object} []dto.NotificationPreference
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /notifications/preferences [get]
+// @Security session_cookie
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	preferences, err := h.notification.GetPreferences(ctx, principal.UserID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(preferences); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// UpdatePreferences godoc
+// @Tags Notification
+// @Summary Изменение настроек уведомлений
+// @Description Меняет только переданные типы. Уведомления о безопасности отключить нельзя.
+// @Accept json
+// @Produce json
+// @Param preferencesData body dto.UpdateNotificationPreferencesRequest true "Типы уведомлений"
+// @Success 200 {object} []dto.NotificationPreference
+// @Failure 400 {object} utils.APIError "Неизвестный или обязательный тип"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /notifications/preferences [put]
+// @Security csrf_token
+// @Security session_cookie
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	var req dto.UpdateNotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	preferences, err := h.notification.UpdatePreferences(ctx, principal.UserID, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(preferences); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testSessionPrincipal = &entity.Principal{UserID: 1, Method: entity.AuthMethodSession, SessionID: "session"}
+
+// serveWithPrincipal прогоняет запрос через маршруты обработчика от имени principal.
+func serveWithPrincipal(configure func(*http.ServeMux), req *http.Request, principal *entity.Principal) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	configure(mux)
+
+	if principal != nil {
+		req = req.WithContext(GlobalUtils.SetPrincipal(req.Context(), principal))
+	}
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestNotificationHandler_GetNotifications(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		url            string
+		principal      *entity.Principal
+		mockSetup      func(*mock.MockNotificationUsecase)
+		expectedStatus int
+	}{
+		{
+			name:      "Параметры по умолчанию",
+			url:       "/notifications",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockNotificationUsecase) {
+				m.EXPECT().GetByUserID(gomock.Any(), 1, false, 0, entity.NotificationsDefaultPage).
+					Return([]dto.NotificationResponse{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Только непрочитанные со смещением",
+			url:       "/notifications?unread=true&limit=5&offset=10",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockNotificationUsecase) {
+				m.EXPECT().GetByUserID(gomock.Any(), 1, true, 10, 5).Return([]dto.NotificationResponse{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Некорректный unread",
+			url:            "/notifications?unread=maybe",
+			principal:      testSessionPrincipal,
+			mockSetup:      func(*mock.MockNotificationUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Слишком большой limit",
+			url:            "/notifications?limit=100000",
+			principal:      testSessionPrincipal,
+			mockSetup:      func(*mock.MockNotificationUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Без входа",
+			url:            "/notifications",
+			mockSetup:      func(*mock.MockNotificationUsecase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "API-ключ не дает доступа к уведомлениям",
+			url:            "/notifications/unread",
+			principal:      &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey},
+			mockSetup:      func(*mock.MockNotificationUsecase) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			notifications := mock.NewMockNotificationUsecase(ctrl)
+			tc.mockSetup(notifications)
+			handler := NewNotificationHandler(notifications, config.CSRFConfig{})
+
+			rr := serveWithPrincipal(handler.Configure, httptest.NewRequest(http.MethodGet, tc.url, nil), tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+type OfferHandler struct {
+	offer usecase.OfferUsecase
+	cfg   config.CSRFConfig
+}
+
+func NewOfferHandler(offer usecase.OfferUsecase, cfg config.CSRFConfig) OfferHandler {
+	return OfferHandler{offer: offer, cfg: cfg}
+}
+
+func (h *OfferHandler) Configure(r *http.ServeMux) {
+	r.Handle("POST /ad/{id}/offers", middleware.RequireSession()(http.HandlerFunc(h.CreateOffer)))
+	r.Handle("GET /ad/{id}/offers", middleware.RequireSession()(http.HandlerFunc(h.GetAdvertisementOffers)))
+
+	offerMux := http.NewServeMux()
+	offerMux.HandleFunc("GET /all", h.GetOffers)
+	offerMux.HandleFunc("POST /{id}/accept", h.AcceptOffer)
+	offerMux.HandleFunc("POST /{id}/decline", h.DeclineOffer)
+	offerMux.HandleFunc("POST /{id}/counter", h.CounterOffer)
+	offerMux.HandleFunc("POST /{id}/withdraw", h.WithdrawOffer)
+
+	r.Handle("/offers/", http.StripPrefix("/offers", middleware.RequireSession()(offerMux)))
+}
+
+// CreateOffer godoc
+// @Tags Offer
+// @Summary Предложить цену
+// @Description Предложение ждет ответа продавца offers.ttl, после чего истекает.
+// @Description У покупателя может быть только одно ожидающее предложение по объявлению.
+// @Accept json
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Param offerData body dto.OfferRequest true "Сумма предложения"
+// @Success 201 {object} dto.OfferResponse
+// @Failure 400 {object} utils.APIError "Неверная сумма или собственное объявление"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Объявление не найдено"
+// @Failure 409 {object} utils.APIError "Объявление зарезервировано или предложение уже есть"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /ad/{id}/offers [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *OfferHandler) CreateOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var req dto.OfferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	offer, err := h.offer.Create(ctx, principal.UserID, adID, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(offer); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetAdvertisementOffers godoc
+// @Tags Offer
+// @Summary Предложения по объявлению
+// @Description Все предложения по объявлению, новые первыми. Доступно только продавцу.
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Param limit query int false "Количество предложений на странице (по умолчанию 20)"
+// @Param offset query int false "Смещение от начала списка (по умолчанию 0)"
+// @Success 200 {object} []dto.OfferResponse
+// @Failure 400 {object} utils.APIError "Некорректные параметры запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Чужое объявление"
+// @Failure 404 {object} utils.APIError "Объявление не найдено"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /ad/{id}/offers [get]
+// @Security session_cookie
+func (h *OfferHandler) GetAdvertisementOffers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	offset, limit, err := utils.ParsePagination(r, entity.OffersDefaultPage, entity.OffersMaxPage)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	offers, err := h.offer.GetByAdvertisementID(ctx, principal.UserID, adID, offset, limit)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(offers); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetOffers godoc
+// @Tags Offer
+// @Summary Список предложений
+// @Description Предложения пользователя как покупателя и как продавца, новые первыми.
+// @Produce json
+// @Param limit query int false "Количество предложений на странице (по умолчанию 20)"
+// @Param offset query int false "Смещение от начала списка (по умолчанию 0)"
+// @Success 200 {object} []dto.OfferResponse
+// @Failure 400 {object} utils.APIError "Некорректные параметры запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /offers/all [get]
+// @Security session_cookie
+func (h *OfferHandler) GetOffers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	offset, limit, err := utils.ParsePagination(r, entity.OffersDefaultPage, entity.OffersMaxPage)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	offers, err := h.offer.GetByUserID(ctx, principal.UserID, offset, limit)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(offers); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// AcceptOffer godoc
+// @Tags Offer
+// @Summary Принять предложение
+// @Description Объявление переходит в статус reserved, остальные ожидающие предложения по нему отклоняются.
+// @Produce json
+// @Param id path int true "ID предложения"
+// @Success 200 {object} dto.OfferResponse
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Собственное предложение"
+// @Failure 404 {object} utils.APIError "Предложение не найдено"
+// @Failure 409 {object} utils.APIError "Предложение закрыто, истекло или объявление зарезервировано"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /offers/{id}/accept [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *OfferHandler) AcceptOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	offerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	offer, err := h.offer.Accept(ctx, principal.UserID, offerID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(offer); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// DeclineOffer godoc
+// @Tags Offer
+// @Summary Отклонить предложение
+// @Produce json
+// @Param id path int true "ID предложения"
+// @Success 200 {object} dto.OfferResponse
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Собственное предложение"
+// @Failure 404 {object} utils.APIError "Предложение не найдено"
+// @Failure 409 {object} utils.APIError "Предложение закрыто или истекло"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /offers/{id}/decline [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *OfferHandler) DeclineOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	offerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	offer, err := h.offer.Decline(ctx, principal.UserID, offerID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(offer); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// CounterOffer godoc
+// @Tags Offer
+// @Summary Встречное предложение
+// @Description Закрывает предложение со статусом countered и создает новое от имени ответившего.
+// @Description Отвечать на встречное предложение может вторая сторона.
+// @Accept json
+// @Produce json
+// @Param id path int true "ID предложения"
+// @Param offerData body dto.OfferRequest true "Сумма встречного предложения"
+// @Success 201 {object} dto.OfferResponse
+// @Failure 400 {object} utils.APIError "Неверный ID или сумма"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Собственное предложение"
+// @Failure 404 {object} utils.APIError "Предложение не найдено"
+// @Failure 409 {object} utils.APIError "Предложение закрыто, истекло или объявление зарезервировано"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /offers/{id}/counter [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *OfferHandler) CounterOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	offerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var req dto.OfferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	offer, err := h.offer.Counter(ctx, principal.UserID, offerID, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(offer); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// WithdrawOffer godoc
+// @Tags Offer
+// @Summary Отказаться от принятого предложения
+// @Description Покупатель отказывается от покупки, продавец снимает резерв. Объявление снова публикуется.
+// @Description Доступно обоим участникам, пока по предложению не оформлен заказ.
+// @Produce json
+// @Param id path int true "ID предложения"
+// @Success 200 {object} dto.OfferResponse
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Предложение не найдено"
+// @Failure 409 {object} utils.APIError "Предложение не принято или по нему оформлен заказ"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /offers/{id}/withdraw [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *OfferHandler) WithdrawOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	offerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	offer, err := h.offer.Withdraw(ctx, principal.UserID, offerID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(offer); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
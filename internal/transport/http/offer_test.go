@@ -0,0 +1,138 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOfferHandler_Respond(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		url            string
+		body           string
+		principal      *entity.Principal
+		mockSetup      func(*mock.MockOfferUsecase)
+		expectedStatus int
+	}{
+		{
+			name:      "Принятие предложения",
+			url:       "/offers/5/accept",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockOfferUsecase) {
+				m.EXPECT().Accept(gomock.Any(), 1, 5).
+					Return(&dto.OfferResponse{ID: 5, Status: string(entity.OfferAccepted)}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Объявление уже недоступно для резерва",
+			url:       "/offers/5/accept",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockOfferUsecase) {
+				m.EXPECT().Accept(gomock.Any(), 1, 5).
+					Return(nil, entity.NewError(entity.ErrConflict, entity.NewLocalizedMessage(entity.MsgOfferExpired, nil)))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:      "Отклонение предложения",
+			url:       "/offers/5/decline",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockOfferUsecase) {
+				m.EXPECT().Decline(gomock.Any(), 1, 5).
+					Return(&dto.OfferResponse{ID: 5, Status: string(entity.OfferDeclined)}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Встречное предложение",
+			url:       "/offers/5/counter",
+			body:      `{"amount":950}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockOfferUsecase) {
+				m.EXPECT().Counter(gomock.Any(), 1, 5, &dto.OfferRequest{Amount: 950}).
+					Return(&dto.OfferResponse{ID: 6, Status: string(entity.OfferPending)}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:      "Отказ от принятого предложения",
+			url:       "/offers/5/withdraw",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockOfferUsecase) {
+				m.EXPECT().Withdraw(gomock.Any(), 1, 5).
+					Return(&dto.OfferResponse{ID: 5, Status: string(entity.OfferWithdrawn)}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Отказ от непринятого предложения",
+			url:       "/offers/5/withdraw",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockOfferUsecase) {
+				m.EXPECT().Withdraw(gomock.Any(), 1, 5).
+					Return(nil, entity.NewError(entity.ErrConflict, entity.NewLocalizedMessage(entity.MsgOfferNotAccepted,
+						entity.MessageParams{"status": entity.OfferExpired})))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Встречное предложение без суммы",
+			url:            "/offers/5/counter",
+			body:           `{`,
+			principal:      testSessionPrincipal,
+			mockSetup:      func(*mock.MockOfferUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Некорректный ID",
+			url:            "/offers/abc/accept",
+			principal:      testSessionPrincipal,
+			mockSetup:      func(*mock.MockOfferUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Без входа",
+			url:            "/offers/5/accept",
+			mockSetup:      func(*mock.MockOfferUsecase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "API-ключ не отвечает на предложения",
+			url:            "/offers/5/accept",
+			principal:      &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey},
+			mockSetup:      func(*mock.MockOfferUsecase) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			offers := mock.NewMockOfferUsecase(ctrl)
+			tc.mockSetup(offers)
+			handler := NewOfferHandler(offers, config.CSRFConfig{})
+
+			req := httptest.NewRequest(http.MethodPost, tc.url, strings.NewReader(tc.body))
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
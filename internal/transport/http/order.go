@@ -0,0 +1,194 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+type OrderHandler struct {
+	order usecase.OrderUsecase
+	cfg   config.CSRFConfig
+}
+
+func NewOrderHandler(order usecase.OrderUsecase, cfg config.CSRFConfig) OrderHandler {
+	return OrderHandler{order: order, cfg: cfg}
+}
+
+func (h *OrderHandler) Configure(r *http.ServeMux) {
+	r.Handle("POST /ad/{id}/orders", middleware.RequireSession()(http.HandlerFunc(h.CreateOrder)))
+
+	orderMux := http.NewServeMux()
+	orderMux.HandleFunc("GET /all", h.GetOrders)
+	orderMux.HandleFunc("GET /{id}", h.GetOrder)
+	orderMux.HandleFunc("POST /{id}/{action}", h.UpdateOrderStatus)
+
+	r.Handle("/orders/", http.StripPrefix("/orders", middleware.RequireSession()(orderMux)))
+}
+
+// CreateOrder godoc
+// @Tags Order
+// @Summary Оформить заказ
+// @Description Оформляет заказ по цене объявления и резервирует его. Если продавец принял предложение
+// @Description покупателя, заказ оформляется по цене предложения.
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Success 201 {object} dto.OrderResponse
+// @Failure 400 {object} utils.APIError "Собственное объявление"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Объявление не найдено"
+// @Failure 409 {object} utils.APIError "Объявление зарезервировано, продано или заказ уже оформлен"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /ad/{id}/orders [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	order, err := h.order.Create(ctx, principal.UserID, adID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(order); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetOrders godoc
+// @Tags Order
+// @Summary История заказов
+// @Description Заказы пользователя как покупателя и как продавца, новые первыми.
+// @Produce json
+// @Param limit query int false "Количество заказов на странице (по умолчанию 20)"
+// @Param offset query int false "Смещение от начала списка (по умолчанию 0)"
+// @Success 200 {object} []dto.OrderResponse
+// @Failure 400 {object} utils.APIError "Некорректные параметры запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /orders/all [get]
+// @Security session_cookie
+func (h *OrderHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	offset, limit, err := utils.ParsePagination(r, entity.OrdersDefaultPage, entity.OrdersMaxPage)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	orders, err := h.order.GetByUserID(ctx, principal.UserID, offset, limit)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(orders); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetOrder godoc
+// @Tags Order
+// @Summary Заказ с историей статусов
+// @Produce json
+// @Param id path int true "ID заказа"
+// @Success 200 {object} dto.OrderResponse
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Заказ не найден"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /orders/{id} [get]
+// @Security session_cookie
+func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	orderID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	order, err := h.order.GetByID(ctx, principal.UserID, orderID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(order); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// UpdateOrderStatus godoc
+// @Tags Order
+// @Summary Изменить статус заказа
+// @Description Продавец подтверждает (confirm) и отправляет (ship) заказ, покупатель подтверждает получение
+// @Description (complete) или открывает спор (dispute). До отправки заказ может отменить (cancel) любая сторона,
+// @Description спор закрывает покупатель через complete или продавец через cancel.
+// @Produce json
+// @Param id path int true "ID заказа"
+// @Param action path string true "Действие" Enums(confirm, ship, complete, dispute, cancel)
+// @Success 200 {object} dto.OrderResponse
+// @Failure 400 {object} utils.APIError "Неверный ID или действие"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Действие недоступно этой стороне"
+// @Failure 404 {object} utils.APIError "Заказ не найден"
+// @Failure 409 {object} utils.APIError "Действие недоступно в текущем статусе"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /orders/{id}/{action} [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *OrderHandler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	orderID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	order, err := h.order.UpdateStatus(ctx, principal.UserID, orderID, r.PathValue("action"))
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(order); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOrderHandler_UpdateOrderStatus(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		url            string
+		principal      *entity.Principal
+		mockSetup      func(*mock.MockOrderUsecase)
+		expectedStatus int
+	}{
+		{
+			name:      "Подтверждение заказа",
+			url:       "/orders/7/confirm",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockOrderUsecase) {
+				m.EXPECT().UpdateStatus(gomock.Any(), 1, 7, "confirm").
+					Return(&dto.OrderResponse{ID: 7, Status: string(entity.OrderConfirmed)}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Неизвестное действие",
+			url:       "/orders/7/refund",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockOrderUsecase) {
+				m.EXPECT().UpdateStatus(gomock.Any(), 1, 7, "refund").
+					Return(nil, entity.NewError(entity.ErrBadRequest, fmt.Errorf("неизвестное действие с заказом %q", "refund")))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "Недопустимый переход",
+			url:       "/orders/7/complete",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockOrderUsecase) {
+				m.EXPECT().UpdateStatus(gomock.Any(), 1, 7, "complete").
+					Return(nil, entity.NewError(entity.ErrConflict, entity.NewLocalizedMessage(entity.MsgOrderTransitionInvalid,
+						entity.MessageParams{"from": entity.OrderCreated, "to": entity.OrderCompleted})))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:      "Переход не для этой роли",
+			url:       "/orders/7/ship",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockOrderUsecase) {
+				m.EXPECT().UpdateStatus(gomock.Any(), 1, 7, "ship").
+					Return(nil, entity.NewError(entity.ErrForbidden, entity.NewLocalizedMessage(entity.MsgOrderTransitionForbidden,
+						entity.MessageParams{"role": entity.OrderRoleBuyer, "to": entity.OrderShipped})))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Некорректный ID",
+			url:            "/orders/abc/confirm",
+			principal:      testSessionPrincipal,
+			mockSetup:      func(*mock.MockOrderUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Без входа",
+			url:            "/orders/7/confirm",
+			mockSetup:      func(*mock.MockOrderUsecase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orders := mock.NewMockOrderUsecase(ctrl)
+			tc.mockSetup(orders)
+			handler := NewOrderHandler(orders, config.CSRFConfig{})
+
+			req := httptest.NewRequest(http.MethodPost, tc.url, nil)
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+// ChangePassword godoc
+// @Tags User
+// @Summary Смена пароля
+// @Description Требует текущий пароль. Все остальные сессии пользователя завершаются, текущая сохраняется.
+// @Accept json
+// @Param passwordData body dto.ChangePasswordRequest true "Текущий и новый пароль"
+// @Success 204
+// @Failure 400 {object} utils.APIError "Неверный формат запроса или новый пароль не подходит"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Неверный текущий пароль"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/password [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	var req dto.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.password.ChangePassword(ctx, principal.UserID, principal.SessionID, &req); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RequestPasswordReset godoc
+// @Tags User
+// @Summary Запрос сброса пароля
+// @Description Отправляет одноразовую ссылку для сброса пароля. Ответ не зависит от того,
+// @Description существует ли пользователь с таким логином.
+// @Accept json
+// @Param resetData body dto.PasswordResetRequest true "Логин"
+// @Success 202
+// @Failure 400 {object} utils.APIError "Неверный формат запроса"
+// @Failure 429 {object} utils.APIError "Слишком много запросов сброса для этого логина"
+// @Header 429 {integer} Retry-After "Секунд до снятия блокировки"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/password/reset/request [post]
+// @Security csrf_token
+func (h *UserHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req dto.PasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.password.RequestReset(ctx, &req); err != nil {
+		if writeLockoutError(w, r, err) {
+			return
+		}
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ConfirmPasswordReset godoc
+// @Tags User
+// @Summary Установка нового пароля по ссылке сброса
+// @Description Токен одноразовый и ограничен по времени. После смены пароля все сессии пользователя завершаются.
+// @Accept json
+// @Param resetData body dto.PasswordResetConfirmRequest true "Токен из ссылки и новый пароль"
+// @Success 204
+// @Failure 400 {object} utils.APIError "Токен недействителен или новый пароль не подходит"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/password/reset/confirm [post]
+// @Security csrf_token
+func (h *UserHandler) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req dto.PasswordResetConfirmRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.password.ConfirmReset(ctx, &req); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUserHandler_Password(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		url              string
+		body             string
+		principal        *entity.Principal
+		mockSetup        func(userMocks)
+		expectedStatus   int
+		expectRetryAfter string
+	}{
+		{
+			name:      "Смена пароля сохраняет текущую сессию",
+			url:       "/user/password",
+			body:      `{"current_password":"Current123","new_password":"Changed123"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m userMocks) {
+				m.password.EXPECT().ChangePassword(gomock.Any(), 1, "session",
+					&dto.ChangePasswordRequest{CurrentPassword: "Current123", NewPassword: "Changed123"}).
+					Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:      "Неверный текущий пароль",
+			url:       "/user/password",
+			body:      `{"current_password":"Wrong12345","new_password":"Changed123"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m userMocks) {
+				m.password.EXPECT().ChangePassword(gomock.Any(), 1, "session", gomock.Any()).
+					Return(entity.NewError(entity.ErrForbidden, fmt.Errorf("неверный текущий пароль")))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Смена пароля без входа",
+			url:            "/user/password",
+			body:           `{"current_password":"Current123","new_password":"Changed123"}`,
+			mockSetup:      func(userMocks) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "Запрос сброса",
+			url:  "/user/password/reset/request",
+			body: `{"login":"user.test"}`,
+			mockSetup: func(m userMocks) {
+				m.password.EXPECT().RequestReset(gomock.Any(), &dto.PasswordResetRequest{Login: "user.test"}).Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name: "Лимит запросов сброса",
+			url:  "/user/password/reset/request",
+			body: `{"login":"user.test"}`,
+			mockSetup: func(m userMocks) {
+				m.password.EXPECT().RequestReset(gomock.Any(), gomock.Any()).
+					Return(entity.NewError(entity.ErrTooManyRequests, entity.LockoutError{RetryAfter: 90 * time.Second}))
+			},
+			expectedStatus:   http.StatusTooManyRequests,
+			expectRetryAfter: "90",
+		},
+		{
+			name: "Установка нового пароля по ссылке",
+			url:  "/user/password/reset/confirm",
+			body: `{"token":"reset-token","new_password":"Changed123"}`,
+			mockSetup: func(m userMocks) {
+				m.password.EXPECT().ConfirmReset(gomock.Any(),
+					&dto.PasswordResetConfirmRequest{Token: "reset-token", NewPassword: "Changed123"}).
+					Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Повторное использование ссылки",
+			url:  "/user/password/reset/confirm",
+			body: `{"token":"reset-token","new_password":"Changed123"}`,
+			mockSetup: func(m userMocks) {
+				m.password.EXPECT().ConfirmReset(gomock.Any(), gomock.Any()).
+					Return(entity.NewError(entity.ErrBadRequest, fmt.Errorf("ссылка недействительна")))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Некорректное тело",
+			url:            "/user/password/reset/confirm",
+			body:           `{`,
+			mockSetup:      func(userMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler, m := newTestUserHandler(ctrl)
+			tc.mockSetup(m)
+
+			req := httptest.NewRequest(http.MethodPost, tc.url, strings.NewReader(tc.body))
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Equal(t, tc.expectRetryAfter, rr.Header().Get("Retry-After"))
+		})
+	}
+}
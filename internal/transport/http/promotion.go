@@ -0,0 +1,157 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+type PromotionHandler struct {
+	promotion usecase.PromotionUsecase
+	cfg       config.CSRFConfig
+}
+
+func NewPromotionHandler(promotion usecase.PromotionUsecase, cfg config.CSRFConfig) PromotionHandler {
+	return PromotionHandler{promotion: promotion, cfg: cfg}
+}
+
+func (h *PromotionHandler) Configure(r *http.ServeMux) {
+	r.Handle("POST /ad/{id}/promotions", middleware.RequireSession()(http.HandlerFunc(h.CreatePromotion)))
+	r.Handle("GET /ad/{id}/promotions", middleware.RequireSession()(http.HandlerFunc(h.GetPromotions)))
+	r.Handle("DELETE /ad/{id}/promotions/{promotionID}", middleware.RequireSession()(http.HandlerFunc(h.CancelPromotion)))
+}
+
+// CreatePromotion godoc
+// @Tags Promotion
+// @Summary Продвинуть объявление
+// @Description С starts_at до ends_at объявление закреплено в начале списка /ad/all выше обычных,
+// @Description между собой продвигаемые объявления упорядочены по уровню: top, premium, basic.
+// @Accept json
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Param promotionData body dto.PromotionRequest true "Уровень и период продвижения"
+// @Success 201 {object} dto.PromotionResponse
+// @Failure 400 {object} utils.APIError "Неверный уровень или период"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Чужое объявление"
+// @Failure 404 {object} utils.APIError "Объявление не найдено"
+// @Failure 409 {object} utils.APIError "Объявление не опубликовано или уже продвигается"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /ad/{id}/promotions [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *PromotionHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var req dto.PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	promotion, err := h.promotion.Create(ctx, principal.UserID, adID, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(promotion); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetPromotions godoc
+// @Tags Promotion
+// @Summary Продвижения объявления
+// @Description Все продвижения объявления, новые первыми. Доступно только продавцу.
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Success 200 {object} []dto.PromotionResponse
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Чужое объявление"
+// @Failure 404 {object} utils.APIError "Объявление не найдено"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /ad/{id}/promotions [get]
+// @Security session_cookie
+func (h *PromotionHandler) GetPromotions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	promotions, err := h.promotion.GetByAdvertisementID(ctx, principal.UserID, adID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(promotions); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// CancelPromotion godoc
+// @Tags Promotion
+// @Summary Отменить продвижение
+// @Param id path int true "ID объявления"
+// @Param promotionID path int true "ID продвижения"
+// @Success 204
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Чужое объявление"
+// @Failure 404 {object} utils.APIError "Активное продвижение не найдено"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /ad/{id}/promotions/{promotionID} [delete]
+// @Security csrf_token
+// @Security session_cookie
+func (h *PromotionHandler) CancelPromotion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+	promotionID, err := strconv.Atoi(r.PathValue("promotionID"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.promotion.Cancel(ctx, principal.UserID, adID, promotionID); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
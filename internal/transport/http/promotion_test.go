@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPromotionHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		principal      *entity.Principal
+		mockSetup      func(*mock.MockPromotionUsecase)
+		expectedStatus int
+	}{
+		{
+			name:      "Продвижение своего объявления",
+			method:    http.MethodPost,
+			url:       "/ad/10/promotions",
+			body:      `{"tier":"top","ends_at":"2030-01-02T00:00:00Z"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockPromotionUsecase) {
+				m.EXPECT().Create(gomock.Any(), 1, 10, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ int, req *dto.PromotionRequest) (*dto.PromotionResponse, error) {
+						require.Equal(t, "top", req.Tier)
+						return &dto.PromotionResponse{ID: 3, Tier: req.Tier}, nil
+					})
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:      "Продвижение чужого объявления",
+			method:    http.MethodPost,
+			url:       "/ad/10/promotions",
+			body:      `{"tier":"top","ends_at":"2030-01-02T00:00:00Z"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockPromotionUsecase) {
+				m.EXPECT().Create(gomock.Any(), 1, 10, gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrForbidden, fmt.Errorf("продвигать можно только собственные объявления")))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Список продвижений",
+			method:    http.MethodGet,
+			url:       "/ad/10/promotions",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockPromotionUsecase) {
+				m.EXPECT().GetByAdvertisementID(gomock.Any(), 1, 10).Return([]dto.PromotionResponse{{ID: 3}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Отмена продвижения",
+			method:    http.MethodDelete,
+			url:       "/ad/10/promotions/3",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockPromotionUsecase) {
+				m.EXPECT().Cancel(gomock.Any(), 1, 10, 3).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Некорректное тело",
+			method:         http.MethodPost,
+			url:            "/ad/10/promotions",
+			body:           `{`,
+			principal:      testSessionPrincipal,
+			mockSetup:      func(*mock.MockPromotionUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Без входа",
+			method:         http.MethodPost,
+			url:            "/ad/10/promotions",
+			body:           `{"tier":"top"}`,
+			mockSetup:      func(*mock.MockPromotionUsecase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			promotions := mock.NewMockPromotionUsecase(ctrl)
+			tc.mockSetup(promotions)
+			handler := NewPromotionHandler(promotions, config.CSRFConfig{})
+
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+type ReviewHandler struct {
+	review usecase.ReviewUsecase
+	cfg    config.CSRFConfig
+}
+
+func NewReviewHandler(review usecase.ReviewUsecase, cfg config.CSRFConfig) ReviewHandler {
+	return ReviewHandler{review: review, cfg: cfg}
+}
+
+func (h *ReviewHandler) Configure(r *http.ServeMux) {
+	r.Handle("POST /ad/{id}/reviews", middleware.RequireSession()(http.HandlerFunc(h.CreateReview)))
+
+	reviewMux := http.NewServeMux()
+	reviewMux.HandleFunc("GET /seller/{id}", h.GetSellerReviews)
+	reviewMux.Handle("POST /{id}/reply", middleware.RequireSession()(http.HandlerFunc(h.ReplyToReview)))
+	reviewMux.Handle("POST /{id}/report", middleware.RequireSession()(http.HandlerFunc(h.ReportReview)))
+
+	r.Handle("/review/", http.StripPrefix("/review", reviewMux))
+}
+
+// CreateReview godoc
+// @Tags Review
+// @Summary Отзыв о продавце
+// @Description Оставляет оценку от 1 до 5 и текст по объявлению продавца. Отзыв доступен покупателю
+// @Description после завершения заказа по объявлению, по одному объявлению — только один отзыв.
+// @Description Рейтинг продавца пересчитывается сразу.
+// @Accept json
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Param reviewData body dto.CreateReviewRequest true "Оценка и текст"
+// @Success 201 {object} dto.ReviewResponse
+// @Failure 400 {object} utils.APIError "Неверная оценка или слишком длинный текст"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Отзыв на собственное объявление или без завершенного заказа"
+// @Failure 404 {object} utils.APIError "Объявление не найдено"
+// @Failure 409 {object} utils.APIError "Отзыв уже оставлен"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /ad/{id}/reviews [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *ReviewHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	adID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var req dto.CreateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	review, err := h.review.Create(ctx, principal.UserID, adID, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// GetSellerReviews godoc
+// @Tags Review
+// @Summary Отзывы о продавце
+// @Description Возвращает опубликованные отзывы о продавце, новые первыми. Скрытые модерацией отзывы не показываются.
+// @Produce json
+// @Param id path int true "ID продавца"
+// @Param limit query int false "Количество отзывов на странице (по умолчанию 20)"
+// @Param offset query int false "Смещение от начала списка (по умолчанию 0)"
+// @Success 200 {object} []dto.ReviewResponse
+// @Failure 400 {object} utils.APIError "Некорректные параметры запроса"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /review/seller/{id} [get]
+func (h *ReviewHandler) GetSellerReviews(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sellerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	offset, limit, err := utils.ParsePagination(r, entity.ReviewsDefaultPage, entity.ReviewsMaxPage)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	reviews, err := h.review.GetBySellerID(ctx, sellerID, offset, limit)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(reviews); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// ReplyToReview godoc
+// @Tags Review
+// @Summary Ответ продавца на отзыв
+// @Description Продавец может ответить на отзыв о себе один раз.
+// @Accept json
+// @Produce json
+// @Param id path int true "ID отзыва"
+// @Param replyData body dto.ReviewReplyRequest true "Текст ответа"
+// @Success 200 {object} dto.ReviewResponse
+// @Failure 400 {object} utils.APIError "Неверный формат запроса"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 403 {object} utils.APIError "Отзыв не о текущем пользователе"
+// @Failure 404 {object} utils.APIError "Отзыв не найден"
+// @Failure 409 {object} utils.APIError "Ответ уже дан"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /review/{id}/reply [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *ReviewHandler) ReplyToReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	reviewID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var req dto.ReviewReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	review, err := h.review.Reply(ctx, principal.UserID, reviewID, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// ReportReview godoc
+// @Tags Review
+// @Summary Жалоба на отзыв
+// @Description Отзыв, на который пожаловались несколько разных пользователей, скрывается
+// @Description и перестает учитываться в рейтинге продавца.
+// @Param id path int true "ID отзыва"
+// @Success 204
+// @Failure 400 {object} utils.APIError "Жалоба на собственный отзыв"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Отзыв не найден"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /review/{id}/report [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *ReviewHandler) ReportReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	reviewID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.review.Report(ctx, principal.UserID, reviewID); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
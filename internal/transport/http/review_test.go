@@ -0,0 +1,114 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReviewHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		principal      *entity.Principal
+		mockSetup      func(*mock.MockReviewUsecase)
+		expectedStatus int
+	}{
+		{
+			name:      "Отзыв после завершенного заказа",
+			method:    http.MethodPost,
+			url:       "/ad/10/reviews",
+			body:      `{"rating":5,"text":"Все отлично"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockReviewUsecase) {
+				m.EXPECT().Create(gomock.Any(), 1, 10, &dto.CreateReviewRequest{Rating: 5, Text: "Все отлично"}).
+					Return(&dto.ReviewResponse{ID: 7}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:      "Отзыв без сделки",
+			method:    http.MethodPost,
+			url:       "/ad/10/reviews",
+			body:      `{"rating":5}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockReviewUsecase) {
+				m.EXPECT().Create(gomock.Any(), 1, 10, gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrForbidden, fmt.Errorf("отзыв можно оставить только после завершенного заказа")))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Отзывы продавца доступны без входа",
+			method: http.MethodGet,
+			url:    "/review/seller/2",
+			mockSetup: func(m *mock.MockReviewUsecase) {
+				m.EXPECT().GetBySellerID(gomock.Any(), 2, 0, gomock.Any()).Return([]dto.ReviewResponse{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Ответ продавца",
+			method:    http.MethodPost,
+			url:       "/review/7/reply",
+			body:      `{"reply":"Спасибо"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockReviewUsecase) {
+				m.EXPECT().Reply(gomock.Any(), 1, 7, &dto.ReviewReplyRequest{Reply: "Спасибо"}).
+					Return(&dto.ReviewResponse{ID: 7}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Жалоба на отзыв",
+			method:    http.MethodPost,
+			url:       "/review/7/report",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockReviewUsecase) {
+				m.EXPECT().Report(gomock.Any(), 1, 7).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "API-ключ не оставляет отзывы",
+			method:         http.MethodPost,
+			url:            "/ad/10/reviews",
+			body:           `{"rating":5}`,
+			principal:      &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey},
+			mockSetup:      func(*mock.MockReviewUsecase) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			reviews := mock.NewMockReviewUsecase(ctrl)
+			tc.mockSetup(reviews)
+			handler := NewReviewHandler(reviews, config.CSRFConfig{})
+
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
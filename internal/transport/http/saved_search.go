@@ -0,0 +1,179 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+type SavedSearchHandler struct {
+	savedSearch usecase.SavedSearchUsecase
+	cfg         config.CSRFConfig
+}
+
+func NewSavedSearchHandler(savedSearch usecase.SavedSearchUsecase, cfg config.CSRFConfig) SavedSearchHandler {
+	return SavedSearchHandler{savedSearch: savedSearch, cfg: cfg}
+}
+
+func (h *SavedSearchHandler) Configure(r *http.ServeMux) {
+	r.Handle("GET /saved-searches", middleware.RequireSession()(http.HandlerFunc(h.GetSavedSearches)))
+	r.Handle("POST /saved-searches", middleware.RequireSession()(http.HandlerFunc(h.CreateSavedSearch)))
+
+	savedSearchMux := http.NewServeMux()
+	savedSearchMux.HandleFunc("PUT /{id}", h.UpdateSavedSearch)
+	savedSearchMux.HandleFunc("DELETE /{id}", h.DeleteSavedSearch)
+
+	r.Handle("/saved-searches/", http.StripPrefix("/saved-searches", middleware.RequireSession()(savedSearchMux)))
+}
+
+// GetSavedSearches godoc
+// @Tags SavedSearch
+// @Summary Список сохраненных поисков
+// @Produce json
+// @Success 200 {object} []dto.SavedSearchResponse
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /saved-searches [get]
+// @Security session_cookie
+func (h *SavedSearchHandler) GetSavedSearches(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	searches, err := h.savedSearch.GetByUserID(ctx, principal.UserID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(searches); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// CreateSavedSearch godoc
+// @Tags SavedSearch
+// @Summary Сохранение поиска
+// @Description Фильтры совпадают с параметрами GET /ad/all. При alerts_enabled=true в дайджест попадают объявления, опубликованные после сохранения поиска.
+// @Accept json
+// @Produce json
+// @Param savedSearchData body dto.SavedSearchRequest true "Параметры поиска"
+// @Success 201 {object} dto.SavedSearchResponse
+// @Failure 400 {object} utils.APIError "Некорректные параметры или превышен лимит поисков"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /saved-searches [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *SavedSearchHandler) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	var req dto.SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	search, err := h.savedSearch.Create(ctx, principal.UserID, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(search); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// UpdateSavedSearch godoc
+// @Tags SavedSearch
+// @Summary Изменение сохраненного поиска
+// @Accept json
+// @Produce json
+// @Param id path int true "ID поиска"
+// @Param savedSearchData body dto.SavedSearchRequest true "Параметры поиска"
+// @Success 200 {object} dto.SavedSearchResponse
+// @Failure 400 {object} utils.APIError "Некорректные параметры"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Поиск не найден"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /saved-searches/{id} [put]
+// @Security csrf_token
+// @Security session_cookie
+func (h *SavedSearchHandler) UpdateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	searchID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	var req dto.SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	search, err := h.savedSearch.Update(ctx, principal.UserID, searchID, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(search); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// DeleteSavedSearch godoc
+// @Tags SavedSearch
+// @Summary Удаление сохраненного поиска
+// @Param id path int true "ID поиска"
+// @Success 204
+// @Failure 400 {object} utils.APIError "Неверный ID"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 404 {object} utils.APIError "Поиск не найден"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /saved-searches/{id} [delete]
+// @Security csrf_token
+// @Security session_cookie
+func (h *SavedSearchHandler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	searchID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.savedSearch.Delete(ctx, principal.UserID, searchID); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
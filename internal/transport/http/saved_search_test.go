@@ -0,0 +1,149 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSavedSearchHandler(t *testing.T) {
+	t.Parallel()
+
+	notFound := entity.NewError(entity.ErrNotFound, fmt.Errorf("поиск id=5 не найден"))
+
+	testCases := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		principal      *entity.Principal
+		mockSetup      func(*mock.MockSavedSearchUsecase)
+		expectedStatus int
+	}{
+		{
+			name:      "Список поисков",
+			method:    http.MethodGet,
+			url:       "/saved-searches",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockSavedSearchUsecase) {
+				m.EXPECT().GetByUserID(gomock.Any(), 1).Return([]dto.SavedSearchResponse{{ID: 5}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Сохранение поиска",
+			method:    http.MethodPost,
+			url:       "/saved-searches",
+			body:      `{"alerts_enabled":true}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockSavedSearchUsecase) {
+				m.EXPECT().Create(gomock.Any(), 1, gomock.Any()).Return(&dto.SavedSearchResponse{ID: 5}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Некорректное тело",
+			method:         http.MethodPost,
+			url:            "/saved-searches",
+			body:           `{`,
+			principal:      testSessionPrincipal,
+			mockSetup:      func(*mock.MockSavedSearchUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "Превышен лимит поисков",
+			method:    http.MethodPost,
+			url:       "/saved-searches",
+			body:      `{}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockSavedSearchUsecase) {
+				m.EXPECT().Create(gomock.Any(), 1, gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrBadRequest, fmt.Errorf("превышен лимит сохраненных поисков")))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "Изменение поиска",
+			method:    http.MethodPut,
+			url:       "/saved-searches/5",
+			body:      `{"alerts_enabled":false}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockSavedSearchUsecase) {
+				m.EXPECT().Update(gomock.Any(), 1, 5, gomock.Any()).Return(&dto.SavedSearchResponse{ID: 5}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Изменение чужого поиска",
+			method:    http.MethodPut,
+			url:       "/saved-searches/5",
+			body:      `{}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockSavedSearchUsecase) {
+				m.EXPECT().Update(gomock.Any(), 1, 5, gomock.Any()).Return(nil, notFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:      "Удаление поиска",
+			method:    http.MethodDelete,
+			url:       "/saved-searches/5",
+			principal: testSessionPrincipal,
+			mockSetup: func(m *mock.MockSavedSearchUsecase) {
+				m.EXPECT().Delete(gomock.Any(), 1, 5).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Неверный ID",
+			method:         http.MethodDelete,
+			url:            "/saved-searches/abc",
+			principal:      testSessionPrincipal,
+			mockSetup:      func(*mock.MockSavedSearchUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Поиски недоступны по API-ключу",
+			method:         http.MethodGet,
+			url:            "/saved-searches",
+			principal:      &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey, Scopes: []string{entity.ScopeAdsRead}},
+			mockSetup:      func(*mock.MockSavedSearchUsecase) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Без входа",
+			method:         http.MethodDelete,
+			url:            "/saved-searches/5",
+			mockSetup:      func(*mock.MockSavedSearchUsecase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			savedSearch := mock.NewMockSavedSearchUsecase(ctrl)
+			tc.mockSetup(savedSearch)
+			handler := NewSavedSearchHandler(savedSearch, config.CSRFConfig{})
+
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
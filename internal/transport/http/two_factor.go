@@ -0,0 +1,219 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+// LoginTwoFactor godoc
+// @Tags User
+// @Summary Второй шаг входа
+// @Description Принимает challenge_token из ответа /user/login и код TOTP или код восстановления.
+// @Description При успехе создает сессию так же, как обычный вход.
+// @Accept json
+// @Produce json
+// @Param loginData body dto.LoginTwoFactorRequest true "Токен второго шага и код"
+// @Header 200 {string} Set-Cookie "Сессионные cookies"
+// @Header 200 {string} X-CSRF-Token "CSRF-токен"
+// @Success 200 {object} dto.LoginResponse
+// @Failure 400 {object} utils.APIError "Неверный формат запроса"
+// @Failure 401 {object} utils.APIError "Неверный код или токен истек"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/login/2fa [post]
+// @Security csrf_token
+func (h *UserHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req dto.LoginTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	userID, err := h.twoFactor.VerifyChallenge(ctx, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	if err := h.user.CompleteLogin(ctx, userID, utils.ClientIP(r)); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	token, err := utils.CreateSession(w, r, h.auth, userID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	middleware.SetCSRFToken(w, r, h.cfg)
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(dto.LoginResponse{Token: token}); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// EnrollTwoFactor godoc
+// @Tags User
+// @Summary Подключение двухфакторной аутентификации
+// @Description Генерирует секрет TOTP и otpauth-ссылку для QR-кода. 2FA включится после подтверждения кодом.
+// @Produce json
+// @Success 200 {object} dto.TOTPEnrollResponse
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 409 {object} utils.APIError "2FA уже включена"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/2fa/enroll [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *UserHandler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	enrollment, err := h.twoFactor.Enroll(ctx, principal.UserID)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(enrollment); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// ConfirmTwoFactor godoc
+// @Tags User
+// @Summary Подтверждение двухфакторной аутентификации
+// @Description Включает 2FA после проверки первого кода из приложения и возвращает коды восстановления.
+// @Accept json
+// @Produce json
+// @Param codeData body dto.TOTPCodeRequest true "Код из приложения"
+// @Success 200 {object} dto.RecoveryCodesResponse
+// @Failure 400 {object} utils.APIError "Неверный код"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 429 {object} utils.APIError "Слишком много неверных кодов"
+// @Header 429 {integer} Retry-After "Секунд до снятия блокировки"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/2fa/confirm [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *UserHandler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	var req dto.TOTPCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	codes, err := h.twoFactor.Confirm(ctx, principal.UserID, req.Code)
+	if err != nil {
+		if writeLockoutError(w, r, err) {
+			return
+		}
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(codes); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
+
+// DisableTwoFactor godoc
+// @Tags User
+// @Summary Отключение двухфакторной аутентификации
+// @Description Требует текущий пароль и код TOTP или код восстановления.
+// @Accept json
+// @Param disableData body dto.TOTPDisableRequest true "Пароль и код"
+// @Success 204
+// @Failure 400 {object} utils.APIError "Неверный формат запроса"
+// @Failure 401 {object} utils.APIError "Неверный код"
+// @Failure 403 {object} utils.APIError "Неверный пароль"
+// @Failure 429 {object} utils.APIError "Слишком много неверных кодов"
+// @Header 429 {integer} Retry-After "Секунд до снятия блокировки"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/2fa/disable [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *UserHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	var req dto.TOTPDisableRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	if err := h.twoFactor.Disable(ctx, principal.UserID, &req); err != nil {
+		if writeLockoutError(w, r, err) {
+			return
+		}
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Tags User
+// @Summary Перевыпуск кодов восстановления
+// @Description Старые коды перестают действовать. Требует код TOTP.
+// @Accept json
+// @Produce json
+// @Param codeData body dto.TOTPCodeRequest true "Код из приложения"
+// @Success 200 {object} dto.RecoveryCodesResponse
+// @Failure 400 {object} utils.APIError "2FA не включена"
+// @Failure 401 {object} utils.APIError "Неверный код"
+// @Failure 429 {object} utils.APIError "Слишком много неверных кодов"
+// @Header 429 {integer} Retry-After "Секунд до снятия блокировки"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/2fa/recovery-codes [post]
+// @Security csrf_token
+// @Security session_cookie
+func (h *UserHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	var req dto.TOTPCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	codes, err := h.twoFactor.RegenerateRecoveryCodes(ctx, principal.UserID, req.Code)
+	if err != nil {
+		if writeLockoutError(w, r, err) {
+			return
+		}
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(codes); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUserHandler_TwoFactorLockout(t *testing.T) {
+	t.Parallel()
+
+	lockout := entity.NewError(entity.ErrTooManyRequests, entity.LockoutError{RetryAfter: 90 * time.Second})
+
+	testCases := []struct {
+		name      string
+		url       string
+		body      string
+		mockSetup func(userMocks)
+	}{
+		{
+			name: "Подтверждение 2FA",
+			url:  "/user/2fa/confirm",
+			body: `{"code":"123456"}`,
+			mockSetup: func(m userMocks) {
+				m.twoFactor.EXPECT().Confirm(gomock.Any(), 1, "123456").Return(nil, lockout)
+			},
+		},
+		{
+			name: "Отключение 2FA",
+			url:  "/user/2fa/disable",
+			body: `{"password":"Correct123","code":"123456"}`,
+			mockSetup: func(m userMocks) {
+				m.twoFactor.EXPECT().Disable(gomock.Any(), 1, &dto.TOTPDisableRequest{Password: "Correct123", Code: "123456"}).
+					Return(lockout)
+			},
+		},
+		{
+			name: "Перевыпуск кодов восстановления",
+			url:  "/user/2fa/recovery-codes",
+			body: `{"code":"123456"}`,
+			mockSetup: func(m userMocks) {
+				m.twoFactor.EXPECT().RegenerateRecoveryCodes(gomock.Any(), 1, "123456").Return(nil, lockout)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler, m := newTestUserHandler(ctrl)
+			tc.mockSetup(m)
+
+			req := httptest.NewRequest(http.MethodPost, tc.url, strings.NewReader(tc.body))
+			rr := serveWithPrincipal(handler.Configure, req, testSessionPrincipal)
+
+			require.Equal(t, http.StatusTooManyRequests, rr.Code)
+			require.Equal(t, "90", rr.Header().Get("Retry-After"))
+		})
+	}
+}
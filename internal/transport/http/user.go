@@ -2,7 +2,7 @@ package http
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -12,16 +12,37 @@ import (
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/middleware"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/transport/http/utils"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
 )
 
 type UserHandler struct {
-	auth usecase.AuthUsecase
-	user usecase.UserUsecase
-	cfg  config.CSRFConfig
+	auth      usecase.AuthUsecase
+	user      usecase.UserUsecase
+	twoFactor usecase.TwoFactorUsecase
+	password  usecase.PasswordUsecase
+	contact   usecase.ContactUsecase
+	account   usecase.AccountUsecase
+	cfg       config.CSRFConfig
 }
 
-func NewUserHandler(auth usecase.AuthUsecase, user usecase.UserUsecase, cfg config.CSRFConfig) UserHandler {
-	return UserHandler{auth: auth, user: user, cfg: cfg}
+func NewUserHandler(
+	auth usecase.AuthUsecase,
+	user usecase.UserUsecase,
+	twoFactor usecase.TwoFactorUsecase,
+	password usecase.PasswordUsecase,
+	contact usecase.ContactUsecase,
+	account usecase.AccountUsecase,
+	cfg config.CSRFConfig,
+) UserHandler {
+	return UserHandler{
+		auth:      auth,
+		user:      user,
+		twoFactor: twoFactor,
+		password:  password,
+		contact:   contact,
+		account:   account,
+		cfg:       cfg,
+	}
 }
 
 func (h *UserHandler) Configure(r *http.ServeMux) {
@@ -29,7 +50,24 @@ func (h *UserHandler) Configure(r *http.ServeMux) {
 
 	userMux.HandleFunc("POST /register", h.Register)
 	userMux.HandleFunc("POST /login", h.Login)
-	userMux.HandleFunc("GET /profile/{id}", h.GetProfile)
+	userMux.HandleFunc("POST /login/2fa", h.LoginTwoFactor)
+	userMux.Handle("GET /profile/{id}", middleware.RequireAuth()(http.HandlerFunc(h.GetProfile)))
+	userMux.Handle("GET /me", middleware.RequireAuth()(http.HandlerFunc(h.GetCurrentUser)))
+	userMux.Handle("PATCH /me", middleware.RequireSession()(http.HandlerFunc(h.UpdateProfile)))
+	userMux.Handle("DELETE /me", middleware.RequireSession()(http.HandlerFunc(h.DeleteAccount)))
+	userMux.Handle("GET /me/export", middleware.RequireSession()(http.HandlerFunc(h.ExportAccount)))
+	userMux.Handle("POST /password", middleware.RequireSession()(http.HandlerFunc(h.ChangePassword)))
+	userMux.HandleFunc("POST /password/reset/request", h.RequestPasswordReset)
+	userMux.HandleFunc("POST /password/reset/confirm", h.ConfirmPasswordReset)
+	userMux.Handle("PUT /contacts/{channel}", middleware.RequireSession()(http.HandlerFunc(h.SetContact)))
+	userMux.Handle("POST /contacts/{channel}/verify", middleware.RequireSession()(http.HandlerFunc(h.VerifyContact)))
+
+	twoFactorMux := http.NewServeMux()
+	twoFactorMux.HandleFunc("POST /enroll", h.EnrollTwoFactor)
+	twoFactorMux.HandleFunc("POST /confirm", h.ConfirmTwoFactor)
+	twoFactorMux.HandleFunc("POST /disable", h.DisableTwoFactor)
+	twoFactorMux.HandleFunc("POST /recovery-codes", h.RegenerateRecoveryCodes)
+	userMux.Handle("/2fa/", http.StripPrefix("/2fa", middleware.RequireSession()(twoFactorMux)))
 
 	r.Handle("/user/", http.StripPrefix("/user", userMux))
 }
@@ -53,18 +91,18 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	var registerDTO dto.UserRegister
 	if err := json.NewDecoder(r.Body).Decode(&registerDTO); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, entity.ErrBadRequest)
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
 		return
 	}
 
 	user, err := h.user.Register(ctx, &registerDTO)
 	if err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
 
 	if _, err := utils.CreateSession(w, r, h.auth, user.ID); err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
 
@@ -78,7 +116,7 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}); err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, entity.ErrInternal)
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
 		return
 	}
 }
@@ -89,6 +127,10 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 // @Description Авторизация пользователя. При успешной авторизации отправляет куки с сессией.
 // Если пользователь уже авторизован, предыдущие cookies с сессией перезаписываются.
 // Также устанавливает CSRF-токен при успешной авторизации.
+// Если у пользователя включена двухфакторная аутентификация, сессия не создается:
+// в ответе возвращается challenge_token для второго шага /user/login/2fa.
+// После серии неудачных попыток вход для логина или IP временно блокируется,
+// время до снятия блокировки передается в заголовке Retry-After.
 // @Accept json
 // @Produce json
 // @Param loginData body dto.Login true "Данные для авторизации (login и пароль)"
@@ -96,8 +138,9 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 // @Header 200 {string} X-CSRF-Token "CSRF-токен"
 // @Success 200 {object} dto.LoginResponse
 // @Failure 400 {object} utils.APIError "Неверный формат запроса"
-// @Failure 403 {object} utils.APIError "Доступ запрещен (неверные учетные данные)"
-// @Failure 404 {object} utils.APIError "Пользователь не найден"
+// @Failure 401 {object} utils.APIError "Неверные учетные данные"
+// @Failure 429 {object} utils.APIError "Слишком много неудачных попыток"
+// @Header 429 {integer} Retry-After "Секунд до снятия блокировки"
 // @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
 // @Router /user/login [post]
 // @Security csrf_token
@@ -106,27 +149,50 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	var loginDTO dto.Login
 	if err := json.NewDecoder(r.Body).Decode(&loginDTO); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, entity.ErrBadRequest)
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	userID, err := h.user.Login(ctx, &loginDTO, utils.ClientIP(r))
+	if err != nil {
+		if writeLockoutError(w, r, err) {
+			return
+		}
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
 
-	userID, err := h.user.Login(ctx, &loginDTO)
+	challenge, required, err := h.twoFactor.StartChallenge(ctx, userID)
 	if err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+	if required {
+		w.Header().Set("Content-Type", "application/json")
+		if err = json.NewEncoder(w).Encode(dto.LoginResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+		}); err != nil {
+			utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		}
+		return
+	}
+
+	if err := h.user.CompleteLogin(ctx, userID, utils.ClientIP(r)); err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
 
 	token, err := utils.CreateSession(w, r, h.auth, userID)
 	if err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
 
 	middleware.SetCSRFToken(w, r, h.cfg)
-	fmt.Println(token)
 	w.Header().Set("Content-Type", "application/json")
 	if err = json.NewEncoder(w).Encode(dto.LoginResponse{Token: token}); err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, entity.ErrInternal)
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
 		return
 	}
 }
@@ -134,50 +200,128 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 // GetProfile godoc
 // @Tags User
 // @Summary Получить профиль пользователя
-// @Description Возвращает профиль пользователя по ID. Требует авторизации. Доступен только для владельца профиля.
+// @Description Возвращает профиль пользователя по ID. Требует авторизации.
+// @Description Email и телефон видны всем только после подтверждения, владельцу профиля — всегда.
 // @Produce json
 // @Param id path int true "ID пользователя"
 // @Success 200 {object} dto.UserProfileResponse "Профиль пользователя"
 // @Failure 400 {object} utils.APIError "Неверный ID"
 // @Failure 401 {object} utils.APIError "Не авторизован"
-// @Failure 403 {object} utils.APIError "Нет доступа к этому профилю"
 // @Failure 404 {object} utils.APIError "Профиль не найден"
 // @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
 // @Router /user/profile/{id} [get]
 // @Security session_cookie
+// @Security bearer_token
+// @Security api_key
 func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	cookie, err := r.Cookie("session_id")
-	if err != nil || cookie == nil {
-		utils.WriteError(w, http.StatusUnauthorized, entity.ErrUnauthorized)
+	requestedID := r.PathValue("id")
+	applicantID, err := strconv.Atoi(requestedID)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
 		return
 	}
 
-	_, err = h.auth.GetUserIDBySession(ctx, cookie.Value)
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	user, err := h.user.GetUser(ctx, principal.UserID, applicantID)
 	if err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
 
-	requestedID := r.PathValue("id")
-	applicantID, err := strconv.Atoi(requestedID)
-	if err != nil {
-		utils.WriteError(w, http.StatusBadRequest, entity.ErrBadRequest)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err = json.NewEncoder(w).Encode(user); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
 		return
 	}
+}
+
+// GetCurrentUser godoc
+// @Tags User
+// @Summary Профиль текущего пользователя
+// @Description Возвращает полный профиль вошедшего пользователя, его роли, число непрочитанных уведомлений,
+// @Description способ входа и срок действия сессии.
+// @Produce json
+// @Success 200 {object} dto.CurrentUserResponse
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/me [get]
+// @Security session_cookie
+// @Security bearer_token
+// @Security api_key
+func (h *UserHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
 
-	user, err := h.user.GetUser(ctx, applicantID)
+	current, err := h.user.GetCurrentUser(ctx, principal)
 	if err != nil {
-		utils.WriteAPIError(w, utils.ToAPIError(err))
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(current); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
+		return
+	}
+}
 
-	if err = json.NewEncoder(w).Encode(user); err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, entity.ErrInternal)
+// UpdateProfile godoc
+// @Tags User
+// @Summary Изменение профиля
+// @Description Частичное изменение профиля текущего пользователя: меняются только переданные поля.
+// @Description Пустая строка в bio, city или avatar_url очищает поле. avatar_url проверяется
+// @Description по тем же правилам, что и изображения объявлений.
+// @Accept json
+// @Produce json
+// @Param profileData body dto.UpdateProfileRequest true "Изменяемые поля профиля"
+// @Success 200 {object} dto.UserProfileResponse "Обновленный профиль"
+// @Failure 400 {object} utils.APIError "Неверные данные профиля"
+// @Failure 401 {object} utils.APIError "Не авторизован"
+// @Failure 500 {object} utils.APIError "Внутренняя ошибка сервера"
+// @Router /user/me [patch]
+// @Security csrf_token
+// @Security session_cookie
+func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal, _ := GlobalUtils.GetPrincipal(ctx)
+
+	var req dto.UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, entity.ErrBadRequest)
+		return
+	}
+
+	profile, err := h.user.UpdateProfile(ctx, principal.UserID, &req)
+	if err != nil {
+		utils.WriteAPIError(w, r, utils.ToAPIError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(profile); err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, entity.ErrInternal)
 		return
 	}
 }
+
+// writeLockoutError отвечает 429 с Retry-After, если err описывает временную блокировку.
+func writeLockoutError(w http.ResponseWriter, r *http.Request, err error) bool {
+	var appErr entity.Error
+	var lockout entity.LockoutError
+	if !errors.As(err, &appErr) || !errors.As(appErr.InternalErr(), &lockout) {
+		return false
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(entity.RetryAfterSeconds(lockout.RetryAfter)))
+	utils.WriteError(w, r, http.StatusTooManyRequests, entity.ErrTooManyRequests)
+	return true
+}
@@ -0,0 +1,258 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type userMocks struct {
+	auth      *mock.MockAuthUsecase
+	user      *mock.MockUserUsecase
+	twoFactor *mock.MockTwoFactorUsecase
+	password  *mock.MockPasswordUsecase
+	contact   *mock.MockContactUsecase
+	account   *mock.MockAccountUsecase
+}
+
+func newTestUserHandler(ctrl *gomock.Controller) (*UserHandler, userMocks) {
+	m := userMocks{
+		auth:      mock.NewMockAuthUsecase(ctrl),
+		user:      mock.NewMockUserUsecase(ctrl),
+		twoFactor: mock.NewMockTwoFactorUsecase(ctrl),
+		password:  mock.NewMockPasswordUsecase(ctrl),
+		contact:   mock.NewMockContactUsecase(ctrl),
+		account:   mock.NewMockAccountUsecase(ctrl),
+	}
+	handler := NewUserHandler(m.auth, m.user, m.twoFactor, m.password, m.contact, m.account,
+		config.CSRFConfig{CookieName: "csrf_token", Secret: "secret"})
+	return &handler, m
+}
+
+func sessionCookie(rr *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range rr.Result().Cookies() {
+		if cookie.Name == "session_id" {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestUserHandler_Login(t *testing.T) {
+	t.Parallel()
+
+	const clientIP = "192.0.2.1"
+
+	testCases := []struct {
+		name             string
+		mockSetup        func(userMocks)
+		expectedStatus   int
+		expectedResponse dto.LoginResponse
+		expectSession    bool
+		expectRetryAfter string
+	}{
+		{
+			name: "Без 2FA вход завершается сразу",
+			mockSetup: func(m userMocks) {
+				m.user.EXPECT().Login(gomock.Any(), gomock.Any(), clientIP).Return(1, nil)
+				m.twoFactor.EXPECT().StartChallenge(gomock.Any(), 1).Return("", false, nil)
+				m.user.EXPECT().CompleteLogin(gomock.Any(), 1, clientIP).Return(nil)
+				m.auth.EXPECT().CreateSession(gomock.Any(), 1).Return("session-token", nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedResponse: dto.LoginResponse{Token: "session-token"},
+			expectSession:    true,
+		},
+		{
+			name: "С 2FA выдается токен второго шага без сессии",
+			mockSetup: func(m userMocks) {
+				m.user.EXPECT().Login(gomock.Any(), gomock.Any(), clientIP).Return(1, nil)
+				m.twoFactor.EXPECT().StartChallenge(gomock.Any(), 1).Return("challenge-token", true, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedResponse: dto.LoginResponse{TwoFactorRequired: true, ChallengeToken: "challenge-token"},
+		},
+		{
+			name: "Блокировка возвращает Retry-After",
+			mockSetup: func(m userMocks) {
+				m.user.EXPECT().Login(gomock.Any(), gomock.Any(), clientIP).
+					Return(0, entity.NewError(entity.ErrTooManyRequests, entity.LockoutError{RetryAfter: 90 * time.Second}))
+			},
+			expectedStatus:   http.StatusTooManyRequests,
+			expectRetryAfter: "90",
+		},
+		{
+			name: "Неверные учетные данные",
+			mockSetup: func(m userMocks) {
+				m.user.EXPECT().Login(gomock.Any(), gomock.Any(), clientIP).
+					Return(0, entity.NewError(entity.ErrUnauthorized, fmt.Errorf("неверные учетные данные")))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler, m := newTestUserHandler(ctrl)
+			tc.mockSetup(m)
+
+			req := httptest.NewRequest(http.MethodPost, "/user/login",
+				strings.NewReader(`{"login":"user.test","password":"Correct123"}`))
+			rr := httptest.NewRecorder()
+			handler.Login(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Equal(t, tc.expectRetryAfter, rr.Header().Get("Retry-After"))
+			require.Equal(t, tc.expectSession, sessionCookie(rr) != nil)
+			if tc.expectedStatus == http.StatusOK {
+				var resp dto.LoginResponse
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+				require.Equal(t, tc.expectedResponse, resp)
+			}
+		})
+	}
+}
+
+func TestUserHandler_LoginTwoFactor(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		mockSetup      func(userMocks)
+		expectedStatus int
+		expectSession  bool
+	}{
+		{
+			name: "Верный код создает сессию",
+			mockSetup: func(m userMocks) {
+				m.twoFactor.EXPECT().VerifyChallenge(gomock.Any(), &dto.LoginTwoFactorRequest{
+					ChallengeToken: "challenge-token",
+					Code:           "123456",
+				}).Return(1, nil)
+				m.user.EXPECT().CompleteLogin(gomock.Any(), 1, "192.0.2.1").Return(nil)
+				m.auth.EXPECT().CreateSession(gomock.Any(), 1).Return("session-token", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectSession:  true,
+		},
+		{
+			name: "Неверный код не завершает вход",
+			mockSetup: func(m userMocks) {
+				m.twoFactor.EXPECT().VerifyChallenge(gomock.Any(), gomock.Any()).
+					Return(0, entity.NewError(entity.ErrUnauthorized, fmt.Errorf("неверный код")))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler, m := newTestUserHandler(ctrl)
+			tc.mockSetup(m)
+
+			req := httptest.NewRequest(http.MethodPost, "/user/login/2fa",
+				strings.NewReader(`{"challenge_token":"challenge-token","code":"123456"}`))
+			rr := httptest.NewRecorder()
+			handler.LoginTwoFactor(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Equal(t, tc.expectSession, sessionCookie(rr) != nil)
+		})
+	}
+}
+
+func TestUserHandler_UpdateProfile(t *testing.T) {
+	t.Parallel()
+
+	city := "Москва"
+
+	testCases := []struct {
+		name           string
+		body           string
+		principal      *entity.Principal
+		mockSetup      func(userMocks)
+		expectedStatus int
+	}{
+		{
+			name:      "Изменение города",
+			body:      `{"city":"Москва"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m userMocks) {
+				m.user.EXPECT().UpdateProfile(gomock.Any(), 1, &dto.UpdateProfileRequest{City: &city}).
+					Return(&dto.UserProfileResponse{ID: 1, City: city}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Некорректный аватар",
+			body:      `{"avatar_url":"ftp://example.com/a.exe"}`,
+			principal: testSessionPrincipal,
+			mockSetup: func(m userMocks) {
+				m.user.EXPECT().UpdateProfile(gomock.Any(), 1, gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrBadRequest, fmt.Errorf("некорректный адрес изображения")))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Некорректное тело",
+			body:           `{`,
+			principal:      testSessionPrincipal,
+			mockSetup:      func(userMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "API-ключ не меняет профиль",
+			body:           `{"city":"Москва"}`,
+			principal:      &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey},
+			mockSetup:      func(userMocks) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Без входа",
+			body:           `{"city":"Москва"}`,
+			mockSetup:      func(userMocks) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler, m := newTestUserHandler(ctrl)
+			tc.mockSetup(m)
+
+			req := httptest.NewRequest(http.MethodPatch, "/user/me", strings.NewReader(tc.body))
+			rr := serveWithPrincipal(handler.Configure, req, tc.principal)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"net"
+	"net/http"
+
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+)
+
+// ClientIP возвращает адрес клиента, который определил ClientIPMiddleware с учетом
+// доверенных прокси. Без него — адрес соединения без порта: заголовкам X-Forwarded-For
+// и X-Real-Ip от произвольного клиента верить нельзя.
+func ClientIP(r *http.Request) string {
+	if ip, ok := GlobalUtils.GetClientIP(r.Context()); ok {
+		return ip
+	}
+	return RemoteIP(r)
+}
+
+// RemoteIP возвращает адрес соединения без порта.
+func RemoteIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
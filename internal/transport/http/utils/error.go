@@ -6,55 +6,162 @@ import (
 	"net/http"
 
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/i18n"
 )
 
+// ProblemContentType — тип содержимого ответов с ошибкой по RFC 7807.
+const ProblemContentType = "application/problem+json"
+
+// Машиночитаемые коды ошибок. Клиенты должны опираться на код, а не на текст detail:
+// текст переводится на язык запроса по коду error.<code> из каталога pkg/i18n.
+const (
+	CodeBadRequest       = "bad_request"
+	CodeValidationFailed = "validation_failed"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeAlreadyExists    = "already_exists"
+	CodeConflict         = "conflict"
+	CodeTooManyRequests  = "too_many_requests"
+	CodeInternal         = "internal_error"
+
+	CodePreconditionFailed   = "precondition_failed"
+	CodePreconditionRequired = "precondition_required"
+
+	CodeCSRFTokenMissing  = "csrf_token_missing"
+	CodeCSRFCookieMissing = "csrf_cookie_missing"
+	CodeCSRFTokenInvalid  = "csrf_token_invalid"
+)
+
+// APIError — тело ответа с ошибкой в формате application/problem+json.
+// Поле errors заполняется только для ошибок валидации: ключ — имя поля, значение — причина.
+// Detail и errors переводятся на язык запроса при записи ответа.
 type APIError struct {
-	Status  int    `json:"status"`
-	Message string `json:"message"`
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Status    int               `json:"status"`
+	Detail    string            `json:"detail,omitempty"`
+	Instance  string            `json:"instance,omitempty"`
+	Code      string            `json:"code"`
+	Errors    map[string]string `json:"errors,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+
+	message entity.LocalizedMessage
+	fields  entity.FieldErrors
 }
 
-var errorToStatus = map[error]int{
-	entity.ErrNotFound:      http.StatusNotFound,
-	entity.ErrBadRequest:    http.StatusBadRequest,
-	entity.ErrUnauthorized:  http.StatusUnauthorized,
-	entity.ErrForbidden:     http.StatusForbidden,
-	entity.ErrAlreadyExists: http.StatusConflict,
-	entity.ErrInternal:      http.StatusInternalServerError,
+type problem struct {
+	status int
+	code   string
 }
 
-func ToAPIError(err error) APIError {
-	var apiError APIError
-	var customError *entity.Error
+var errorToProblem = map[error]problem{
+	entity.ErrNotFound:      {http.StatusNotFound, CodeNotFound},
+	entity.ErrBadRequest:    {http.StatusBadRequest, CodeBadRequest},
+	entity.ErrUnauthorized:  {http.StatusUnauthorized, CodeUnauthorized},
+	entity.ErrForbidden:     {http.StatusForbidden, CodeForbidden},
+	entity.ErrAlreadyExists: {http.StatusConflict, CodeAlreadyExists},
+	entity.ErrInternal:      {http.StatusInternalServerError, CodeInternal},
 
-	if errors.As(err, &customError) {
-		client := customError.ClientErr()
-		apiError.Message = customError.ClientErr().Error()
+	entity.ErrTooManyRequests: {http.StatusTooManyRequests, CodeTooManyRequests},
+	entity.ErrConflict:        {http.StatusConflict, CodeConflict},
 
-		if status, ok := errorToStatus[client]; ok {
-			apiError.Status = status
-		} else {
-			apiError.Status = http.StatusInternalServerError
+	entity.ErrPreconditionFailed:   {http.StatusPreconditionFailed, CodePreconditionFailed},
+	entity.ErrPreconditionRequired: {http.StatusPreconditionRequired, CodePreconditionRequired},
+}
+
+// NewAPIError собирает ответ с ошибкой для статуса и кода. Title всегда совпадает
+// со стандартным текстом статуса, поэтому type остается about:blank.
+func NewAPIError(status int, code string) APIError {
+	apiError := APIError{
+		Type:    "about:blank",
+		Title:   http.StatusText(status),
+		Status:  status,
+		Code:    code,
+		message: entity.NewLocalizedMessage("error."+code, nil),
+	}
+	return apiError.Localize(i18n.Default)
+}
+
+// Localize переводит detail и ошибки полей на язык lang.
+func (e APIError) Localize(lang i18n.Lang) APIError {
+	e.Detail = e.message.Translate(lang)
+	if len(e.fields) > 0 {
+		e.Errors = make(map[string]string, len(e.fields))
+		for field, m := range e.fields {
+			e.Errors[field] = m.Translate(lang)
 		}
-		return apiError
+	}
+	return e
+}
+
+// ToAPIError переводит ошибку слоя usecase в ответ клиенту. Внутренняя причина
+// в ответ не попадает, из нее достаются только ошибки полей и сообщение для клиента.
+func ToAPIError(err error) APIError {
+	var customError entity.Error
+	if !errors.As(err, &customError) {
+		return NewAPIError(http.StatusInternalServerError, CodeInternal)
+	}
+
+	p, ok := errorToProblem[customError.ClientErr()]
+	if !ok || p.status == http.StatusInternalServerError {
+		return NewAPIError(http.StatusInternalServerError, CodeInternal)
 	}
 
-	apiError.Message = "internal server error"
-	apiError.Status = http.StatusInternalServerError
-	return apiError
+	var validationErr *entity.AdvValidationError
+	if errors.As(customError.InternalErr(), &validationErr) && len(validationErr.Fields) > 0 {
+		apiError := NewAPIError(p.status, CodeValidationFailed)
+		apiError.fields = validationErr.Fields
+		return apiError.Localize(i18n.Default)
+	}
+
+	apiError := NewAPIError(p.status, p.code)
+	var message entity.LocalizedMessage
+	if errors.As(customError.InternalErr(), &message) {
+		apiError.message = message
+	}
+	return apiError.Localize(i18n.Default)
 }
 
-func WriteError(w http.ResponseWriter, status int, err error) {
-	WriteAPIError(w, APIError{
-		Status:  status,
-		Message: err.Error(),
-	})
+// WriteError отвечает ошибкой с явно заданным статусом. Код берется из известных
+// ошибок entity, для остальных — из статуса.
+func WriteError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	code := CodeInternal
+	if p, ok := errorToProblem[err]; ok {
+		code = p.code
+	} else if status < http.StatusInternalServerError {
+		code = CodeBadRequest
+	}
+
+	WriteAPIError(w, r, NewAPIError(status, code))
 }
 
-func WriteAPIError(w http.ResponseWriter, apiError APIError) {
-	w.Header().Set("Content-Type", "application/json")
+// WriteAPIError дополняет ответ адресом запроса и его ID, переводит его на язык
+// запроса и пишет клиенту.
+func WriteAPIError(w http.ResponseWriter, r *http.Request, apiError APIError) {
+	lang := i18n.Default
+	if r != nil {
+		apiError.Instance = r.URL.Path
+		apiError.RequestID = GlobalUtils.GetRequestID(r.Context())
+		lang = RequestLanguage(r)
+	}
+	apiError = apiError.Localize(lang)
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("Content-Language", string(lang))
 	w.WriteHeader(apiError.Status)
 	err := json.NewEncoder(w).Encode(apiError)
 	if err != nil {
 		return
 	}
 }
+
+// RequestLanguage возвращает язык, выбранный LanguageMiddleware. Ответы middleware,
+// которые срабатывают раньше него, опираются только на Accept-Language.
+func RequestLanguage(r *http.Request) i18n.Lang {
+	if lang, ok := GlobalUtils.GetLanguage(r.Context()); ok {
+		return lang
+	}
+	return i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+}
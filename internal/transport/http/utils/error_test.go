@@ -1,13 +1,16 @@
 package utils
 
 import (
-	"ResuMatch/internal/entity"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	GlobalUtils "github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/i18n"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,55 +21,110 @@ func TestToAPIError(t *testing.T) {
 		name           string
 		inputError     error
 		expectedStatus int
-		expectedMsg    string
+		expectedCode   string
+		expectedDetail string
+		expectedFields map[string]string
 	}{
 		{
 			name:           "Custom error - Not Found",
 			inputError:     entity.NewError(entity.ErrNotFound, errors.New("not found")),
 			expectedStatus: http.StatusNotFound,
-			expectedMsg:    "not found",
+			expectedCode:   CodeNotFound,
+			expectedDetail: "Не найдено",
 		},
 		{
 			name:           "Custom error - Bad Request",
 			inputError:     entity.NewError(entity.ErrBadRequest, errors.New("bad request")),
 			expectedStatus: http.StatusBadRequest,
-			expectedMsg:    "bad request",
+			expectedCode:   CodeBadRequest,
+			expectedDetail: "Неверный запрос",
 		},
 		{
 			name:           "Custom error - Unauthorized",
 			inputError:     entity.NewError(entity.ErrUnauthorized, errors.New("unauthorized")),
 			expectedStatus: http.StatusUnauthorized,
-			expectedMsg:    "unauthorized",
+			expectedCode:   CodeUnauthorized,
+			expectedDetail: "Требуется авторизация",
 		},
 		{
 			name:           "Custom error - Forbidden",
 			inputError:     entity.NewError(entity.ErrForbidden, errors.New("forbidden")),
 			expectedStatus: http.StatusForbidden,
-			expectedMsg:    "forbidden",
+			expectedCode:   CodeForbidden,
+			expectedDetail: "Доступ запрещен",
 		},
 		{
-			name:           "Custom error - Conflict",
+			name:           "Custom error - Already exists",
 			inputError:     entity.NewError(entity.ErrAlreadyExists, errors.New("conflict")),
 			expectedStatus: http.StatusConflict,
-			expectedMsg:    "conflict",
+			expectedCode:   CodeAlreadyExists,
+			expectedDetail: "Такая запись уже существует",
+		},
+		{
+			name:           "Custom error - Conflict",
+			inputError:     entity.NewError(entity.ErrConflict, errors.New("conflict")),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   CodeConflict,
+			expectedDetail: "Действие невозможно в текущем состоянии",
 		},
 		{
 			name:           "Custom error - Internal",
-			inputError:     entity.NewError(entity.ErrInternal, errors.New("internal error")),
+			inputError:     entity.NewError(entity.ErrInternal, errors.New("db is down")),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   CodeInternal,
+			expectedDetail: "Внутренняя ошибка сервера",
+		},
+		{
+			name: "Validation error",
+			inputError: entity.NewError(entity.ErrBadRequest,
+				fmt.Errorf("ошибка валидации: %w", &entity.AdvValidationError{Fields: entity.FieldErrors{
+					"title": entity.NewLocalizedMessage(entity.MsgLengthBetween, entity.MessageParams{"min": 3, "max": 50}),
+					"price": entity.NewLocalizedMessage(entity.MsgPositive, nil),
+				}})),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeValidationFailed,
+			expectedDetail: "Проверьте правильность заполнения полей",
+			expectedFields: map[string]string{
+				"title": "Длина должна быть от 3 до 50",
+				"price": "Значение должно быть больше 0",
+			},
+		},
+		{
+			name: "Message for client",
+			inputError: entity.NewError(entity.ErrConflict,
+				entity.NewLocalizedMessage(entity.MsgOfferClosed, entity.MessageParams{"status": "accepted"})),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   CodeConflict,
+			expectedDetail: "Предложение уже закрыто со статусом accepted",
+		},
+		{
+			name: "Message inside internal error is hidden",
+			inputError: entity.NewError(entity.ErrInternal,
+				entity.NewLocalizedMessage(entity.MsgOfferClosed, entity.MessageParams{"status": "accepted"})),
 			expectedStatus: http.StatusInternalServerError,
-			expectedMsg:    "internal error",
+			expectedCode:   CodeInternal,
+			expectedDetail: "Внутренняя ошибка сервера",
+		},
+		{
+			name:           "Wrapped custom error",
+			inputError:     fmt.Errorf("обработчик: %w", entity.NewError(entity.ErrNotFound, errors.New("нет строки"))),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   CodeNotFound,
+			expectedDetail: "Не найдено",
 		},
 		{
 			name:           "Standard error",
 			inputError:     errors.New("some error"),
 			expectedStatus: http.StatusInternalServerError,
-			expectedMsg:    "internal server error",
+			expectedCode:   CodeInternal,
+			expectedDetail: "Внутренняя ошибка сервера",
 		},
 		{
 			name:           "Custom error with unregistered client error",
 			inputError:     entity.NewError(errors.New("unknown"), errors.New("some error")),
 			expectedStatus: http.StatusInternalServerError,
-			expectedMsg:    "some error",
+			expectedCode:   CodeInternal,
+			expectedDetail: "Внутренняя ошибка сервера",
 		},
 	}
 
@@ -77,8 +135,12 @@ func TestToAPIError(t *testing.T) {
 
 			result := ToAPIError(tc.inputError)
 
+			require.Equal(t, "about:blank", result.Type)
+			require.Equal(t, http.StatusText(tc.expectedStatus), result.Title)
 			require.Equal(t, tc.expectedStatus, result.Status)
-			require.Equal(t, tc.expectedMsg, result.Message)
+			require.Equal(t, tc.expectedCode, result.Code)
+			require.Equal(t, tc.expectedDetail, result.Detail)
+			require.Equal(t, tc.expectedFields, result.Errors)
 		})
 	}
 }
@@ -91,17 +153,32 @@ func TestWriteError(t *testing.T) {
 		status         int
 		err            error
 		expectedStatus int
-		expectedBody   APIError
+		expectedCode   string
+		expectedDetail string
 	}{
 		{
-			name:           "Standard error",
+			name:           "Known error",
+			status:         http.StatusBadRequest,
+			err:            entity.ErrBadRequest,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeBadRequest,
+			expectedDetail: "Неверный запрос",
+		},
+		{
+			name:           "Too many requests",
+			status:         http.StatusTooManyRequests,
+			err:            entity.ErrTooManyRequests,
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCode:   CodeTooManyRequests,
+			expectedDetail: "Слишком много запросов, попробуйте позже",
+		},
+		{
+			name:           "Unknown client error",
 			status:         http.StatusBadRequest,
 			err:            errors.New("test error"),
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: APIError{
-				Status:  http.StatusBadRequest,
-				Message: "test error",
-			},
+			expectedCode:   CodeBadRequest,
+			expectedDetail: "Неверный запрос",
 		},
 	}
 
@@ -111,15 +188,25 @@ func TestWriteError(t *testing.T) {
 			t.Parallel()
 
 			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/ad/create", nil)
+			r = r.WithContext(GlobalUtils.SetRequestID(r.Context(), "test-request-id"))
 
-			WriteError(w, tc.status, tc.err)
+			WriteError(w, r, tc.status, tc.err)
 
 			require.Equal(t, tc.expectedStatus, w.Code)
 
 			var result APIError
 			err := json.NewDecoder(w.Body).Decode(&result)
 			require.NoError(t, err)
-			require.Equal(t, tc.expectedBody, result)
+			require.Equal(t, APIError{
+				Type:      "about:blank",
+				Title:     http.StatusText(tc.expectedStatus),
+				Status:    tc.expectedStatus,
+				Detail:    tc.expectedDetail,
+				Instance:  "/ad/create",
+				Code:      tc.expectedCode,
+				RequestID: "test-request-id",
+			}, result)
 		})
 	}
 }
@@ -130,31 +217,57 @@ func TestWriteAPIError(t *testing.T) {
 	testCases := []struct {
 		name           string
 		inputError     APIError
+		acceptLanguage string
+		language       i18n.Lang
 		expectedStatus int
+		expectedLang   string
 		expectedBody   APIError
 	}{
 		{
-			name: "Standard API error",
-			inputError: APIError{
-				Status:  http.StatusNotFound,
-				Message: "Not found",
-			},
+			name:           "Standard API error",
+			inputError:     NewAPIError(http.StatusNotFound, CodeNotFound),
 			expectedStatus: http.StatusNotFound,
+			expectedLang:   "ru",
 			expectedBody: APIError{
-				Status:  http.StatusNotFound,
-				Message: "Not found",
+				Type:     "about:blank",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "Не найдено",
+				Instance: "/ad/1",
+				Code:     CodeNotFound,
 			},
 		},
 		{
-			name: "Internal server error",
-			inputError: APIError{
-				Status:  http.StatusInternalServerError,
-				Message: "Internal error",
+			name: "Validation error in English",
+			inputError: ToAPIError(entity.NewError(entity.ErrBadRequest,
+				&entity.AdvValidationError{Fields: entity.FieldErrors{"title": entity.NewLocalizedMessage(entity.MsgRequired, nil)}})),
+			acceptLanguage: "en-US,en;q=0.9,ru;q=0.5",
+			expectedStatus: http.StatusBadRequest,
+			expectedLang:   "en",
+			expectedBody: APIError{
+				Type:     "about:blank",
+				Title:    "Bad Request",
+				Status:   http.StatusBadRequest,
+				Detail:   "Some fields are invalid",
+				Instance: "/ad/1",
+				Code:     CodeValidationFailed,
+				Errors:   map[string]string{"title": "This field is required"},
 			},
-			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Language from context wins over header",
+			inputError:     NewAPIError(http.StatusForbidden, CodeForbidden),
+			acceptLanguage: "en",
+			language:       i18n.Russian,
+			expectedStatus: http.StatusForbidden,
+			expectedLang:   "ru",
 			expectedBody: APIError{
-				Status:  http.StatusInternalServerError,
-				Message: "Internal error",
+				Type:     "about:blank",
+				Title:    "Forbidden",
+				Status:   http.StatusForbidden,
+				Detail:   "Доступ запрещен",
+				Instance: "/ad/1",
+				Code:     CodeForbidden,
 			},
 		},
 	}
@@ -165,11 +278,19 @@ func TestWriteAPIError(t *testing.T) {
 			t.Parallel()
 
 			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/ad/1", nil)
+			if tc.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tc.acceptLanguage)
+			}
+			if tc.language != "" {
+				r = r.WithContext(GlobalUtils.SetLanguage(r.Context(), tc.language))
+			}
 
-			WriteAPIError(w, tc.inputError)
+			WriteAPIError(w, r, tc.inputError)
 
 			require.Equal(t, tc.expectedStatus, w.Code)
-			require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			require.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+			require.Equal(t, tc.expectedLang, w.Header().Get("Content-Language"))
 
 			var result APIError
 			err := json.NewDecoder(w.Body).Decode(&result)
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+// ETag возвращает сильный ETag ресурса: версию и хеш его представления, "3-1f2e3d4c5b6a7980".
+// В ответе есть поля, которые меняются без смены версии (рейтинг автора, продвижение,
+// статус модерации), поэтому для If-None-Match версии недостаточно. If-Match сверяет только версию.
+func ETag(version int, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + strconv.Itoa(version) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// IfMatchVersion достает версию ресурса из заголовка If-Match, хеш представления не учитывается.
+// Без заголовка изменение запрещено, If-Match: * подходит к любой версии и дает 0. Сравнение сильное: слабый ETag,
+// список или чужое значение не совпадут ни с одной версией и сразу дают 412.
+func IfMatchVersion(r *http.Request) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return 0, entity.NewError(entity.ErrPreconditionRequired,
+			errors.New("не передан заголовок If-Match"))
+	}
+	if header == "*" {
+		return 0, nil
+	}
+
+	unquoted, ok := strings.CutPrefix(header, `"`)
+	if ok {
+		unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	}
+	unquoted, _, _ = strings.Cut(unquoted, "-")
+	version, err := strconv.Atoi(unquoted)
+	if !ok || err != nil || version <= 0 {
+		return 0, entity.NewError(entity.ErrPreconditionFailed,
+			errors.New("If-Match не совпадает ни с одной версией: "+header))
+	}
+	return version, nil
+}
+
+// NotModified сообщает, что у клиента актуальная версия ресурса: If-None-Match равен *
+// или содержит etag. Сравнение слабое, префикс W/ не учитывается.
+func NotModified(r *http.Request, etag string) bool {
+	header := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
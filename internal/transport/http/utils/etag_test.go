@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		header          string
+		expectedVersion int
+		expectedErr     error
+	}{
+		{name: "Missing header", header: "", expectedErr: entity.ErrPreconditionRequired},
+		{name: "Strong ETag", header: `"3"`, expectedVersion: 3},
+		{name: "ETag with hash", header: `"3-1f2e3d4c5b6a7980"`, expectedVersion: 3},
+		{name: "Any version", header: "*", expectedVersion: 0},
+		{name: "Weak ETag", header: `W/"3"`, expectedErr: entity.ErrPreconditionFailed},
+		{name: "Unquoted", header: "3", expectedErr: entity.ErrPreconditionFailed},
+		{name: "List", header: `"3", "4"`, expectedErr: entity.ErrPreconditionFailed},
+		{name: "Not a version", header: `"abc"`, expectedErr: entity.ErrPreconditionFailed},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPut, "/ad/1", nil)
+			if tc.header != "" {
+				r.Header.Set("If-Match", tc.header)
+			}
+
+			version, err := IfMatchVersion(r)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedVersion, version)
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"id":1,"author_rating":4}`)
+	etag := ETag(3, body)
+
+	testCases := []struct {
+		name     string
+		header   string
+		expected bool
+	}{
+		{name: "No header", header: "", expected: false},
+		{name: "Same ETag", header: etag, expected: true},
+		{name: "Weak ETag", header: "W/" + etag, expected: true},
+		{name: "List", header: `"1", ` + etag, expected: true},
+		{name: "Any", header: "*", expected: true},
+		{name: "Other version", header: ETag(2, body), expected: false},
+		{name: "Same version, other body", header: ETag(3, []byte(`{"id":1,"author_rating":4.5}`)), expected: false},
+		{name: "Version only", header: `"3"`, expected: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/ad/1", nil)
+			if tc.header != "" {
+				r.Header.Set("If-None-Match", tc.header)
+			}
+
+			require.Equal(t, tc.expected, NotModified(r, etag))
+		})
+	}
+}
+
+func TestToAPIError_Precondition(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodPut, "/ad/1", nil)
+	_, err := IfMatchVersion(r)
+	apiError := ToAPIError(err)
+	require.Equal(t, http.StatusPreconditionRequired, apiError.Status)
+	require.Equal(t, CodePreconditionRequired, apiError.Code)
+
+	apiError = ToAPIError((&entity.Advertisement{ID: 1, Version: 2}).CheckVersion(1))
+	require.Equal(t, http.StatusPreconditionFailed, apiError.Status)
+	require.Equal(t, CodePreconditionFailed, apiError.Code)
+	require.Equal(t, "Объявление изменилось после загрузки, обновите его и повторите попытку", apiError.Detail)
+}
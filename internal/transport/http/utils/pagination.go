@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+// ParsePagination читает параметры limit и offset из строки запроса.
+func ParsePagination(r *http.Request, defaultLimit, maxLimit int) (offset, limit int, err error) {
+	limit = defaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l <= 0 || l > maxLimit {
+			return 0, 0, entity.NewError(
+				entity.ErrBadRequest,
+				fmt.Errorf("limit должен быть от 1 до %d", maxLimit),
+			)
+		}
+		limit = l
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		o, err := strconv.Atoi(v)
+		if err != nil || o < 0 {
+			return 0, 0, entity.NewError(
+				entity.ErrBadRequest,
+				fmt.Errorf("offset не может быть отрицательным"),
+			)
+		}
+		offset = o
+	}
+
+	return offset, limit, nil
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
 )
 
+// APIKeyHeader — заголовок, в котором скрипты передают персональный API-ключ.
+const APIKeyHeader = "X-API-Key"
+
 func ClearTokenCookies(w http.ResponseWriter) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     "session_id",
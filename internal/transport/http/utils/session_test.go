@@ -1,15 +1,14 @@
 package utils
 
 import (
-	// "ResuMatch/internal/usecase"
-	"ResuMatch/internal/entity"
-	"ResuMatch/internal/usecase/mock"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -51,17 +50,18 @@ func TestCreateSession_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	authMock := mock.NewMockAuth(ctrl)
+	authMock := mock.NewMockAuthUsecase(ctrl)
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 
 	// Ожидаем вызов CreateSession с любыми аргументами
 	authMock.EXPECT().
-		CreateSession(gomock.Any(), 123, "applicant").
+		CreateSession(gomock.Any(), 123).
 		Return("session-token", nil)
 
-	err := CreateSession(w, r, authMock, 123, "applicant")
+	session, err := CreateSession(w, r, authMock, 123)
 	require.NoError(t, err)
+	require.Equal(t, "session-token", session)
 
 	// Проверяем, что cookie установлен правильно
 	cookies := w.Result().Cookies()
@@ -82,21 +82,21 @@ func TestCreateSession_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	authMock := mock.NewMockAuth(ctrl)
+	authMock := mock.NewMockAuthUsecase(ctrl)
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 
 	// Ожидаем вызов CreateSession, который вернет ошибку
 	expectedErr := entity.NewError(
 		entity.ErrInternal,
-		fmt.Errorf("не удалось получить сессию для пользователя с id=123, role=applicant"),
+		fmt.Errorf("не удалось получить сессию для пользователя с id=123"),
 	)
 
 	authMock.EXPECT().
-		CreateSession(gomock.Any(), 123, "applicant").
+		CreateSession(gomock.Any(), 123).
 		Return("", expectedErr)
 
-	err := CreateSession(w, r, authMock, 123, "applicant")
+	_, err := CreateSession(w, r, authMock, 123)
 	require.Error(t, err)
 	require.Equal(t, expectedErr, err)
 }
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+)
+
+type AccountUsecase interface {
+	Export(ctx context.Context, userID int, sessionID string) (*dto.AccountExport, error)
+	ScheduleDeletion(ctx context.Context, userID int, req *dto.DeleteAccountRequest) (*dto.DeleteAccountResponse, error)
+	PurgeDeleted(ctx context.Context) (int64, error)
+}
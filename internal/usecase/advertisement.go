@@ -8,7 +8,16 @@ import (
 
 type AdvertisementUsecase interface {
 	Create(ctx context.Context, userID int, req *dto.CreateAdvertisementRequest) (*dto.AdvertisementShort, error)
-	GetByID(ctx context.Context, id int) (*dto.AdvertisementShort, error)
+	GetByID(ctx context.Context, viewerID, id int) (*dto.AdvertisementShort, error)
 	GetAll(ctx context.Context, userID int, page, limit int, sortBy, order string, minPrice, maxPrice *float64) ([]dto.AdvertisementResponse, error)
 	GetByUserID(ctx context.Context, userID int) ([]dto.AdvertisementResponse, error)
+	GetSimilar(ctx context.Context, viewerID, id int) ([]dto.AdvertisementResponse, error)
+	// Update и Delete принимают версию из If-Match; 0 означает If-Match: *.
+	Update(ctx context.Context, userID, id, version int, req *dto.UpdateAdvertisementRequest) (*dto.AdvertisementShort, error)
+	Delete(ctx context.Context, userID, id, version int) error
+	Renew(ctx context.Context, userID, id int) (*dto.AdvertisementShort, error)
+	// ArchiveExpired переводит в архив объявления с истекшим сроком и возвращает их число.
+	ArchiveExpired(ctx context.Context) (int, error)
+	// NotifyExpiring предупреждает владельцев о скором истечении и возвращает число объявлений.
+	NotifyExpiring(ctx context.Context) (int, error)
 }
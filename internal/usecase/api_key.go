@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+)
+
+type APIKeyUsecase interface {
+	Create(ctx context.Context, userID int, req *dto.CreateAPIKeyRequest) (*dto.APIKeyCreatedResponse, error)
+	GetByUserID(ctx context.Context, userID int) ([]dto.APIKeyResponse, error)
+	Update(ctx context.Context, userID, id int, req *dto.UpdateAPIKeyRequest) (*dto.APIKeyResponse, error)
+	Delete(ctx context.Context, userID, id int) error
+}
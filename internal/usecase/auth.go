@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
 )
 
@@ -10,6 +11,7 @@ type AuthUsecase interface {
 	Logout(context.Context, string) error
 	LogoutAll(context.Context, int) error
 	GetUserIDBySession(context.Context, string) (int, error)
+	GetPrincipalByAPIKey(ctx context.Context, key string) (*entity.Principal, error)
 	CreateSession(context.Context, int) (string, error)
-	EmailExists(context.Context, string) (*dto.LoginExistsResponse, error)
+	EmailExists(context.Context, string) (*dto.EmailExistsResponse, error)
 }
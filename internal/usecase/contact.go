@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type ContactUsecase interface {
+	SetContact(ctx context.Context, userID int, channel entity.ContactChannel, value string) error
+	VerifyContact(ctx context.Context, userID int, channel entity.ContactChannel, code string) error
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+)
+
+type ConversationUsecase interface {
+	Start(ctx context.Context, buyerID, adID int, req *dto.StartConversationRequest) (*dto.ConversationResponse, error)
+	GetByUserID(ctx context.Context, userID, offset, limit int) ([]dto.ConversationResponse, error)
+	CountUnread(ctx context.Context, userID int) (*dto.UnreadCountResponse, error)
+	GetMessages(ctx context.Context, userID, conversationID int, before int64, limit int) (*dto.MessagesPage, error)
+	SendMessage(ctx context.Context, userID, conversationID int, req *dto.SendMessageRequest) (*dto.MessageResponse, error)
+	MarkRead(ctx context.Context, userID, conversationID int, req *dto.MarkReadRequest) error
+	Block(ctx context.Context, userID, conversationID int) error
+	Unblock(ctx context.Context, userID, conversationID int) error
+}
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+)
+
+type EventUsecase interface {
+	// Subscribe регистрирует поток пользователя на этом экземпляре. Канал закрывается,
+	// если клиент не успевает читать события или сервер останавливается.
+	// Возвращенную функцию нужно вызвать при отключении клиента.
+	Subscribe(userID int) (<-chan *entity.Event, func(), error)
+	// Run получает события из общей шины и раздает их локальным потокам, пока не отменен ctx.
+	Run(ctx context.Context)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase (interfaces: AccountUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/usecase/mock/mock_account.go github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase AccountUsecase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAccountUsecase is a mock of AccountUsecase interface.
+type MockAccountUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountUsecaseMockRecorder is the mock recorder for MockAccountUsecase.
+type MockAccountUsecaseMockRecorder struct {
+	mock *MockAccountUsecase
+}
+
+// NewMockAccountUsecase creates a new mock instance.
+func NewMockAccountUsecase(ctrl *gomock.Controller) *MockAccountUsecase {
+	mock := &MockAccountUsecase{ctrl: ctrl}
+	mock.recorder = &MockAccountUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountUsecase) EXPECT() *MockAccountUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockAccountUsecase) Export(ctx context.Context, userID int, sessionID string) (*dto.AccountExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, userID, sessionID)
+	ret0, _ := ret[0].(*dto.AccountExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockAccountUsecaseMockRecorder) Export(ctx, userID, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockAccountUsecase)(nil).Export), ctx, userID, sessionID)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockAccountUsecase) PurgeDeleted(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockAccountUsecaseMockRecorder) PurgeDeleted(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockAccountUsecase)(nil).PurgeDeleted), ctx)
+}
+
+// ScheduleDeletion mocks base method.
+func (m *MockAccountUsecase) ScheduleDeletion(ctx context.Context, userID int, req *dto.DeleteAccountRequest) (*dto.DeleteAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleDeletion", ctx, userID, req)
+	ret0, _ := ret[0].(*dto.DeleteAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleDeletion indicates an expected call of ScheduleDeletion.
+func (mr *MockAccountUsecaseMockRecorder) ScheduleDeletion(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleDeletion", reflect.TypeOf((*MockAccountUsecase)(nil).ScheduleDeletion), ctx, userID, req)
+}
@@ -41,6 +41,21 @@ func (m *MockAdvertisementUsecase) EXPECT() *MockAdvertisementUsecaseMockRecorde
 	return m.recorder
 }
 
+// ArchiveExpired mocks base method.
+func (m *MockAdvertisementUsecase) ArchiveExpired(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveExpired", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveExpired indicates an expected call of ArchiveExpired.
+func (mr *MockAdvertisementUsecaseMockRecorder) ArchiveExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveExpired", reflect.TypeOf((*MockAdvertisementUsecase)(nil).ArchiveExpired), ctx)
+}
+
 // Create mocks base method.
 func (m *MockAdvertisementUsecase) Create(ctx context.Context, userID int, req *dto.CreateAdvertisementRequest) (*dto.AdvertisementShort, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +71,20 @@ func (mr *MockAdvertisementUsecaseMockRecorder) Create(ctx, userID, req any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAdvertisementUsecase)(nil).Create), ctx, userID, req)
 }
 
+// Delete mocks base method.
+func (m *MockAdvertisementUsecase) Delete(ctx context.Context, userID, id, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAdvertisementUsecaseMockRecorder) Delete(ctx, userID, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAdvertisementUsecase)(nil).Delete), ctx, userID, id, version)
+}
+
 // GetAll mocks base method.
 func (m *MockAdvertisementUsecase) GetAll(ctx context.Context, userID, page, limit int, sortBy, order string, minPrice, maxPrice *float64) ([]dto.AdvertisementResponse, error) {
 	m.ctrl.T.Helper()
@@ -72,18 +101,18 @@ func (mr *MockAdvertisementUsecaseMockRecorder) GetAll(ctx, userID, page, limit,
 }
 
 // GetByID mocks base method.
-func (m *MockAdvertisementUsecase) GetByID(ctx context.Context, id int) (*dto.AdvertisementShort, error) {
+func (m *MockAdvertisementUsecase) GetByID(ctx context.Context, viewerID, id int) (*dto.AdvertisementShort, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, viewerID, id)
 	ret0, _ := ret[0].(*dto.AdvertisementShort)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockAdvertisementUsecaseMockRecorder) GetByID(ctx, id any) *gomock.Call {
+func (mr *MockAdvertisementUsecaseMockRecorder) GetByID(ctx, viewerID, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAdvertisementUsecase)(nil).GetByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAdvertisementUsecase)(nil).GetByID), ctx, viewerID, id)
 }
 
 // GetByUserID mocks base method.
@@ -100,3 +129,63 @@ func (mr *MockAdvertisementUsecaseMockRecorder) GetByUserID(ctx, userID any) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockAdvertisementUsecase)(nil).GetByUserID), ctx, userID)
 }
+
+// GetSimilar mocks base method.
+func (m *MockAdvertisementUsecase) GetSimilar(ctx context.Context, viewerID, id int) ([]dto.AdvertisementResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSimilar", ctx, viewerID, id)
+	ret0, _ := ret[0].([]dto.AdvertisementResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSimilar indicates an expected call of GetSimilar.
+func (mr *MockAdvertisementUsecaseMockRecorder) GetSimilar(ctx, viewerID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimilar", reflect.TypeOf((*MockAdvertisementUsecase)(nil).GetSimilar), ctx, viewerID, id)
+}
+
+// NotifyExpiring mocks base method.
+func (m *MockAdvertisementUsecase) NotifyExpiring(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyExpiring", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NotifyExpiring indicates an expected call of NotifyExpiring.
+func (mr *MockAdvertisementUsecaseMockRecorder) NotifyExpiring(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyExpiring", reflect.TypeOf((*MockAdvertisementUsecase)(nil).NotifyExpiring), ctx)
+}
+
+// Renew mocks base method.
+func (m *MockAdvertisementUsecase) Renew(ctx context.Context, userID, id int) (*dto.AdvertisementShort, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, userID, id)
+	ret0, _ := ret[0].(*dto.AdvertisementShort)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockAdvertisementUsecaseMockRecorder) Renew(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockAdvertisementUsecase)(nil).Renew), ctx, userID, id)
+}
+
+// Update mocks base method.
+func (m *MockAdvertisementUsecase) Update(ctx context.Context, userID, id, version int, req *dto.UpdateAdvertisementRequest) (*dto.AdvertisementShort, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userID, id, version, req)
+	ret0, _ := ret[0].(*dto.AdvertisementShort)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAdvertisementUsecaseMockRecorder) Update(ctx, userID, id, version, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAdvertisementUsecase)(nil).Update), ctx, userID, id, version, req)
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	entity "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	dto "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -57,10 +58,10 @@ func (mr *MockAuthUsecaseMockRecorder) CreateSession(arg0, arg1 any) *gomock.Cal
 }
 
 // EmailExists mocks base method.
-func (m *MockAuthUsecase) EmailExists(arg0 context.Context, arg1 string) (*dto.LoginExistsResponse, error) {
+func (m *MockAuthUsecase) EmailExists(arg0 context.Context, arg1 string) (*dto.EmailExistsResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "EmailExists", arg0, arg1)
-	ret0, _ := ret[0].(*dto.LoginExistsResponse)
+	ret0, _ := ret[0].(*dto.EmailExistsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -71,6 +72,21 @@ func (mr *MockAuthUsecaseMockRecorder) EmailExists(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailExists", reflect.TypeOf((*MockAuthUsecase)(nil).EmailExists), arg0, arg1)
 }
 
+// GetPrincipalByAPIKey mocks base method.
+func (m *MockAuthUsecase) GetPrincipalByAPIKey(ctx context.Context, key string) (*entity.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrincipalByAPIKey", ctx, key)
+	ret0, _ := ret[0].(*entity.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrincipalByAPIKey indicates an expected call of GetPrincipalByAPIKey.
+func (mr *MockAuthUsecaseMockRecorder) GetPrincipalByAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrincipalByAPIKey", reflect.TypeOf((*MockAuthUsecase)(nil).GetPrincipalByAPIKey), ctx, key)
+}
+
 // GetUserIDBySession mocks base method.
 func (m *MockAuthUsecase) GetUserIDBySession(arg0 context.Context, arg1 string) (int, error) {
 	m.ctrl.T.Helper()
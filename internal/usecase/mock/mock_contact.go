@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase (interfaces: ContactUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/usecase/mock/mock_contact.go github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase ContactUsecase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockContactUsecase is a mock of ContactUsecase interface.
+type MockContactUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockContactUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockContactUsecaseMockRecorder is the mock recorder for MockContactUsecase.
+type MockContactUsecaseMockRecorder struct {
+	mock *MockContactUsecase
+}
+
+// NewMockContactUsecase creates a new mock instance.
+func NewMockContactUsecase(ctrl *gomock.Controller) *MockContactUsecase {
+	mock := &MockContactUsecase{ctrl: ctrl}
+	mock.recorder = &MockContactUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContactUsecase) EXPECT() *MockContactUsecaseMockRecorder {
+	return m.recorder
+}
+
+// SetContact mocks base method.
+func (m *MockContactUsecase) SetContact(ctx context.Context, userID int, channel entity.ContactChannel, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetContact", ctx, userID, channel, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetContact indicates an expected call of SetContact.
+func (mr *MockContactUsecaseMockRecorder) SetContact(ctx, userID, channel, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContact", reflect.TypeOf((*MockContactUsecase)(nil).SetContact), ctx, userID, channel, value)
+}
+
+// VerifyContact mocks base method.
+func (m *MockContactUsecase) VerifyContact(ctx context.Context, userID int, channel entity.ContactChannel, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyContact", ctx, userID, channel, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyContact indicates an expected call of VerifyContact.
+func (mr *MockContactUsecaseMockRecorder) VerifyContact(ctx, userID, channel, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyContact", reflect.TypeOf((*MockContactUsecase)(nil).VerifyContact), ctx, userID, channel, code)
+}
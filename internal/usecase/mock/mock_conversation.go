@@ -0,0 +1,159 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase (interfaces: ConversationUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/usecase/mock/mock_conversation.go github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase ConversationUsecase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConversationUsecase is a mock of ConversationUsecase interface.
+type MockConversationUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockConversationUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockConversationUsecaseMockRecorder is the mock recorder for MockConversationUsecase.
+type MockConversationUsecaseMockRecorder struct {
+	mock *MockConversationUsecase
+}
+
+// NewMockConversationUsecase creates a new mock instance.
+func NewMockConversationUsecase(ctrl *gomock.Controller) *MockConversationUsecase {
+	mock := &MockConversationUsecase{ctrl: ctrl}
+	mock.recorder = &MockConversationUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConversationUsecase) EXPECT() *MockConversationUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Block mocks base method.
+func (m *MockConversationUsecase) Block(ctx context.Context, userID, conversationID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Block", ctx, userID, conversationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Block indicates an expected call of Block.
+func (mr *MockConversationUsecaseMockRecorder) Block(ctx, userID, conversationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Block", reflect.TypeOf((*MockConversationUsecase)(nil).Block), ctx, userID, conversationID)
+}
+
+// CountUnread mocks base method.
+func (m *MockConversationUsecase) CountUnread(ctx context.Context, userID int) (*dto.UnreadCountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnread", ctx, userID)
+	ret0, _ := ret[0].(*dto.UnreadCountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnread indicates an expected call of CountUnread.
+func (mr *MockConversationUsecaseMockRecorder) CountUnread(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnread", reflect.TypeOf((*MockConversationUsecase)(nil).CountUnread), ctx, userID)
+}
+
+// GetByUserID mocks base method.
+func (m *MockConversationUsecase) GetByUserID(ctx context.Context, userID, offset, limit int) ([]dto.ConversationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID, offset, limit)
+	ret0, _ := ret[0].([]dto.ConversationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockConversationUsecaseMockRecorder) GetByUserID(ctx, userID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockConversationUsecase)(nil).GetByUserID), ctx, userID, offset, limit)
+}
+
+// GetMessages mocks base method.
+func (m *MockConversationUsecase) GetMessages(ctx context.Context, userID, conversationID int, before int64, limit int) (*dto.MessagesPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessages", ctx, userID, conversationID, before, limit)
+	ret0, _ := ret[0].(*dto.MessagesPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessages indicates an expected call of GetMessages.
+func (mr *MockConversationUsecaseMockRecorder) GetMessages(ctx, userID, conversationID, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockConversationUsecase)(nil).GetMessages), ctx, userID, conversationID, before, limit)
+}
+
+// MarkRead mocks base method.
+func (m *MockConversationUsecase) MarkRead(ctx context.Context, userID, conversationID int, req *dto.MarkReadRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, userID, conversationID, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockConversationUsecaseMockRecorder) MarkRead(ctx, userID, conversationID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockConversationUsecase)(nil).MarkRead), ctx, userID, conversationID, req)
+}
+
+// SendMessage mocks base method.
+func (m *MockConversationUsecase) SendMessage(ctx context.Context, userID, conversationID int, req *dto.SendMessageRequest) (*dto.MessageResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", ctx, userID, conversationID, req)
+	ret0, _ := ret[0].(*dto.MessageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockConversationUsecaseMockRecorder) SendMessage(ctx, userID, conversationID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockConversationUsecase)(nil).SendMessage), ctx, userID, conversationID, req)
+}
+
+// Start mocks base method.
+func (m *MockConversationUsecase) Start(ctx context.Context, buyerID, adID int, req *dto.StartConversationRequest) (*dto.ConversationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, buyerID, adID, req)
+	ret0, _ := ret[0].(*dto.ConversationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockConversationUsecaseMockRecorder) Start(ctx, buyerID, adID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockConversationUsecase)(nil).Start), ctx, buyerID, adID, req)
+}
+
+// Unblock mocks base method.
+func (m *MockConversationUsecase) Unblock(ctx context.Context, userID, conversationID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unblock", ctx, userID, conversationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unblock indicates an expected call of Unblock.
+func (mr *MockConversationUsecaseMockRecorder) Unblock(ctx, userID, conversationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unblock", reflect.TypeOf((*MockConversationUsecase)(nil).Unblock), ctx, userID, conversationID)
+}
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase (interfaces: EventUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/usecase/mock/mock_event.go github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase EventUsecase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEventUsecase is a mock of EventUsecase interface.
+type MockEventUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockEventUsecaseMockRecorder is the mock recorder for MockEventUsecase.
+type MockEventUsecaseMockRecorder struct {
+	mock *MockEventUsecase
+}
+
+// NewMockEventUsecase creates a new mock instance.
+func NewMockEventUsecase(ctrl *gomock.Controller) *MockEventUsecase {
+	mock := &MockEventUsecase{ctrl: ctrl}
+	mock.recorder = &MockEventUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventUsecase) EXPECT() *MockEventUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Run mocks base method.
+func (m *MockEventUsecase) Run(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockEventUsecaseMockRecorder) Run(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockEventUsecase)(nil).Run), ctx)
+}
+
+// Subscribe mocks base method.
+func (m *MockEventUsecase) Subscribe(userID int) (<-chan *entity.Event, func(), error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", userID)
+	ret0, _ := ret[0].(<-chan *entity.Event)
+	ret1, _ := ret[1].(func())
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventUsecaseMockRecorder) Subscribe(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEventUsecase)(nil).Subscribe), userID)
+}
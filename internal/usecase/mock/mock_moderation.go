@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase (interfaces: ModerationUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/usecase/mock/mock_moderation.go github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase ModerationUsecase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockModerationUsecase is a mock of ModerationUsecase interface.
+type MockModerationUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockModerationUsecaseMockRecorder is the mock recorder for MockModerationUsecase.
+type MockModerationUsecaseMockRecorder struct {
+	mock *MockModerationUsecase
+}
+
+// NewMockModerationUsecase creates a new mock instance.
+func NewMockModerationUsecase(ctrl *gomock.Controller) *MockModerationUsecase {
+	mock := &MockModerationUsecase{ctrl: ctrl}
+	mock.recorder = &MockModerationUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerationUsecase) EXPECT() *MockModerationUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockModerationUsecase) Approve(ctx context.Context, userID, adID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, userID, adID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockModerationUsecaseMockRecorder) Approve(ctx, userID, adID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockModerationUsecase)(nil).Approve), ctx, userID, adID)
+}
+
+// GetDuplicates mocks base method.
+func (m *MockModerationUsecase) GetDuplicates(ctx context.Context, userID, offset, limit int) ([]dto.DuplicateAdResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDuplicates", ctx, userID, offset, limit)
+	ret0, _ := ret[0].([]dto.DuplicateAdResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDuplicates indicates an expected call of GetDuplicates.
+func (mr *MockModerationUsecaseMockRecorder) GetDuplicates(ctx, userID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDuplicates", reflect.TypeOf((*MockModerationUsecase)(nil).GetDuplicates), ctx, userID, offset, limit)
+}
+
+// GetPending mocks base method.
+func (m *MockModerationUsecase) GetPending(ctx context.Context, userID, offset, limit int) ([]dto.PendingAdResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPending", ctx, userID, offset, limit)
+	ret0, _ := ret[0].([]dto.PendingAdResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPending indicates an expected call of GetPending.
+func (mr *MockModerationUsecaseMockRecorder) GetPending(ctx, userID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*MockModerationUsecase)(nil).GetPending), ctx, userID, offset, limit)
+}
+
+// GetSimilar mocks base method.
+func (m *MockModerationUsecase) GetSimilar(ctx context.Context, userID, adID int) ([]dto.SimilarAdResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSimilar", ctx, userID, adID)
+	ret0, _ := ret[0].([]dto.SimilarAdResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSimilar indicates an expected call of GetSimilar.
+func (mr *MockModerationUsecaseMockRecorder) GetSimilar(ctx, userID, adID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimilar", reflect.TypeOf((*MockModerationUsecase)(nil).GetSimilar), ctx, userID, adID)
+}
+
+// Reject mocks base method.
+func (m *MockModerationUsecase) Reject(ctx context.Context, userID, adID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, userID, adID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockModerationUsecaseMockRecorder) Reject(ctx, userID, adID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockModerationUsecase)(nil).Reject), ctx, userID, adID)
+}
@@ -0,0 +1,162 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase (interfaces: OfferUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/usecase/mock/mock_offer.go github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase OfferUsecase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOfferUsecase is a mock of OfferUsecase interface.
+type MockOfferUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockOfferUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockOfferUsecaseMockRecorder is the mock recorder for MockOfferUsecase.
+type MockOfferUsecaseMockRecorder struct {
+	mock *MockOfferUsecase
+}
+
+// NewMockOfferUsecase creates a new mock instance.
+func NewMockOfferUsecase(ctrl *gomock.Controller) *MockOfferUsecase {
+	mock := &MockOfferUsecase{ctrl: ctrl}
+	mock.recorder = &MockOfferUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOfferUsecase) EXPECT() *MockOfferUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockOfferUsecase) Accept(ctx context.Context, userID, id int) (*dto.OfferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", ctx, userID, id)
+	ret0, _ := ret[0].(*dto.OfferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockOfferUsecaseMockRecorder) Accept(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockOfferUsecase)(nil).Accept), ctx, userID, id)
+}
+
+// Counter mocks base method.
+func (m *MockOfferUsecase) Counter(ctx context.Context, userID, id int, req *dto.OfferRequest) (*dto.OfferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Counter", ctx, userID, id, req)
+	ret0, _ := ret[0].(*dto.OfferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Counter indicates an expected call of Counter.
+func (mr *MockOfferUsecaseMockRecorder) Counter(ctx, userID, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Counter", reflect.TypeOf((*MockOfferUsecase)(nil).Counter), ctx, userID, id, req)
+}
+
+// Create mocks base method.
+func (m *MockOfferUsecase) Create(ctx context.Context, buyerID, adID int, req *dto.OfferRequest) (*dto.OfferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, buyerID, adID, req)
+	ret0, _ := ret[0].(*dto.OfferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOfferUsecaseMockRecorder) Create(ctx, buyerID, adID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOfferUsecase)(nil).Create), ctx, buyerID, adID, req)
+}
+
+// Decline mocks base method.
+func (m *MockOfferUsecase) Decline(ctx context.Context, userID, id int) (*dto.OfferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decline", ctx, userID, id)
+	ret0, _ := ret[0].(*dto.OfferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decline indicates an expected call of Decline.
+func (mr *MockOfferUsecaseMockRecorder) Decline(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decline", reflect.TypeOf((*MockOfferUsecase)(nil).Decline), ctx, userID, id)
+}
+
+// ExpirePending mocks base method.
+func (m *MockOfferUsecase) ExpirePending(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePending", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePending indicates an expected call of ExpirePending.
+func (mr *MockOfferUsecaseMockRecorder) ExpirePending(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePending", reflect.TypeOf((*MockOfferUsecase)(nil).ExpirePending), ctx)
+}
+
+// GetByAdvertisementID mocks base method.
+func (m *MockOfferUsecase) GetByAdvertisementID(ctx context.Context, userID, adID, offset, limit int) ([]dto.OfferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAdvertisementID", ctx, userID, adID, offset, limit)
+	ret0, _ := ret[0].([]dto.OfferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAdvertisementID indicates an expected call of GetByAdvertisementID.
+func (mr *MockOfferUsecaseMockRecorder) GetByAdvertisementID(ctx, userID, adID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAdvertisementID", reflect.TypeOf((*MockOfferUsecase)(nil).GetByAdvertisementID), ctx, userID, adID, offset, limit)
+}
+
+// GetByUserID mocks base method.
+func (m *MockOfferUsecase) GetByUserID(ctx context.Context, userID, offset, limit int) ([]dto.OfferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID, offset, limit)
+	ret0, _ := ret[0].([]dto.OfferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockOfferUsecaseMockRecorder) GetByUserID(ctx, userID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockOfferUsecase)(nil).GetByUserID), ctx, userID, offset, limit)
+}
+
+// Withdraw mocks base method.
+func (m *MockOfferUsecase) Withdraw(ctx context.Context, userID, id int) (*dto.OfferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdraw", ctx, userID, id)
+	ret0, _ := ret[0].(*dto.OfferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockOfferUsecaseMockRecorder) Withdraw(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockOfferUsecase)(nil).Withdraw), ctx, userID, id)
+}
@@ -0,0 +1,84 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase (interfaces: PasswordUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/usecase/mock/mock_password.go github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase PasswordUsecase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPasswordUsecase is a mock of PasswordUsecase interface.
+type MockPasswordUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockPasswordUsecaseMockRecorder is the mock recorder for MockPasswordUsecase.
+type MockPasswordUsecaseMockRecorder struct {
+	mock *MockPasswordUsecase
+}
+
+// NewMockPasswordUsecase creates a new mock instance.
+func NewMockPasswordUsecase(ctrl *gomock.Controller) *MockPasswordUsecase {
+	mock := &MockPasswordUsecase{ctrl: ctrl}
+	mock.recorder = &MockPasswordUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordUsecase) EXPECT() *MockPasswordUsecaseMockRecorder {
+	return m.recorder
+}
+
+// ChangePassword mocks base method.
+func (m *MockPasswordUsecase) ChangePassword(ctx context.Context, userID int, sessionID string, req *dto.ChangePasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, userID, sessionID, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockPasswordUsecaseMockRecorder) ChangePassword(ctx, userID, sessionID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockPasswordUsecase)(nil).ChangePassword), ctx, userID, sessionID, req)
+}
+
+// ConfirmReset mocks base method.
+func (m *MockPasswordUsecase) ConfirmReset(ctx context.Context, req *dto.PasswordResetConfirmRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmReset", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmReset indicates an expected call of ConfirmReset.
+func (mr *MockPasswordUsecaseMockRecorder) ConfirmReset(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmReset", reflect.TypeOf((*MockPasswordUsecase)(nil).ConfirmReset), ctx, req)
+}
+
+// RequestReset mocks base method.
+func (m *MockPasswordUsecase) RequestReset(ctx context.Context, req *dto.PasswordResetRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestReset", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestReset indicates an expected call of RequestReset.
+func (mr *MockPasswordUsecaseMockRecorder) RequestReset(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestReset", reflect.TypeOf((*MockPasswordUsecase)(nil).RequestReset), ctx, req)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase (interfaces: PromotionUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/usecase/mock/mock_promotion.go github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase PromotionUsecase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPromotionUsecase is a mock of PromotionUsecase interface.
+type MockPromotionUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromotionUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockPromotionUsecaseMockRecorder is the mock recorder for MockPromotionUsecase.
+type MockPromotionUsecaseMockRecorder struct {
+	mock *MockPromotionUsecase
+}
+
+// NewMockPromotionUsecase creates a new mock instance.
+func NewMockPromotionUsecase(ctrl *gomock.Controller) *MockPromotionUsecase {
+	mock := &MockPromotionUsecase{ctrl: ctrl}
+	mock.recorder = &MockPromotionUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromotionUsecase) EXPECT() *MockPromotionUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockPromotionUsecase) Cancel(ctx context.Context, userID, adID, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, userID, adID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockPromotionUsecaseMockRecorder) Cancel(ctx, userID, adID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockPromotionUsecase)(nil).Cancel), ctx, userID, adID, id)
+}
+
+// Create mocks base method.
+func (m *MockPromotionUsecase) Create(ctx context.Context, userID, adID int, req *dto.PromotionRequest) (*dto.PromotionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, adID, req)
+	ret0, _ := ret[0].(*dto.PromotionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPromotionUsecaseMockRecorder) Create(ctx, userID, adID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPromotionUsecase)(nil).Create), ctx, userID, adID, req)
+}
+
+// ExpireFinished mocks base method.
+func (m *MockPromotionUsecase) ExpireFinished(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireFinished", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireFinished indicates an expected call of ExpireFinished.
+func (mr *MockPromotionUsecaseMockRecorder) ExpireFinished(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireFinished", reflect.TypeOf((*MockPromotionUsecase)(nil).ExpireFinished), ctx)
+}
+
+// GetByAdvertisementID mocks base method.
+func (m *MockPromotionUsecase) GetByAdvertisementID(ctx context.Context, userID, adID int) ([]dto.PromotionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAdvertisementID", ctx, userID, adID)
+	ret0, _ := ret[0].([]dto.PromotionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAdvertisementID indicates an expected call of GetByAdvertisementID.
+func (mr *MockPromotionUsecaseMockRecorder) GetByAdvertisementID(ctx, userID, adID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAdvertisementID", reflect.TypeOf((*MockPromotionUsecase)(nil).GetByAdvertisementID), ctx, userID, adID)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase (interfaces: ReviewUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/usecase/mock/mock_review.go github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase ReviewUsecase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReviewUsecase is a mock of ReviewUsecase interface.
+type MockReviewUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockReviewUsecaseMockRecorder is the mock recorder for MockReviewUsecase.
+type MockReviewUsecaseMockRecorder struct {
+	mock *MockReviewUsecase
+}
+
+// NewMockReviewUsecase creates a new mock instance.
+func NewMockReviewUsecase(ctrl *gomock.Controller) *MockReviewUsecase {
+	mock := &MockReviewUsecase{ctrl: ctrl}
+	mock.recorder = &MockReviewUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewUsecase) EXPECT() *MockReviewUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockReviewUsecase) Create(ctx context.Context, authorID, adID int, req *dto.CreateReviewRequest) (*dto.ReviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, authorID, adID, req)
+	ret0, _ := ret[0].(*dto.ReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReviewUsecaseMockRecorder) Create(ctx, authorID, adID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReviewUsecase)(nil).Create), ctx, authorID, adID, req)
+}
+
+// GetBySellerID mocks base method.
+func (m *MockReviewUsecase) GetBySellerID(ctx context.Context, sellerID, offset, limit int) ([]dto.ReviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySellerID", ctx, sellerID, offset, limit)
+	ret0, _ := ret[0].([]dto.ReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySellerID indicates an expected call of GetBySellerID.
+func (mr *MockReviewUsecaseMockRecorder) GetBySellerID(ctx, sellerID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySellerID", reflect.TypeOf((*MockReviewUsecase)(nil).GetBySellerID), ctx, sellerID, offset, limit)
+}
+
+// Reply mocks base method.
+func (m *MockReviewUsecase) Reply(ctx context.Context, sellerID, reviewID int, req *dto.ReviewReplyRequest) (*dto.ReviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reply", ctx, sellerID, reviewID, req)
+	ret0, _ := ret[0].(*dto.ReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reply indicates an expected call of Reply.
+func (mr *MockReviewUsecaseMockRecorder) Reply(ctx, sellerID, reviewID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reply", reflect.TypeOf((*MockReviewUsecase)(nil).Reply), ctx, sellerID, reviewID, req)
+}
+
+// Report mocks base method.
+func (m *MockReviewUsecase) Report(ctx context.Context, userID, reviewID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", ctx, userID, reviewID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockReviewUsecaseMockRecorder) Report(ctx, userID, reviewID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockReviewUsecase)(nil).Report), ctx, userID, reviewID)
+}
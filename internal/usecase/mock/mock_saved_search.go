@@ -0,0 +1,170 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase (interfaces: SavedSearchUsecase,SearchDigestSender)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/usecase/mock/mock_saved_search.go github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase SavedSearchUsecase,SearchDigestSender
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	dto "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSavedSearchUsecase is a mock of SavedSearchUsecase interface.
+type MockSavedSearchUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockSavedSearchUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockSavedSearchUsecaseMockRecorder is the mock recorder for MockSavedSearchUsecase.
+type MockSavedSearchUsecaseMockRecorder struct {
+	mock *MockSavedSearchUsecase
+}
+
+// NewMockSavedSearchUsecase creates a new mock instance.
+func NewMockSavedSearchUsecase(ctrl *gomock.Controller) *MockSavedSearchUsecase {
+	mock := &MockSavedSearchUsecase{ctrl: ctrl}
+	mock.recorder = &MockSavedSearchUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSavedSearchUsecase) EXPECT() *MockSavedSearchUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSavedSearchUsecase) Create(ctx context.Context, userID int, req *dto.SavedSearchRequest) (*dto.SavedSearchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, req)
+	ret0, _ := ret[0].(*dto.SavedSearchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSavedSearchUsecaseMockRecorder) Create(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSavedSearchUsecase)(nil).Create), ctx, userID, req)
+}
+
+// Delete mocks base method.
+func (m *MockSavedSearchUsecase) Delete(ctx context.Context, userID, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSavedSearchUsecaseMockRecorder) Delete(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSavedSearchUsecase)(nil).Delete), ctx, userID, id)
+}
+
+// GetByUserID mocks base method.
+func (m *MockSavedSearchUsecase) GetByUserID(ctx context.Context, userID int) ([]dto.SavedSearchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].([]dto.SavedSearchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockSavedSearchUsecaseMockRecorder) GetByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockSavedSearchUsecase)(nil).GetByUserID), ctx, userID)
+}
+
+// MatchNewAds mocks base method.
+func (m *MockSavedSearchUsecase) MatchNewAds(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchNewAds", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchNewAds indicates an expected call of MatchNewAds.
+func (mr *MockSavedSearchUsecaseMockRecorder) MatchNewAds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchNewAds", reflect.TypeOf((*MockSavedSearchUsecase)(nil).MatchNewAds), ctx)
+}
+
+// SendDigests mocks base method.
+func (m *MockSavedSearchUsecase) SendDigests(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendDigests", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendDigests indicates an expected call of SendDigests.
+func (mr *MockSavedSearchUsecaseMockRecorder) SendDigests(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendDigests", reflect.TypeOf((*MockSavedSearchUsecase)(nil).SendDigests), ctx)
+}
+
+// Update mocks base method.
+func (m *MockSavedSearchUsecase) Update(ctx context.Context, userID, id int, req *dto.SavedSearchRequest) (*dto.SavedSearchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userID, id, req)
+	ret0, _ := ret[0].(*dto.SavedSearchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSavedSearchUsecaseMockRecorder) Update(ctx, userID, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSavedSearchUsecase)(nil).Update), ctx, userID, id, req)
+}
+
+// MockSearchDigestSender is a mock of SearchDigestSender interface.
+type MockSearchDigestSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchDigestSenderMockRecorder
+	isgomock struct{}
+}
+
+// MockSearchDigestSenderMockRecorder is the mock recorder for MockSearchDigestSender.
+type MockSearchDigestSenderMockRecorder struct {
+	mock *MockSearchDigestSender
+}
+
+// NewMockSearchDigestSender creates a new mock instance.
+func NewMockSearchDigestSender(ctrl *gomock.Controller) *MockSearchDigestSender {
+	mock := &MockSearchDigestSender{ctrl: ctrl}
+	mock.recorder = &MockSearchDigestSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchDigestSender) EXPECT() *MockSearchDigestSenderMockRecorder {
+	return m.recorder
+}
+
+// SendDigest mocks base method.
+func (m *MockSearchDigestSender) SendDigest(ctx context.Context, digest *entity.SearchDigest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendDigest", ctx, digest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendDigest indicates an expected call of SendDigest.
+func (mr *MockSearchDigestSenderMockRecorder) SendDigest(ctx, digest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendDigest", reflect.TypeOf((*MockSearchDigestSender)(nil).SendDigest), ctx, digest)
+}
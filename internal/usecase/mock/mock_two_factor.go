@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase (interfaces: TwoFactorUsecase)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination internal/usecase/mock/mock_two_factor.go github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase TwoFactorUsecase
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTwoFactorUsecase is a mock of TwoFactorUsecase interface.
+type MockTwoFactorUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockTwoFactorUsecaseMockRecorder is the mock recorder for MockTwoFactorUsecase.
+type MockTwoFactorUsecaseMockRecorder struct {
+	mock *MockTwoFactorUsecase
+}
+
+// NewMockTwoFactorUsecase creates a new mock instance.
+func NewMockTwoFactorUsecase(ctrl *gomock.Controller) *MockTwoFactorUsecase {
+	mock := &MockTwoFactorUsecase{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactorUsecase) EXPECT() *MockTwoFactorUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockTwoFactorUsecase) Confirm(ctx context.Context, userID int, code string) (*dto.RecoveryCodesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, userID, code)
+	ret0, _ := ret[0].(*dto.RecoveryCodesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockTwoFactorUsecaseMockRecorder) Confirm(ctx, userID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockTwoFactorUsecase)(nil).Confirm), ctx, userID, code)
+}
+
+// Disable mocks base method.
+func (m *MockTwoFactorUsecase) Disable(ctx context.Context, userID int, req *dto.TOTPDisableRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, userID, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockTwoFactorUsecaseMockRecorder) Disable(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockTwoFactorUsecase)(nil).Disable), ctx, userID, req)
+}
+
+// Enroll mocks base method.
+func (m *MockTwoFactorUsecase) Enroll(ctx context.Context, userID int) (*dto.TOTPEnrollResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enroll", ctx, userID)
+	ret0, _ := ret[0].(*dto.TOTPEnrollResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enroll indicates an expected call of Enroll.
+func (mr *MockTwoFactorUsecaseMockRecorder) Enroll(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockTwoFactorUsecase)(nil).Enroll), ctx, userID)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockTwoFactorUsecase) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*dto.RecoveryCodesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", ctx, userID, code)
+	ret0, _ := ret[0].(*dto.RecoveryCodesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockTwoFactorUsecaseMockRecorder) RegenerateRecoveryCodes(ctx, userID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockTwoFactorUsecase)(nil).RegenerateRecoveryCodes), ctx, userID, code)
+}
+
+// StartChallenge mocks base method.
+func (m *MockTwoFactorUsecase) StartChallenge(ctx context.Context, userID int) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartChallenge", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// StartChallenge indicates an expected call of StartChallenge.
+func (mr *MockTwoFactorUsecaseMockRecorder) StartChallenge(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartChallenge", reflect.TypeOf((*MockTwoFactorUsecase)(nil).StartChallenge), ctx, userID)
+}
+
+// VerifyChallenge mocks base method.
+func (m *MockTwoFactorUsecase) VerifyChallenge(ctx context.Context, req *dto.LoginTwoFactorRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyChallenge", ctx, req)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyChallenge indicates an expected call of VerifyChallenge.
+func (mr *MockTwoFactorUsecaseMockRecorder) VerifyChallenge(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyChallenge", reflect.TypeOf((*MockTwoFactorUsecase)(nil).VerifyChallenge), ctx, req)
+}
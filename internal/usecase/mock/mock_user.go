@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	entity "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	dto "github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,34 +42,78 @@ func (m *MockUserUsecase) EXPECT() *MockUserUsecaseMockRecorder {
 	return m.recorder
 }
 
+// CompleteLogin mocks base method.
+func (m *MockUserUsecase) CompleteLogin(ctx context.Context, userID int, clientIP string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLogin", ctx, userID, clientIP)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteLogin indicates an expected call of CompleteLogin.
+func (mr *MockUserUsecaseMockRecorder) CompleteLogin(ctx, userID, clientIP any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLogin", reflect.TypeOf((*MockUserUsecase)(nil).CompleteLogin), ctx, userID, clientIP)
+}
+
+// GetCurrentUser mocks base method.
+func (m *MockUserUsecase) GetCurrentUser(ctx context.Context, principal *entity.Principal) (*dto.CurrentUserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentUser", ctx, principal)
+	ret0, _ := ret[0].(*dto.CurrentUserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentUser indicates an expected call of GetCurrentUser.
+func (mr *MockUserUsecaseMockRecorder) GetCurrentUser(ctx, principal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentUser", reflect.TypeOf((*MockUserUsecase)(nil).GetCurrentUser), ctx, principal)
+}
+
+// GetLanguage mocks base method.
+func (m *MockUserUsecase) GetLanguage(ctx context.Context, userID int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLanguage", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLanguage indicates an expected call of GetLanguage.
+func (mr *MockUserUsecaseMockRecorder) GetLanguage(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLanguage", reflect.TypeOf((*MockUserUsecase)(nil).GetLanguage), ctx, userID)
+}
+
 // GetUser mocks base method.
-func (m *MockUserUsecase) GetUser(ctx context.Context, employerID int) (*dto.UserProfileResponse, error) {
+func (m *MockUserUsecase) GetUser(ctx context.Context, viewerID, employerID int) (*dto.UserProfileResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", ctx, employerID)
+	ret := m.ctrl.Call(m, "GetUser", ctx, viewerID, employerID)
 	ret0, _ := ret[0].(*dto.UserProfileResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUser indicates an expected call of GetUser.
-func (mr *MockUserUsecaseMockRecorder) GetUser(ctx, employerID any) *gomock.Call {
+func (mr *MockUserUsecaseMockRecorder) GetUser(ctx, viewerID, employerID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserUsecase)(nil).GetUser), ctx, employerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserUsecase)(nil).GetUser), ctx, viewerID, employerID)
 }
 
 // Login mocks base method.
-func (m *MockUserUsecase) Login(ctx context.Context, loginDTO *dto.Login) (int, error) {
+func (m *MockUserUsecase) Login(ctx context.Context, loginDTO *dto.Login, clientIP string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", ctx, loginDTO)
+	ret := m.ctrl.Call(m, "Login", ctx, loginDTO, clientIP)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockUserUsecaseMockRecorder) Login(ctx, loginDTO any) *gomock.Call {
+func (mr *MockUserUsecaseMockRecorder) Login(ctx, loginDTO, clientIP any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserUsecase)(nil).Login), ctx, loginDTO)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserUsecase)(nil).Login), ctx, loginDTO, clientIP)
 }
 
 // LoginExists mocks base method.
@@ -100,3 +145,18 @@ func (mr *MockUserUsecaseMockRecorder) Register(ctx, registerDTO any) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserUsecase)(nil).Register), ctx, registerDTO)
 }
+
+// UpdateProfile mocks base method.
+func (m *MockUserUsecase) UpdateProfile(ctx context.Context, userID int, req *dto.UpdateProfileRequest) (*dto.UserProfileResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, userID, req)
+	ret0, _ := ret[0].(*dto.UserProfileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserUsecaseMockRecorder) UpdateProfile(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserUsecase)(nil).UpdateProfile), ctx, userID, req)
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+)
+
+type ModerationUsecase interface {
+	GetDuplicates(ctx context.Context, userID, offset, limit int) ([]dto.DuplicateAdResponse, error)
+	GetSimilar(ctx context.Context, userID, adID int) ([]dto.SimilarAdResponse, error)
+	GetPending(ctx context.Context, userID, offset, limit int) ([]dto.PendingAdResponse, error)
+	Approve(ctx context.Context, userID, adID int) error
+	Reject(ctx context.Context, userID, adID int) error
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+)
+
+type NotificationUsecase interface {
+	// Notify создает уведомление и отправляет его в поток событий. Ошибки только
+	// логируются: уведомление не должно срывать действие, которое его вызвало.
+	Notify(ctx context.Context, userID int, notificationType entity.NotificationType, data any, dedupKey string)
+	GetByUserID(ctx context.Context, userID int, unreadOnly bool, offset, limit int) ([]dto.NotificationResponse, error)
+	CountUnread(ctx context.Context, userID int) (*dto.UnreadCountResponse, error)
+	MarkRead(ctx context.Context, userID int, id int64) error
+	MarkAllRead(ctx context.Context, userID int) error
+	GetPreferences(ctx context.Context, userID int) ([]dto.NotificationPreference, error)
+	UpdatePreferences(ctx context.Context, userID int, req *dto.UpdateNotificationPreferencesRequest) ([]dto.NotificationPreference, error)
+}
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+)
+
+type OfferUsecase interface {
+	Create(ctx context.Context, buyerID, adID int, req *dto.OfferRequest) (*dto.OfferResponse, error)
+	GetByUserID(ctx context.Context, userID, offset, limit int) ([]dto.OfferResponse, error)
+	// GetByAdvertisementID доступен только продавцу объявления.
+	GetByAdvertisementID(ctx context.Context, userID, adID, offset, limit int) ([]dto.OfferResponse, error)
+	Accept(ctx context.Context, userID, id int) (*dto.OfferResponse, error)
+	Decline(ctx context.Context, userID, id int) (*dto.OfferResponse, error)
+	Counter(ctx context.Context, userID, id int, req *dto.OfferRequest) (*dto.OfferResponse, error)
+	// Withdraw отменяет принятое предложение, по которому еще нет заказа, и снимает резерв.
+	Withdraw(ctx context.Context, userID, id int) (*dto.OfferResponse, error)
+	// ExpirePending закрывает просроченные предложения и возвращает их число.
+	ExpirePending(ctx context.Context) (int, error)
+}
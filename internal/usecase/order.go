@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+)
+
+type OrderUsecase interface {
+	Create(ctx context.Context, buyerID, adID int) (*dto.OrderResponse, error)
+	GetByID(ctx context.Context, userID, id int) (*dto.OrderResponse, error)
+	GetByUserID(ctx context.Context, userID, offset, limit int) ([]dto.OrderResponse, error)
+	// UpdateStatus выполняет действие над заказом: confirm, ship, complete, dispute или cancel.
+	UpdateStatus(ctx context.Context, userID, id int, action string) (*dto.OrderResponse, error)
+}
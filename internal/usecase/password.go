@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+)
+
+type PasswordUsecase interface {
+	ChangePassword(ctx context.Context, userID int, sessionID string, req *dto.ChangePasswordRequest) error
+	RequestReset(ctx context.Context, req *dto.PasswordResetRequest) error
+	ConfirmReset(ctx context.Context, req *dto.PasswordResetConfirmRequest) error
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+)
+
+type PromotionUsecase interface {
+	Create(ctx context.Context, userID, adID int, req *dto.PromotionRequest) (*dto.PromotionResponse, error)
+	GetByAdvertisementID(ctx context.Context, userID, adID int) ([]dto.PromotionResponse, error)
+	Cancel(ctx context.Context, userID, adID, id int) error
+	// ExpireFinished закрывает закончившиеся продвижения и возвращает их число.
+	ExpireFinished(ctx context.Context) (int, error)
+}
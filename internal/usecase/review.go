@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+)
+
+type ReviewUsecase interface {
+	Create(ctx context.Context, authorID, adID int, req *dto.CreateReviewRequest) (*dto.ReviewResponse, error)
+	GetBySellerID(ctx context.Context, sellerID, offset, limit int) ([]dto.ReviewResponse, error)
+	Reply(ctx context.Context, sellerID, reviewID int, req *dto.ReviewReplyRequest) (*dto.ReviewResponse, error)
+	Report(ctx context.Context, userID, reviewID int) error
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+)
+
+type SavedSearchUsecase interface {
+	Create(ctx context.Context, userID int, req *dto.SavedSearchRequest) (*dto.SavedSearchResponse, error)
+	GetByUserID(ctx context.Context, userID int) ([]dto.SavedSearchResponse, error)
+	Update(ctx context.Context, userID, id int, req *dto.SavedSearchRequest) (*dto.SavedSearchResponse, error)
+	Delete(ctx context.Context, userID, id int) error
+	// MatchNewAds сопоставляет все еще не проверенные объявления с поисками.
+	MatchNewAds(ctx context.Context) (int, error)
+	// SendDigests отправляет накопленные совпадения и возвращает число дайджестов.
+	SendDigests(ctx context.Context) (int, error)
+}
+
+// SearchDigestSender — канал доставки дайджеста сохраненных поисков.
+type SearchDigestSender interface {
+	SendDigest(ctx context.Context, digest *entity.SearchDigest) error
+}
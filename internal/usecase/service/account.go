@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type AccountService struct {
+	userRepo      repository.UserRepository
+	adRepo        repository.AdvertisementRepository
+	apiKeyRepo    repository.APIKeyRepository
+	sessionRepo   repository.SessionRepository
+	twoFactorRepo repository.TwoFactorRepository
+	pepper        entity.Pepper
+	cfg           config.AccountConfig
+}
+
+func NewAccountService(
+	userRepo repository.UserRepository,
+	adRepo repository.AdvertisementRepository,
+	apiKeyRepo repository.APIKeyRepository,
+	sessionRepo repository.SessionRepository,
+	twoFactorRepo repository.TwoFactorRepository,
+	pepper entity.Pepper,
+	cfg config.AccountConfig,
+) usecase.AccountUsecase {
+	return &AccountService{
+		userRepo:      userRepo,
+		adRepo:        adRepo,
+		apiKeyRepo:    apiKeyRepo,
+		sessionRepo:   sessionRepo,
+		twoFactorRepo: twoFactorRepo,
+		pepper:        pepper,
+		cfg:           cfg,
+	}
+}
+
+// Export собирает все данные пользователя. Секреты (хеши пароля и API-ключей,
+// секрет TOTP, полные токены сессий) в выгрузку не попадают.
+func (s *AccountService) Export(ctx context.Context, userID int, sessionID string) (*dto.AccountExport, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+	}).Info("Выгрузка данных пользователя")
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ads, err := s.adRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.apiKeyRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepo.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tf, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &dto.AccountExport{
+		ExportedAt:       time.Now().UTC(),
+		Profile:          *employerEntityToDTO(user, true),
+		Advertisements:   make([]dto.AdvertisementResponse, 0, len(ads)),
+		APIKeys:          make([]dto.APIKeyResponse, 0, len(keys)),
+		Sessions:         make([]dto.SessionExport, 0, len(sessions)),
+		TwoFactorEnabled: tf.Enabled,
+	}
+	for _, ad := range ads {
+		export.Advertisements = append(export.Advertisements, dto.AdvertisementResponse{
+			ID:             ad.ID,
+			Title:          ad.Title,
+			Description:    ad.Description,
+			ImageURL:       ad.ImageURL,
+			Price:          ad.Price,
+			AuthorLogin:    user.Login,
+			AuthorVerified: user.IsVerified(),
+			AuthorRating:   user.Rating,
+			AuthorReviews:  user.RatingCount,
+			IsMine:         true,
+			CreatedAt:      ad.CreatedAt,
+			UpdatedAt:      ad.UpdatedAt,
+		})
+	}
+	for i := range keys {
+		export.APIKeys = append(export.APIKeys, apiKeyEntityToDTO(&keys[i]))
+	}
+	for i := range sessions {
+		export.Sessions = append(export.Sessions, dto.SessionExport{
+			ID:        sessions[i].DisplayID(),
+			ExpiresAt: sessions[i].ExpiresAt,
+			Current:   sessions[i].Token == sessionID,
+		})
+	}
+
+	return export, nil
+}
+
+// ScheduleDeletion помечает аккаунт на удаление и завершает все сессии.
+// До истечения срока ожидания удаление отменяется обычным входом.
+func (s *AccountService) ScheduleDeletion(ctx context.Context, userID int, req *dto.DeleteAccountRequest) (*dto.DeleteAccountResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if ok, _ := entity.CheckPassword(req.Password, user.PasswordHash, s.pepper); !ok {
+		return nil, entity.NewError(entity.ErrForbidden, fmt.Errorf("неверный пароль"))
+	}
+
+	deleteAfter := time.Now().Add(s.cfg.DeletionGracePeriod).UTC()
+	if err := s.userRepo.ScheduleDeletion(ctx, userID, deleteAfter); err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.DeleteAllSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID":   utils.GetRequestID(ctx),
+		"userID":      userID,
+		"deleteAfter": deleteAfter,
+	}).Info("Аккаунт помечен на удаление")
+
+	return &dto.DeleteAccountResponse{DeleteAfter: deleteAfter}, nil
+}
+
+// PurgeDeleted окончательно удаляет аккаунты с истекшим сроком ожидания.
+func (s *AccountService) PurgeDeleted(ctx context.Context) (int64, error) {
+	deleted, err := s.userRepo.DeleteScheduled(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if deleted > 0 {
+		logger.Log.WithFields(logrus.Fields{
+			"deleted": deleted,
+		}).Info("Удалены аккаунты с истекшим сроком ожидания")
+	}
+
+	return deleted, nil
+}
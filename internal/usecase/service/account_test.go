@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type accountMocks struct {
+	userRepo      *mock.MockUserRepository
+	adRepo        *mock.MockAdvertisementRepository
+	apiKeyRepo    *mock.MockAPIKeyRepository
+	sessionRepo   *mock.MockSessionRepository
+	twoFactorRepo *mock.MockTwoFactorRepository
+}
+
+var testAccountCfg = config.AccountConfig{DeletionGracePeriod: 14 * 24 * time.Hour}
+
+func newTestAccountService(ctrl *gomock.Controller) (*AccountService, accountMocks) {
+	m := accountMocks{
+		userRepo:      mock.NewMockUserRepository(ctrl),
+		adRepo:        mock.NewMockAdvertisementRepository(ctrl),
+		apiKeyRepo:    mock.NewMockAPIKeyRepository(ctrl),
+		sessionRepo:   mock.NewMockSessionRepository(ctrl),
+		twoFactorRepo: mock.NewMockTwoFactorRepository(ctrl),
+	}
+	service := NewAccountService(m.userRepo, m.adRepo, m.apiKeyRepo, m.sessionRepo, m.twoFactorRepo,
+		entity.Pepper{}, testAccountCfg).(*AccountService)
+	return service, m
+}
+
+func TestAccountService_Export(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestAccountService(ctrl)
+
+	const (
+		passwordHash = "$argon2id$v=19$m=65536,t=1,p=4$c2FsdA$aGFzaA"
+		totpSecret   = "JBSWY3DPEHPK3PXP"
+		currentToken = "current-session-token"
+		otherToken   = "other-session-token"
+	)
+
+	m.userRepo.EXPECT().GetByID(gomock.Any(), 1).
+		Return(&entity.User{ID: 1, Login: "seller", PasswordHash: passwordHash}, nil)
+	m.adRepo.EXPECT().GetByUserID(gomock.Any(), 1).
+		Return([]entity.Advertisement{{ID: 10, UserID: 1, Title: "Велосипед"}}, nil)
+	m.apiKeyRepo.EXPECT().GetByUserID(gomock.Any(), 1).
+		Return([]entity.APIKey{{ID: 3, UserID: 1, Name: "CI", Prefix: "mk_abc", KeyHash: []byte("key-hash")}}, nil)
+	m.sessionRepo.EXPECT().ListSessions(gomock.Any(), 1).
+		Return([]entity.Session{{Token: currentToken}, {Token: otherToken}}, nil)
+	m.twoFactorRepo.EXPECT().Get(gomock.Any(), 1).
+		Return(&entity.TwoFactor{UserID: 1, Secret: totpSecret, Enabled: true}, nil)
+
+	export, err := service.Export(context.Background(), 1, currentToken)
+	require.NoError(t, err)
+
+	require.Equal(t, "seller", export.Profile.Login)
+	require.Len(t, export.Advertisements, 1)
+	require.True(t, export.Advertisements[0].IsMine)
+	require.Len(t, export.APIKeys, 1)
+	require.True(t, export.TwoFactorEnabled)
+	require.Equal(t, []dto.SessionExport{
+		{ID: currentToken[:entity.SessionDisplayLen], Current: true},
+		{ID: otherToken[:entity.SessionDisplayLen], Current: false},
+	}, export.Sessions)
+
+	body, err := json.Marshal(export)
+	require.NoError(t, err)
+	for _, secret := range []string{passwordHash, totpSecret, currentToken, otherToken, "key-hash"} {
+		require.NotContains(t, string(body), secret)
+	}
+}
+
+func TestAccountService_ScheduleDeletion(t *testing.T) {
+	t.Parallel()
+
+	hash, err := entity.HashPassword("Correct123", entity.Pepper{})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		password    string
+		mockSetup   func(accountMocks)
+		expectedErr error
+	}{
+		{
+			name:     "Аккаунт помечается на удаление, сессии завершаются",
+			password: "Correct123",
+			mockSetup: func(m accountMocks) {
+				m.userRepo.EXPECT().GetByID(gomock.Any(), 1).Return(&entity.User{ID: 1, PasswordHash: hash}, nil)
+				m.userRepo.EXPECT().ScheduleDeletion(gomock.Any(), 1, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, deleteAfter time.Time) error {
+						require.WithinDuration(t, time.Now().Add(testAccountCfg.DeletionGracePeriod), deleteAfter, time.Minute)
+						return nil
+					})
+				m.sessionRepo.EXPECT().DeleteAllSessions(gomock.Any(), 1).Return(nil)
+			},
+		},
+		{
+			name:     "Неверный пароль не помечает аккаунт",
+			password: "Wrong12345",
+			mockSetup: func(m accountMocks) {
+				m.userRepo.EXPECT().GetByID(gomock.Any(), 1).Return(&entity.User{ID: 1, PasswordHash: hash}, nil)
+			},
+			expectedErr: entity.ErrForbidden,
+		},
+		{
+			name:     "Ошибка хранилища не завершает сессии",
+			password: "Correct123",
+			mockSetup: func(m accountMocks) {
+				m.userRepo.EXPECT().GetByID(gomock.Any(), 1).Return(&entity.User{ID: 1, PasswordHash: hash}, nil)
+				m.userRepo.EXPECT().ScheduleDeletion(gomock.Any(), 1, gomock.Any()).
+					Return(entity.NewError(entity.ErrInternal, fmt.Errorf("соединение потеряно")))
+			},
+			expectedErr: entity.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestAccountService(ctrl)
+			tc.mockSetup(m)
+
+			resp, err := service.ScheduleDeletion(context.Background(), 1, &dto.DeleteAccountRequest{Password: tc.password})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.WithinDuration(t, time.Now().Add(testAccountCfg.DeletionGracePeriod), resp.DeleteAfter, time.Minute)
+		})
+	}
+}
+
+func TestAccountService_PurgeDeleted(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestAccountService(ctrl)
+
+	m.userRepo.EXPECT().DeleteScheduled(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, now time.Time) (int64, error) {
+			require.WithinDuration(t, time.Now(), now, time.Minute)
+			return 2, nil
+		})
+
+	deleted, err := service.PurgeDeleted(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 2, deleted)
+}
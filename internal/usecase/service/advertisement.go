@@ -2,30 +2,60 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"strings"
+	"time"
 
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/contentfilter"
 	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
 	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/sanitizer"
 	"github.com/sirupsen/logrus"
 )
 
 type AdvertisementService struct {
-	adRepo   repository.AdvertisementRepository
-	userRepo repository.UserRepository
+	adRepo        repository.AdvertisementRepository
+	userRepo      repository.UserRepository
+	similarCache  repository.SimilarAdsCacheRepository
+	notifications usecase.NotificationUsecase
+	filter        *contentfilter.Filter
+	cfg           config.AdExpiryConfig
+	duplicates    config.DuplicateConfig
+	similar       config.SimilarAdsConfig
 }
 
+// duplicateActionFlag публикует почти дубликат с пометкой вместо отказа.
+const duplicateActionFlag = "flag"
+
+// duplicateCandidatesLimit — сколько ближайших объявлений автора сверяется с новым.
+const duplicateCandidatesLimit = 10
+
 func NewAdvertisementService(
 	adRepo repository.AdvertisementRepository,
 	userRepo repository.UserRepository,
+	similarCache repository.SimilarAdsCacheRepository,
+	notifications usecase.NotificationUsecase,
+	filter *contentfilter.Filter,
+	cfg config.AdExpiryConfig,
+	duplicates config.DuplicateConfig,
+	similar config.SimilarAdsConfig,
 ) usecase.AdvertisementUsecase {
 	return &AdvertisementService{
-		adRepo:   adRepo,
-		userRepo: userRepo,
+		adRepo:        adRepo,
+		userRepo:      userRepo,
+		similarCache:  similarCache,
+		notifications: notifications,
+		filter:        filter,
+		cfg:           cfg,
+		duplicates:    duplicates,
+		similar:       similar,
 	}
 }
 
@@ -47,11 +77,20 @@ func (s *AdvertisementService) Create(ctx context.Context, userID int, req *dto.
 		Description: req.Description,
 		ImageURL:    req.ImageURL,
 		Price:       req.Price,
+		ExpiresAt:   time.Now().Add(s.cfg.TTL),
 	}
 
-	if _, err := ad.Validate(); err != nil {
-		return nil, entity.NewError(entity.ErrBadRequest,
-			fmt.Errorf("ошибка валидации объявления: %w", err))
+	if err := s.validate(ctx, ad); err != nil {
+		return nil, err
+	}
+
+	author, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDuplicates(ctx, ad); err != nil {
+		return nil, err
 	}
 
 	createdAd, err := s.adRepo.Create(ctx, ad)
@@ -63,23 +102,42 @@ func (s *AdvertisementService) Create(ctx context.Context, userID int, req *dto.
 		return nil, err
 	}
 
+	// Об объявлении на модерации автор узнает после решения модератора.
+	if createdAd.ModerationStatus == entity.ModerationApproved {
+		s.notifications.Notify(ctx, userID, entity.NotificationAdPublished, dto.AdPublishedNotification{
+			AdvertisementID: createdAd.ID,
+			Title:           createdAd.Title,
+		}, "")
+	}
+
 	response := &dto.AdvertisementShort{
-		Title:       createdAd.Title,
-		Description: createdAd.Description,
-		ImageURL:    createdAd.ImageURL,
-		Price:       createdAd.Price,
-		CreatedAt:   createdAd.CreatedAt,
-		UpdatedAt:   createdAd.UpdatedAt,
+		Title:            createdAd.Title,
+		Description:      createdAd.Description,
+		ImageURL:         createdAd.ImageURL,
+		Price:            createdAd.Price,
+		AuthorVerified:   author.IsVerified(),
+		AuthorRating:     author.Rating,
+		AuthorReviews:    author.RatingCount,
+		Status:           string(createdAd.Status),
+		ModerationStatus: string(createdAd.ModerationStatus),
+		ExpiresAt:        createdAd.ExpiresAt,
+		RenewalsCount:    createdAd.RenewalsCount,
+		Version:          createdAd.Version,
+		CreatedAt:        createdAd.CreatedAt,
+		UpdatedAt:        createdAd.UpdatedAt,
 	}
 
 	return response, nil
 }
 
-func (s *AdvertisementService) GetByID(ctx context.Context, id int) (*dto.AdvertisementShort, error) {
+// GetByID возвращает объявление. Не прошедшее модерацию объявление видят только автор
+// и модераторы, остальным оно не находится.
+func (s *AdvertisementService) GetByID(ctx context.Context, viewerID, id int) (*dto.AdvertisementShort, error) {
 	requestID := utils.GetRequestID(ctx)
 
 	logger.Log.WithFields(logrus.Fields{
 		"requestID": requestID,
+		"userID":    viewerID,
 		"adID":      id,
 	}).Info("Получение объявления по ID")
 
@@ -93,18 +151,140 @@ func (s *AdvertisementService) GetByID(ctx context.Context, id int) (*dto.Advert
 		return nil, fmt.Errorf("ошибка при получении объявления: %w", err)
 	}
 
+	if err := s.checkCanView(ctx, viewerID, ad); err != nil {
+		return nil, err
+	}
+	isMine := viewerID != 0 && ad.UserID == viewerID
+
 	response := &dto.AdvertisementShort{
-		Title:       ad.Title,
-		Description: ad.Description,
-		ImageURL:    ad.ImageURL,
-		Price:       ad.Price,
-		CreatedAt:   ad.CreatedAt,
-		UpdatedAt:   ad.UpdatedAt,
+		Title:          ad.Title,
+		Description:    ad.Description,
+		ImageURL:       ad.ImageURL,
+		Price:          ad.Price,
+		AuthorVerified: ad.AuthorVerified,
+		AuthorRating:   ad.AuthorRating,
+		AuthorReviews:  ad.AuthorReviews,
+		Status:         string(ad.Status),
+		IsPromoted:     ad.IsPromoted,
+		ExpiresAt:      ad.ExpiresAt,
+		RenewalsCount:  ad.RenewalsCount,
+		Version:        ad.Version,
+		CreatedAt:      ad.CreatedAt,
+		UpdatedAt:      ad.UpdatedAt,
+	}
+	if isMine {
+		response.ModerationStatus = string(ad.ModerationStatus)
 	}
 
 	return response, nil
 }
 
+// checkCanView пропускает к неодобренному объявлению только автора и модераторов.
+func (s *AdvertisementService) checkCanView(ctx context.Context, viewerID int, ad *entity.Advertisement) error {
+	if ad.ModerationStatus == entity.ModerationApproved || (viewerID != 0 && ad.UserID == viewerID) {
+		return nil
+	}
+	return s.checkCanViewUnmoderated(ctx, viewerID, ad.ID)
+}
+
+// checkCanViewUnmoderated пропускает к неодобренному объявлению только модератора.
+// Остальным отвечает 404, чтобы не выдавать само существование объявления.
+func (s *AdvertisementService) checkCanViewUnmoderated(ctx context.Context, viewerID, id int) error {
+	notFound := entity.NewError(
+		entity.ErrNotFound,
+		fmt.Errorf("объявление с id=%d не прошло модерацию", id),
+	)
+	if viewerID == 0 {
+		return notFound
+	}
+
+	viewer, err := s.userRepo.GetByID(ctx, viewerID)
+	if err != nil {
+		return err
+	}
+	if !viewer.IsModerator {
+		return notFound
+	}
+	return nil
+}
+
+// Update заменяет содержимое объявления, если его версия совпадает с version.
+// Новый текст заново проходит фильтр содержимого и проверку на дубликаты.
+func (s *AdvertisementService) Update(
+	ctx context.Context,
+	userID, id, version int,
+	req *dto.UpdateAdvertisementRequest,
+) (*dto.AdvertisementShort, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"adID":      id,
+		"version":   version,
+	}).Info("Изменение объявления")
+
+	ad, err := s.adRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := ad.CanEdit(userID); err != nil {
+		return nil, err
+	}
+	if err := ad.CheckVersion(version); err != nil {
+		return nil, err
+	}
+
+	previousModeration := ad.ModerationStatus
+
+	ad.Title = sanitizer.StrictPolicy.Sanitize(req.Title)
+	ad.Description = sanitizer.StrictPolicy.Sanitize(req.Description)
+	ad.ImageURL = sanitizer.StrictPolicy.Sanitize(req.ImageURL)
+	ad.Price = req.Price
+
+	if err := s.validate(ctx, ad); err != nil {
+		return nil, err
+	}
+
+	// Правка не снимает решение модератора: отклоненное или ждущее проверки объявление
+	// снова уходит на модерацию, даже если фильтр ничего не нашел.
+	if previousModeration != entity.ModerationApproved && ad.ModerationStatus == entity.ModerationApproved {
+		ad.ModerationStatus = entity.ModerationPending
+		ad.ModerationNote = "объявление изменено автором после отправки на модерацию"
+	}
+
+	if err := s.checkDuplicates(ctx, ad); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.adRepo.Update(ctx, ad); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, userID, id)
+}
+
+// Delete удаляет объявление, если его версия совпадает с version.
+func (s *AdvertisementService) Delete(ctx context.Context, userID, id, version int) error {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"adID":      id,
+		"version":   version,
+	}).Info("Удаление объявления")
+
+	ad, err := s.adRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := ad.CanDelete(userID); err != nil {
+		return err
+	}
+	if err := ad.CheckVersion(version); err != nil {
+		return err
+	}
+
+	return s.adRepo.Delete(ctx, id, ad.Version)
+}
+
 func (s *AdvertisementService) GetAll(
 	ctx context.Context,
 	userID int,
@@ -126,15 +306,22 @@ func (s *AdvertisementService) GetAll(
 	response := make([]dto.AdvertisementResponse, 0, len(ads))
 	for _, ad := range ads {
 		response = append(response, dto.AdvertisementResponse{
-			ID:          ad.ID,
-			Title:       ad.Title,
-			Description: ad.Description,
-			ImageURL:    ad.ImageURL,
-			Price:       ad.Price,
-			AuthorLogin: ad.AuthorLogin,
-			IsMine:      ad.IsMine && userID != 0,
-			CreatedAt:   ad.CreatedAt,
-			UpdatedAt:   ad.UpdatedAt,
+			ID:             ad.ID,
+			Title:          ad.Title,
+			Description:    ad.Description,
+			ImageURL:       ad.ImageURL,
+			Price:          ad.Price,
+			AuthorLogin:    ad.AuthorLogin,
+			AuthorVerified: ad.AuthorVerified,
+			AuthorRating:   ad.AuthorRating,
+			AuthorReviews:  ad.AuthorReviews,
+			IsMine:         ad.IsMine && userID != 0,
+			Status:         string(ad.Status),
+			IsPromoted:     ad.IsPromoted,
+			ExpiresAt:      ad.ExpiresAt,
+			RenewalsCount:  ad.RenewalsCount,
+			CreatedAt:      ad.CreatedAt,
+			UpdatedAt:      ad.UpdatedAt,
 		})
 	}
 
@@ -161,16 +348,260 @@ func (s *AdvertisementService) GetByUserID(ctx context.Context, userID int) ([]d
 	response := make([]dto.AdvertisementResponse, 0, len(ads))
 	for _, ad := range ads {
 		response = append(response, dto.AdvertisementResponse{
-			ID:          ad.ID,
-			Title:       ad.Title,
-			Description: ad.Description,
-			ImageURL:    ad.ImageURL,
-			Price:       ad.Price,
-			UserID:      ad.UserID,
-			CreatedAt:   ad.CreatedAt,
-			UpdatedAt:   ad.UpdatedAt,
+			ID:               ad.ID,
+			Title:            ad.Title,
+			Description:      ad.Description,
+			ImageURL:         ad.ImageURL,
+			Price:            ad.Price,
+			UserID:           ad.UserID,
+			AuthorVerified:   ad.AuthorVerified,
+			AuthorRating:     ad.AuthorRating,
+			AuthorReviews:    ad.AuthorReviews,
+			Status:           string(ad.Status),
+			ModerationStatus: string(ad.ModerationStatus),
+			IsPromoted:       ad.IsPromoted,
+			ExpiresAt:        ad.ExpiresAt,
+			RenewalsCount:    ad.RenewalsCount,
+			CreatedAt:        ad.CreatedAt,
+			UpdatedAt:        ad.UpdatedAt,
 		})
 	}
 
 	return response, nil
 }
+
+// GetSimilar возвращает объявления других авторов, похожие на объявление id, без объявлений
+// зрителя. Подборка кешируется на объявление с запасом в два раза, потому что объявления
+// зрителя отсеиваются уже после кеша. Доступ к исходному объявлению проверяется до кеша:
+// подборка не должна выдавать существование объявления, которое зритель не видит.
+// Ошибки кеша не мешают ответу.
+func (s *AdvertisementService) GetSimilar(ctx context.Context, viewerID, id int) ([]dto.AdvertisementResponse, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"adID":      id,
+	}).Info("Получение похожих объявлений")
+
+	ad, err := s.adRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCanView(ctx, viewerID, ad); err != nil {
+		return nil, err
+	}
+
+	ads, cached, err := s.similarCache.Get(ctx, id)
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Warn("Не удалось прочитать похожие объявления из кеша")
+	}
+
+	if !cached {
+		ads, err = s.adRepo.GetSimilar(ctx, ad, 2*s.similar.Limit)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.similarCache.Set(ctx, id, ads, s.similar.CacheTTL); err != nil {
+			logger.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+				"error":     err,
+			}).Warn("Не удалось сохранить похожие объявления в кеш")
+		}
+	}
+
+	response := make([]dto.AdvertisementResponse, 0, s.similar.Limit)
+	for _, ad := range ads {
+		if len(response) == s.similar.Limit {
+			break
+		}
+		if viewerID != 0 && ad.UserID == viewerID {
+			continue
+		}
+		response = append(response, dto.AdvertisementResponse{
+			ID:             ad.ID,
+			Title:          ad.Title,
+			Description:    ad.Description,
+			ImageURL:       ad.ImageURL,
+			Price:          ad.Price,
+			UserID:         ad.UserID,
+			AuthorLogin:    ad.AuthorLogin,
+			AuthorVerified: ad.AuthorVerified,
+			AuthorRating:   ad.AuthorRating,
+			AuthorReviews:  ad.AuthorReviews,
+			Status:         string(ad.Status),
+			IsPromoted:     ad.IsPromoted,
+			ExpiresAt:      ad.ExpiresAt,
+			RenewalsCount:  ad.RenewalsCount,
+			CreatedAt:      ad.CreatedAt,
+			UpdatedAt:      ad.UpdatedAt,
+		})
+	}
+
+	return response, nil
+}
+
+// validate проверяет поля объявления и прогоняет заголовок и описание через фильтр содержимого.
+// Нарушения с важностью block возвращаются вместе с ошибками полей, review отправляет
+// объявление на модерацию с перечнем причин.
+func (s *AdvertisementService) validate(ctx context.Context, ad *entity.Advertisement) error {
+	fe := entity.FieldErrors{}
+	if _, err := ad.Validate(); err != nil {
+		var validationErr *entity.AdvValidationError
+		if !errors.As(err, &validationErr) {
+			return entity.NewError(entity.ErrBadRequest,
+				fmt.Errorf("ошибка валидации объявления: %w", err))
+		}
+		maps.Copy(fe, validationErr.Fields)
+	}
+
+	violations := append(s.filter.Check("title", ad.Title), s.filter.Check("description", ad.Description)...)
+
+	var reviewReasons []string
+	for _, v := range violations {
+		switch v.Severity {
+		case contentfilter.SeverityBlock:
+			if _, ok := fe[v.Field]; !ok {
+				fe[v.Field] = entity.NewLocalizedMessage(entity.MsgContentRule+"."+v.Rule, entity.MessageParams{"reason": v.Reason})
+			}
+		case contentfilter.SeverityReview:
+			reviewReasons = append(reviewReasons, v.Field+": "+v.Reason)
+		}
+	}
+
+	if len(fe) > 0 {
+		return entity.NewError(entity.ErrBadRequest,
+			fmt.Errorf("ошибка валидации объявления: %w", &entity.AdvValidationError{Fields: fe}))
+	}
+
+	ad.ModerationStatus = entity.ModerationApproved
+	if len(reviewReasons) > 0 {
+		ad.ModerationStatus = entity.ModerationPending
+		ad.ModerationNote = strings.Join(reviewReasons, "; ")
+
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"userID":    ad.UserID,
+			"reasons":   ad.ModerationNote,
+		}).Info("Объявление отправлено на модерацию")
+	}
+	return nil
+}
+
+// checkDuplicates снимает отпечаток объявления и сверяет его с активными объявлениями
+// автора. Почти дубликат отклоняется или помечается в зависимости от настроек.
+func (s *AdvertisementService) checkDuplicates(ctx context.Context, ad *entity.Advertisement) error {
+	ad.Fingerprint = entity.NewAdFingerprint(ad.Title, ad.Description, ad.ImageURL)
+	// После правки прежняя пометка снимается, если новый текст ни на что не похож.
+	ad.DuplicateOf = nil
+
+	similar, err := s.adRepo.FindSimilar(ctx, ad.Fingerprint, entity.SimilarAdsFilter{
+		UserID:      ad.UserID,
+		ExcludeID:   ad.ID,
+		MaxDistance: s.duplicates.MaxDistance,
+		Limit:       duplicateCandidatesLimit,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range similar {
+		if !similar[i].IsNearDuplicate(s.duplicates.MaxDistance) {
+			continue
+		}
+
+		original := similar[i].ID
+		if s.duplicates.Action != duplicateActionFlag {
+			return entity.NewError(
+				entity.ErrAlreadyExists,
+				fmt.Errorf("объявление повторяет ваше активное объявление с id=%d", original),
+			)
+		}
+
+		logger.Log.WithFields(logrus.Fields{
+			"requestID":  utils.GetRequestID(ctx),
+			"userID":     ad.UserID,
+			"originalID": original,
+			"distance":   similar[i].Distance,
+		}).Warn("Объявление помечено как почти дубликат")
+
+		ad.DuplicateOf = &original
+		return nil
+	}
+	return nil
+}
+
+// Renew продлевает публикацию объявления на TTL от текущего момента.
+// Архивное объявление возвращается в ленту.
+func (s *AdvertisementService) Renew(ctx context.Context, userID, id int) (*dto.AdvertisementShort, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"adID":      id,
+	}).Info("Продление объявления")
+
+	ad, err := s.adRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := ad.CanRenew(userID, s.cfg.MaxRenewals); err != nil {
+		return nil, err
+	}
+
+	if err := s.adRepo.Renew(ctx, id, time.Now().Add(s.cfg.TTL), s.cfg.MaxRenewals); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, userID, id)
+}
+
+// ArchiveExpired переводит в архив объявления с истекшим сроком и сообщает об этом владельцам.
+func (s *AdvertisementService) ArchiveExpired(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		ads, err := s.adRepo.ArchiveExpired(ctx, s.cfg.BatchSize)
+		if err != nil {
+			return total, err
+		}
+		s.notifyExpiry(ctx, entity.NotificationAdArchived, ads)
+		total += len(ads)
+
+		if len(ads) == 0 || len(ads) < s.cfg.BatchSize {
+			return total, nil
+		}
+	}
+}
+
+// NotifyExpiring заранее предупреждает владельцев объявлений, срок которых истекает
+// в течение NotifyBefore.
+func (s *AdvertisementService) NotifyExpiring(ctx context.Context) (int, error) {
+	deadline := time.Now().Add(s.cfg.NotifyBefore)
+
+	total := 0
+	for {
+		ads, err := s.adRepo.ClaimExpiring(ctx, deadline, s.cfg.BatchSize)
+		if err != nil {
+			return total, err
+		}
+		s.notifyExpiry(ctx, entity.NotificationAdExpiring, ads)
+		total += len(ads)
+
+		if len(ads) == 0 || len(ads) < s.cfg.BatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (s *AdvertisementService) notifyExpiry(ctx context.Context, notificationType entity.NotificationType, ads []entity.Advertisement) {
+	for _, ad := range ads {
+		s.notifications.Notify(ctx, ad.UserID, notificationType, dto.AdExpiryNotification{
+			AdvertisementID: ad.ID,
+			Title:           ad.Title,
+			ExpiresAt:       ad.ExpiresAt,
+			RenewalsLeft:    max(s.cfg.MaxRenewals-ad.RenewalsCount, 0),
+		}, "")
+	}
+}
@@ -0,0 +1,448 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	usecaseMock "github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAdvertisementService_GetByIDModeration(t *testing.T) {
+	t.Parallel()
+
+	const (
+		adID     = 10
+		authorID = 1
+		viewerID = 2
+	)
+
+	testCases := []struct {
+		name               string
+		viewerID           int
+		moderation         entity.ModerationStatus
+		mockSetup          func(*mock.MockUserRepository)
+		expectedErr        error
+		expectedModeration string
+	}{
+		{
+			name:       "Одобренное объявление видно анониму без статуса модерации",
+			viewerID:   0,
+			moderation: entity.ModerationApproved,
+			mockSetup:  func(*mock.MockUserRepository) {},
+		},
+		{
+			name:               "Автор видит статус модерации",
+			viewerID:           authorID,
+			moderation:         entity.ModerationApproved,
+			mockSetup:          func(*mock.MockUserRepository) {},
+			expectedModeration: "approved",
+		},
+		{
+			name:        "Аноним не видит объявление на модерации",
+			viewerID:    0,
+			moderation:  entity.ModerationPending,
+			mockSetup:   func(*mock.MockUserRepository) {},
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:       "Чужой пользователь не видит отклоненное объявление",
+			viewerID:   viewerID,
+			moderation: entity.ModerationRejected,
+			mockSetup: func(userRepo *mock.MockUserRepository) {
+				userRepo.EXPECT().GetByID(gomock.Any(), viewerID).Return(&entity.User{ID: viewerID}, nil)
+			},
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:               "Автор видит свое объявление на модерации",
+			viewerID:           authorID,
+			moderation:         entity.ModerationPending,
+			mockSetup:          func(*mock.MockUserRepository) {},
+			expectedModeration: "pending",
+		},
+		{
+			name:       "Модератор видит объявление на модерации без статуса",
+			viewerID:   viewerID,
+			moderation: entity.ModerationPending,
+			mockSetup: func(userRepo *mock.MockUserRepository) {
+				userRepo.EXPECT().GetByID(gomock.Any(), viewerID).
+					Return(&entity.User{ID: viewerID, IsModerator: true}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			adRepo := mock.NewMockAdvertisementRepository(ctrl)
+			userRepo := mock.NewMockUserRepository(ctrl)
+			service := NewAdvertisementService(adRepo, userRepo, nil, nil, nil,
+				config.AdExpiryConfig{}, config.DuplicateConfig{}, config.SimilarAdsConfig{})
+
+			adRepo.EXPECT().GetByID(gomock.Any(), adID).Return(&entity.Advertisement{
+				ID:               adID,
+				UserID:           authorID,
+				Title:            "Велосипед",
+				Status:           entity.AdPublished,
+				ModerationStatus: tc.moderation,
+				Version:          2,
+			}, nil)
+			tc.mockSetup(userRepo)
+
+			ad, err := service.GetByID(context.Background(), tc.viewerID, adID)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "Велосипед", ad.Title)
+			require.Equal(t, tc.expectedModeration, ad.ModerationStatus)
+		})
+	}
+}
+
+func TestAdvertisementService_GetSimilar(t *testing.T) {
+	t.Parallel()
+
+	const (
+		sourceID    = 10
+		viewerID    = 5
+		moderatorID = 6
+	)
+
+	similarCfg := config.SimilarAdsConfig{Limit: 2, CacheTTL: 10 * time.Minute}
+	source := &entity.Advertisement{ID: sourceID, UserID: 1, Title: "Велосипед горный", Price: 15000, ModerationStatus: entity.ModerationApproved}
+	pending := &entity.Advertisement{ID: sourceID, UserID: 1, Title: "Велосипед горный", Price: 15000, ModerationStatus: entity.ModerationPending}
+	// Репозиторий отдает подборку уже отранжированной по тексту и цене, без исходного
+	// объявления, объявлений автора и не прошедших модерацию.
+	ranked := []entity.Advertisement{
+		{ID: 21, UserID: 2, Title: "Горный велосипед", Price: 14000},
+		{ID: 22, UserID: viewerID, Title: "Велосипед", Price: 15000},
+		{ID: 23, UserID: 3, Title: "Велосипед детский", Price: 5000},
+		{ID: 24, UserID: 4, Title: "Самокат", Price: 15000},
+	}
+
+	testCases := []struct {
+		name        string
+		viewerID    int
+		mockSetup   func(*mock.MockAdvertisementRepository, *mock.MockUserRepository, *mock.MockSimilarAdsCacheRepository)
+		expectedIDs []int
+		expectedErr error
+	}{
+		{
+			name:     "Промах кеша: подборка считается по исходному объявлению и сохраняется",
+			viewerID: 0,
+			mockSetup: func(adRepo *mock.MockAdvertisementRepository, _ *mock.MockUserRepository, cache *mock.MockSimilarAdsCacheRepository) {
+				adRepo.EXPECT().GetByID(gomock.Any(), sourceID).Return(source, nil)
+				cache.EXPECT().Get(gomock.Any(), sourceID).Return(nil, false, nil)
+				adRepo.EXPECT().GetSimilar(gomock.Any(), source, 2*similarCfg.Limit).Return(ranked, nil)
+				cache.EXPECT().Set(gomock.Any(), sourceID, ranked, similarCfg.CacheTTL).Return(nil)
+			},
+			expectedIDs: []int{21, 22},
+		},
+		{
+			name:     "Попадание в кеш не пересчитывает подборку и сохраняет порядок",
+			viewerID: 0,
+			mockSetup: func(adRepo *mock.MockAdvertisementRepository, _ *mock.MockUserRepository, cache *mock.MockSimilarAdsCacheRepository) {
+				adRepo.EXPECT().GetByID(gomock.Any(), sourceID).Return(source, nil)
+				cache.EXPECT().Get(gomock.Any(), sourceID).Return(ranked, true, nil)
+			},
+			expectedIDs: []int{21, 22},
+		},
+		{
+			name:     "Объявления зрителя не попадают в подборку, лимит добирается следующими",
+			viewerID: viewerID,
+			mockSetup: func(adRepo *mock.MockAdvertisementRepository, _ *mock.MockUserRepository, cache *mock.MockSimilarAdsCacheRepository) {
+				adRepo.EXPECT().GetByID(gomock.Any(), sourceID).Return(source, nil)
+				cache.EXPECT().Get(gomock.Any(), sourceID).Return(ranked, true, nil)
+			},
+			expectedIDs: []int{21, 23},
+		},
+		{
+			name:     "Ошибки кеша не мешают подбору",
+			viewerID: 0,
+			mockSetup: func(adRepo *mock.MockAdvertisementRepository, _ *mock.MockUserRepository, cache *mock.MockSimilarAdsCacheRepository) {
+				adRepo.EXPECT().GetByID(gomock.Any(), sourceID).Return(source, nil)
+				cache.EXPECT().Get(gomock.Any(), sourceID).
+					Return(nil, false, entity.NewError(entity.ErrInternal, fmt.Errorf("redis недоступен")))
+				adRepo.EXPECT().GetSimilar(gomock.Any(), source, 2*similarCfg.Limit).Return(ranked[2:], nil)
+				cache.EXPECT().Set(gomock.Any(), sourceID, ranked[2:], similarCfg.CacheTTL).
+					Return(entity.NewError(entity.ErrInternal, fmt.Errorf("redis недоступен")))
+			},
+			expectedIDs: []int{23, 24},
+		},
+		{
+			name:     "Пустая подборка тоже кешируется",
+			viewerID: 0,
+			mockSetup: func(adRepo *mock.MockAdvertisementRepository, _ *mock.MockUserRepository, cache *mock.MockSimilarAdsCacheRepository) {
+				adRepo.EXPECT().GetByID(gomock.Any(), sourceID).Return(source, nil)
+				cache.EXPECT().Get(gomock.Any(), sourceID).Return(nil, false, nil)
+				adRepo.EXPECT().GetSimilar(gomock.Any(), source, 2*similarCfg.Limit).Return(nil, nil)
+				cache.EXPECT().Set(gomock.Any(), sourceID, gomock.Nil(), similarCfg.CacheTTL).Return(nil)
+			},
+			expectedIDs: []int{},
+		},
+		{
+			name:     "Несуществующее объявление",
+			viewerID: 0,
+			mockSetup: func(adRepo *mock.MockAdvertisementRepository, _ *mock.MockUserRepository, _ *mock.MockSimilarAdsCacheRepository) {
+				adRepo.EXPECT().GetByID(gomock.Any(), sourceID).
+					Return(nil, entity.NewError(entity.ErrNotFound, fmt.Errorf("объявление с id=%d не найдено", sourceID)))
+			},
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:     "Объявление на модерации не выдается анониму даже из кеша",
+			viewerID: 0,
+			mockSetup: func(adRepo *mock.MockAdvertisementRepository, _ *mock.MockUserRepository, _ *mock.MockSimilarAdsCacheRepository) {
+				adRepo.EXPECT().GetByID(gomock.Any(), sourceID).Return(pending, nil)
+			},
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:     "Объявление на модерации не выдается чужому пользователю",
+			viewerID: viewerID,
+			mockSetup: func(adRepo *mock.MockAdvertisementRepository, userRepo *mock.MockUserRepository, _ *mock.MockSimilarAdsCacheRepository) {
+				adRepo.EXPECT().GetByID(gomock.Any(), sourceID).Return(pending, nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), viewerID).Return(&entity.User{ID: viewerID}, nil)
+			},
+			expectedErr: entity.ErrNotFound,
+		},
+		{
+			name:     "Модератор видит подборку к объявлению на модерации",
+			viewerID: moderatorID,
+			mockSetup: func(adRepo *mock.MockAdvertisementRepository, userRepo *mock.MockUserRepository, cache *mock.MockSimilarAdsCacheRepository) {
+				adRepo.EXPECT().GetByID(gomock.Any(), sourceID).Return(pending, nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), moderatorID).Return(&entity.User{ID: moderatorID, IsModerator: true}, nil)
+				cache.EXPECT().Get(gomock.Any(), sourceID).Return(ranked, true, nil)
+			},
+			expectedIDs: []int{21, 22},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			adRepo := mock.NewMockAdvertisementRepository(ctrl)
+			userRepo := mock.NewMockUserRepository(ctrl)
+			cache := mock.NewMockSimilarAdsCacheRepository(ctrl)
+			service := NewAdvertisementService(adRepo, userRepo, cache, nil, nil,
+				config.AdExpiryConfig{}, config.DuplicateConfig{}, similarCfg)
+
+			tc.mockSetup(adRepo, userRepo, cache)
+
+			ads, err := service.GetSimilar(context.Background(), tc.viewerID, sourceID)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			ids := make([]int, 0, len(ads))
+			for _, ad := range ads {
+				require.NotEqual(t, sourceID, ad.ID)
+				ids = append(ids, ad.ID)
+			}
+			require.Equal(t, tc.expectedIDs, ids)
+		})
+	}
+}
+
+func TestAdvertisementService_UpdateDuplicateOf(t *testing.T) {
+	t.Parallel()
+
+	const (
+		adID     = 10
+		authorID = 1
+	)
+
+	previousOriginal := 7
+	newOriginal := 8
+	req := &dto.UpdateAdvertisementRequest{
+		Title:       "Велосипед горный",
+		Description: "Почти новый, 21 скорость",
+		ImageURL:    "https://example.com/bike.jpg",
+		Price:       15000,
+	}
+
+	testCases := []struct {
+		name                string
+		similar             []entity.SimilarAd
+		expectedDuplicateOf *int
+	}{
+		{
+			name:                "Правка без похожих снимает пометку",
+			similar:             nil,
+			expectedDuplicateOf: nil,
+		},
+		{
+			name:                "Непохожие кандидаты тоже снимают пометку",
+			similar:             []entity.SimilarAd{{Advertisement: entity.Advertisement{ID: previousOriginal}, Distance: 20}},
+			expectedDuplicateOf: nil,
+		},
+		{
+			name:                "Новый почти дубликат помечается заново",
+			similar:             []entity.SimilarAd{{Advertisement: entity.Advertisement{ID: newOriginal}, Distance: 1}},
+			expectedDuplicateOf: &newOriginal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			adRepo := mock.NewMockAdvertisementRepository(ctrl)
+			service := NewAdvertisementService(adRepo, nil, nil, nil, nil,
+				config.AdExpiryConfig{}, config.DuplicateConfig{Action: duplicateActionFlag, MaxDistance: 3}, config.SimilarAdsConfig{})
+
+			stored := func() *entity.Advertisement {
+				return &entity.Advertisement{
+					ID:               adID,
+					UserID:           authorID,
+					Title:            "Велосипед",
+					Description:      "Горный, 21 скорость",
+					Status:           entity.AdPublished,
+					ModerationStatus: entity.ModerationApproved,
+					DuplicateOf:      &previousOriginal,
+					Version:          2,
+				}
+			}
+			adRepo.EXPECT().GetByID(gomock.Any(), adID).Return(stored(), nil).Times(2)
+			adRepo.EXPECT().FindSimilar(gomock.Any(), gomock.Any(), gomock.Any()).Return(tc.similar, nil)
+			adRepo.EXPECT().Update(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, ad *entity.Advertisement) (int, error) {
+					require.Equal(t, tc.expectedDuplicateOf, ad.DuplicateOf)
+					return 3, nil
+				})
+
+			_, err := service.Update(context.Background(), authorID, adID, 2, req)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAdvertisementService_GetAllPromoted(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Репозиторий уже закрепил продвигаемые объявления сверху, сервис не должен менять порядок.
+	adRepo := mock.NewMockAdvertisementRepository(ctrl)
+	adRepo.EXPECT().GetAll(gomock.Any(), 0, 0, 10, "price", "asc", nil, nil).Return([]entity.Advertisement{
+		{ID: 3, Price: 900, IsPromoted: true},
+		{ID: 1, Price: 100},
+		{ID: 2, Price: 200},
+	}, nil)
+	service := NewAdvertisementService(adRepo, nil, nil, nil, nil,
+		config.AdExpiryConfig{}, config.DuplicateConfig{}, config.SimilarAdsConfig{})
+
+	ads, err := service.GetAll(context.Background(), 0, 0, 10, "price", "asc", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, ads, 3)
+	require.Equal(t, []int{3, 1, 2}, []int{ads[0].ID, ads[1].ID, ads[2].ID})
+	require.True(t, ads[0].IsPromoted)
+	require.False(t, ads[1].IsPromoted)
+}
+
+func TestAdvertisementService_ArchiveExpired(t *testing.T) {
+	t.Parallel()
+
+	expiryCfg := config.AdExpiryConfig{BatchSize: 2}
+	expired := func(ids ...int) []entity.Advertisement {
+		ads := make([]entity.Advertisement, 0, len(ids))
+		for _, id := range ids {
+			ads = append(ads, entity.Advertisement{ID: id, UserID: id * 10})
+		}
+		return ads
+	}
+
+	testCases := []struct {
+		name          string
+		mockSetup     func(*mock.MockAdvertisementRepository)
+		expectedTotal int
+		expectedErr   error
+	}{
+		{
+			name: "Полные пачки архивируются до неполной",
+			mockSetup: func(adRepo *mock.MockAdvertisementRepository) {
+				gomock.InOrder(
+					adRepo.EXPECT().ArchiveExpired(gomock.Any(), 2).Return(expired(1, 2), nil),
+					adRepo.EXPECT().ArchiveExpired(gomock.Any(), 2).Return(expired(3), nil),
+				)
+			},
+			expectedTotal: 3,
+		},
+		{
+			name: "Пустая пачка останавливает архивацию",
+			mockSetup: func(adRepo *mock.MockAdvertisementRepository) {
+				gomock.InOrder(
+					adRepo.EXPECT().ArchiveExpired(gomock.Any(), 2).Return(expired(1, 2), nil),
+					adRepo.EXPECT().ArchiveExpired(gomock.Any(), 2).Return(nil, nil),
+				)
+			},
+			expectedTotal: 2,
+		},
+		{
+			name: "Ошибка возвращает уже обработанное",
+			mockSetup: func(adRepo *mock.MockAdvertisementRepository) {
+				gomock.InOrder(
+					adRepo.EXPECT().ArchiveExpired(gomock.Any(), 2).Return(expired(1, 2), nil),
+					adRepo.EXPECT().ArchiveExpired(gomock.Any(), 2).
+						Return(nil, entity.NewError(entity.ErrInternal, fmt.Errorf("соединение потеряно"))),
+				)
+			},
+			expectedTotal: 2,
+			expectedErr:   entity.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			adRepo := mock.NewMockAdvertisementRepository(ctrl)
+			notifications := usecaseMock.NewMockNotificationUsecase(ctrl)
+			service := NewAdvertisementService(adRepo, nil, nil, notifications, nil,
+				expiryCfg, config.DuplicateConfig{}, config.SimilarAdsConfig{})
+
+			tc.mockSetup(adRepo)
+			notifications.EXPECT().Notify(gomock.Any(), gomock.Any(), entity.NotificationAdArchived, gomock.Any(), gomock.Any()).
+				Times(tc.expectedTotal)
+
+			total, err := service.ArchiveExpired(context.Background())
+			require.Equal(t, tc.expectedTotal, total)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
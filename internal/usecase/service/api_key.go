@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/sanitizer"
+	"github.com/sirupsen/logrus"
+)
+
+type APIKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) usecase.APIKeyUsecase {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+func (s *APIKeyService) Create(ctx context.Context, userID int, req *dto.CreateAPIKeyRequest) (*dto.APIKeyCreatedResponse, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": requestID,
+		"userID":    userID,
+	}).Info("Создание API-ключа")
+
+	name := strings.TrimSpace(sanitizer.StrictPolicy.Sanitize(req.Name))
+	if err := entity.ValidateAPIKeyName(name); err != nil {
+		return nil, err
+	}
+	if err := entity.ValidateAPIKeyScopes(req.Scopes); err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("срок действия ключа должен быть в будущем"),
+		)
+	}
+
+	count, err := s.apiKeyRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= entity.APIKeyMaxPerUser {
+		return nil, entity.NewError(
+			entity.ErrForbidden,
+			fmt.Errorf("достигнуто максимальное количество API-ключей: %d", entity.APIKeyMaxPerUser),
+		)
+	}
+
+	raw, prefix, hash, err := entity.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.apiKeyRepo.Create(ctx, &entity.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"error":     err,
+		}).Error("Ошибка при создании API-ключа")
+		return nil, err
+	}
+
+	return &dto.APIKeyCreatedResponse{
+		APIKeyResponse: apiKeyEntityToDTO(created),
+		Key:            raw,
+	}, nil
+}
+
+func (s *APIKeyService) GetByUserID(ctx context.Context, userID int) ([]dto.APIKeyResponse, error) {
+	keys, err := s.apiKeyRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		response = append(response, apiKeyEntityToDTO(&keys[i]))
+	}
+	return response, nil
+}
+
+func (s *APIKeyService) Update(ctx context.Context, userID, id int, req *dto.UpdateAPIKeyRequest) (*dto.APIKeyResponse, error) {
+	name := strings.TrimSpace(sanitizer.StrictPolicy.Sanitize(req.Name))
+	if err := entity.ValidateAPIKeyName(name); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.apiKeyRepo.UpdateName(ctx, userID, id, name)
+	if err != nil {
+		return nil, err
+	}
+
+	response := apiKeyEntityToDTO(updated)
+	return &response, nil
+}
+
+func (s *APIKeyService) Delete(ctx context.Context, userID, id int) error {
+	return s.apiKeyRepo.Delete(ctx, userID, id)
+}
+
+func apiKeyEntityToDTO(key *entity.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
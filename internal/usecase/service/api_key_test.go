@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIKeyService_Create(t *testing.T) {
+	t.Parallel()
+
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	testCases := []struct {
+		name        string
+		req         dto.CreateAPIKeyRequest
+		mockSetup   func(*mock.MockAPIKeyRepository)
+		expectedErr error
+	}{
+		{
+			name: "Ключ создается, в базе хранится только хеш",
+			req:  dto.CreateAPIKeyRequest{Name: " Импорт ", Scopes: []string{entity.ScopeAdsRead}, ExpiresAt: &future},
+			mockSetup: func(repo *mock.MockAPIKeyRepository) {
+				repo.EXPECT().CountByUserID(gomock.Any(), 1).Return(0, nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, key *entity.APIKey) (*entity.APIKey, error) {
+						require.Equal(t, 1, key.UserID)
+						require.Equal(t, "Импорт", key.Name)
+						require.Equal(t, []string{entity.ScopeAdsRead}, key.Scopes)
+						require.NotEmpty(t, key.KeyHash)
+						created := *key
+						created.ID = 5
+						return &created, nil
+					})
+			},
+		},
+		{
+			name:        "Неизвестное право",
+			req:         dto.CreateAPIKeyRequest{Name: "Импорт", Scopes: []string{"users:delete"}},
+			mockSetup:   func(*mock.MockAPIKeyRepository) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Ключ без прав",
+			req:         dto.CreateAPIKeyRequest{Name: "Импорт"},
+			mockSetup:   func(*mock.MockAPIKeyRepository) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Срок действия в прошлом",
+			req:         dto.CreateAPIKeyRequest{Name: "Импорт", Scopes: []string{entity.ScopeAdsRead}, ExpiresAt: &past},
+			mockSetup:   func(*mock.MockAPIKeyRepository) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name: "Достигнут лимит ключей",
+			req:  dto.CreateAPIKeyRequest{Name: "Импорт", Scopes: []string{entity.ScopeAdsRead}},
+			mockSetup: func(repo *mock.MockAPIKeyRepository) {
+				repo.EXPECT().CountByUserID(gomock.Any(), 1).Return(entity.APIKeyMaxPerUser, nil)
+			},
+			expectedErr: entity.ErrForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockAPIKeyRepository(ctrl)
+			tc.mockSetup(repo)
+			service := NewAPIKeyService(repo)
+
+			created, err := service.Create(context.Background(), 1, &tc.req)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, 5, created.ID)
+			require.True(t, strings.HasPrefix(created.Key, created.Prefix))
+		})
+	}
+}
+
+func TestAPIKeyService_GetByUserID(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockAPIKeyRepository(ctrl)
+	repo.EXPECT().GetByUserID(gomock.Any(), 1).Return([]entity.APIKey{
+		{ID: 1, UserID: 1, Name: "Импорт", Prefix: "mk_abc", KeyHash: []byte("hash"), Scopes: []string{entity.ScopeAdsRead}},
+		{ID: 2, UserID: 1, Name: "Выгрузка", Prefix: "mk_def", KeyHash: []byte("hash"), Scopes: []string{entity.ScopeAdsWrite}},
+	}, nil)
+
+	keys, err := NewAPIKeyService(repo).GetByUserID(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, []dto.APIKeyResponse{
+		{ID: 1, Name: "Импорт", Prefix: "mk_abc", Scopes: []string{entity.ScopeAdsRead}},
+		{ID: 2, Name: "Выгрузка", Prefix: "mk_def", Scopes: []string{entity.ScopeAdsWrite}},
+	}, keys)
+}
+
+func TestAPIKeyService_Delete(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			name: "Отзыв своего ключа",
+		},
+		{
+			name:        "Чужой или уже отозванный ключ",
+			repoErr:     entity.NewError(entity.ErrNotFound, fmt.Errorf("ключ не найден")),
+			expectedErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockAPIKeyRepository(ctrl)
+			repo.EXPECT().Delete(gomock.Any(), 1, 5).Return(tc.repoErr)
+
+			err := NewAPIKeyService(repo).Delete(context.Background(), 1, 5)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -3,50 +3,49 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
 )
 
 type AuthService struct {
 	sessionRepository repository.SessionRepository
 	userRepository    repository.UserRepository
+	apiKeyRepository  repository.APIKeyRepository
 }
 
 func NewAuthService(
 	sessionRepo repository.SessionRepository,
 	userRepo repository.UserRepository,
+	apiKeyRepo repository.APIKeyRepository,
 ) usecase.AuthUsecase {
 	return &AuthService{
 		sessionRepository: sessionRepo,
 		userRepository:    userRepo,
+		apiKeyRepository:  apiKeyRepo,
 	}
 }
 
-func (a *AuthService) EmailExists(ctx context.Context, login string) (*dto.LoginExistsResponse, error) {
-	if err := entity.ValidateLogin(login); err != nil {
+// EmailExists проверяет, занят ли email подтвержденным аккаунтом.
+func (a *AuthService) EmailExists(ctx context.Context, email string) (*dto.EmailExistsResponse, error) {
+	email, err := entity.NormalizeEmail(email)
+	if err != nil {
 		return nil, err
 	}
 
-	applicant, err := a.userRepository.GetByLogin(ctx, login)
-	if err == nil && applicant != nil {
-		return &dto.LoginExistsResponse{
-			Exists: true,
-		}, err
-	}
-
-	var e entity.Error
-	if errors.As(err, &e) && !errors.Is(e.ClientErr(), entity.ErrNotFound) {
-		return nil, err
+	_, err = a.userRepository.GetByEmail(ctx, email)
+	if err == nil {
+		return &dto.EmailExistsResponse{Exists: true}, nil
 	}
-
-	employer, err := a.userRepository.GetByLogin(ctx, login)
-	if err == nil && employer != nil {
-		return &dto.LoginExistsResponse{
-			Exists: true,
-		}, err
+	if isNotFound(err) {
+		return &dto.EmailExistsResponse{Exists: false}, nil
 	}
 
 	return nil, err
@@ -74,6 +73,37 @@ func (a *AuthService) GetUserIDBySession(ctx context.Context, session string) (i
 	return userID, nil
 }
 
+// GetPrincipalByAPIKey находит владельца API-ключа и проверяет срок действия ключа.
+// Права ключа проверяются на уровне маршрута.
+func (a *AuthService) GetPrincipalByAPIKey(ctx context.Context, key string) (*entity.Principal, error) {
+	apiKey, err := a.apiKeyRepository.GetByHash(ctx, entity.HashAPIKey(key))
+	if err != nil {
+		var e entity.Error
+		if errors.As(err, &e) && errors.Is(e.ClientErr(), entity.ErrNotFound) {
+			return nil, entity.NewError(entity.ErrUnauthorized, fmt.Errorf("недействительный API-ключ"))
+		}
+		return nil, err
+	}
+
+	if apiKey.IsExpired(time.Now()) {
+		return nil, entity.NewError(entity.ErrUnauthorized, fmt.Errorf("срок действия API-ключа истёк"))
+	}
+
+	if err := a.apiKeyRepository.TouchLastUsed(ctx, apiKey.ID); err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"keyID":     apiKey.ID,
+			"error":     err,
+		}).Warn("Не удалось обновить время использования API-ключа")
+	}
+
+	return &entity.Principal{
+		UserID: apiKey.UserID,
+		Method: entity.AuthMethodAPIKey,
+		Scopes: apiKey.Scopes,
+	}, nil
+}
+
 func (a *AuthService) CreateSession(ctx context.Context, userID int) (string, error) {
 	session, err := a.sessionRepository.CreateSession(ctx, userID)
 	if err != nil {
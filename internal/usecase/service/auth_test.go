@@ -1,12 +1,13 @@
 package service
 
 import (
-	"ResuMatch/internal/entity"
-	"ResuMatch/internal/repository/mock"
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -17,7 +18,6 @@ func TestAuthService_CreateSession(t *testing.T) {
 	testCases := []struct {
 		name        string
 		userID      int
-		role        string
 		expected    string
 		mockSetup   func(*mock.MockSessionRepository)
 		expectedErr error
@@ -25,11 +25,10 @@ func TestAuthService_CreateSession(t *testing.T) {
 		{
 			name:     "Успешное создание сессии",
 			userID:   1,
-			role:     "applicant",
 			expected: "session_token_123",
 			mockSetup: func(mockRepo *mock.MockSessionRepository) {
 				mockRepo.EXPECT().
-					CreateSession(gomock.Any(), 1, "applicant").
+					CreateSession(gomock.Any(), 1).
 					Return("session_token_123", nil)
 			},
 			expectedErr: nil,
@@ -37,18 +36,17 @@ func TestAuthService_CreateSession(t *testing.T) {
 		{
 			name:   "Ошибка при создании сессии",
 			userID: 2,
-			role:   "employer",
 			mockSetup: func(mockRepo *mock.MockSessionRepository) {
 				mockRepo.EXPECT().
-					CreateSession(gomock.Any(), 2, "employer").
+					CreateSession(gomock.Any(), 2).
 					Return("", entity.NewError(
 						entity.ErrInternal,
-						fmt.Errorf("не удалось создать сессию для пользователя с id=2, role=employer"),
+						fmt.Errorf("не удалось создать сессию для пользователя с id=2"),
 					))
 			},
 			expectedErr: entity.NewError(
 				entity.ErrInternal,
-				fmt.Errorf("не удалось создать сессию для пользователя с id=2, role=employer"),
+				fmt.Errorf("не удалось создать сессию для пользователя с id=2"),
 			),
 		},
 	}
@@ -62,11 +60,11 @@ func TestAuthService_CreateSession(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockSessRepo := mock.NewMockSessionRepository(ctrl)
-			service := NewAuthService(mockSessRepo)
+			service := NewAuthService(mockSessRepo, nil, nil)
 
 			tc.mockSetup(mockSessRepo)
 
-			result, err := service.CreateSession(context.Background(), tc.userID, tc.role)
+			result, err := service.CreateSession(context.Background(), tc.userID)
 
 			if tc.expectedErr != nil {
 				require.Error(t, err)
@@ -85,12 +83,11 @@ func TestAuthService_GetUserIDBySession(t *testing.T) {
 	t.Parallel()
 
 	testCases := []struct {
-		name         string
-		session      string
-		mockSetup    func(*mock.MockSessionRepository)
-		expectedID   int
-		expectedRole string
-		expectedErr  error
+		name        string
+		session     string
+		mockSetup   func(*mock.MockSessionRepository)
+		expectedID  int
+		expectedErr error
 	}{
 		{
 			name:    "Сессия найдена",
@@ -98,11 +95,10 @@ func TestAuthService_GetUserIDBySession(t *testing.T) {
 			mockSetup: func(repo *mock.MockSessionRepository) {
 				repo.EXPECT().
 					GetSession(gomock.Any(), "valid_token").
-					Return(1, "applicant", nil)
+					Return(1, nil)
 			},
-			expectedID:   1,
-			expectedRole: "applicant",
-			expectedErr:  nil,
+			expectedID:  1,
+			expectedErr: nil,
 		},
 		{
 			name:    "Ошибка при получении сессии",
@@ -110,13 +106,12 @@ func TestAuthService_GetUserIDBySession(t *testing.T) {
 			mockSetup: func(repo *mock.MockSessionRepository) {
 				repo.EXPECT().
 					GetSession(gomock.Any(), "invalid_token").
-					Return(-1, "", entity.NewError(
+					Return(-1, entity.NewError(
 						entity.ErrInternal,
 						fmt.Errorf("не удалось получить сессию с токеном=invalid_token"),
 					))
 			},
-			expectedID:   -1,
-			expectedRole: "",
+			expectedID: -1,
 			expectedErr: entity.NewError(
 				entity.ErrInternal,
 				fmt.Errorf("не удалось получить сессию с токеном=invalid_token"),
@@ -133,11 +128,11 @@ func TestAuthService_GetUserIDBySession(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockSessRepo := mock.NewMockSessionRepository(ctrl)
-			service := NewAuthService(mockSessRepo)
+			service := NewAuthService(mockSessRepo, nil, nil)
 
 			tc.mockSetup(mockSessRepo)
 
-			id, role, err := service.GetUserIDBySession(context.Background(), tc.session)
+			id, err := service.GetUserIDBySession(context.Background(), tc.session)
 
 			if tc.expectedErr != nil {
 				require.Error(t, err)
@@ -147,7 +142,6 @@ func TestAuthService_GetUserIDBySession(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				require.Equal(t, tc.expectedID, id)
-				require.Equal(t, tc.expectedRole, role)
 			}
 		})
 	}
@@ -199,7 +193,7 @@ func TestAuthService_Logout(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockSessRepo := mock.NewMockSessionRepository(ctrl)
-			service := NewAuthService(mockSessRepo)
+			service := NewAuthService(mockSessRepo, nil, nil)
 
 			tc.mockSetup(mockSessRepo)
 
@@ -223,17 +217,15 @@ func TestAuthService_LogoutAll(t *testing.T) {
 	testCases := []struct {
 		name        string
 		userID      int
-		role        string
 		mockSetup   func(*mock.MockSessionRepository)
 		expectedErr error
 	}{
 		{
 			name:   "Успешный выход со всех устройств",
 			userID: 10,
-			role:   "employer",
 			mockSetup: func(repo *mock.MockSessionRepository) {
 				repo.EXPECT().
-					DeleteAllSessions(gomock.Any(), 10, "employer").
+					DeleteAllSessions(gomock.Any(), 10).
 					Return(nil)
 			},
 			expectedErr: nil,
@@ -241,10 +233,9 @@ func TestAuthService_LogoutAll(t *testing.T) {
 		{
 			name:   "Ошибка при выходе со всех устройств",
 			userID: 99,
-			role:   "applicant",
 			mockSetup: func(repo *mock.MockSessionRepository) {
 				repo.EXPECT().
-					DeleteAllSessions(gomock.Any(), 99, "applicant").
+					DeleteAllSessions(gomock.Any(), 99).
 					Return(entity.NewError(
 						entity.ErrInternal,
 						fmt.Errorf("не удалось удалить активные сессии пользователя"),
@@ -266,11 +257,11 @@ func TestAuthService_LogoutAll(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockSessRepo := mock.NewMockSessionRepository(ctrl)
-			service := NewAuthService(mockSessRepo)
+			service := NewAuthService(mockSessRepo, nil, nil)
 
 			tc.mockSetup(mockSessRepo)
 
-			err := service.LogoutAll(context.Background(), tc.userID, tc.role)
+			err := service.LogoutAll(context.Background(), tc.userID)
 
 			if tc.expectedErr != nil {
 				require.Error(t, err)
@@ -283,3 +274,80 @@ func TestAuthService_LogoutAll(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthService_GetPrincipalByAPIKey(t *testing.T) {
+	t.Parallel()
+
+	const rawKey = "mk_test-key"
+	expired := time.Now().Add(-time.Hour)
+	valid := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		name        string
+		mockSetup   func(*mock.MockAPIKeyRepository)
+		expected    *entity.Principal
+		expectedErr error
+	}{
+		{
+			name: "Действующий ключ дает субъект с его правами",
+			mockSetup: func(repo *mock.MockAPIKeyRepository) {
+				repo.EXPECT().GetByHash(gomock.Any(), entity.HashAPIKey(rawKey)).Return(&entity.APIKey{
+					ID: 3, UserID: 1, Scopes: []string{entity.ScopeAdsRead}, ExpiresAt: &valid,
+				}, nil)
+				repo.EXPECT().TouchLastUsed(gomock.Any(), 3).Return(nil)
+			},
+			expected: &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey, Scopes: []string{entity.ScopeAdsRead}},
+		},
+		{
+			name: "Ошибка обновления времени использования не мешает входу",
+			mockSetup: func(repo *mock.MockAPIKeyRepository) {
+				repo.EXPECT().GetByHash(gomock.Any(), entity.HashAPIKey(rawKey)).Return(&entity.APIKey{
+					ID: 3, UserID: 1, Scopes: []string{entity.ScopeAdsWrite},
+				}, nil)
+				repo.EXPECT().TouchLastUsed(gomock.Any(), 3).
+					Return(entity.NewError(entity.ErrInternal, fmt.Errorf("соединение потеряно")))
+			},
+			expected: &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey, Scopes: []string{entity.ScopeAdsWrite}},
+		},
+		{
+			name: "Отозванный или неизвестный ключ",
+			mockSetup: func(repo *mock.MockAPIKeyRepository) {
+				repo.EXPECT().GetByHash(gomock.Any(), entity.HashAPIKey(rawKey)).
+					Return(nil, entity.NewError(entity.ErrNotFound, fmt.Errorf("ключ не найден")))
+			},
+			expectedErr: entity.ErrUnauthorized,
+		},
+		{
+			name: "Просроченный ключ",
+			mockSetup: func(repo *mock.MockAPIKeyRepository) {
+				repo.EXPECT().GetByHash(gomock.Any(), entity.HashAPIKey(rawKey)).Return(&entity.APIKey{
+					ID: 3, UserID: 1, ExpiresAt: &expired,
+				}, nil)
+			},
+			expectedErr: entity.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAPIKeyRepo := mock.NewMockAPIKeyRepository(ctrl)
+			service := NewAuthService(nil, nil, mockAPIKeyRepo)
+
+			tc.mockSetup(mockAPIKeyRepo)
+
+			principal, err := service.GetPrincipalByAPIKey(context.Background(), rawKey)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, principal)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type ContactService struct {
+	userRepo         repository.UserRepository
+	verificationRepo repository.ContactVerificationRepository
+	notifier         repository.Notifier
+	cfg              config.ContactsConfig
+}
+
+func NewContactService(
+	userRepo repository.UserRepository,
+	verificationRepo repository.ContactVerificationRepository,
+	notifier repository.Notifier,
+	cfg config.ContactsConfig,
+) usecase.ContactUsecase {
+	return &ContactService{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		notifier:         notifier,
+		cfg:              cfg,
+	}
+}
+
+// SetContact сохраняет email или телефон и отправляет на него код подтверждения.
+// Повторный вызов с тем же значением отправляет новый код.
+func (s *ContactService) SetContact(ctx context.Context, userID int, channel entity.ContactChannel, value string) error {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"channel":   channel,
+	}).Info("Изменение контакта пользователя")
+
+	value, err := entity.NormalizeContact(channel, value)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.SetContact(ctx, userID, channel, value); err != nil {
+		return err
+	}
+
+	code, err := entity.GenerateVerificationCode()
+	if err != nil {
+		return err
+	}
+
+	verification := &entity.ContactVerification{
+		Value:    value,
+		CodeHash: entity.HashVerificationCode(userID, value, code),
+	}
+	if err := s.verificationRepo.Create(ctx, userID, channel, verification, s.cfg.CodeLifetime); err != nil {
+		return err
+	}
+
+	// Код уходит на сам подтверждаемый контакт, а не на адрес для уведомлений.
+	return s.notifier.Send(ctx, &entity.OutboundMessage{
+		UserID:  userID,
+		Channel: channel,
+		To:      value,
+		Subject: "Код подтверждения",
+		Body:    fmt.Sprintf("Ваш код подтверждения: %s. Код действует %s.", code, s.cfg.CodeLifetime),
+	})
+}
+
+func (s *ContactService) VerifyContact(ctx context.Context, userID int, channel entity.ContactChannel, code string) error {
+	verification, err := s.verificationRepo.Get(ctx, userID, channel)
+	if err != nil {
+		return err
+	}
+
+	attempts, err := s.verificationRepo.IncrementAttempts(ctx, userID, channel)
+	if err != nil {
+		return err
+	}
+	if attempts > s.cfg.CodeMaxAttempts {
+		if err := s.verificationRepo.Delete(ctx, userID, channel); err != nil {
+			return err
+		}
+		return entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("превышено количество попыток, запросите новый код"),
+		)
+	}
+
+	expected := entity.HashVerificationCode(userID, verification.Value, code)
+	if subtle.ConstantTimeCompare(expected, verification.CodeHash) != 1 {
+		return entity.NewError(entity.ErrBadRequest, fmt.Errorf("неверный код подтверждения"))
+	}
+
+	if err := s.userRepo.MarkContactVerified(ctx, userID, channel, verification.Value); err != nil {
+		return err
+	}
+
+	return s.verificationRepo.Delete(ctx, userID, channel)
+}
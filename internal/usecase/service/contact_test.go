@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testContactsCfg = config.ContactsConfig{
+	CodeLifetime:    15 * time.Minute,
+	CodeMaxAttempts: 3,
+}
+
+type contactMocks struct {
+	userRepo         *mock.MockUserRepository
+	verificationRepo *mock.MockContactVerificationRepository
+	notifier         *mock.MockNotifier
+}
+
+func newTestContactService(ctrl *gomock.Controller) (*ContactService, contactMocks) {
+	m := contactMocks{
+		userRepo:         mock.NewMockUserRepository(ctrl),
+		verificationRepo: mock.NewMockContactVerificationRepository(ctrl),
+		notifier:         mock.NewMockNotifier(ctrl),
+	}
+	service := NewContactService(m.userRepo, m.verificationRepo, m.notifier, testContactsCfg).(*ContactService)
+	return service, m
+}
+
+func TestContactService_SetContact(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestContactService(ctrl)
+
+	var stored *entity.ContactVerification
+	m.userRepo.EXPECT().SetContact(gomock.Any(), 1, entity.ContactEmail, "user@example.com").Return(nil)
+	m.verificationRepo.EXPECT().Create(gomock.Any(), 1, entity.ContactEmail, gomock.Any(), testContactsCfg.CodeLifetime).
+		DoAndReturn(func(_ context.Context, _ int, _ entity.ContactChannel, v *entity.ContactVerification, _ time.Duration) error {
+			stored = v
+			return nil
+		})
+	m.notifier.EXPECT().Send(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, msg *entity.OutboundMessage) error {
+			// Код уходит на новый контакт, а в хранилище попадает только его хеш.
+			require.Equal(t, "user@example.com", msg.To)
+			code := regexp.MustCompile(`\d{6}`).FindString(msg.Body)
+			require.NotEmpty(t, code)
+			require.Equal(t, entity.HashVerificationCode(1, "user@example.com", code), stored.CodeHash)
+			return nil
+		})
+
+	require.NoError(t, service.SetContact(context.Background(), 1, entity.ContactEmail, " User@Example.com "))
+	require.Equal(t, "user@example.com", stored.Value)
+}
+
+func TestContactService_VerifyContact(t *testing.T) {
+	t.Parallel()
+
+	const (
+		userID = 1
+		value  = "user@example.com"
+		code   = "123456"
+	)
+
+	verification := &entity.ContactVerification{
+		Value:    value,
+		CodeHash: entity.HashVerificationCode(userID, value, code),
+	}
+
+	testCases := []struct {
+		name        string
+		code        string
+		mockSetup   func(contactMocks)
+		expectedErr error
+	}{
+		{
+			name: "Верный код подтверждает контакт и удаляется",
+			code: code,
+			mockSetup: func(m contactMocks) {
+				m.verificationRepo.EXPECT().Get(gomock.Any(), userID, entity.ContactEmail).Return(verification, nil)
+				m.verificationRepo.EXPECT().IncrementAttempts(gomock.Any(), userID, entity.ContactEmail).Return(1, nil)
+				m.userRepo.EXPECT().MarkContactVerified(gomock.Any(), userID, entity.ContactEmail, value).Return(nil)
+				m.verificationRepo.EXPECT().Delete(gomock.Any(), userID, entity.ContactEmail).Return(nil)
+			},
+		},
+		{
+			name: "Неверный код оставляет попытки",
+			code: "654321",
+			mockSetup: func(m contactMocks) {
+				m.verificationRepo.EXPECT().Get(gomock.Any(), userID, entity.ContactEmail).Return(verification, nil)
+				m.verificationRepo.EXPECT().IncrementAttempts(gomock.Any(), userID, entity.ContactEmail).Return(1, nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name: "Исчерпанные попытки удаляют код без проверки",
+			code: code,
+			mockSetup: func(m contactMocks) {
+				m.verificationRepo.EXPECT().Get(gomock.Any(), userID, entity.ContactEmail).Return(verification, nil)
+				m.verificationRepo.EXPECT().IncrementAttempts(gomock.Any(), userID, entity.ContactEmail).
+					Return(testContactsCfg.CodeMaxAttempts+1, nil)
+				m.verificationRepo.EXPECT().Delete(gomock.Any(), userID, entity.ContactEmail).Return(nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name: "Истекший код",
+			code: code,
+			mockSetup: func(m contactMocks) {
+				m.verificationRepo.EXPECT().Get(gomock.Any(), userID, entity.ContactEmail).
+					Return(nil, entity.NewError(entity.ErrBadRequest, fmt.Errorf("код подтверждения не найден или истек")))
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name: "Код от прежнего значения контакта не подходит",
+			code: code,
+			mockSetup: func(m contactMocks) {
+				m.verificationRepo.EXPECT().Get(gomock.Any(), userID, entity.ContactEmail).Return(&entity.ContactVerification{
+					Value:    "new@example.com",
+					CodeHash: verification.CodeHash,
+				}, nil)
+				m.verificationRepo.EXPECT().IncrementAttempts(gomock.Any(), userID, entity.ContactEmail).Return(1, nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestContactService(ctrl)
+			tc.mockSetup(m)
+
+			err := service.VerifyContact(context.Background(), userID, entity.ContactEmail, tc.code)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestContactService_VerifyContactSingleUse(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestContactService(ctrl)
+
+	verification := &entity.ContactVerification{
+		Value:    "user@example.com",
+		CodeHash: entity.HashVerificationCode(1, "user@example.com", "123456"),
+	}
+	gomock.InOrder(
+		m.verificationRepo.EXPECT().Get(gomock.Any(), 1, entity.ContactEmail).Return(verification, nil),
+		m.verificationRepo.EXPECT().IncrementAttempts(gomock.Any(), 1, entity.ContactEmail).Return(1, nil),
+		m.userRepo.EXPECT().MarkContactVerified(gomock.Any(), 1, entity.ContactEmail, "user@example.com").Return(nil),
+		m.verificationRepo.EXPECT().Delete(gomock.Any(), 1, entity.ContactEmail).Return(nil),
+		// После удаления хранилище кода уже не находит.
+		m.verificationRepo.EXPECT().Get(gomock.Any(), 1, entity.ContactEmail).
+			Return(nil, entity.NewError(entity.ErrBadRequest, fmt.Errorf("код подтверждения не найден или истек"))),
+	)
+
+	require.NoError(t, service.VerifyContact(context.Background(), 1, entity.ContactEmail, "123456"))
+	require.ErrorIs(t, service.VerifyContact(context.Background(), 1, entity.ContactEmail, "123456"), entity.ErrBadRequest)
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/sanitizer"
+	"github.com/sirupsen/logrus"
+)
+
+type ConversationService struct {
+	conversationRepo repository.ConversationRepository
+	adRepo           repository.AdvertisementRepository
+	eventRepo        repository.EventRepository
+	notifications    usecase.NotificationUsecase
+}
+
+func NewConversationService(
+	conversationRepo repository.ConversationRepository,
+	adRepo repository.AdvertisementRepository,
+	eventRepo repository.EventRepository,
+	notifications usecase.NotificationUsecase,
+) usecase.ConversationUsecase {
+	return &ConversationService{
+		conversationRepo: conversationRepo,
+		adRepo:           adRepo,
+		eventRepo:        eventRepo,
+		notifications:    notifications,
+	}
+}
+
+// Start открывает переписку с продавцом по объявлению. Повторный вызов возвращает
+// существующую переписку. Если передан текст, он отправляется первым сообщением.
+func (s *ConversationService) Start(ctx context.Context, buyerID, adID int, req *dto.StartConversationRequest) (*dto.ConversationResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"buyerID":   buyerID,
+		"adID":      adID,
+	}).Info("Начало переписки по объявлению")
+
+	ad, err := s.adRepo.GetByID(ctx, adID)
+	if err != nil {
+		return nil, err
+	}
+	if ad.UserID == buyerID {
+		return nil, entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("нельзя написать по собственному объявлению"),
+		)
+	}
+
+	conversation, err := s.conversationRepo.GetOrCreate(ctx, ad.ID, buyerID, ad.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.Message) != "" {
+		if _, err := s.send(ctx, buyerID, conversation, req.Message); err != nil {
+			return nil, err
+		}
+		if conversation, err = s.conversationRepo.GetByID(ctx, conversation.ID, buyerID); err != nil {
+			return nil, err
+		}
+	}
+
+	response := conversationEntityToDTO(conversation, buyerID)
+	return &response, nil
+}
+
+func (s *ConversationService) GetByUserID(ctx context.Context, userID, offset, limit int) ([]dto.ConversationResponse, error) {
+	conversations, err := s.conversationRepo.GetByUserID(ctx, userID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.ConversationResponse, 0, len(conversations))
+	for i := range conversations {
+		response = append(response, conversationEntityToDTO(&conversations[i], userID))
+	}
+	return response, nil
+}
+
+func (s *ConversationService) CountUnread(ctx context.Context, userID int) (*dto.UnreadCountResponse, error) {
+	unread, err := s.conversationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UnreadCountResponse{Unread: unread}, nil
+}
+
+func (s *ConversationService) GetMessages(ctx context.Context, userID, conversationID int, before int64, limit int) (*dto.MessagesPage, error) {
+	conversation, err := s.getForParticipant(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.conversationRepo.GetMessages(ctx, conversationID, before, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.MessagesPage{Messages: make([]dto.MessageResponse, 0, len(messages))}
+	for i := range messages {
+		page.Messages = append(page.Messages, messageEntityToDTO(&messages[i], conversation, userID))
+	}
+	if len(messages) == limit {
+		cursor := messages[len(messages)-1].ID
+		page.NextCursor = &cursor
+	}
+
+	return page, nil
+}
+
+func (s *ConversationService) SendMessage(ctx context.Context, userID, conversationID int, req *dto.SendMessageRequest) (*dto.MessageResponse, error) {
+	conversation, err := s.getForParticipant(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := s.send(ctx, userID, conversation, req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	response := messageEntityToDTO(msg, conversation, userID)
+	return &response, nil
+}
+
+func (s *ConversationService) send(ctx context.Context, senderID int, conversation *entity.Conversation, body string) (*entity.Message, error) {
+	if conversation.IsBlocked() {
+		return nil, entity.NewError(
+			entity.ErrForbidden,
+			fmt.Errorf("переписка заблокирована"),
+		)
+	}
+
+	body = strings.TrimSpace(sanitizer.StrictPolicy.Sanitize(body))
+	if err := entity.ValidateMessageBody(body); err != nil {
+		return nil, err
+	}
+
+	msg, err := s.conversationRepo.CreateMessage(ctx, &entity.Message{
+		ConversationID: conversation.ID,
+		SenderID:       senderID,
+		Body:           body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Отправителю тоже: сообщение появится в его других вкладках.
+	for _, userID := range []int{conversation.BuyerID, conversation.SellerID} {
+		s.publish(ctx, userID, entity.EventMessageNew, messageEntityToDTO(msg, conversation, userID))
+	}
+
+	// Одно непрочитанное уведомление на переписку, а не на каждое сообщение.
+	recipientID, _ := conversation.Counterpart(senderID)
+	s.notifications.Notify(ctx, recipientID, entity.NotificationNewMessage, dto.NewMessageNotification{
+		ConversationID: conversation.ID,
+		SenderID:       senderID,
+		Preview:        messagePreview(msg.Body),
+	}, fmt.Sprintf("conversation:%d", conversation.ID))
+
+	return msg, nil
+}
+
+func (s *ConversationService) MarkRead(ctx context.Context, userID, conversationID int, req *dto.MarkReadRequest) error {
+	conversation, err := s.getForParticipant(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+
+	upTo := req.MessageID
+	if upTo <= 0 {
+		upTo = math.MaxInt64
+	}
+	lastReadID, err := s.conversationRepo.MarkRead(ctx, conversationID, userID, upTo)
+	if err != nil {
+		return err
+	}
+
+	if lastReadID > conversation.LastReadID(userID) {
+		counterpartID, _ := conversation.Counterpart(userID)
+		s.publish(ctx, counterpartID, entity.EventMessageRead, dto.MessageReadEvent{
+			ConversationID: conversationID,
+			ReaderID:       userID,
+			LastReadID:     lastReadID,
+		})
+	}
+
+	return nil
+}
+
+// Block запрещает обоим участникам отправлять сообщения. Снять блокировку может только тот, кто ее поставил.
+func (s *ConversationService) Block(ctx context.Context, userID, conversationID int) error {
+	conversation, err := s.getForParticipant(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+	if conversation.IsBlocked() {
+		return entity.NewError(
+			entity.ErrAlreadyExists,
+			fmt.Errorf("переписка уже заблокирована"),
+		)
+	}
+
+	return s.conversationRepo.SetBlockedBy(ctx, conversationID, &userID)
+}
+
+func (s *ConversationService) Unblock(ctx context.Context, userID, conversationID int) error {
+	conversation, err := s.getForParticipant(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+	if !conversation.IsBlocked() {
+		return nil
+	}
+	if *conversation.BlockedBy != userID {
+		return entity.NewError(
+			entity.ErrForbidden,
+			fmt.Errorf("снять блокировку может только заблокировавший участник"),
+		)
+	}
+
+	return s.conversationRepo.SetBlockedBy(ctx, conversationID, nil)
+}
+
+// publish не возвращает ошибку: сообщение уже сохранено, а клиент без потока событий
+// увидит изменения при следующем запросе.
+func (s *ConversationService) publish(ctx context.Context, userID int, eventType entity.EventType, data any) {
+	event, err := entity.NewEvent(userID, eventType, data)
+	if err == nil {
+		err = s.eventRepo.Publish(ctx, event)
+	}
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"userID":    userID,
+			"type":      eventType,
+			"error":     err,
+		}).Error("Не удалось опубликовать событие")
+	}
+}
+
+// getForParticipant скрывает чужие переписки так же, как несуществующие.
+func (s *ConversationService) getForParticipant(ctx context.Context, userID, conversationID int) (*entity.Conversation, error) {
+	conversation, err := s.conversationRepo.GetByID(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !conversation.IsParticipant(userID) {
+		return nil, entity.NewError(
+			entity.ErrNotFound,
+			fmt.Errorf("переписка с id=%d не найдена", conversationID),
+		)
+	}
+	return conversation, nil
+}
+
+func messagePreview(body string) string {
+	runes := []rune(body)
+	if len(runes) <= entity.MessagePreviewLen {
+		return body
+	}
+	return string(runes[:entity.MessagePreviewLen]) + "…"
+}
+
+func conversationEntityToDTO(c *entity.Conversation, viewerID int) dto.ConversationResponse {
+	counterpartID, counterpartLogin := c.Counterpart(viewerID)
+
+	role := "buyer"
+	if viewerID == c.SellerID {
+		role = "seller"
+	}
+
+	return dto.ConversationResponse{
+		ID:                    c.ID,
+		AdvertisementID:       c.AdvertisementID,
+		AdvertisementTitle:    c.AdvertisementTitle,
+		Role:                  role,
+		CounterpartID:         counterpartID,
+		CounterpartLogin:      counterpartLogin,
+		CounterpartLastReadID: c.CounterpartLastReadID(viewerID),
+		Unread:                c.Unread,
+		Blocked:               c.IsBlocked(),
+		BlockedByMe:           c.BlockedBy != nil && *c.BlockedBy == viewerID,
+		LastMessageAt:         c.LastMessageAt,
+		CreatedAt:             c.CreatedAt,
+	}
+}
+
+// messageEntityToDTO считает свое сообщение прочитанным, если до него дочитал второй участник,
+// а входящее — если до него дочитал сам пользователь.
+func messageEntityToDTO(msg *entity.Message, c *entity.Conversation, viewerID int) dto.MessageResponse {
+	isMine := msg.SenderID == viewerID
+
+	lastReadID := c.LastReadID(viewerID)
+	if isMine {
+		lastReadID = c.CounterpartLastReadID(viewerID)
+	}
+
+	return dto.MessageResponse{
+		ID:             msg.ID,
+		ConversationID: msg.ConversationID,
+		SenderID:       msg.SenderID,
+		Body:           msg.Body,
+		IsMine:         isMine,
+		Read:           msg.ID <= lastReadID,
+		CreatedAt:      msg.CreatedAt,
+	}
+}
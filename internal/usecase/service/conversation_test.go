@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	usecaseMock "github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testConversationID = 7
+	testBuyerID        = 3
+	testSellerID       = 2
+	testStrangerID     = 9
+)
+
+type conversationMocks struct {
+	conversationRepo *mock.MockConversationRepository
+	adRepo           *mock.MockAdvertisementRepository
+	eventRepo        *mock.MockEventRepository
+	notifications    *usecaseMock.MockNotificationUsecase
+}
+
+func newTestConversationService(ctrl *gomock.Controller) (*ConversationService, conversationMocks) {
+	m := conversationMocks{
+		conversationRepo: mock.NewMockConversationRepository(ctrl),
+		adRepo:           mock.NewMockAdvertisementRepository(ctrl),
+		eventRepo:        mock.NewMockEventRepository(ctrl),
+		notifications:    usecaseMock.NewMockNotificationUsecase(ctrl),
+	}
+	service := NewConversationService(m.conversationRepo, m.adRepo, m.eventRepo, m.notifications).(*ConversationService)
+	return service, m
+}
+
+func testConversation() *entity.Conversation {
+	return &entity.Conversation{ID: testConversationID, BuyerID: testBuyerID, SellerID: testSellerID}
+}
+
+func TestConversationService_Start(t *testing.T) {
+	t.Parallel()
+
+	ad := &entity.Advertisement{ID: 10, UserID: testSellerID}
+
+	testCases := []struct {
+		name        string
+		buyerID     int
+		req         dto.StartConversationRequest
+		mockSetup   func(conversationMocks)
+		expectedErr error
+	}{
+		{
+			name:    "Переписка без первого сообщения",
+			buyerID: testBuyerID,
+			mockSetup: func(m conversationMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), 10).Return(ad, nil)
+				m.conversationRepo.EXPECT().GetOrCreate(gomock.Any(), 10, testBuyerID, testSellerID).Return(testConversation(), nil)
+			},
+		},
+		{
+			name:    "Первое сообщение уходит продавцу",
+			buyerID: testBuyerID,
+			req:     dto.StartConversationRequest{Message: "Еще продается?"},
+			mockSetup: func(m conversationMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), 10).Return(ad, nil)
+				m.conversationRepo.EXPECT().GetOrCreate(gomock.Any(), 10, testBuyerID, testSellerID).Return(testConversation(), nil)
+				m.conversationRepo.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).
+					Return(&entity.Message{ID: 1, ConversationID: testConversationID, SenderID: testBuyerID, Body: "Еще продается?"}, nil)
+				m.eventRepo.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				m.notifications.EXPECT().Notify(gomock.Any(), testSellerID, entity.NotificationNewMessage, gomock.Any(), "conversation:7")
+				m.conversationRepo.EXPECT().GetByID(gomock.Any(), testConversationID, testBuyerID).Return(testConversation(), nil)
+			},
+		},
+		{
+			name:    "Собственное объявление",
+			buyerID: testSellerID,
+			mockSetup: func(m conversationMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), 10).Return(ad, nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestConversationService(ctrl)
+			tc.mockSetup(m)
+
+			conversation, err := service.Start(context.Background(), tc.buyerID, 10, &tc.req)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "buyer", conversation.Role)
+			require.Equal(t, testSellerID, conversation.CounterpartID)
+		})
+	}
+}
+
+func TestConversationService_ParticipantOnly(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		call func(*ConversationService) error
+	}{
+		{
+			name: "Чтение сообщений",
+			call: func(s *ConversationService) error {
+				_, err := s.GetMessages(context.Background(), testStrangerID, testConversationID, 0, 50)
+				return err
+			},
+		},
+		{
+			name: "Отправка сообщения",
+			call: func(s *ConversationService) error {
+				_, err := s.SendMessage(context.Background(), testStrangerID, testConversationID, &dto.SendMessageRequest{Body: "Привет"})
+				return err
+			},
+		},
+		{
+			name: "Отметка о прочтении",
+			call: func(s *ConversationService) error {
+				return s.MarkRead(context.Background(), testStrangerID, testConversationID, &dto.MarkReadRequest{})
+			},
+		},
+		{
+			name: "Блокировка",
+			call: func(s *ConversationService) error {
+				return s.Block(context.Background(), testStrangerID, testConversationID)
+			},
+		},
+		{
+			name: "Снятие блокировки",
+			call: func(s *ConversationService) error {
+				return s.Unblock(context.Background(), testStrangerID, testConversationID)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Чужая переписка выглядит как несуществующая, и дальше репозитория запрос не идет.
+			service, m := newTestConversationService(ctrl)
+			m.conversationRepo.EXPECT().GetByID(gomock.Any(), testConversationID, testStrangerID).Return(testConversation(), nil)
+
+			require.ErrorIs(t, tc.call(service), entity.ErrNotFound)
+		})
+	}
+}
+
+func TestConversationService_SendMessage(t *testing.T) {
+	t.Parallel()
+
+	blockedBy := testSellerID
+
+	testCases := []struct {
+		name         string
+		conversation *entity.Conversation
+		body         string
+		mockSetup    func(conversationMocks)
+		expectedErr  error
+	}{
+		{
+			name:         "Сообщение получают оба участника, уведомление — только собеседник",
+			conversation: testConversation(),
+			body:         " <b>Привет</b> ",
+			mockSetup: func(m conversationMocks) {
+				m.conversationRepo.EXPECT().CreateMessage(gomock.Any(), &entity.Message{
+					ConversationID: testConversationID,
+					SenderID:       testBuyerID,
+					Body:           "Привет",
+				}).Return(&entity.Message{ID: 11, ConversationID: testConversationID, SenderID: testBuyerID, Body: "Привет"}, nil)
+				m.eventRepo.EXPECT().Publish(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, event *entity.Event) error {
+						require.Equal(t, entity.EventMessageNew, event.Type)
+						require.Contains(t, []int{testBuyerID, testSellerID}, event.UserID)
+						return nil
+					}).Times(2)
+				m.notifications.EXPECT().Notify(gomock.Any(), testSellerID, entity.NotificationNewMessage,
+					dto.NewMessageNotification{ConversationID: testConversationID, SenderID: testBuyerID, Preview: "Привет"},
+					"conversation:7")
+			},
+		},
+		{
+			name:         "Заблокированная переписка",
+			conversation: &entity.Conversation{ID: testConversationID, BuyerID: testBuyerID, SellerID: testSellerID, BlockedBy: &blockedBy},
+			body:         "Привет",
+			mockSetup:    func(conversationMocks) {},
+			expectedErr:  entity.ErrForbidden,
+		},
+		{
+			name:         "Пустое сообщение",
+			conversation: testConversation(),
+			body:         "<script></script>",
+			mockSetup:    func(conversationMocks) {},
+			expectedErr:  entity.ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestConversationService(ctrl)
+			m.conversationRepo.EXPECT().GetByID(gomock.Any(), testConversationID, testBuyerID).Return(tc.conversation, nil)
+			tc.mockSetup(m)
+
+			msg, err := service.SendMessage(context.Background(), testBuyerID, testConversationID, &dto.SendMessageRequest{Body: tc.body})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, msg.IsMine)
+		})
+	}
+}
+
+func TestConversationService_MarkRead(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		lastReadID    int64
+		expectPublish bool
+	}{
+		{
+			name:          "Продвинутая отметка уходит собеседнику",
+			lastReadID:    15,
+			expectPublish: true,
+		},
+		{
+			name:       "Отметка не изменилась",
+			lastReadID: 10,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestConversationService(ctrl)
+			conversation := testConversation()
+			conversation.BuyerLastReadID = 10
+			m.conversationRepo.EXPECT().GetByID(gomock.Any(), testConversationID, testBuyerID).Return(conversation, nil)
+			m.conversationRepo.EXPECT().MarkRead(gomock.Any(), testConversationID, testBuyerID, int64(15)).Return(tc.lastReadID, nil)
+			if tc.expectPublish {
+				m.eventRepo.EXPECT().Publish(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, event *entity.Event) error {
+						require.Equal(t, entity.EventMessageRead, event.Type)
+						require.Equal(t, testSellerID, event.UserID)
+						return nil
+					})
+			}
+
+			require.NoError(t, service.MarkRead(context.Background(), testBuyerID, testConversationID, &dto.MarkReadRequest{MessageID: 15}))
+		})
+	}
+}
+
+func TestConversationService_Unblock(t *testing.T) {
+	t.Parallel()
+
+	blockedBySeller := testSellerID
+
+	testCases := []struct {
+		name      string
+		blockedBy *int
+		expectSet bool
+	}{
+		{
+			name:      "Снимает заблокировавший",
+			blockedBy: &blockedBySeller,
+			expectSet: true,
+		},
+		{
+			name: "Переписка не заблокирована",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestConversationService(ctrl)
+			conversation := testConversation()
+			conversation.BlockedBy = tc.blockedBy
+			m.conversationRepo.EXPECT().GetByID(gomock.Any(), testConversationID, testSellerID).Return(conversation, nil)
+			if tc.expectSet {
+				m.conversationRepo.EXPECT().SetBlockedBy(gomock.Any(), testConversationID, nil).Return(nil)
+			}
+
+			require.NoError(t, service.Unblock(context.Background(), testSellerID, testConversationID))
+		})
+	}
+
+	t.Run("Снять может только заблокировавший", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, m := newTestConversationService(ctrl)
+		conversation := testConversation()
+		conversation.BlockedBy = &blockedBySeller
+		m.conversationRepo.EXPECT().GetByID(gomock.Any(), testConversationID, testBuyerID).Return(conversation, nil)
+
+		require.ErrorIs(t, service.Unblock(context.Background(), testBuyerID, testConversationID), entity.ErrForbidden)
+	})
+}
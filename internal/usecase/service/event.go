@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type subscriber struct {
+	events chan *entity.Event
+}
+
+// EventService раздает события из общей шины потокам, открытым на этом экземпляре.
+type EventService struct {
+	eventRepo repository.EventRepository
+	cfg       config.StreamConfig
+
+	mu          sync.Mutex
+	subscribers map[int]map[*subscriber]struct{}
+	closed      bool
+}
+
+func NewEventService(eventRepo repository.EventRepository, cfg config.StreamConfig) usecase.EventUsecase {
+	return &EventService{
+		eventRepo:   eventRepo,
+		cfg:         cfg,
+		subscribers: make(map[int]map[*subscriber]struct{}),
+	}
+}
+
+func (s *EventService) Subscribe(userID int) (<-chan *entity.Event, func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, nil, entity.NewError(
+			entity.ErrInternal,
+			fmt.Errorf("сервер останавливается"),
+		)
+	}
+	if s.cfg.MaxConnectionsPerUser > 0 && len(s.subscribers[userID]) >= s.cfg.MaxConnectionsPerUser {
+		return nil, nil, entity.NewError(
+			entity.ErrTooManyRequests,
+			fmt.Errorf("превышено число открытых потоков событий для пользователя с id=%d", userID),
+		)
+	}
+
+	sub := &subscriber{events: make(chan *entity.Event, s.cfg.BufferSize)}
+	if s.subscribers[userID] == nil {
+		s.subscribers[userID] = make(map[*subscriber]struct{})
+	}
+	s.subscribers[userID][sub] = struct{}{}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.remove(userID, sub)
+		})
+	}
+
+	return sub.events, unsubscribe, nil
+}
+
+func (s *EventService) Run(ctx context.Context) {
+	defer s.closeAll()
+
+	for {
+		err := s.eventRepo.Subscribe(ctx, s.dispatch)
+		if ctx.Err() != nil {
+			return
+		}
+
+		logger.Log.WithFields(logrus.Fields{
+			"error": err,
+		}).Error("Подписка на события прервана, переподключение")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.ReconnectDelay):
+		}
+	}
+}
+
+// dispatch не блокируется на медленном клиенте: если его буфер заполнен,
+// поток закрывается, и клиент переподключится сам.
+func (s *EventService) dispatch(event *entity.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers[event.UserID] {
+		select {
+		case sub.events <- event:
+		default:
+			logger.Log.WithFields(logrus.Fields{
+				"userID": event.UserID,
+			}).Warn("Клиент не успевает читать события, поток закрыт")
+			s.remove(event.UserID, sub)
+		}
+	}
+}
+
+// remove вызывается под s.mu.
+func (s *EventService) remove(userID int, sub *subscriber) {
+	if _, ok := s.subscribers[userID][sub]; !ok {
+		return
+	}
+	delete(s.subscribers[userID], sub)
+	if len(s.subscribers[userID]) == 0 {
+		delete(s.subscribers, userID)
+	}
+	close(sub.events)
+}
+
+func (s *EventService) closeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for userID, subs := range s.subscribers {
+		for sub := range subs {
+			s.remove(userID, sub)
+		}
+	}
+}
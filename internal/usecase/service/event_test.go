@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testStreamCfg = config.StreamConfig{
+	BufferSize:            2,
+	MaxConnectionsPerUser: 2,
+	ReconnectDelay:        time.Millisecond,
+}
+
+func newTestEventService(ctrl *gomock.Controller) (*EventService, *mock.MockEventRepository) {
+	repo := mock.NewMockEventRepository(ctrl)
+	return NewEventService(repo, testStreamCfg).(*EventService), repo
+}
+
+func TestEventService_Dispatch(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _ := newTestEventService(ctrl)
+
+	first, unsubscribeFirst, err := service.Subscribe(1)
+	require.NoError(t, err)
+	defer unsubscribeFirst()
+	second, unsubscribeSecond, err := service.Subscribe(1)
+	require.NoError(t, err)
+	defer unsubscribeSecond()
+	other, unsubscribeOther, err := service.Subscribe(2)
+	require.NoError(t, err)
+	defer unsubscribeOther()
+
+	// Событие получают все потоки пользователя и только они.
+	event := &entity.Event{UserID: 1, Type: entity.EventMessageNew}
+	service.dispatch(event)
+
+	require.Equal(t, event, <-first)
+	require.Equal(t, event, <-second)
+	require.Empty(t, other)
+}
+
+func TestEventService_SlowClient(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _ := newTestEventService(ctrl)
+
+	events, unsubscribe, err := service.Subscribe(1)
+	require.NoError(t, err)
+
+	for i := 0; i <= testStreamCfg.BufferSize; i++ {
+		service.dispatch(&entity.Event{UserID: 1, Type: entity.EventMessageNew})
+	}
+
+	// Буфер вычитывается, после чего канал оказывается закрыт.
+	for i := 0; i < testStreamCfg.BufferSize; i++ {
+		<-events
+	}
+	_, ok := <-events
+	require.False(t, ok)
+
+	// Повторная отписка после закрытия сервером безопасна.
+	unsubscribe()
+	unsubscribe()
+}
+
+func TestEventService_MaxConnectionsPerUser(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _ := newTestEventService(ctrl)
+
+	unsubscribes := make([]func(), 0, testStreamCfg.MaxConnectionsPerUser)
+	for i := 0; i < testStreamCfg.MaxConnectionsPerUser; i++ {
+		_, unsubscribe, err := service.Subscribe(1)
+		require.NoError(t, err)
+		unsubscribes = append(unsubscribes, unsubscribe)
+	}
+
+	_, _, err := service.Subscribe(1)
+	require.ErrorIs(t, err, entity.ErrTooManyRequests)
+
+	// Закрытый поток освобождает место.
+	unsubscribes[0]()
+	_, _, err = service.Subscribe(1)
+	require.NoError(t, err)
+}
+
+func TestEventService_Run(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, repo := newTestEventService(ctrl)
+
+	events, _, err := service.Subscribe(1)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	gomock.InOrder(
+		// Обрыв соединения с шиной приводит к переподключению.
+		repo.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(errors.New("connection reset")),
+		repo.EXPECT().Subscribe(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, handle func(*entity.Event)) error {
+				handle(&entity.Event{UserID: 1, Type: entity.EventNotificationNew})
+				cancel()
+				<-ctx.Done()
+				return nil
+			}),
+	)
+
+	service.Run(ctx)
+
+	event, ok := <-events
+	require.True(t, ok)
+	require.Equal(t, entity.EventNotificationNew, event.Type)
+
+	// При остановке все потоки закрываются, а новые не открываются.
+	_, ok = <-events
+	require.False(t, ok)
+	_, _, err = service.Subscribe(1)
+	require.ErrorIs(t, err, entity.ErrInternal)
+}
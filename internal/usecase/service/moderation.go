@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type ModerationService struct {
+	adRepo        repository.AdvertisementRepository
+	userRepo      repository.UserRepository
+	notifications usecase.NotificationUsecase
+	duplicates    config.DuplicateConfig
+}
+
+func NewModerationService(
+	adRepo repository.AdvertisementRepository,
+	userRepo repository.UserRepository,
+	notifications usecase.NotificationUsecase,
+	duplicates config.DuplicateConfig,
+) usecase.ModerationUsecase {
+	return &ModerationService{
+		adRepo:        adRepo,
+		userRepo:      userRepo,
+		notifications: notifications,
+		duplicates:    duplicates,
+	}
+}
+
+func (s *ModerationService) GetDuplicates(ctx context.Context, userID, offset, limit int) ([]dto.DuplicateAdResponse, error) {
+	if err := s.checkModerator(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	ads, err := s.adRepo.GetDuplicates(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.DuplicateAdResponse, 0, len(ads))
+	for _, ad := range ads {
+		item := dto.DuplicateAdResponse{
+			ID:          ad.ID,
+			UserID:      ad.UserID,
+			AuthorLogin: ad.AuthorLogin,
+			Title:       ad.Title,
+			ImageURL:    ad.ImageURL,
+			Price:       ad.Price,
+			Status:      string(ad.Status),
+			CreatedAt:   ad.CreatedAt,
+		}
+		if ad.DuplicateOf != nil {
+			item.DuplicateOf = *ad.DuplicateOf
+		}
+		response = append(response, item)
+	}
+	return response, nil
+}
+
+// GetSimilar ищет среди активных объявлений всех авторов похожие на объявление adID.
+// Отпечаток считается заново, поэтому работает и для объявлений, созданных до его появления.
+func (s *ModerationService) GetSimilar(ctx context.Context, userID, adID int) ([]dto.SimilarAdResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"adID":      adID,
+	}).Info("Поиск похожих объявлений модератором")
+
+	if err := s.checkModerator(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	ad, err := s.adRepo.GetByID(ctx, adID)
+	if err != nil {
+		return nil, err
+	}
+
+	similar, err := s.adRepo.FindSimilar(ctx,
+		entity.NewAdFingerprint(ad.Title, ad.Description, ad.ImageURL),
+		entity.SimilarAdsFilter{
+			ExcludeID:   adID,
+			MaxDistance: s.duplicates.MaxDistance,
+			Limit:       entity.SimilarAdsLimit,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.SimilarAdResponse, 0, len(similar))
+	for i := range similar {
+		response = append(response, dto.SimilarAdResponse{
+			ID:            similar[i].ID,
+			UserID:        similar[i].UserID,
+			AuthorLogin:   similar[i].AuthorLogin,
+			Title:         similar[i].Title,
+			ImageURL:      similar[i].ImageURL,
+			Price:         similar[i].Price,
+			Status:        string(similar[i].Status),
+			Distance:      similar[i].Distance,
+			SameImage:     similar[i].SameImage,
+			NearDuplicate: similar[i].IsNearDuplicate(s.duplicates.MaxDistance),
+			CreatedAt:     similar[i].CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+func (s *ModerationService) GetPending(ctx context.Context, userID, offset, limit int) ([]dto.PendingAdResponse, error) {
+	if err := s.checkModerator(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	ads, err := s.adRepo.GetPendingModeration(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.PendingAdResponse, 0, len(ads))
+	for _, ad := range ads {
+		response = append(response, dto.PendingAdResponse{
+			ID:             ad.ID,
+			UserID:         ad.UserID,
+			AuthorLogin:    ad.AuthorLogin,
+			Title:          ad.Title,
+			Description:    ad.Description,
+			ImageURL:       ad.ImageURL,
+			Price:          ad.Price,
+			Status:         string(ad.Status),
+			ModerationNote: ad.ModerationNote,
+			CreatedAt:      ad.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+// Approve публикует объявление в ленте и сообщает автору, как при обычной публикации.
+func (s *ModerationService) Approve(ctx context.Context, userID, adID int) error {
+	return s.moderate(ctx, userID, adID, entity.ModerationApproved, entity.NotificationAdPublished)
+}
+
+// Reject оставляет объявление скрытым и сообщает автору об отказе.
+func (s *ModerationService) Reject(ctx context.Context, userID, adID int) error {
+	return s.moderate(ctx, userID, adID, entity.ModerationRejected, entity.NotificationAdRejected)
+}
+
+func (s *ModerationService) moderate(
+	ctx context.Context,
+	userID, adID int,
+	status entity.ModerationStatus,
+	notificationType entity.NotificationType,
+) error {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"adID":      adID,
+		"status":    status,
+	}).Info("Решение модератора по объявлению")
+
+	if err := s.checkModerator(ctx, userID); err != nil {
+		return err
+	}
+
+	ad, err := s.adRepo.Moderate(ctx, adID, status)
+	if err != nil {
+		return err
+	}
+
+	s.notifications.Notify(ctx, ad.UserID, notificationType, dto.AdPublishedNotification{
+		AdvertisementID: ad.ID,
+		Title:           ad.Title,
+	}, "")
+	return nil
+}
+
+func (s *ModerationService) checkModerator(ctx context.Context, userID int) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsModerator {
+		return entity.NewError(
+			entity.ErrForbidden,
+			fmt.Errorf("пользователь id=%d не модератор", userID),
+		)
+	}
+	return nil
+}
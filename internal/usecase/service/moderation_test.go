@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	usecaseMock "github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testModeratorID = 1
+	testRegularID   = 2
+)
+
+var testDuplicatesCfg = config.DuplicateConfig{MaxDistance: 3}
+
+type moderationMocks struct {
+	adRepo        *mock.MockAdvertisementRepository
+	userRepo      *mock.MockUserRepository
+	notifications *usecaseMock.MockNotificationUsecase
+}
+
+func newTestModerationService(ctrl *gomock.Controller) (*ModerationService, moderationMocks) {
+	m := moderationMocks{
+		adRepo:        mock.NewMockAdvertisementRepository(ctrl),
+		userRepo:      mock.NewMockUserRepository(ctrl),
+		notifications: usecaseMock.NewMockNotificationUsecase(ctrl),
+	}
+	m.userRepo.EXPECT().GetByID(gomock.Any(), testModeratorID).
+		Return(&entity.User{ID: testModeratorID, IsModerator: true}, nil).AnyTimes()
+	m.userRepo.EXPECT().GetByID(gomock.Any(), testRegularID).
+		Return(&entity.User{ID: testRegularID}, nil).AnyTimes()
+
+	service := NewModerationService(m.adRepo, m.userRepo, m.notifications, testDuplicatesCfg).(*ModerationService)
+	return service, m
+}
+
+func TestModerationService_GetDuplicates(t *testing.T) {
+	t.Parallel()
+
+	originalID := 5
+
+	testCases := []struct {
+		name        string
+		userID      int
+		mockSetup   func(moderationMocks)
+		expectedErr error
+	}{
+		{
+			name:   "Модератор видит дубликаты со ссылкой на оригинал",
+			userID: testModeratorID,
+			mockSetup: func(m moderationMocks) {
+				m.adRepo.EXPECT().GetDuplicates(gomock.Any(), 0, 20).
+					Return([]entity.Advertisement{{ID: 9, UserID: 3, DuplicateOf: &originalID}}, nil)
+			},
+		},
+		{
+			name:        "Обычный пользователь",
+			userID:      testRegularID,
+			mockSetup:   func(moderationMocks) {},
+			expectedErr: entity.ErrForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestModerationService(ctrl)
+			tc.mockSetup(m)
+
+			duplicates, err := service.GetDuplicates(context.Background(), tc.userID, 0, 20)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, duplicates, 1)
+			require.Equal(t, originalID, duplicates[0].DuplicateOf)
+		})
+	}
+}
+
+func TestModerationService_GetSimilar(t *testing.T) {
+	t.Parallel()
+
+	ad := &entity.Advertisement{ID: 10, UserID: 3, Title: "Велосипед", Description: "Горный, 21 скорость", ImageURL: "https://img/1.jpg"}
+
+	testCases := []struct {
+		name        string
+		userID      int
+		mockSetup   func(moderationMocks)
+		expectedErr error
+	}{
+		{
+			name:   "Поиск по всем авторам без самого объявления",
+			userID: testModeratorID,
+			mockSetup: func(m moderationMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), 10).Return(ad, nil)
+				m.adRepo.EXPECT().FindSimilar(gomock.Any(),
+					entity.NewAdFingerprint(ad.Title, ad.Description, ad.ImageURL),
+					entity.SimilarAdsFilter{ExcludeID: 10, MaxDistance: 3, Limit: entity.SimilarAdsLimit},
+				).Return([]entity.SimilarAd{
+					{Advertisement: entity.Advertisement{ID: 11}, Distance: 2},
+					{Advertisement: entity.Advertisement{ID: 12}, Distance: 5, SameImage: true},
+					{Advertisement: entity.Advertisement{ID: 13}, Distance: 8},
+				}, nil)
+			},
+		},
+		{
+			name:        "Обычный пользователь",
+			userID:      testRegularID,
+			mockSetup:   func(moderationMocks) {},
+			expectedErr: entity.ErrForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestModerationService(ctrl)
+			tc.mockSetup(m)
+
+			similar, err := service.GetSimilar(context.Background(), tc.userID, 10)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			nearDuplicates := make([]bool, 0, len(similar))
+			for _, ad := range similar {
+				nearDuplicates = append(nearDuplicates, ad.NearDuplicate)
+			}
+			require.Equal(t, []bool{true, true, false}, nearDuplicates)
+		})
+	}
+}
+
+func TestModerationService_GetPending(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		userID      int
+		mockSetup   func(moderationMocks)
+		expectedErr error
+	}{
+		{
+			name:   "Модератор видит очередь с причиной отправки на проверку",
+			userID: testModeratorID,
+			mockSetup: func(m moderationMocks) {
+				m.adRepo.EXPECT().GetPendingModeration(gomock.Any(), 0, 20).
+					Return([]entity.Advertisement{{ID: 10, ModerationNote: "запрещенное слово"}}, nil)
+			},
+		},
+		{
+			name:        "Обычный пользователь",
+			userID:      testRegularID,
+			mockSetup:   func(moderationMocks) {},
+			expectedErr: entity.ErrForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestModerationService(ctrl)
+			tc.mockSetup(m)
+
+			pending, err := service.GetPending(context.Background(), tc.userID, 0, 20)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, pending, 1)
+			require.Equal(t, "запрещенное слово", pending[0].ModerationNote)
+		})
+	}
+}
+
+func TestModerationService_Decide(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		userID       int
+		decide       func(*ModerationService, context.Context, int, int) error
+		status       entity.ModerationStatus
+		notification entity.NotificationType
+		expectedErr  error
+	}{
+		{
+			name:         "Одобрение публикует объявление",
+			userID:       testModeratorID,
+			decide:       (*ModerationService).Approve,
+			status:       entity.ModerationApproved,
+			notification: entity.NotificationAdPublished,
+		},
+		{
+			name:         "Отклонение сообщает автору",
+			userID:       testModeratorID,
+			decide:       (*ModerationService).Reject,
+			status:       entity.ModerationRejected,
+			notification: entity.NotificationAdRejected,
+		},
+		{
+			name:        "Одобрить может только модератор",
+			userID:      testRegularID,
+			decide:      (*ModerationService).Approve,
+			expectedErr: entity.ErrForbidden,
+		},
+		{
+			name:        "Отклонить может только модератор",
+			userID:      testRegularID,
+			decide:      (*ModerationService).Reject,
+			expectedErr: entity.ErrForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestModerationService(ctrl)
+			if tc.expectedErr == nil {
+				m.adRepo.EXPECT().Moderate(gomock.Any(), 10, tc.status).
+					Return(&entity.Advertisement{ID: 10, UserID: 3, Title: "Велосипед"}, nil)
+				m.notifications.EXPECT().Notify(gomock.Any(), 3, tc.notification,
+					dto.AdPublishedNotification{AdvertisementID: 10, Title: "Велосипед"}, "")
+			}
+
+			err := tc.decide(service, context.Background(), tc.userID, 10)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type NotificationService struct {
+	notificationRepo repository.NotificationRepository
+	eventRepo        repository.EventRepository
+}
+
+func NewNotificationService(
+	notificationRepo repository.NotificationRepository,
+	eventRepo repository.EventRepository,
+) usecase.NotificationUsecase {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		eventRepo:        eventRepo,
+	}
+}
+
+func (s *NotificationService) Notify(ctx context.Context, userID int, notificationType entity.NotificationType, data any, dedupKey string) {
+	log := logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"type":      notificationType,
+	})
+
+	notification, err := entity.NewNotification(userID, notificationType, data, dedupKey)
+	if err != nil {
+		log.WithField("error", err).Error("Не удалось подготовить уведомление")
+		return
+	}
+
+	created, err := s.notificationRepo.Create(ctx, notification)
+	if err != nil {
+		log.WithField("error", err).Error("Не удалось сохранить уведомление")
+		return
+	}
+	if created == nil {
+		// Тип отключен пользователем или уже есть такое же непрочитанное уведомление.
+		return
+	}
+
+	event, err := entity.NewEvent(userID, entity.EventNotificationNew, notificationEntityToDTO(created))
+	if err == nil {
+		err = s.eventRepo.Publish(ctx, event)
+	}
+	if err != nil {
+		log.WithField("error", err).Error("Не удалось отправить уведомление в поток событий")
+	}
+}
+
+func (s *NotificationService) GetByUserID(ctx context.Context, userID int, unreadOnly bool, offset, limit int) ([]dto.NotificationResponse, error) {
+	notifications, err := s.notificationRepo.GetByUserID(ctx, userID, unreadOnly, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.NotificationResponse, 0, len(notifications))
+	for i := range notifications {
+		response = append(response, notificationEntityToDTO(&notifications[i]))
+	}
+	return response, nil
+}
+
+func (s *NotificationService) CountUnread(ctx context.Context, userID int) (*dto.UnreadCountResponse, error) {
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UnreadCountResponse{Unread: unread}, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID int, id int64) error {
+	return s.notificationRepo.MarkRead(ctx, userID, id)
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int) error {
+	return s.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+func (s *NotificationService) GetPreferences(ctx context.Context, userID int) ([]dto.NotificationPreference, error) {
+	stored, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make([]dto.NotificationPreference, 0, len(entity.NotificationTypes))
+	for _, notificationType := range entity.NotificationTypes {
+		enabled, ok := stored[notificationType]
+		if !ok || !notificationType.Configurable() {
+			enabled = true
+		}
+		preferences = append(preferences, dto.NotificationPreference{
+			Type:         string(notificationType),
+			Enabled:      enabled,
+			Configurable: notificationType.Configurable(),
+		})
+	}
+	return preferences, nil
+}
+
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID int, req *dto.UpdateNotificationPreferencesRequest) ([]dto.NotificationPreference, error) {
+	preferences := make(map[entity.NotificationType]bool, len(req.Preferences))
+	for name, enabled := range req.Preferences {
+		notificationType := entity.NotificationType(name)
+		if !notificationType.Valid() {
+			return nil, entity.NewError(
+				entity.ErrBadRequest,
+				fmt.Errorf("неизвестный тип уведомления %q", name),
+			)
+		}
+		if !notificationType.Configurable() && !enabled {
+			return nil, entity.NewError(
+				entity.ErrBadRequest,
+				fmt.Errorf("уведомления %q нельзя отключить", name),
+			)
+		}
+		preferences[notificationType] = enabled
+	}
+
+	if err := s.notificationRepo.SetPreferences(ctx, userID, preferences); err != nil {
+		return nil, err
+	}
+
+	return s.GetPreferences(ctx, userID)
+}
+
+func notificationEntityToDTO(n *entity.Notification) dto.NotificationResponse {
+	return dto.NotificationResponse{
+		ID:        n.ID,
+		Type:      string(n.Type),
+		Data:      n.Data,
+		Read:      n.ReadAt != nil,
+		CreatedAt: n.CreatedAt,
+	}
+}
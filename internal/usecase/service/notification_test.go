@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNotificationService_Notify(t *testing.T) {
+	t.Parallel()
+
+	data := dto.NewLoginNotification{IP: "192.0.2.1"}
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		mockSetup func(*mock.MockNotificationRepository, *mock.MockEventRepository)
+	}{
+		{
+			name: "Созданное уведомление уходит в поток событий",
+			mockSetup: func(repo *mock.MockNotificationRepository, events *mock.MockEventRepository) {
+				repo.EXPECT().Create(gomock.Any(), &entity.Notification{
+					UserID:   1,
+					Type:     entity.NotificationNewLogin,
+					Data:     payload,
+					DedupKey: "dedup",
+				}).DoAndReturn(func(_ context.Context, n *entity.Notification) (*entity.Notification, error) {
+					created := *n
+					created.ID = 42
+					return &created, nil
+				})
+				events.EXPECT().Publish(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, event *entity.Event) error {
+						require.Equal(t, 1, event.UserID)
+						require.Equal(t, entity.EventNotificationNew, event.Type)
+
+						var notification dto.NotificationResponse
+						require.NoError(t, json.Unmarshal(event.Data, &notification))
+						require.EqualValues(t, 42, notification.ID)
+						require.Equal(t, string(entity.NotificationNewLogin), notification.Type)
+						require.False(t, notification.Read)
+						return nil
+					})
+			},
+		},
+		{
+			name: "Отключенный тип или дубликат не публикуется",
+			mockSetup: func(repo *mock.MockNotificationRepository, _ *mock.MockEventRepository) {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+		},
+		{
+			name: "Ошибка сохранения не публикуется",
+			mockSetup: func(repo *mock.MockNotificationRepository, _ *mock.MockEventRepository) {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrInternal, fmt.Errorf("соединение потеряно")))
+			},
+		},
+		{
+			name: "Ошибка публикации не прерывает вызывающего",
+			mockSetup: func(repo *mock.MockNotificationRepository, events *mock.MockEventRepository) {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entity.Notification{ID: 42, UserID: 1}, nil)
+				events.EXPECT().Publish(gomock.Any(), gomock.Any()).
+					Return(entity.NewError(entity.ErrInternal, fmt.Errorf("redis недоступен")))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockNotificationRepository(ctrl)
+			events := mock.NewMockEventRepository(ctrl)
+			service := NewNotificationService(repo, events)
+
+			tc.mockSetup(repo, events)
+
+			service.Notify(context.Background(), 1, entity.NotificationNewLogin, data, "dedup")
+		})
+	}
+}
+
+func TestNotificationService_UpdatePreferences(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		preferences map[string]bool
+		mockSetup   func(*mock.MockNotificationRepository)
+		expectedErr error
+	}{
+		{
+			name:        "Отключение настраиваемого типа",
+			preferences: map[string]bool{string(entity.NotificationNewMessage): false},
+			mockSetup: func(repo *mock.MockNotificationRepository) {
+				stored := map[entity.NotificationType]bool{entity.NotificationNewMessage: false}
+				repo.EXPECT().SetPreferences(gomock.Any(), 1, stored).Return(nil)
+				repo.EXPECT().GetPreferences(gomock.Any(), 1).Return(stored, nil)
+			},
+		},
+		{
+			name:        "Неизвестный тип",
+			preferences: map[string]bool{"unknown": false},
+			mockSetup:   func(*mock.MockNotificationRepository) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Уведомления безопасности нельзя отключить",
+			preferences: map[string]bool{string(entity.NotificationNewLogin): false},
+			mockSetup:   func(*mock.MockNotificationRepository) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockNotificationRepository(ctrl)
+			service := NewNotificationService(repo, nil)
+
+			tc.mockSetup(repo)
+
+			preferences, err := service.UpdatePreferences(context.Background(), 1,
+				&dto.UpdateNotificationPreferencesRequest{Preferences: tc.preferences})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, preferences, len(entity.NotificationTypes))
+			for _, p := range preferences {
+				require.Equal(t, p.Type != string(entity.NotificationNewMessage), p.Enabled, p.Type)
+			}
+		})
+	}
+}
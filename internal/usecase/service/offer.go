@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type OfferService struct {
+	offerRepo     repository.OfferRepository
+	adRepo        repository.AdvertisementRepository
+	notifications usecase.NotificationUsecase
+	cfg           config.OfferConfig
+}
+
+func NewOfferService(
+	offerRepo repository.OfferRepository,
+	adRepo repository.AdvertisementRepository,
+	notifications usecase.NotificationUsecase,
+	cfg config.OfferConfig,
+) usecase.OfferUsecase {
+	return &OfferService{
+		offerRepo:     offerRepo,
+		adRepo:        adRepo,
+		notifications: notifications,
+		cfg:           cfg,
+	}
+}
+
+func (s *OfferService) Create(ctx context.Context, buyerID, adID int, req *dto.OfferRequest) (*dto.OfferResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"buyerID":   buyerID,
+		"adID":      adID,
+	}).Info("Предложение цены по объявлению")
+
+	if err := entity.ValidateOfferAmount(req.Amount); err != nil {
+		return nil, err
+	}
+
+	ad, err := s.adRepo.GetByID(ctx, adID)
+	if err != nil {
+		return nil, err
+	}
+	if ad.UserID == buyerID {
+		return nil, entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("нельзя предложить цену за собственное объявление"),
+		)
+	}
+
+	created, err := s.offerRepo.Create(ctx, &entity.Offer{
+		AdvertisementID: ad.ID,
+		BuyerID:         buyerID,
+		SellerID:        ad.UserID,
+		ProposedBy:      buyerID,
+		Amount:          req.Amount,
+		ExpiresAt:       time.Now().Add(s.cfg.TTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, created.RecipientID(), entity.NotificationNewOffer, created)
+
+	response := offerEntityToDTO(created, buyerID)
+	return &response, nil
+}
+
+func (s *OfferService) GetByUserID(ctx context.Context, userID, offset, limit int) ([]dto.OfferResponse, error) {
+	offers, err := s.offerRepo.GetByUserID(ctx, userID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return offersToDTO(offers, userID), nil
+}
+
+func (s *OfferService) GetByAdvertisementID(ctx context.Context, userID, adID, offset, limit int) ([]dto.OfferResponse, error) {
+	ad, err := s.adRepo.GetByID(ctx, adID)
+	if err != nil {
+		return nil, err
+	}
+	if ad.UserID != userID {
+		return nil, entity.NewError(
+			entity.ErrForbidden,
+			fmt.Errorf("предложения по объявлению видит только продавец"),
+		)
+	}
+
+	offers, err := s.offerRepo.GetByAdvertisementID(ctx, adID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return offersToDTO(offers, userID), nil
+}
+
+// Accept принимает предложение. Объявление резервируется на cfg.AcceptedTTL, а остальные
+// ожидающие предложения по нему отклоняются в той же транзакции.
+func (s *OfferService) Accept(ctx context.Context, userID, id int) (*dto.OfferResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"offerID":   id,
+	}).Info("Принятие предложения")
+
+	accepted, declined, err := s.offerRepo.Accept(ctx, id, userID, time.Now().Add(s.cfg.AcceptedTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, accepted.ProposedBy, entity.NotificationOfferAnswered, accepted)
+	for i := range declined {
+		s.notify(ctx, declined[i].ProposedBy, entity.NotificationOfferAnswered, &declined[i])
+	}
+
+	response := offerEntityToDTO(accepted, userID)
+	return &response, nil
+}
+
+func (s *OfferService) Decline(ctx context.Context, userID, id int) (*dto.OfferResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"offerID":   id,
+	}).Info("Отклонение предложения")
+
+	declined, err := s.offerRepo.Decline(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, declined.ProposedBy, entity.NotificationOfferAnswered, declined)
+
+	response := offerEntityToDTO(declined, userID)
+	return &response, nil
+}
+
+func (s *OfferService) Counter(ctx context.Context, userID, id int, req *dto.OfferRequest) (*dto.OfferResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"offerID":   id,
+	}).Info("Встречное предложение")
+
+	if err := entity.ValidateOfferAmount(req.Amount); err != nil {
+		return nil, err
+	}
+
+	counter, err := s.offerRepo.Counter(ctx, id, userID, req.Amount, time.Now().Add(s.cfg.TTL))
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, counter.RecipientID(), entity.NotificationNewOffer, counter)
+
+	response := offerEntityToDTO(counter, userID)
+	return &response, nil
+}
+
+// Withdraw отменяет принятое предложение до оформления заказа: покупатель отказывается
+// от покупки, продавец снимает резерв. Объявление снова публикуется.
+func (s *OfferService) Withdraw(ctx context.Context, userID, id int) (*dto.OfferResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"offerID":   id,
+	}).Info("Отказ от принятого предложения")
+
+	withdrawn, err := s.offerRepo.Withdraw(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	counterpart := withdrawn.BuyerID
+	if userID == withdrawn.BuyerID {
+		counterpart = withdrawn.SellerID
+	}
+	s.notify(ctx, counterpart, entity.NotificationOfferAnswered, withdrawn)
+
+	response := offerEntityToDTO(withdrawn, userID)
+	return &response, nil
+}
+
+// ExpirePending закрывает предложения без ответа и снимает резерв по принятым предложениям,
+// по которым не оформили заказ за cfg.AcceptedTTL.
+func (s *OfferService) ExpirePending(ctx context.Context) (int, error) {
+	expired, err := s.offerRepo.ExpirePending(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i := range expired {
+		s.notify(ctx, expired[i].ProposedBy, entity.NotificationOfferAnswered, &expired[i])
+	}
+
+	released, err := s.offerRepo.ExpireAccepted(ctx)
+	if err != nil {
+		return len(expired), err
+	}
+	for i := range released {
+		s.notify(ctx, released[i].BuyerID, entity.NotificationOfferAnswered, &released[i])
+		s.notify(ctx, released[i].SellerID, entity.NotificationOfferAnswered, &released[i])
+	}
+
+	return len(expired) + len(released), nil
+}
+
+func (s *OfferService) notify(ctx context.Context, userID int, notificationType entity.NotificationType, offer *entity.Offer) {
+	s.notifications.Notify(ctx, userID, notificationType, dto.OfferNotification{
+		OfferID:         offer.ID,
+		AdvertisementID: offer.AdvertisementID,
+		Title:           offer.AdvertisementTitle,
+		Amount:          offer.Amount,
+		Status:          string(offer.Status),
+	}, "")
+}
+
+func offersToDTO(offers []entity.Offer, viewerID int) []dto.OfferResponse {
+	response := make([]dto.OfferResponse, 0, len(offers))
+	for i := range offers {
+		response = append(response, offerEntityToDTO(&offers[i], viewerID))
+	}
+	return response
+}
+
+func offerEntityToDTO(o *entity.Offer, viewerID int) dto.OfferResponse {
+	role := "buyer"
+	if viewerID == o.SellerID {
+		role = "seller"
+	}
+
+	return dto.OfferResponse{
+		ID:                 o.ID,
+		AdvertisementID:    o.AdvertisementID,
+		AdvertisementTitle: o.AdvertisementTitle,
+		BuyerID:            o.BuyerID,
+		SellerID:           o.SellerID,
+		ParentID:           o.ParentID,
+		ProposedBy:         o.ProposedBy,
+		Role:               role,
+		Amount:             o.Amount,
+		Status:             string(o.Status),
+		CanRespond:         o.CanRespond(viewerID, time.Now()) == nil,
+		CanWithdraw:        o.CanWithdraw(viewerID) == nil,
+		ExpiresAt:          o.ExpiresAt,
+		RespondedAt:        o.RespondedAt,
+		CreatedAt:          o.CreatedAt,
+		UpdatedAt:          o.UpdatedAt,
+	}
+}
@@ -0,0 +1,374 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	usecaseMock "github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testOfferBuyerID  = 1
+	testOfferSellerID = 2
+)
+
+var testOfferCfg = config.OfferConfig{TTL: 48 * time.Hour, AcceptedTTL: 72 * time.Hour}
+
+type offerMocks struct {
+	offerRepo     *mock.MockOfferRepository
+	adRepo        *mock.MockAdvertisementRepository
+	notifications *usecaseMock.MockNotificationUsecase
+}
+
+func newTestOfferService(ctrl *gomock.Controller) (*OfferService, offerMocks) {
+	m := offerMocks{
+		offerRepo:     mock.NewMockOfferRepository(ctrl),
+		adRepo:        mock.NewMockAdvertisementRepository(ctrl),
+		notifications: usecaseMock.NewMockNotificationUsecase(ctrl),
+	}
+	service := NewOfferService(m.offerRepo, m.adRepo, m.notifications, testOfferCfg).(*OfferService)
+	return service, m
+}
+
+func testOffer(id, proposedBy int, status entity.OfferStatus) entity.Offer {
+	return entity.Offer{
+		ID:              id,
+		AdvertisementID: 10,
+		BuyerID:         testOfferBuyerID,
+		SellerID:        testOfferSellerID,
+		ProposedBy:      proposedBy,
+		Amount:          900,
+		Status:          status,
+		ExpiresAt:       time.Now().Add(testOfferCfg.TTL),
+	}
+}
+
+// expectOfferNotification ждет уведомление участнику userID о предложении с заданным статусом.
+func expectOfferNotification(t *testing.T, m offerMocks, userID int, notificationType entity.NotificationType, offerID int, status entity.OfferStatus) {
+	m.notifications.EXPECT().Notify(gomock.Any(), userID, notificationType, gomock.Any(), "").
+		Do(func(_ context.Context, _ int, _ entity.NotificationType, data any, _ string) {
+			require.IsType(t, dto.OfferNotification{}, data)
+			notification := data.(dto.OfferNotification)
+			require.Equal(t, offerID, notification.OfferID)
+			require.Equal(t, string(status), notification.Status)
+		})
+}
+
+func TestOfferService_Create(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		amount      float64
+		mockSetup   func(*testing.T, offerMocks)
+		expectedErr error
+	}{
+		{
+			name:   "Предложение создается и уходит продавцу",
+			amount: 900,
+			mockSetup: func(t *testing.T, m offerMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), 10).
+					Return(&entity.Advertisement{ID: 10, UserID: testOfferSellerID}, nil)
+				m.offerRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, offer *entity.Offer) (*entity.Offer, error) {
+						require.Equal(t, testOfferBuyerID, offer.BuyerID)
+						require.Equal(t, testOfferSellerID, offer.SellerID)
+						require.Equal(t, testOfferBuyerID, offer.ProposedBy)
+						require.WithinDuration(t, time.Now().Add(testOfferCfg.TTL), offer.ExpiresAt, time.Minute)
+						created := *offer
+						created.ID = 5
+						created.Status = entity.OfferPending
+						return &created, nil
+					})
+				expectOfferNotification(t, m, testOfferSellerID, entity.NotificationNewOffer, 5, entity.OfferPending)
+			},
+		},
+		{
+			name:        "Неположительная сумма",
+			amount:      0,
+			mockSetup:   func(*testing.T, offerMocks) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:   "Собственное объявление",
+			amount: 900,
+			mockSetup: func(t *testing.T, m offerMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), 10).
+					Return(&entity.Advertisement{ID: 10, UserID: testOfferBuyerID}, nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:   "Объявление зарезервировано",
+			amount: 900,
+			mockSetup: func(t *testing.T, m offerMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), 10).
+					Return(&entity.Advertisement{ID: 10, UserID: testOfferSellerID}, nil)
+				m.offerRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrConflict, fmt.Errorf("объявление зарезервировано")))
+			},
+			expectedErr: entity.ErrConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestOfferService(ctrl)
+			tc.mockSetup(t, m)
+
+			offer, err := service.Create(context.Background(), testOfferBuyerID, 10, &dto.OfferRequest{Amount: tc.amount})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "buyer", offer.Role)
+			require.False(t, offer.CanRespond)
+		})
+	}
+}
+
+func TestOfferService_Accept(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		mockSetup   func(*testing.T, offerMocks)
+		expectedErr error
+	}{
+		{
+			name: "Принятие уведомляет автора и отклоненных покупателей",
+			mockSetup: func(t *testing.T, m offerMocks) {
+				accepted := testOffer(5, testOfferBuyerID, entity.OfferAccepted)
+				declined := testOffer(6, 3, entity.OfferDeclined)
+				declined.BuyerID = 3
+				m.offerRepo.EXPECT().Accept(gomock.Any(), 5, testOfferSellerID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ int, orderDeadline time.Time) (*entity.Offer, []entity.Offer, error) {
+						require.WithinDuration(t, time.Now().Add(testOfferCfg.AcceptedTTL), orderDeadline, time.Minute)
+						return &accepted, []entity.Offer{declined}, nil
+					})
+				expectOfferNotification(t, m, testOfferBuyerID, entity.NotificationOfferAnswered, 5, entity.OfferAccepted)
+				expectOfferNotification(t, m, 3, entity.NotificationOfferAnswered, 6, entity.OfferDeclined)
+			},
+		},
+		{
+			name: "Объявление сняли с публикации или оно истекло",
+			mockSetup: func(t *testing.T, m offerMocks) {
+				m.offerRepo.EXPECT().Accept(gomock.Any(), 5, testOfferSellerID, gomock.Any()).
+					Return(nil, nil, entity.NewError(entity.ErrConflict, fmt.Errorf("срок публикации объявления истек")))
+			},
+			expectedErr: entity.ErrConflict,
+		},
+		{
+			name: "Свое предложение принять нельзя",
+			mockSetup: func(t *testing.T, m offerMocks) {
+				m.offerRepo.EXPECT().Accept(gomock.Any(), 5, testOfferSellerID, gomock.Any()).
+					Return(nil, nil, entity.NewError(entity.ErrForbidden, entity.NewLocalizedMessage(entity.MsgOfferOwn, nil)))
+			},
+			expectedErr: entity.ErrForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestOfferService(ctrl)
+			tc.mockSetup(t, m)
+
+			offer, err := service.Accept(context.Background(), testOfferSellerID, 5)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "seller", offer.Role)
+			require.Equal(t, string(entity.OfferAccepted), offer.Status)
+			require.True(t, offer.CanWithdraw)
+		})
+	}
+}
+
+func TestOfferService_Decline(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestOfferService(ctrl)
+
+	declined := testOffer(5, testOfferBuyerID, entity.OfferDeclined)
+	m.offerRepo.EXPECT().Decline(gomock.Any(), 5, testOfferSellerID).Return(&declined, nil)
+	expectOfferNotification(t, m, testOfferBuyerID, entity.NotificationOfferAnswered, 5, entity.OfferDeclined)
+
+	offer, err := service.Decline(context.Background(), testOfferSellerID, 5)
+	require.NoError(t, err)
+	require.Equal(t, string(entity.OfferDeclined), offer.Status)
+}
+
+func TestOfferService_Counter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		amount      float64
+		mockSetup   func(*testing.T, offerMocks)
+		expectedErr error
+	}{
+		{
+			name:   "Встречное предложение уходит покупателю",
+			amount: 950,
+			mockSetup: func(t *testing.T, m offerMocks) {
+				counter := testOffer(6, testOfferSellerID, entity.OfferPending)
+				counter.Amount = 950
+				m.offerRepo.EXPECT().Counter(gomock.Any(), 5, testOfferSellerID, 950.0, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ int, _ float64, expiresAt time.Time) (*entity.Offer, error) {
+						require.WithinDuration(t, time.Now().Add(testOfferCfg.TTL), expiresAt, time.Minute)
+						return &counter, nil
+					})
+				expectOfferNotification(t, m, testOfferBuyerID, entity.NotificationNewOffer, 6, entity.OfferPending)
+			},
+		},
+		{
+			name:        "Неположительная сумма",
+			amount:      -1,
+			mockSetup:   func(*testing.T, offerMocks) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:   "Предложение уже закрыто",
+			amount: 950,
+			mockSetup: func(t *testing.T, m offerMocks) {
+				m.offerRepo.EXPECT().Counter(gomock.Any(), 5, testOfferSellerID, 950.0, gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrConflict, entity.NewLocalizedMessage(entity.MsgOfferClosed, entity.MessageParams{"status": entity.OfferAccepted})))
+			},
+			expectedErr: entity.ErrConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestOfferService(ctrl)
+			tc.mockSetup(t, m)
+
+			offer, err := service.Counter(context.Background(), testOfferSellerID, 5, &dto.OfferRequest{Amount: tc.amount})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testOfferSellerID, offer.ProposedBy)
+			require.False(t, offer.CanRespond)
+		})
+	}
+}
+
+func TestOfferService_Withdraw(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		userID      int
+		mockSetup   func(*testing.T, offerMocks)
+		expectedErr error
+	}{
+		{
+			name:   "Продавец снимает резерв, покупатель получает уведомление",
+			userID: testOfferSellerID,
+			mockSetup: func(t *testing.T, m offerMocks) {
+				withdrawn := testOffer(5, testOfferBuyerID, entity.OfferWithdrawn)
+				m.offerRepo.EXPECT().Withdraw(gomock.Any(), 5, testOfferSellerID).Return(&withdrawn, nil)
+				expectOfferNotification(t, m, testOfferBuyerID, entity.NotificationOfferAnswered, 5, entity.OfferWithdrawn)
+			},
+		},
+		{
+			name:   "Покупатель отказывается от покупки, продавец получает уведомление",
+			userID: testOfferBuyerID,
+			mockSetup: func(t *testing.T, m offerMocks) {
+				withdrawn := testOffer(5, testOfferBuyerID, entity.OfferWithdrawn)
+				m.offerRepo.EXPECT().Withdraw(gomock.Any(), 5, testOfferBuyerID).Return(&withdrawn, nil)
+				expectOfferNotification(t, m, testOfferSellerID, entity.NotificationOfferAnswered, 5, entity.OfferWithdrawn)
+			},
+		},
+		{
+			name:   "По предложению уже оформлен заказ",
+			userID: testOfferBuyerID,
+			mockSetup: func(t *testing.T, m offerMocks) {
+				m.offerRepo.EXPECT().Withdraw(gomock.Any(), 5, testOfferBuyerID).
+					Return(nil, entity.NewError(entity.ErrConflict, fmt.Errorf("по предложению уже оформлен заказ")))
+			},
+			expectedErr: entity.ErrConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestOfferService(ctrl)
+			tc.mockSetup(t, m)
+
+			offer, err := service.Withdraw(context.Background(), tc.userID, 5)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, string(entity.OfferWithdrawn), offer.Status)
+			require.False(t, offer.CanWithdraw)
+		})
+	}
+}
+
+func TestOfferService_ExpirePending(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestOfferService(ctrl)
+
+	first := testOffer(5, testOfferBuyerID, entity.OfferExpired)
+	second := testOffer(6, testOfferSellerID, entity.OfferExpired)
+	m.offerRepo.EXPECT().ExpirePending(gomock.Any()).Return([]entity.Offer{first, second}, nil)
+	expectOfferNotification(t, m, testOfferBuyerID, entity.NotificationOfferAnswered, 5, entity.OfferExpired)
+	expectOfferNotification(t, m, testOfferSellerID, entity.NotificationOfferAnswered, 6, entity.OfferExpired)
+
+	// Резерв по принятому предложению без заказа снят: узнают оба участника.
+	overdue := testOffer(7, testOfferBuyerID, entity.OfferExpired)
+	m.offerRepo.EXPECT().ExpireAccepted(gomock.Any()).Return([]entity.Offer{overdue}, nil)
+	expectOfferNotification(t, m, testOfferBuyerID, entity.NotificationOfferAnswered, 7, entity.OfferExpired)
+	expectOfferNotification(t, m, testOfferSellerID, entity.NotificationOfferAnswered, 7, entity.OfferExpired)
+
+	expired, err := service.ExpirePending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, expired)
+}
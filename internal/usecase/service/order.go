@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type OrderService struct {
+	orderRepo     repository.OrderRepository
+	adRepo        repository.AdvertisementRepository
+	notifications usecase.NotificationUsecase
+}
+
+func NewOrderService(
+	orderRepo repository.OrderRepository,
+	adRepo repository.AdvertisementRepository,
+	notifications usecase.NotificationUsecase,
+) usecase.OrderUsecase {
+	return &OrderService{
+		orderRepo:     orderRepo,
+		adRepo:        adRepo,
+		notifications: notifications,
+	}
+}
+
+func (s *OrderService) Create(ctx context.Context, buyerID, adID int) (*dto.OrderResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"buyerID":   buyerID,
+		"adID":      adID,
+	}).Info("Оформление заказа")
+
+	ad, err := s.adRepo.GetByID(ctx, adID)
+	if err != nil {
+		return nil, err
+	}
+	if ad.UserID == buyerID {
+		return nil, entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("нельзя оформить заказ на собственное объявление"),
+		)
+	}
+
+	order, err := s.orderRepo.Create(ctx, ad.ID, buyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, order.SellerID, order)
+
+	response := orderEntityToDTO(order, buyerID)
+	return &response, nil
+}
+
+// GetByID возвращает заказ с историей. Чужие заказы для пользователя не существуют.
+func (s *OrderService) GetByID(ctx context.Context, userID, id int) (*dto.OrderResponse, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := order.Role(userID); !ok {
+		return nil, entity.NewError(
+			entity.ErrNotFound,
+			fmt.Errorf("заказ с id=%d не найден", id),
+		)
+	}
+
+	response := orderEntityToDTO(order, userID)
+	return &response, nil
+}
+
+func (s *OrderService) GetByUserID(ctx context.Context, userID, offset, limit int) ([]dto.OrderResponse, error) {
+	orders, err := s.orderRepo.GetByUserID(ctx, userID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.OrderResponse, 0, len(orders))
+	for i := range orders {
+		response = append(response, orderEntityToDTO(&orders[i], userID))
+	}
+	return response, nil
+}
+
+func (s *OrderService) UpdateStatus(ctx context.Context, userID, id int, action string) (*dto.OrderResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"orderID":   id,
+		"action":    action,
+	}).Info("Изменение статуса заказа")
+
+	to, ok := entity.ParseOrderAction(action)
+	if !ok {
+		return nil, entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("неизвестное действие с заказом %q", action),
+		)
+	}
+
+	order, err := s.orderRepo.UpdateStatus(ctx, id, userID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, order.Counterpart(userID), order)
+
+	response := orderEntityToDTO(order, userID)
+	return &response, nil
+}
+
+func (s *OrderService) notify(ctx context.Context, userID int, order *entity.Order) {
+	s.notifications.Notify(ctx, userID, entity.NotificationOrderUpdated, dto.OrderNotification{
+		OrderID:         order.ID,
+		AdvertisementID: order.AdvertisementID,
+		Title:           order.AdvertisementTitle,
+		Status:          string(order.Status),
+	}, "")
+}
+
+func orderEntityToDTO(o *entity.Order, viewerID int) dto.OrderResponse {
+	role, _ := o.Role(viewerID)
+
+	response := dto.OrderResponse{
+		ID:                 o.ID,
+		AdvertisementID:    o.AdvertisementID,
+		AdvertisementTitle: o.AdvertisementTitle,
+		BuyerID:            o.BuyerID,
+		SellerID:           o.SellerID,
+		OfferID:            o.OfferID,
+		Role:               string(role),
+		Amount:             o.Amount,
+		Status:             string(o.Status),
+		AllowedActions:     o.AllowedActions(viewerID),
+		CreatedAt:          o.CreatedAt,
+		UpdatedAt:          o.UpdatedAt,
+	}
+	for _, event := range o.History {
+		response.History = append(response.History, dto.OrderEventResponse{
+			Status:    string(event.Status),
+			ActorID:   event.ActorID,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	return response
+}
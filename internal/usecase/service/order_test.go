@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	usecaseMock "github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testOrderBuyerID  = 1
+	testOrderSellerID = 2
+)
+
+type orderMocks struct {
+	orderRepo     *mock.MockOrderRepository
+	adRepo        *mock.MockAdvertisementRepository
+	notifications *usecaseMock.MockNotificationUsecase
+}
+
+func newTestOrderService(ctrl *gomock.Controller) (*OrderService, orderMocks) {
+	m := orderMocks{
+		orderRepo:     mock.NewMockOrderRepository(ctrl),
+		adRepo:        mock.NewMockAdvertisementRepository(ctrl),
+		notifications: usecaseMock.NewMockNotificationUsecase(ctrl),
+	}
+	service := NewOrderService(m.orderRepo, m.adRepo, m.notifications).(*OrderService)
+	return service, m
+}
+
+func testOrder(status entity.OrderStatus) *entity.Order {
+	return &entity.Order{ID: 7, BuyerID: testOrderBuyerID, SellerID: testOrderSellerID, Amount: 900, Status: status}
+}
+
+func TestOrderService_Create(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		mockSetup   func(orderMocks)
+		expectedErr error
+	}{
+		{
+			name: "Заказ оформляется и уходит продавцу",
+			mockSetup: func(m orderMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), 10).
+					Return(&entity.Advertisement{ID: 10, UserID: testOrderSellerID}, nil)
+				m.orderRepo.EXPECT().Create(gomock.Any(), 10, testOrderBuyerID).Return(testOrder(entity.OrderCreated), nil)
+				m.notifications.EXPECT().Notify(gomock.Any(), testOrderSellerID, entity.NotificationOrderUpdated,
+					dto.OrderNotification{OrderID: 7, Status: string(entity.OrderCreated)}, "")
+			},
+		},
+		{
+			name: "Собственное объявление",
+			mockSetup: func(m orderMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), 10).
+					Return(&entity.Advertisement{ID: 10, UserID: testOrderBuyerID}, nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestOrderService(ctrl)
+			tc.mockSetup(m)
+
+			order, err := service.Create(context.Background(), testOrderBuyerID, 10)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, string(entity.OrderRoleBuyer), order.Role)
+			require.Equal(t, []string{"cancel"}, order.AllowedActions)
+		})
+	}
+}
+
+func TestOrderService_GetByID(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		userID      int
+		expectedErr error
+	}{
+		{
+			name:   "Продавец видит заказ",
+			userID: testOrderSellerID,
+		},
+		{
+			name:        "Чужой заказ не найден",
+			userID:      3,
+			expectedErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestOrderService(ctrl)
+			m.orderRepo.EXPECT().GetByID(gomock.Any(), 7).Return(testOrder(entity.OrderCreated), nil)
+
+			order, err := service.GetByID(context.Background(), tc.userID, 7)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{"confirm", "cancel"}, order.AllowedActions)
+		})
+	}
+}
+
+func TestOrderService_UpdateStatus(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		userID          int
+		action          string
+		mockSetup       func(orderMocks)
+		expectedStatus  entity.OrderStatus
+		expectedActions []string
+		expectedErr     error
+	}{
+		{
+			name:   "Продавец подтверждает, покупатель получает уведомление",
+			userID: testOrderSellerID,
+			action: "confirm",
+			mockSetup: func(m orderMocks) {
+				m.orderRepo.EXPECT().UpdateStatus(gomock.Any(), 7, testOrderSellerID, entity.OrderConfirmed).
+					Return(testOrder(entity.OrderConfirmed), nil)
+				m.notifications.EXPECT().Notify(gomock.Any(), testOrderBuyerID, entity.NotificationOrderUpdated,
+					dto.OrderNotification{OrderID: 7, Status: string(entity.OrderConfirmed)}, "")
+			},
+			expectedStatus:  entity.OrderConfirmed,
+			expectedActions: []string{"ship", "cancel"},
+		},
+		{
+			name:   "Покупатель оспаривает отправленный заказ",
+			userID: testOrderBuyerID,
+			action: "dispute",
+			mockSetup: func(m orderMocks) {
+				m.orderRepo.EXPECT().UpdateStatus(gomock.Any(), 7, testOrderBuyerID, entity.OrderDisputed).
+					Return(testOrder(entity.OrderDisputed), nil)
+				m.notifications.EXPECT().Notify(gomock.Any(), testOrderSellerID, entity.NotificationOrderUpdated,
+					dto.OrderNotification{OrderID: 7, Status: string(entity.OrderDisputed)}, "")
+			},
+			expectedStatus:  entity.OrderDisputed,
+			expectedActions: []string{"complete"},
+		},
+		{
+			name:        "Неизвестное действие",
+			userID:      testOrderSellerID,
+			action:      "refund",
+			mockSetup:   func(orderMocks) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:   "Недопустимый переход",
+			userID: testOrderBuyerID,
+			action: "complete",
+			mockSetup: func(m orderMocks) {
+				m.orderRepo.EXPECT().UpdateStatus(gomock.Any(), 7, testOrderBuyerID, entity.OrderCompleted).
+					Return(nil, testOrder(entity.OrderCreated).CanTransition(testOrderBuyerID, entity.OrderCompleted))
+			},
+			expectedErr: entity.ErrConflict,
+		},
+		{
+			name:   "Переход не для этой роли",
+			userID: testOrderBuyerID,
+			action: "ship",
+			mockSetup: func(m orderMocks) {
+				m.orderRepo.EXPECT().UpdateStatus(gomock.Any(), 7, testOrderBuyerID, entity.OrderShipped).
+					Return(nil, testOrder(entity.OrderConfirmed).CanTransition(testOrderBuyerID, entity.OrderShipped))
+			},
+			expectedErr: entity.ErrForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestOrderService(ctrl)
+			tc.mockSetup(m)
+
+			order, err := service.UpdateStatus(context.Background(), tc.userID, 7, tc.action)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, string(tc.expectedStatus), order.Status)
+			require.Equal(t, tc.expectedActions, order.AllowedActions)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type PasswordService struct {
+	userRepo          repository.UserRepository
+	sessionRepo       repository.SessionRepository
+	passwordResetRepo repository.PasswordResetRepository
+	loginAttemptRepo  repository.LoginAttemptRepository
+	notifier          repository.Notifier
+	notifications     usecase.NotificationUsecase
+	pepper            entity.Pepper
+	cfg               config.PasswordConfig
+}
+
+func NewPasswordService(
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	passwordResetRepo repository.PasswordResetRepository,
+	loginAttemptRepo repository.LoginAttemptRepository,
+	notifier repository.Notifier,
+	notifications usecase.NotificationUsecase,
+	pepper entity.Pepper,
+	cfg config.PasswordConfig,
+) usecase.PasswordUsecase {
+	return &PasswordService{
+		userRepo:          userRepo,
+		sessionRepo:       sessionRepo,
+		passwordResetRepo: passwordResetRepo,
+		loginAttemptRepo:  loginAttemptRepo,
+		notifier:          notifier,
+		notifications:     notifications,
+		pepper:            pepper,
+		cfg:               cfg,
+	}
+}
+
+// ChangePassword меняет пароль и завершает все сессии пользователя, кроме текущей.
+func (s *PasswordService) ChangePassword(ctx context.Context, userID int, sessionID string, req *dto.ChangePasswordRequest) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if ok, _ := entity.CheckPassword(req.CurrentPassword, user.PasswordHash, s.pepper); !ok {
+		return entity.NewError(entity.ErrForbidden, fmt.Errorf("неверный текущий пароль"))
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("новый пароль должен отличаться от текущего"),
+		)
+	}
+
+	if err := s.setPassword(ctx, userID, req.NewPassword); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.DeleteOtherSessions(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	s.notifyPasswordChanged(ctx, user)
+	return nil
+}
+
+// RequestReset отправляет ссылку для сброса пароля. Для несуществующего логина
+// ничего не отправляется, но ответ тот же, чтобы нельзя было перебирать аккаунты.
+// Число запросов на один логин ограничено, чтобы ссылками нельзя было засыпать владельца.
+func (s *PasswordService) RequestReset(ctx context.Context, req *dto.PasswordResetRequest) error {
+	requestID := utils.GetRequestID(ctx)
+
+	if err := entity.ValidateLogin(req.Login); err != nil {
+		return err
+	}
+
+	// Лимит считается и для несуществующих логинов, иначе 429 выдавал бы существующий аккаунт.
+	if err := s.limitResetRequests(ctx, req.Login); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByLogin(ctx, req.Login)
+	if err != nil {
+		if !isNotFound(err) {
+			return err
+		}
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": requestID,
+		}).Info("Сброс пароля запрошен для неизвестного логина")
+		return nil
+	}
+
+	raw, hash, err := entity.GeneratePasswordResetToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.passwordResetRepo.CreateToken(ctx, user.ID, hash, s.cfg.ResetTokenLifetime); err != nil {
+		return err
+	}
+
+	return s.notifier.Send(ctx, entity.NewOutboundMessage(
+		user,
+		"Сброс пароля",
+		fmt.Sprintf(
+			"Для сброса пароля перейдите по ссылке: %s%s\nСсылка действует %s. Если вы не запрашивали сброс, просто проигнорируйте это сообщение.",
+			s.cfg.ResetURL, raw, s.cfg.ResetTokenLifetime,
+		),
+	))
+}
+
+// ConfirmReset устанавливает новый пароль по одноразовому токену и завершает все сессии.
+func (s *PasswordService) ConfirmReset(ctx context.Context, req *dto.PasswordResetConfirmRequest) error {
+	if err := entity.ValidatePassword(req.NewPassword); err != nil {
+		return err
+	}
+
+	userID, err := s.passwordResetRepo.ConsumeToken(ctx, entity.HashPasswordResetToken(req.Token))
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.setPassword(ctx, userID, req.NewPassword); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.DeleteAllSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	// Владелец аккаунта подтвердил доступ к каналу связи, блокировку входа можно снять.
+	if err := s.loginAttemptRepo.Reset(ctx, loginAttemptKey(user.Login)); err != nil {
+		return err
+	}
+
+	s.notifyPasswordChanged(ctx, user)
+	return nil
+}
+
+func passwordResetKey(login string) string {
+	return "reset:" + loginAttemptKey(login)
+}
+
+// limitResetRequests учитывает запрос сброса для логина и после ResetMaxRequests запросов
+// в окне ResetWindow блокирует новые запросы до конца окна.
+func (s *PasswordService) limitResetRequests(ctx context.Context, login string) error {
+	key := passwordResetKey(login)
+
+	remaining, err := s.loginAttemptRepo.GetLockout(ctx, key)
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return entity.NewError(entity.ErrTooManyRequests, entity.LockoutError{RetryAfter: remaining})
+	}
+
+	requests, err := s.loginAttemptRepo.RegisterFailure(ctx, key, s.cfg.ResetWindow)
+	if err != nil {
+		return err
+	}
+	if requests <= s.cfg.ResetMaxRequests {
+		return nil
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"key":       key,
+		"requests":  requests,
+	}).Warn("Превышено количество запросов сброса пароля")
+
+	if err := s.loginAttemptRepo.Lock(ctx, key, s.cfg.ResetWindow); err != nil {
+		return err
+	}
+	return entity.NewError(entity.ErrTooManyRequests, entity.LockoutError{RetryAfter: s.cfg.ResetWindow})
+}
+
+func (s *PasswordService) setPassword(ctx context.Context, userID int, password string) error {
+	if err := entity.ValidatePassword(password); err != nil {
+		return err
+	}
+
+	hash, err := entity.HashPassword(password, s.pepper)
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.UpdatePasswordHash(ctx, userID, hash)
+}
+
+func (s *PasswordService) notifyPasswordChanged(ctx context.Context, user *entity.User) {
+	s.notifications.Notify(ctx, user.ID, entity.NotificationPasswordChanged, struct{}{}, "")
+
+	err := s.notifier.Send(ctx, entity.NewOutboundMessage(
+		user,
+		"Пароль изменен",
+		"Пароль от вашего аккаунта был изменен. Если это были не вы, восстановите доступ через сброс пароля.",
+	))
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"userID":    user.ID,
+			"error":     err,
+		}).Error("Не удалось отправить уведомление о смене пароля")
+	}
+}
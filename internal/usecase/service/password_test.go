@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	usecaseMock "github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testPasswordCfg = config.PasswordConfig{
+	ResetTokenLifetime: 30 * time.Minute,
+	ResetURL:           "http://localhost/reset?token=",
+	ResetMaxRequests:   3,
+	ResetWindow:        time.Hour,
+}
+
+type passwordMocks struct {
+	userRepo      *mock.MockUserRepository
+	sessionRepo   *mock.MockSessionRepository
+	resetRepo     *mock.MockPasswordResetRepository
+	attempts      *mock.MockLoginAttemptRepository
+	notifier      *mock.MockNotifier
+	notifications *usecaseMock.MockNotificationUsecase
+}
+
+func newTestPasswordService(ctrl *gomock.Controller) (*PasswordService, passwordMocks) {
+	m := passwordMocks{
+		userRepo:      mock.NewMockUserRepository(ctrl),
+		sessionRepo:   mock.NewMockSessionRepository(ctrl),
+		resetRepo:     mock.NewMockPasswordResetRepository(ctrl),
+		attempts:      mock.NewMockLoginAttemptRepository(ctrl),
+		notifier:      mock.NewMockNotifier(ctrl),
+		notifications: usecaseMock.NewMockNotificationUsecase(ctrl),
+	}
+	service := NewPasswordService(
+		m.userRepo, m.sessionRepo, m.resetRepo, m.attempts, m.notifier, m.notifications,
+		entity.Pepper{}, testPasswordCfg,
+	).(*PasswordService)
+	return service, m
+}
+
+// expectPasswordChangedNotice ожидает уведомление о смене пароля в ленте и по каналу связи.
+func expectPasswordChangedNotice(m passwordMocks, userID int) {
+	m.notifications.EXPECT().Notify(gomock.Any(), userID, entity.NotificationPasswordChanged, gomock.Any(), "")
+	m.notifier.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+}
+
+func TestPasswordService_ChangePassword(t *testing.T) {
+	t.Parallel()
+
+	hash, err := entity.HashPassword("Current123", entity.Pepper{})
+	require.NoError(t, err)
+	user := &entity.User{ID: 7, Login: "user.test", PasswordHash: hash}
+
+	testCases := []struct {
+		name        string
+		req         dto.ChangePasswordRequest
+		mockSetup   func(passwordMocks)
+		expectedErr error
+	}{
+		{
+			name: "Смена пароля завершает остальные сессии",
+			req:  dto.ChangePasswordRequest{CurrentPassword: "Current123", NewPassword: "Changed123"},
+			mockSetup: func(m passwordMocks) {
+				m.userRepo.EXPECT().GetByID(gomock.Any(), 7).Return(user, nil)
+				m.userRepo.EXPECT().UpdatePasswordHash(gomock.Any(), 7, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, newHash string) error {
+						ok, _ := entity.CheckPassword("Changed123", newHash, entity.Pepper{})
+						require.True(t, ok)
+						return nil
+					})
+				m.sessionRepo.EXPECT().DeleteOtherSessions(gomock.Any(), 7, "current-session").Return(nil)
+				expectPasswordChangedNotice(m, 7)
+			},
+		},
+		{
+			name: "Неверный текущий пароль",
+			req:  dto.ChangePasswordRequest{CurrentPassword: "Wrong12345", NewPassword: "Changed123"},
+			mockSetup: func(m passwordMocks) {
+				m.userRepo.EXPECT().GetByID(gomock.Any(), 7).Return(user, nil)
+			},
+			expectedErr: entity.ErrForbidden,
+		},
+		{
+			name: "Новый пароль совпадает с текущим",
+			req:  dto.ChangePasswordRequest{CurrentPassword: "Current123", NewPassword: "Current123"},
+			mockSetup: func(m passwordMocks) {
+				m.userRepo.EXPECT().GetByID(gomock.Any(), 7).Return(user, nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name: "Слишком короткий новый пароль",
+			req:  dto.ChangePasswordRequest{CurrentPassword: "Current123", NewPassword: "short"},
+			mockSetup: func(m passwordMocks) {
+				m.userRepo.EXPECT().GetByID(gomock.Any(), 7).Return(user, nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestPasswordService(ctrl)
+			tc.mockSetup(m)
+
+			err := service.ChangePassword(context.Background(), 7, "current-session", &tc.req)
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPasswordService_RequestReset(t *testing.T) {
+	t.Parallel()
+
+	const (
+		login    = "User.Test"
+		resetKey = "reset:login:user.test"
+	)
+
+	user := &entity.User{ID: 7, Login: "user.test"}
+
+	testCases := []struct {
+		name        string
+		mockSetup   func(passwordMocks)
+		expectedErr error
+		retryAfter  time.Duration
+	}{
+		{
+			name: "Ссылка отправляется владельцу логина",
+			mockSetup: func(m passwordMocks) {
+				m.attempts.EXPECT().GetLockout(gomock.Any(), resetKey).Return(time.Duration(0), nil)
+				m.attempts.EXPECT().RegisterFailure(gomock.Any(), resetKey, testPasswordCfg.ResetWindow).Return(1, nil)
+				m.userRepo.EXPECT().GetByLogin(gomock.Any(), login).Return(user, nil)
+				m.resetRepo.EXPECT().CreateToken(gomock.Any(), 7, gomock.Any(), testPasswordCfg.ResetTokenLifetime).Return(nil)
+				m.notifier.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "Неизвестный логин учитывается в лимите, но ничего не отправляет",
+			mockSetup: func(m passwordMocks) {
+				m.attempts.EXPECT().GetLockout(gomock.Any(), resetKey).Return(time.Duration(0), nil)
+				m.attempts.EXPECT().RegisterFailure(gomock.Any(), resetKey, testPasswordCfg.ResetWindow).Return(1, nil)
+				m.userRepo.EXPECT().GetByLogin(gomock.Any(), login).
+					Return(nil, entity.NewError(entity.ErrNotFound, fmt.Errorf("не найден")))
+			},
+		},
+		{
+			name: "Последний разрешенный запрос в окне",
+			mockSetup: func(m passwordMocks) {
+				m.attempts.EXPECT().GetLockout(gomock.Any(), resetKey).Return(time.Duration(0), nil)
+				m.attempts.EXPECT().RegisterFailure(gomock.Any(), resetKey, testPasswordCfg.ResetWindow).
+					Return(testPasswordCfg.ResetMaxRequests, nil)
+				m.userRepo.EXPECT().GetByLogin(gomock.Any(), login).Return(user, nil)
+				m.resetRepo.EXPECT().CreateToken(gomock.Any(), 7, gomock.Any(), testPasswordCfg.ResetTokenLifetime).Return(nil)
+				m.notifier.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "Превышение лимита блокирует логин до конца окна",
+			mockSetup: func(m passwordMocks) {
+				m.attempts.EXPECT().GetLockout(gomock.Any(), resetKey).Return(time.Duration(0), nil)
+				m.attempts.EXPECT().RegisterFailure(gomock.Any(), resetKey, testPasswordCfg.ResetWindow).
+					Return(testPasswordCfg.ResetMaxRequests+1, nil)
+				m.attempts.EXPECT().Lock(gomock.Any(), resetKey, testPasswordCfg.ResetWindow).Return(nil)
+			},
+			expectedErr: entity.ErrTooManyRequests,
+			retryAfter:  testPasswordCfg.ResetWindow,
+		},
+		{
+			name: "Действующая блокировка не доходит до поиска пользователя",
+			mockSetup: func(m passwordMocks) {
+				m.attempts.EXPECT().GetLockout(gomock.Any(), resetKey).Return(20*time.Minute, nil)
+			},
+			expectedErr: entity.ErrTooManyRequests,
+			retryAfter:  20 * time.Minute,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestPasswordService(ctrl)
+			tc.mockSetup(m)
+
+			err := service.RequestReset(context.Background(), &dto.PasswordResetRequest{Login: login})
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, tc.expectedErr)
+			var lockoutErr entity.LockoutError
+			require.ErrorAs(t, err, &lockoutErr)
+			require.Equal(t, tc.retryAfter, lockoutErr.RetryAfter)
+		})
+	}
+}
+
+func TestPasswordService_ConfirmReset(t *testing.T) {
+	t.Parallel()
+
+	const token = "reset-token"
+
+	user := &entity.User{ID: 7, Login: "User.Test"}
+	tokenHash := entity.HashPasswordResetToken(token)
+
+	testCases := []struct {
+		name        string
+		newPassword string
+		mockSetup   func(passwordMocks)
+		expectedErr error
+	}{
+		{
+			name:        "Сброс завершает все сессии и снимает блокировку входа",
+			newPassword: "Changed123",
+			mockSetup: func(m passwordMocks) {
+				m.resetRepo.EXPECT().ConsumeToken(gomock.Any(), tokenHash).Return(7, nil)
+				m.userRepo.EXPECT().GetByID(gomock.Any(), 7).Return(user, nil)
+				m.userRepo.EXPECT().UpdatePasswordHash(gomock.Any(), 7, gomock.Any()).Return(nil)
+				m.sessionRepo.EXPECT().DeleteAllSessions(gomock.Any(), 7).Return(nil)
+				m.attempts.EXPECT().Reset(gomock.Any(), "login:user.test").Return(nil)
+				expectPasswordChangedNotice(m, 7)
+			},
+		},
+		{
+			name:        "Использованный или истекший токен",
+			newPassword: "Changed123",
+			mockSetup: func(m passwordMocks) {
+				m.resetRepo.EXPECT().ConsumeToken(gomock.Any(), tokenHash).
+					Return(0, entity.NewError(entity.ErrBadRequest, fmt.Errorf("токен недействителен")))
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Неподходящий пароль не тратит токен",
+			newPassword: "short",
+			mockSetup:   func(passwordMocks) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestPasswordService(ctrl)
+			tc.mockSetup(m)
+
+			err := service.ConfirmReset(context.Background(), &dto.PasswordResetConfirmRequest{
+				Token:       token,
+				NewPassword: tc.newPassword,
+			})
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPasswordService_ConfirmResetSingleUse(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestPasswordService(ctrl)
+
+	tokenHash := entity.HashPasswordResetToken("reset-token")
+	gomock.InOrder(
+		m.resetRepo.EXPECT().ConsumeToken(gomock.Any(), tokenHash).Return(7, nil),
+		// Репозиторий удаляет токен при первом использовании (GETDEL).
+		m.resetRepo.EXPECT().ConsumeToken(gomock.Any(), tokenHash).
+			Return(0, entity.NewError(entity.ErrBadRequest, fmt.Errorf("токен недействителен"))),
+	)
+	m.userRepo.EXPECT().GetByID(gomock.Any(), 7).Return(&entity.User{ID: 7, Login: "user.test"}, nil)
+	m.userRepo.EXPECT().UpdatePasswordHash(gomock.Any(), 7, gomock.Any()).Return(nil).Times(1)
+	m.sessionRepo.EXPECT().DeleteAllSessions(gomock.Any(), 7).Return(nil)
+	m.attempts.EXPECT().Reset(gomock.Any(), "login:user.test").Return(nil)
+	expectPasswordChangedNotice(m, 7)
+
+	req := &dto.PasswordResetConfirmRequest{Token: "reset-token", NewPassword: "Changed123"}
+	require.NoError(t, service.ConfirmReset(context.Background(), req))
+
+	req.NewPassword = "Attacker123"
+	require.ErrorIs(t, service.ConfirmReset(context.Background(), req), entity.ErrBadRequest)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type PromotionService struct {
+	promotionRepo repository.PromotionRepository
+	adRepo        repository.AdvertisementRepository
+	cfg           config.PromotionConfig
+}
+
+func NewPromotionService(
+	promotionRepo repository.PromotionRepository,
+	adRepo repository.AdvertisementRepository,
+	cfg config.PromotionConfig,
+) usecase.PromotionUsecase {
+	return &PromotionService{
+		promotionRepo: promotionRepo,
+		adRepo:        adRepo,
+		cfg:           cfg,
+	}
+}
+
+func (s *PromotionService) Create(ctx context.Context, userID, adID int, req *dto.PromotionRequest) (*dto.PromotionResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"adID":      adID,
+		"tier":      req.Tier,
+	}).Info("Продвижение объявления")
+
+	if err := s.checkOwner(ctx, userID, adID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	promotion := &entity.Promotion{
+		AdvertisementID: adID,
+		Tier:            entity.PromotionTier(req.Tier),
+		StartsAt:        now,
+		EndsAt:          req.EndsAt,
+	}
+	if req.StartsAt != nil {
+		promotion.StartsAt = *req.StartsAt
+	}
+	if err := promotion.Validate(now, s.cfg.MaxDuration); err != nil {
+		return nil, err
+	}
+
+	created, err := s.promotionRepo.Create(ctx, promotion)
+	if err != nil {
+		return nil, err
+	}
+
+	response := promotionEntityToDTO(created, now)
+	return &response, nil
+}
+
+func (s *PromotionService) GetByAdvertisementID(ctx context.Context, userID, adID int) ([]dto.PromotionResponse, error) {
+	if err := s.checkOwner(ctx, userID, adID); err != nil {
+		return nil, err
+	}
+
+	promotions, err := s.promotionRepo.GetByAdvertisementID(ctx, adID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	response := make([]dto.PromotionResponse, 0, len(promotions))
+	for i := range promotions {
+		response = append(response, promotionEntityToDTO(&promotions[i], now))
+	}
+	return response, nil
+}
+
+func (s *PromotionService) Cancel(ctx context.Context, userID, adID, id int) error {
+	if err := s.checkOwner(ctx, userID, adID); err != nil {
+		return err
+	}
+
+	return s.promotionRepo.Cancel(ctx, id, adID)
+}
+
+func (s *PromotionService) ExpireFinished(ctx context.Context) (int, error) {
+	expired, err := s.promotionRepo.ExpireFinished(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int(expired), nil
+}
+
+// checkOwner проверяет, что объявление принадлежит пользователю: продвигать можно только свои объявления.
+func (s *PromotionService) checkOwner(ctx context.Context, userID, adID int) error {
+	ad, err := s.adRepo.GetByID(ctx, adID)
+	if err != nil {
+		return err
+	}
+	if ad.UserID != userID {
+		return entity.NewError(
+			entity.ErrForbidden,
+			fmt.Errorf("продвигать можно только собственные объявления"),
+		)
+	}
+	return nil
+}
+
+func promotionEntityToDTO(p *entity.Promotion, now time.Time) dto.PromotionResponse {
+	return dto.PromotionResponse{
+		ID:              p.ID,
+		AdvertisementID: p.AdvertisementID,
+		Tier:            string(p.Tier),
+		StartsAt:        p.StartsAt,
+		EndsAt:          p.EndsAt,
+		Status:          string(p.Status),
+		Running:         p.IsRunning(now),
+		CreatedAt:       p.CreatedAt,
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testPromotionCfg = config.PromotionConfig{MaxDuration: 7 * 24 * time.Hour}
+
+func TestPromotionService_Create(t *testing.T) {
+	t.Parallel()
+
+	const (
+		adID    = 10
+		ownerID = 1
+	)
+
+	now := time.Now()
+	startsAt := now.Add(time.Hour)
+
+	testCases := []struct {
+		name            string
+		userID          int
+		req             dto.PromotionRequest
+		mockSetup       func(*mock.MockPromotionRepository)
+		expectedErr     error
+		expectedRunning bool
+	}{
+		{
+			name:   "Продвижение с текущего момента сразу поднимает объявление",
+			userID: ownerID,
+			req:    dto.PromotionRequest{Tier: "top", EndsAt: now.Add(24 * time.Hour)},
+			mockSetup: func(repo *mock.MockPromotionRepository) {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, p *entity.Promotion) (*entity.Promotion, error) {
+						require.Equal(t, adID, p.AdvertisementID)
+						require.Equal(t, entity.PromotionTop, p.Tier)
+						created := *p
+						created.ID = 3
+						created.Status = entity.PromotionActive
+						return &created, nil
+					})
+			},
+			expectedRunning: true,
+		},
+		{
+			name:   "Запланированное продвижение еще не идет",
+			userID: ownerID,
+			req:    dto.PromotionRequest{Tier: "basic", StartsAt: &startsAt, EndsAt: startsAt.Add(time.Hour)},
+			mockSetup: func(repo *mock.MockPromotionRepository) {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, p *entity.Promotion) (*entity.Promotion, error) {
+						require.True(t, p.StartsAt.Equal(startsAt))
+						created := *p
+						created.ID = 3
+						created.Status = entity.PromotionActive
+						return &created, nil
+					})
+			},
+		},
+		{
+			name:        "Чужое объявление",
+			userID:      2,
+			req:         dto.PromotionRequest{Tier: "top", EndsAt: now.Add(24 * time.Hour)},
+			mockSetup:   func(*mock.MockPromotionRepository) {},
+			expectedErr: entity.ErrForbidden,
+		},
+		{
+			name:        "Неизвестный уровень",
+			userID:      ownerID,
+			req:         dto.PromotionRequest{Tier: "gold", EndsAt: now.Add(24 * time.Hour)},
+			mockSetup:   func(*mock.MockPromotionRepository) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Срок больше максимального",
+			userID:      ownerID,
+			req:         dto.PromotionRequest{Tier: "top", EndsAt: now.Add(testPromotionCfg.MaxDuration + time.Hour)},
+			mockSetup:   func(*mock.MockPromotionRepository) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:   "У объявления уже есть продвижение",
+			userID: ownerID,
+			req:    dto.PromotionRequest{Tier: "premium", EndsAt: now.Add(24 * time.Hour)},
+			mockSetup: func(repo *mock.MockPromotionRepository) {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrAlreadyExists, fmt.Errorf("у объявления уже есть продвижение")))
+			},
+			expectedErr: entity.ErrAlreadyExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			promotionRepo := mock.NewMockPromotionRepository(ctrl)
+			adRepo := mock.NewMockAdvertisementRepository(ctrl)
+			adRepo.EXPECT().GetByID(gomock.Any(), adID).Return(&entity.Advertisement{ID: adID, UserID: ownerID}, nil)
+			tc.mockSetup(promotionRepo)
+			service := NewPromotionService(promotionRepo, adRepo, testPromotionCfg)
+
+			promotion, err := service.Create(context.Background(), tc.userID, adID, &tc.req)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 3, promotion.ID)
+			require.Equal(t, tc.expectedRunning, promotion.Running)
+		})
+	}
+}
+
+func TestPromotionService_Cancel(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		userID      int
+		mockSetup   func(*mock.MockPromotionRepository)
+		expectedErr error
+	}{
+		{
+			name:   "Владелец отменяет продвижение",
+			userID: 1,
+			mockSetup: func(repo *mock.MockPromotionRepository) {
+				repo.EXPECT().Cancel(gomock.Any(), 3, 10).Return(nil)
+			},
+		},
+		{
+			name:        "Чужое объявление",
+			userID:      2,
+			mockSetup:   func(*mock.MockPromotionRepository) {},
+			expectedErr: entity.ErrForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			promotionRepo := mock.NewMockPromotionRepository(ctrl)
+			adRepo := mock.NewMockAdvertisementRepository(ctrl)
+			adRepo.EXPECT().GetByID(gomock.Any(), 10).Return(&entity.Advertisement{ID: 10, UserID: 1}, nil)
+			tc.mockSetup(promotionRepo)
+			service := NewPromotionService(promotionRepo, adRepo, testPromotionCfg)
+
+			err := service.Cancel(context.Background(), tc.userID, 10, 3)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPromotionService_ExpireFinished(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	promotionRepo := mock.NewMockPromotionRepository(ctrl)
+	promotionRepo.EXPECT().ExpireFinished(gomock.Any()).Return(int64(4), nil)
+
+	expired, err := NewPromotionService(promotionRepo, nil, testPromotionCfg).ExpireFinished(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 4, expired)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/sanitizer"
+	"github.com/sirupsen/logrus"
+)
+
+type ReviewService struct {
+	reviewRepo    repository.ReviewRepository
+	adRepo        repository.AdvertisementRepository
+	orderRepo     repository.OrderRepository
+	notifications usecase.NotificationUsecase
+	cfg           config.ReviewsConfig
+}
+
+func NewReviewService(
+	reviewRepo repository.ReviewRepository,
+	adRepo repository.AdvertisementRepository,
+	orderRepo repository.OrderRepository,
+	notifications usecase.NotificationUsecase,
+	cfg config.ReviewsConfig,
+) usecase.ReviewUsecase {
+	return &ReviewService{
+		reviewRepo:    reviewRepo,
+		adRepo:        adRepo,
+		orderRepo:     orderRepo,
+		notifications: notifications,
+		cfg:           cfg,
+	}
+}
+
+func (s *ReviewService) Create(ctx context.Context, authorID, adID int, req *dto.CreateReviewRequest) (*dto.ReviewResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"authorID":  authorID,
+		"adID":      adID,
+	}).Info("Создание отзыва")
+
+	ad, err := s.adRepo.GetByID(ctx, adID)
+	if err != nil {
+		return nil, err
+	}
+	if ad.UserID == authorID {
+		return nil, entity.NewError(
+			entity.ErrForbidden,
+			fmt.Errorf("нельзя оставить отзыв на собственное объявление"),
+		)
+	}
+
+	completed, err := s.orderRepo.HasCompleted(ctx, ad.ID, authorID)
+	if err != nil {
+		return nil, err
+	}
+	if !completed {
+		return nil, entity.NewError(
+			entity.ErrForbidden,
+			fmt.Errorf("отзыв можно оставить только после завершенного заказа"),
+		)
+	}
+
+	review := &entity.Review{
+		AdvertisementID: &ad.ID,
+		SellerID:        ad.UserID,
+		AuthorID:        authorID,
+		Rating:          req.Rating,
+		Text:            strings.TrimSpace(sanitizer.StrictPolicy.Sanitize(req.Text)),
+	}
+	if err := review.Validate(); err != nil {
+		return nil, err
+	}
+
+	created, err := s.reviewRepo.Create(ctx, review)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifications.Notify(ctx, created.SellerID, entity.NotificationNewReview, dto.ReviewNotification{
+		ReviewID: created.ID,
+		Rating:   created.Rating,
+	}, "")
+
+	response := reviewEntityToDTO(created)
+	return &response, nil
+}
+
+func (s *ReviewService) GetBySellerID(ctx context.Context, sellerID, offset, limit int) ([]dto.ReviewResponse, error) {
+	reviews, err := s.reviewRepo.GetBySellerID(ctx, sellerID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.ReviewResponse, 0, len(reviews))
+	for i := range reviews {
+		response = append(response, reviewEntityToDTO(&reviews[i]))
+	}
+	return response, nil
+}
+
+func (s *ReviewService) Reply(ctx context.Context, sellerID, reviewID int, req *dto.ReviewReplyRequest) (*dto.ReviewResponse, error) {
+	review, err := s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.SellerID != sellerID {
+		return nil, entity.NewError(
+			entity.ErrForbidden,
+			fmt.Errorf("ответить на отзыв может только продавец"),
+		)
+	}
+	if review.RepliedAt != nil {
+		return nil, entity.NewError(
+			entity.ErrAlreadyExists,
+			fmt.Errorf("на отзыв уже дан ответ"),
+		)
+	}
+
+	reply := strings.TrimSpace(sanitizer.StrictPolicy.Sanitize(req.Reply))
+	if err := entity.ValidateReviewReply(reply); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.reviewRepo.SetReply(ctx, reviewID, sellerID, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifications.Notify(ctx, updated.AuthorID, entity.NotificationReviewReply, dto.ReviewNotification{
+		ReviewID: updated.ID,
+		Rating:   updated.Rating,
+	}, "")
+
+	response := reviewEntityToDTO(updated)
+	return &response, nil
+}
+
+// Report принимает жалобу на отзыв. Отзыв скрывается автоматически,
+// когда жалобу подают reviews.hideAfterReports разных пользователей.
+func (s *ReviewService) Report(ctx context.Context, userID, reviewID int) error {
+	review, err := s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if review.AuthorID == userID {
+		return entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("нельзя пожаловаться на собственный отзыв"),
+		)
+	}
+
+	hidden, err := s.reviewRepo.Report(ctx, reviewID, userID, s.cfg.HideAfterReports)
+	if err != nil {
+		return err
+	}
+
+	if hidden && review.Status == entity.ReviewPublished {
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"reviewID":  reviewID,
+		}).Info("Отзыв скрыт по жалобам пользователей")
+	}
+	return nil
+}
+
+func reviewEntityToDTO(review *entity.Review) dto.ReviewResponse {
+	return dto.ReviewResponse{
+		ID:              review.ID,
+		AdvertisementID: review.AdvertisementID,
+		SellerID:        review.SellerID,
+		AuthorID:        review.AuthorID,
+		AuthorLogin:     review.AuthorLogin,
+		Rating:          review.Rating,
+		Text:            review.Text,
+		Reply:           review.Reply,
+		RepliedAt:       review.RepliedAt,
+		CreatedAt:       review.CreatedAt,
+	}
+}
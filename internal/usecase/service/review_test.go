@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	usecaseMock "github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testReviewsCfg = config.ReviewsConfig{HideAfterReports: 3}
+
+type reviewMocks struct {
+	reviewRepo    *mock.MockReviewRepository
+	adRepo        *mock.MockAdvertisementRepository
+	orderRepo     *mock.MockOrderRepository
+	notifications *usecaseMock.MockNotificationUsecase
+}
+
+func newTestReviewService(ctrl *gomock.Controller) (*ReviewService, reviewMocks) {
+	m := reviewMocks{
+		reviewRepo:    mock.NewMockReviewRepository(ctrl),
+		adRepo:        mock.NewMockAdvertisementRepository(ctrl),
+		orderRepo:     mock.NewMockOrderRepository(ctrl),
+		notifications: usecaseMock.NewMockNotificationUsecase(ctrl),
+	}
+	service := NewReviewService(m.reviewRepo, m.adRepo, m.orderRepo, m.notifications, testReviewsCfg).(*ReviewService)
+	return service, m
+}
+
+func TestReviewService_Create(t *testing.T) {
+	t.Parallel()
+
+	const (
+		adID     = 10
+		sellerID = 2
+		buyerID  = 3
+	)
+
+	ad := &entity.Advertisement{ID: adID, UserID: sellerID}
+
+	testCases := []struct {
+		name        string
+		authorID    int
+		req         dto.CreateReviewRequest
+		mockSetup   func(reviewMocks)
+		expectedErr error
+	}{
+		{
+			name:     "Покупатель после завершенного заказа",
+			authorID: buyerID,
+			req:      dto.CreateReviewRequest{Rating: 5, Text: " <i>Все</i> отлично "},
+			mockSetup: func(m reviewMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), adID).Return(ad, nil)
+				m.orderRepo.EXPECT().HasCompleted(gomock.Any(), adID, buyerID).Return(true, nil)
+				m.reviewRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, review *entity.Review) (*entity.Review, error) {
+						require.Equal(t, sellerID, review.SellerID)
+						require.Equal(t, buyerID, review.AuthorID)
+						require.Equal(t, "Все отлично", review.Text)
+						created := *review
+						created.ID = 7
+						return &created, nil
+					})
+				m.notifications.EXPECT().Notify(gomock.Any(), sellerID, entity.NotificationNewReview,
+					dto.ReviewNotification{ReviewID: 7, Rating: 5}, "")
+			},
+		},
+		{
+			name:     "Без завершенного заказа",
+			authorID: buyerID,
+			req:      dto.CreateReviewRequest{Rating: 5},
+			mockSetup: func(m reviewMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), adID).Return(ad, nil)
+				m.orderRepo.EXPECT().HasCompleted(gomock.Any(), adID, buyerID).Return(false, nil)
+			},
+			expectedErr: entity.ErrForbidden,
+		},
+		{
+			name:     "Отзыв на собственное объявление",
+			authorID: sellerID,
+			req:      dto.CreateReviewRequest{Rating: 5},
+			mockSetup: func(m reviewMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), adID).Return(ad, nil)
+			},
+			expectedErr: entity.ErrForbidden,
+		},
+		{
+			name:     "Оценка вне диапазона",
+			authorID: buyerID,
+			req:      dto.CreateReviewRequest{Rating: 6},
+			mockSetup: func(m reviewMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), adID).Return(ad, nil)
+				m.orderRepo.EXPECT().HasCompleted(gomock.Any(), adID, buyerID).Return(true, nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:     "Второй отзыв по той же сделке",
+			authorID: buyerID,
+			req:      dto.CreateReviewRequest{Rating: 4},
+			mockSetup: func(m reviewMocks) {
+				m.adRepo.EXPECT().GetByID(gomock.Any(), adID).Return(ad, nil)
+				m.orderRepo.EXPECT().HasCompleted(gomock.Any(), adID, buyerID).Return(true, nil)
+				m.reviewRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(nil, entity.NewError(entity.ErrAlreadyExists, fmt.Errorf("отзыв уже оставлен")))
+			},
+			expectedErr: entity.ErrAlreadyExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestReviewService(ctrl)
+			tc.mockSetup(m)
+
+			review, err := service.Create(context.Background(), tc.authorID, adID, &tc.req)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 7, review.ID)
+		})
+	}
+}
+
+func TestReviewService_Reply(t *testing.T) {
+	t.Parallel()
+
+	repliedAt := time.Now()
+
+	testCases := []struct {
+		name        string
+		sellerID    int
+		review      *entity.Review
+		mockSetup   func(reviewMocks)
+		expectedErr error
+	}{
+		{
+			name:     "Продавец отвечает и автор получает уведомление",
+			sellerID: 2,
+			review:   &entity.Review{ID: 7, SellerID: 2, AuthorID: 3, Rating: 4},
+			mockSetup: func(m reviewMocks) {
+				m.reviewRepo.EXPECT().SetReply(gomock.Any(), 7, 2, "Спасибо за покупку").
+					Return(&entity.Review{ID: 7, SellerID: 2, AuthorID: 3, Rating: 4}, nil)
+				m.notifications.EXPECT().Notify(gomock.Any(), 3, entity.NotificationReviewReply,
+					dto.ReviewNotification{ReviewID: 7, Rating: 4}, "")
+			},
+		},
+		{
+			name:        "Ответить может только продавец",
+			sellerID:    4,
+			review:      &entity.Review{ID: 7, SellerID: 2, AuthorID: 3},
+			mockSetup:   func(reviewMocks) {},
+			expectedErr: entity.ErrForbidden,
+		},
+		{
+			name:        "Повторный ответ",
+			sellerID:    2,
+			review:      &entity.Review{ID: 7, SellerID: 2, AuthorID: 3, RepliedAt: &repliedAt},
+			mockSetup:   func(reviewMocks) {},
+			expectedErr: entity.ErrAlreadyExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestReviewService(ctrl)
+			m.reviewRepo.EXPECT().GetByID(gomock.Any(), 7).Return(tc.review, nil)
+			tc.mockSetup(m)
+
+			_, err := service.Reply(context.Background(), tc.sellerID, 7, &dto.ReviewReplyRequest{Reply: "Спасибо за покупку"})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestReviewService_Report(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		userID      int
+		mockSetup   func(reviewMocks)
+		expectedErr error
+	}{
+		{
+			name:   "Жалоба учитывается с порогом скрытия из конфига",
+			userID: 5,
+			mockSetup: func(m reviewMocks) {
+				m.reviewRepo.EXPECT().Report(gomock.Any(), 7, 5, testReviewsCfg.HideAfterReports).Return(true, nil)
+			},
+		},
+		{
+			name:        "Жалоба на собственный отзыв",
+			userID:      3,
+			mockSetup:   func(reviewMocks) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestReviewService(ctrl)
+			m.reviewRepo.EXPECT().GetByID(gomock.Any(), 7).
+				Return(&entity.Review{ID: 7, SellerID: 2, AuthorID: 3, Status: entity.ReviewPublished}, nil)
+			tc.mockSetup(m)
+
+			err := service.Report(context.Background(), tc.userID, 7)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/sanitizer"
+	"github.com/sirupsen/logrus"
+)
+
+type SavedSearchService struct {
+	savedSearchRepo repository.SavedSearchRepository
+	senders         []usecase.SearchDigestSender
+	cfg             config.SavedSearchConfig
+}
+
+func NewSavedSearchService(
+	savedSearchRepo repository.SavedSearchRepository,
+	senders []usecase.SearchDigestSender,
+	cfg config.SavedSearchConfig,
+) usecase.SavedSearchUsecase {
+	return &SavedSearchService{
+		savedSearchRepo: savedSearchRepo,
+		senders:         senders,
+		cfg:             cfg,
+	}
+}
+
+func (s *SavedSearchService) Create(ctx context.Context, userID int, req *dto.SavedSearchRequest) (*dto.SavedSearchResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+	}).Info("Сохранение поиска")
+
+	search, err := savedSearchFromRequest(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.savedSearchRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= s.cfg.MaxPerUser {
+		return nil, entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("можно сохранить не больше %d поисков", s.cfg.MaxPerUser),
+		)
+	}
+
+	created, err := s.savedSearchRepo.Create(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	response := savedSearchEntityToDTO(created)
+	return &response, nil
+}
+
+func (s *SavedSearchService) GetByUserID(ctx context.Context, userID int) ([]dto.SavedSearchResponse, error) {
+	searches, err := s.savedSearchRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.SavedSearchResponse, 0, len(searches))
+	for i := range searches {
+		response = append(response, savedSearchEntityToDTO(&searches[i]))
+	}
+	return response, nil
+}
+
+func (s *SavedSearchService) Update(ctx context.Context, userID, id int, req *dto.SavedSearchRequest) (*dto.SavedSearchResponse, error) {
+	search, err := savedSearchFromRequest(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	search.ID = id
+
+	updated, err := s.savedSearchRepo.Update(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	response := savedSearchEntityToDTO(updated)
+	return &response, nil
+}
+
+func (s *SavedSearchService) Delete(ctx context.Context, userID, id int) error {
+	return s.savedSearchRepo.Delete(ctx, id, userID)
+}
+
+func (s *SavedSearchService) MatchNewAds(ctx context.Context) (int, error) {
+	total := 0
+	for ctx.Err() == nil {
+		processed, err := s.savedSearchRepo.MatchNewAds(ctx, s.cfg.MatchBatchSize)
+		if err != nil {
+			return total, err
+		}
+		total += processed
+		if processed == 0 || processed < s.cfg.MatchBatchSize {
+			break
+		}
+	}
+	return total, nil
+}
+
+// SendDigests доставляет дайджест во все настроенные каналы. Ошибка одного канала
+// не мешает остальным, а совпадения к этому моменту уже забраны и повторно не отправятся.
+func (s *SavedSearchService) SendDigests(ctx context.Context) (int, error) {
+	sent := 0
+	for ctx.Err() == nil {
+		matches, err := s.savedSearchRepo.ClaimMatches(ctx, s.cfg.DigestBatchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		digests := entity.BuildSearchDigests(matches, s.cfg.DigestMaxAds)
+		for i := range digests {
+			for _, sender := range s.senders {
+				if err := sender.SendDigest(ctx, &digests[i]); err != nil {
+					logger.Log.WithFields(logrus.Fields{
+						"userID": digests[i].UserID,
+						"error":  err,
+					}).Error("Не удалось отправить дайджест сохраненных поисков")
+				}
+			}
+			sent++
+		}
+
+		if len(digests) == 0 || len(digests) < s.cfg.DigestBatchSize {
+			break
+		}
+	}
+	return sent, nil
+}
+
+func savedSearchFromRequest(userID int, req *dto.SavedSearchRequest) (*entity.SavedSearch, error) {
+	search := &entity.SavedSearch{
+		UserID: userID,
+		Name:   strings.TrimSpace(sanitizer.StrictPolicy.Sanitize(req.Name)),
+		Criteria: entity.SearchCriteria{
+			MinPrice: req.MinPrice,
+			MaxPrice: req.MaxPrice,
+			SortBy:   req.Sort,
+			Order:    req.Order,
+		},
+		AlertsEnabled: req.AlertsEnabled == nil || *req.AlertsEnabled,
+	}
+	search.Criteria.Normalize()
+
+	if err := search.Validate(); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+func savedSearchEntityToDTO(search *entity.SavedSearch) dto.SavedSearchResponse {
+	return dto.SavedSearchResponse{
+		ID:            search.ID,
+		Name:          search.Name,
+		MinPrice:      search.Criteria.MinPrice,
+		MaxPrice:      search.Criteria.MaxPrice,
+		Sort:          search.Criteria.SortBy,
+		Order:         search.Criteria.Order,
+		AlertsEnabled: search.AlertsEnabled,
+		Query:         search.Criteria.Query(),
+		CreatedAt:     search.CreatedAt,
+		UpdatedAt:     search.UpdatedAt,
+	}
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	usecaseMock "github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testSavedSearchCfg = config.SavedSearchConfig{
+	MaxPerUser:      2,
+	MatchBatchSize:  2,
+	DigestBatchSize: 2,
+	DigestMaxAds:    1,
+}
+
+func TestSavedSearchService_MatchNewAds(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		mockSetup     func(*mock.MockSavedSearchRepository)
+		expectedTotal int
+		expectedErr   error
+	}{
+		{
+			name: "Полные пачки обрабатываются до неполной",
+			mockSetup: func(repo *mock.MockSavedSearchRepository) {
+				gomock.InOrder(
+					repo.EXPECT().MatchNewAds(gomock.Any(), 2).Return(2, nil),
+					repo.EXPECT().MatchNewAds(gomock.Any(), 2).Return(2, nil),
+					repo.EXPECT().MatchNewAds(gomock.Any(), 2).Return(1, nil),
+				)
+			},
+			expectedTotal: 5,
+		},
+		{
+			name: "Пустая пачка или сопоставление на другом экземпляре",
+			mockSetup: func(repo *mock.MockSavedSearchRepository) {
+				repo.EXPECT().MatchNewAds(gomock.Any(), 2).Return(0, nil)
+			},
+			expectedTotal: 0,
+		},
+		{
+			name: "Ошибка возвращает уже обработанное",
+			mockSetup: func(repo *mock.MockSavedSearchRepository) {
+				gomock.InOrder(
+					repo.EXPECT().MatchNewAds(gomock.Any(), 2).Return(2, nil),
+					repo.EXPECT().MatchNewAds(gomock.Any(), 2).
+						Return(0, entity.NewError(entity.ErrInternal, fmt.Errorf("соединение потеряно"))),
+				)
+			},
+			expectedTotal: 2,
+			expectedErr:   entity.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockSavedSearchRepository(ctrl)
+			service := NewSavedSearchService(repo, nil, testSavedSearchCfg)
+
+			tc.mockSetup(repo)
+
+			total, err := service.MatchNewAds(context.Background())
+			require.Equal(t, tc.expectedTotal, total)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSavedSearchService_SendDigests(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockSavedSearchRepository(ctrl)
+	inApp := usecaseMock.NewMockSearchDigestSender(ctrl)
+	outbox := usecaseMock.NewMockSearchDigestSender(ctrl)
+	service := NewSavedSearchService(repo, []usecase.SearchDigestSender{inApp, outbox}, testSavedSearchCfg)
+
+	firstBatch := []entity.SavedSearchMatch{
+		{SearchID: 1, SearchName: "Велосипеды", UserID: 10, AdvertisementID: 100},
+		{SearchID: 1, SearchName: "Велосипеды", UserID: 10, AdvertisementID: 101},
+		{SearchID: 2, SearchName: "Самокаты", UserID: 20, AdvertisementID: 102},
+	}
+	secondBatch := []entity.SavedSearchMatch{
+		{SearchID: 3, SearchName: "Лыжи", UserID: 30, AdvertisementID: 103},
+	}
+
+	gomock.InOrder(
+		repo.EXPECT().ClaimMatches(gomock.Any(), 2).Return(firstBatch, nil),
+		repo.EXPECT().ClaimMatches(gomock.Any(), 2).Return(secondBatch, nil),
+	)
+
+	var delivered []int
+	inApp.EXPECT().SendDigest(gomock.Any(), gomock.Any()).Times(3).
+		DoAndReturn(func(_ context.Context, digest *entity.SearchDigest) error {
+			delivered = append(delivered, digest.UserID)
+			if digest.UserID == 10 {
+				require.Equal(t, 2, digest.TotalCount())
+				require.Len(t, digest.Items[0].Ads, testSavedSearchCfg.DigestMaxAds)
+			}
+			return nil
+		})
+	// Ошибка одного канала не мешает остальным и не останавливает рассылку.
+	outbox.EXPECT().SendDigest(gomock.Any(), gomock.Any()).Times(3).
+		Return(entity.NewError(entity.ErrInternal, fmt.Errorf("почтовый сервер недоступен")))
+
+	sent, err := service.SendDigests(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, sent)
+	require.Equal(t, []int{10, 20, 30}, delivered)
+}
+
+func TestSavedSearchService_Create(t *testing.T) {
+	t.Parallel()
+
+	minPrice, maxPrice := 100.0, 50.0
+
+	testCases := []struct {
+		name        string
+		req         dto.SavedSearchRequest
+		mockSetup   func(*mock.MockSavedSearchRepository)
+		expectedErr error
+	}{
+		{
+			name: "Поиск сохраняется с оповещениями по умолчанию",
+			req:  dto.SavedSearchRequest{Name: " Велосипеды ", Sort: "price", Order: "asc"},
+			mockSetup: func(repo *mock.MockSavedSearchRepository) {
+				repo.EXPECT().CountByUserID(gomock.Any(), 1).Return(1, nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, search *entity.SavedSearch) (*entity.SavedSearch, error) {
+						require.Equal(t, "Велосипеды", search.Name)
+						require.True(t, search.AlertsEnabled)
+						created := *search
+						created.ID = 5
+						return &created, nil
+					})
+			},
+		},
+		{
+			name: "Превышен лимит поисков",
+			req:  dto.SavedSearchRequest{Name: "Велосипеды"},
+			mockSetup: func(repo *mock.MockSavedSearchRepository) {
+				repo.EXPECT().CountByUserID(gomock.Any(), 1).Return(testSavedSearchCfg.MaxPerUser, nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Минимальная цена больше максимальной",
+			req:         dto.SavedSearchRequest{Name: "Велосипеды", MinPrice: &minPrice, MaxPrice: &maxPrice},
+			mockSetup:   func(*mock.MockSavedSearchRepository) {},
+			expectedErr: entity.ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockSavedSearchRepository(ctrl)
+			service := NewSavedSearchService(repo, nil, testSavedSearchCfg)
+
+			tc.mockSetup(repo)
+
+			search, err := service.Create(context.Background(), 1, &tc.req)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 5, search.ID)
+		})
+	}
+}
+
+func TestInAppDigestSender_SendDigest(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	notifications := usecaseMock.NewMockNotificationUsecase(ctrl)
+	sender := &InAppDigestSender{notifications: notifications}
+
+	digests := entity.BuildSearchDigests([]entity.SavedSearchMatch{
+		{SearchID: 1, SearchName: "Велосипеды", UserID: 10, AdvertisementID: 100},
+		{SearchID: 1, SearchName: "Велосипеды", UserID: 10, AdvertisementID: 101},
+	}, 1)
+	require.Len(t, digests, 1)
+
+	notifications.EXPECT().Notify(gomock.Any(), 10, entity.NotificationSavedSearch, dto.SavedSearchDigestNotification{
+		Total: 2,
+		Searches: []dto.SavedSearchDigestItem{
+			{SearchID: 1, Name: "Велосипеды", Count: 2, AdvertisementIDs: []int{100}},
+		},
+	}, "")
+
+	require.NoError(t, sender.SendDigest(context.Background(), &digests[0]))
+}
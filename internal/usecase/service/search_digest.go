@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+)
+
+const (
+	DigestChannelInApp  = "in_app"
+	DigestChannelOutbox = "outbox"
+)
+
+// NewSearchDigestSenders собирает каналы доставки дайджеста по именам из конфигурации.
+func NewSearchDigestSenders(
+	channels []string,
+	notifications usecase.NotificationUsecase,
+	userRepo repository.UserRepository,
+	notifier repository.Notifier,
+) ([]usecase.SearchDigestSender, error) {
+	senders := make([]usecase.SearchDigestSender, 0, len(channels))
+	for _, channel := range channels {
+		switch channel {
+		case DigestChannelInApp:
+			senders = append(senders, &InAppDigestSender{notifications: notifications})
+		case DigestChannelOutbox:
+			senders = append(senders, &OutboundDigestSender{userRepo: userRepo, notifier: notifier})
+		default:
+			return nil, fmt.Errorf("неизвестный канал дайджеста %q", channel)
+		}
+	}
+	return senders, nil
+}
+
+// InAppDigestSender создает уведомление saved_search_digest в центре уведомлений.
+type InAppDigestSender struct {
+	notifications usecase.NotificationUsecase
+}
+
+func (s *InAppDigestSender) SendDigest(ctx context.Context, digest *entity.SearchDigest) error {
+	data := dto.SavedSearchDigestNotification{
+		Total:    digest.TotalCount(),
+		Searches: make([]dto.SavedSearchDigestItem, 0, len(digest.Items)),
+	}
+	for _, item := range digest.Items {
+		adIDs := make([]int, 0, len(item.Ads))
+		for _, ad := range item.Ads {
+			adIDs = append(adIDs, ad.AdvertisementID)
+		}
+		data.Searches = append(data.Searches, dto.SavedSearchDigestItem{
+			SearchID:         item.SearchID,
+			Name:             item.SearchName,
+			Count:            item.Count,
+			AdvertisementIDs: adIDs,
+		})
+	}
+
+	s.notifications.Notify(ctx, digest.UserID, entity.NotificationSavedSearch, data, "")
+	return nil
+}
+
+// OutboundDigestSender отправляет дайджест письмом или сообщением через repository.Notifier.
+type OutboundDigestSender struct {
+	userRepo repository.UserRepository
+	notifier repository.Notifier
+}
+
+func (s *OutboundDigestSender) SendDigest(ctx context.Context, digest *entity.SearchDigest) error {
+	user, err := s.userRepo.GetByID(ctx, digest.UserID)
+	if err != nil {
+		return err
+	}
+
+	var body strings.Builder
+	for _, item := range digest.Items {
+		fmt.Fprintf(&body, "«%s»: новых объявлений — %d\n", item.SearchName, item.Count)
+		for _, ad := range item.Ads {
+			fmt.Fprintf(&body, "  %s — %.2f\n", ad.AdTitle, ad.AdPrice)
+		}
+	}
+
+	return s.notifier.Send(ctx, entity.NewOutboundMessage(
+		user,
+		"Новые объявления по сохраненным поискам",
+		body.String(),
+	))
+}
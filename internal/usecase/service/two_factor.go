@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type TwoFactorService struct {
+	userRepo         repository.UserRepository
+	twoFactorRepo    repository.TwoFactorRepository
+	challengeRepo    repository.ChallengeRepository
+	loginAttemptRepo repository.LoginAttemptRepository
+	cfg              config.TwoFactorConfig
+	pepper           entity.Pepper
+}
+
+func NewTwoFactorService(
+	userRepo repository.UserRepository,
+	twoFactorRepo repository.TwoFactorRepository,
+	challengeRepo repository.ChallengeRepository,
+	loginAttemptRepo repository.LoginAttemptRepository,
+	cfg config.TwoFactorConfig,
+	pepper entity.Pepper,
+) usecase.TwoFactorUsecase {
+	return &TwoFactorService{
+		userRepo:         userRepo,
+		twoFactorRepo:    twoFactorRepo,
+		challengeRepo:    challengeRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		cfg:              cfg,
+		pepper:           pepper,
+	}
+}
+
+func (s *TwoFactorService) Enroll(ctx context.Context, userID int) (*dto.TOTPEnrollResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+	}).Info("Подключение двухфакторной аутентификации")
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := entity.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.SetPendingSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &dto.TOTPEnrollResponse{
+		Secret: secret,
+		URI:    entity.TOTPURI(s.cfg.Issuer, user.Login, secret),
+	}, nil
+}
+
+func (s *TwoFactorService) Confirm(ctx context.Context, userID int, code string) (*dto.RecoveryCodesResponse, error) {
+	if err := s.checkCodeLockout(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	tf, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if tf.Enabled {
+		return nil, entity.NewError(
+			entity.ErrAlreadyExists,
+			fmt.Errorf("двухфакторная аутентификация уже включена"),
+		)
+	}
+	if tf.Secret == "" {
+		return nil, entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("сначала необходимо начать подключение двухфакторной аутентификации"),
+		)
+	}
+
+	step, ok := entity.ValidateTOTP(tf.Secret, code, time.Now(), tf.LastStep)
+	if !ok {
+		return nil, s.registerCodeFailure(ctx, userID,
+			entity.NewError(entity.ErrBadRequest, fmt.Errorf("неверный код подтверждения")))
+	}
+	if err := s.loginAttemptRepo.Reset(ctx, twoFactorAttemptKey(userID)); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := entity.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.Enable(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{Codes: codes}, nil
+}
+
+func (s *TwoFactorService) Disable(ctx context.Context, userID int, req *dto.TOTPDisableRequest) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if ok, _ := entity.CheckPassword(req.Password, user.PasswordHash, s.pepper); !ok {
+		return entity.NewError(entity.ErrForbidden, fmt.Errorf("неверный пароль"))
+	}
+
+	if err := s.verifyCodeLimited(ctx, userID, req.Code); err != nil {
+		return err
+	}
+
+	return s.twoFactorRepo.Disable(ctx, userID)
+}
+
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*dto.RecoveryCodesResponse, error) {
+	if err := s.verifyCodeLimited(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := entity.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{Codes: codes}, nil
+}
+
+// StartChallenge выдает токен второго шага, если у пользователя включена 2FA.
+func (s *TwoFactorService) StartChallenge(ctx context.Context, userID int) (string, bool, error) {
+	tf, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return "", false, err
+	}
+	if !tf.Enabled {
+		return "", false, nil
+	}
+
+	token, err := s.challengeRepo.CreateChallenge(ctx, userID, s.cfg.ChallengeLifetime)
+	if err != nil {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+func (s *TwoFactorService) VerifyChallenge(ctx context.Context, req *dto.LoginTwoFactorRequest) (int, error) {
+	requestID := utils.GetRequestID(ctx)
+
+	userID, err := s.challengeRepo.GetChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return 0, err
+	}
+
+	attempts, err := s.challengeRepo.IncrementAttempts(ctx, req.ChallengeToken)
+	if err != nil {
+		return 0, err
+	}
+	if attempts > s.cfg.ChallengeMaxAttempts {
+		if err := s.challengeRepo.DeleteChallenge(ctx, req.ChallengeToken); err != nil {
+			logger.Log.WithFields(logrus.Fields{
+				"requestID": requestID,
+				"error":     err,
+			}).Error("Не удалось удалить токен второго шага")
+		}
+		return 0, entity.NewError(
+			entity.ErrUnauthorized,
+			fmt.Errorf("превышено количество попыток, войдите заново"),
+		)
+	}
+
+	if err := s.verifyCode(ctx, userID, req.Code); err != nil {
+		return 0, err
+	}
+
+	if err := s.challengeRepo.DeleteChallenge(ctx, req.ChallengeToken); err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+// verifyCode принимает код TOTP (6 цифр) либо неиспользованный код восстановления.
+func (s *TwoFactorService) verifyCode(ctx context.Context, userID int, code string) error {
+	tf, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !tf.Enabled {
+		return entity.NewError(
+			entity.ErrBadRequest,
+			fmt.Errorf("двухфакторная аутентификация не включена"),
+		)
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == entity.TOTPDigits {
+		step, ok := entity.ValidateTOTP(tf.Secret, code, time.Now(), tf.LastStep)
+		if !ok {
+			return entity.NewError(entity.ErrUnauthorized, fmt.Errorf("неверный код"))
+		}
+		return s.twoFactorRepo.UpdateLastStep(ctx, userID, step)
+	}
+
+	used, err := s.twoFactorRepo.UseRecoveryCode(ctx, userID, entity.HashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return entity.NewError(entity.ErrUnauthorized, fmt.Errorf("неверный код"))
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+	}).Info("Использован код восстановления")
+
+	return nil
+}
+
+func twoFactorAttemptKey(userID int) string {
+	return "2fa:user:" + strconv.Itoa(userID)
+}
+
+// verifyCodeLimited проверяет код для действий из сессии. Токена второго шага у них нет,
+// поэтому неверные коды считаются по пользователю: после ChallengeMaxAttempts ошибок
+// проверка кодов блокируется на CodeLockout, как и попытки в VerifyChallenge.
+func (s *TwoFactorService) verifyCodeLimited(ctx context.Context, userID int, code string) error {
+	if err := s.checkCodeLockout(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.verifyCode(ctx, userID, code); err != nil {
+		if errors.Is(err, entity.ErrUnauthorized) {
+			return s.registerCodeFailure(ctx, userID, err)
+		}
+		return err
+	}
+
+	return s.loginAttemptRepo.Reset(ctx, twoFactorAttemptKey(userID))
+}
+
+func (s *TwoFactorService) checkCodeLockout(ctx context.Context, userID int) error {
+	remaining, err := s.loginAttemptRepo.GetLockout(ctx, twoFactorAttemptKey(userID))
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return entity.NewError(entity.ErrTooManyRequests, entity.LockoutError{RetryAfter: remaining})
+	}
+	return nil
+}
+
+// registerCodeFailure учитывает неверный код и возвращает codeErr, а при достижении порога
+// блокирует проверку кодов пользователя.
+func (s *TwoFactorService) registerCodeFailure(ctx context.Context, userID int, codeErr error) error {
+	key := twoFactorAttemptKey(userID)
+
+	failures, err := s.loginAttemptRepo.RegisterFailure(ctx, key, s.cfg.CodeLockout)
+	if err != nil {
+		return err
+	}
+	if failures < s.cfg.ChallengeMaxAttempts {
+		return codeErr
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+		"failures":  failures,
+	}).Warn("Превышено количество неверных кодов двухфакторной аутентификации")
+
+	if err := s.loginAttemptRepo.Lock(ctx, key, s.cfg.CodeLockout); err != nil {
+		return err
+	}
+	return codeErr
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testTwoFactorCfg = config.TwoFactorConfig{
+	Issuer:               "Marketplace",
+	ChallengeLifetime:    5 * time.Minute,
+	ChallengeMaxAttempts: 3,
+	CodeLockout:          15 * time.Minute,
+}
+
+func TestTwoFactorService_StartChallenge(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		mockSetup        func(*mock.MockTwoFactorRepository, *mock.MockChallengeRepository)
+		expectedToken    string
+		expectedRequired bool
+	}{
+		{
+			name: "Без 2FA второй шаг не нужен",
+			mockSetup: func(tfRepo *mock.MockTwoFactorRepository, _ *mock.MockChallengeRepository) {
+				tfRepo.EXPECT().Get(gomock.Any(), 1).Return(&entity.TwoFactor{UserID: 1}, nil)
+			},
+		},
+		{
+			name: "С 2FA выдается токен второго шага",
+			mockSetup: func(tfRepo *mock.MockTwoFactorRepository, challengeRepo *mock.MockChallengeRepository) {
+				tfRepo.EXPECT().Get(gomock.Any(), 1).Return(&entity.TwoFactor{UserID: 1, Enabled: true}, nil)
+				challengeRepo.EXPECT().CreateChallenge(gomock.Any(), 1, testTwoFactorCfg.ChallengeLifetime).
+					Return("challenge-token", nil)
+			},
+			expectedToken:    "challenge-token",
+			expectedRequired: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tfRepo := mock.NewMockTwoFactorRepository(ctrl)
+			challengeRepo := mock.NewMockChallengeRepository(ctrl)
+			service := NewTwoFactorService(nil, tfRepo, challengeRepo, nil, testTwoFactorCfg, entity.Pepper{})
+
+			tc.mockSetup(tfRepo, challengeRepo)
+
+			token, required, err := service.StartChallenge(context.Background(), 1)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedToken, token)
+			require.Equal(t, tc.expectedRequired, required)
+		})
+	}
+}
+
+func TestTwoFactorService_VerifyChallenge(t *testing.T) {
+	t.Parallel()
+
+	const (
+		token  = "challenge-token"
+		userID = 1
+	)
+
+	secret, err := entity.GenerateTOTPSecret()
+	require.NoError(t, err)
+	step := entity.TOTPStep(time.Now())
+	code, err := entity.TOTPCode(secret, step)
+	require.NoError(t, err)
+
+	enabled := &entity.TwoFactor{UserID: userID, Secret: secret, Enabled: true}
+
+	testCases := []struct {
+		name        string
+		code        string
+		mockSetup   func(*mock.MockTwoFactorRepository, *mock.MockChallengeRepository)
+		expectedErr error
+	}{
+		{
+			name: "Верный код TOTP завершает второй шаг",
+			code: code,
+			mockSetup: func(tfRepo *mock.MockTwoFactorRepository, challengeRepo *mock.MockChallengeRepository) {
+				challengeRepo.EXPECT().GetChallenge(gomock.Any(), token).Return(userID, nil)
+				challengeRepo.EXPECT().IncrementAttempts(gomock.Any(), token).Return(1, nil)
+				tfRepo.EXPECT().Get(gomock.Any(), userID).Return(enabled, nil)
+				tfRepo.EXPECT().UpdateLastStep(gomock.Any(), userID, gomock.Any()).Return(nil)
+				challengeRepo.EXPECT().DeleteChallenge(gomock.Any(), token).Return(nil)
+			},
+		},
+		{
+			name: "Код восстановления",
+			code: "abcd-efgh",
+			mockSetup: func(tfRepo *mock.MockTwoFactorRepository, challengeRepo *mock.MockChallengeRepository) {
+				challengeRepo.EXPECT().GetChallenge(gomock.Any(), token).Return(userID, nil)
+				challengeRepo.EXPECT().IncrementAttempts(gomock.Any(), token).Return(1, nil)
+				tfRepo.EXPECT().Get(gomock.Any(), userID).Return(enabled, nil)
+				tfRepo.EXPECT().UseRecoveryCode(gomock.Any(), userID, entity.HashRecoveryCode("abcd-efgh")).Return(true, nil)
+				challengeRepo.EXPECT().DeleteChallenge(gomock.Any(), token).Return(nil)
+			},
+		},
+		{
+			name: "Повтор уже использованного кода TOTP",
+			code: code,
+			mockSetup: func(tfRepo *mock.MockTwoFactorRepository, challengeRepo *mock.MockChallengeRepository) {
+				challengeRepo.EXPECT().GetChallenge(gomock.Any(), token).Return(userID, nil)
+				challengeRepo.EXPECT().IncrementAttempts(gomock.Any(), token).Return(2, nil)
+				tfRepo.EXPECT().Get(gomock.Any(), userID).
+					Return(&entity.TwoFactor{UserID: userID, Secret: secret, Enabled: true, LastStep: step + 1}, nil)
+			},
+			expectedErr: entity.ErrUnauthorized,
+		},
+		{
+			name: "Неверный код оставляет токен для следующей попытки",
+			code: "000000",
+			mockSetup: func(tfRepo *mock.MockTwoFactorRepository, challengeRepo *mock.MockChallengeRepository) {
+				challengeRepo.EXPECT().GetChallenge(gomock.Any(), token).Return(userID, nil)
+				challengeRepo.EXPECT().IncrementAttempts(gomock.Any(), token).Return(1, nil)
+				tfRepo.EXPECT().Get(gomock.Any(), userID).
+					Return(&entity.TwoFactor{UserID: userID, Secret: secret, Enabled: true, LastStep: step + 1}, nil)
+			},
+			expectedErr: entity.ErrUnauthorized,
+		},
+		{
+			name: "Исчерпанные попытки удаляют токен без проверки кода",
+			code: code,
+			mockSetup: func(_ *mock.MockTwoFactorRepository, challengeRepo *mock.MockChallengeRepository) {
+				challengeRepo.EXPECT().GetChallenge(gomock.Any(), token).Return(userID, nil)
+				challengeRepo.EXPECT().IncrementAttempts(gomock.Any(), token).
+					Return(testTwoFactorCfg.ChallengeMaxAttempts+1, nil)
+				challengeRepo.EXPECT().DeleteChallenge(gomock.Any(), token).Return(nil)
+			},
+			expectedErr: entity.ErrUnauthorized,
+		},
+		{
+			name: "Истекший токен второго шага",
+			code: code,
+			mockSetup: func(_ *mock.MockTwoFactorRepository, challengeRepo *mock.MockChallengeRepository) {
+				challengeRepo.EXPECT().GetChallenge(gomock.Any(), token).
+					Return(0, entity.NewError(entity.ErrUnauthorized, fmt.Errorf("токен второго шага истек")))
+			},
+			expectedErr: entity.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tfRepo := mock.NewMockTwoFactorRepository(ctrl)
+			challengeRepo := mock.NewMockChallengeRepository(ctrl)
+			service := NewTwoFactorService(nil, tfRepo, challengeRepo, nil, testTwoFactorCfg, entity.Pepper{})
+
+			tc.mockSetup(tfRepo, challengeRepo)
+
+			id, err := service.VerifyChallenge(context.Background(), &dto.LoginTwoFactorRequest{
+				ChallengeToken: token,
+				Code:           tc.code,
+			})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, userID, id)
+		})
+	}
+}
+
+func TestTwoFactorService_ConfirmLockout(t *testing.T) {
+	t.Parallel()
+
+	const (
+		userID     = 1
+		attemptKey = "2fa:user:1"
+	)
+
+	secret, err := entity.GenerateTOTPSecret()
+	require.NoError(t, err)
+	code, err := entity.TOTPCode(secret, entity.TOTPStep(time.Now()))
+	require.NoError(t, err)
+
+	pending := &entity.TwoFactor{UserID: userID, Secret: secret}
+
+	testCases := []struct {
+		name        string
+		code        string
+		mockSetup   func(*mock.MockTwoFactorRepository, *mock.MockLoginAttemptRepository)
+		expectedErr error
+		retryAfter  time.Duration
+	}{
+		{
+			name: "Верный код включает 2FA и сбрасывает счетчик",
+			code: code,
+			mockSetup: func(tfRepo *mock.MockTwoFactorRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), attemptKey).Return(time.Duration(0), nil)
+				tfRepo.EXPECT().Get(gomock.Any(), userID).Return(pending, nil)
+				attempts.EXPECT().Reset(gomock.Any(), attemptKey).Return(nil)
+				tfRepo.EXPECT().Enable(gomock.Any(), userID, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "Неверный код учитывается",
+			code: "000000",
+			mockSetup: func(tfRepo *mock.MockTwoFactorRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), attemptKey).Return(time.Duration(0), nil)
+				tfRepo.EXPECT().Get(gomock.Any(), userID).
+					Return(&entity.TwoFactor{UserID: userID, Secret: secret, LastStep: entity.TOTPStep(time.Now()) + 1}, nil)
+				attempts.EXPECT().RegisterFailure(gomock.Any(), attemptKey, testTwoFactorCfg.CodeLockout).Return(1, nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name: "Порог неверных кодов блокирует проверку",
+			code: "000000",
+			mockSetup: func(tfRepo *mock.MockTwoFactorRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), attemptKey).Return(time.Duration(0), nil)
+				tfRepo.EXPECT().Get(gomock.Any(), userID).
+					Return(&entity.TwoFactor{UserID: userID, Secret: secret, LastStep: entity.TOTPStep(time.Now()) + 1}, nil)
+				attempts.EXPECT().RegisterFailure(gomock.Any(), attemptKey, testTwoFactorCfg.CodeLockout).
+					Return(testTwoFactorCfg.ChallengeMaxAttempts, nil)
+				attempts.EXPECT().Lock(gomock.Any(), attemptKey, testTwoFactorCfg.CodeLockout).Return(nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name: "Во время блокировки даже верный код не проверяется",
+			code: code,
+			mockSetup: func(_ *mock.MockTwoFactorRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), attemptKey).Return(10*time.Minute, nil)
+			},
+			expectedErr: entity.ErrTooManyRequests,
+			retryAfter:  10 * time.Minute,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tfRepo := mock.NewMockTwoFactorRepository(ctrl)
+			attempts := mock.NewMockLoginAttemptRepository(ctrl)
+			service := NewTwoFactorService(nil, tfRepo, nil, attempts, testTwoFactorCfg, entity.Pepper{})
+
+			tc.mockSetup(tfRepo, attempts)
+
+			codes, err := service.Confirm(context.Background(), userID, tc.code)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				if tc.retryAfter > 0 {
+					var lockoutErr entity.LockoutError
+					require.ErrorAs(t, err, &lockoutErr)
+					require.Equal(t, tc.retryAfter, lockoutErr.RetryAfter)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, codes.Codes)
+		})
+	}
+}
+
+func TestTwoFactorService_RegenerateRecoveryCodesLockout(t *testing.T) {
+	t.Parallel()
+
+	const (
+		userID     = 1
+		attemptKey = "2fa:user:1"
+	)
+
+	enabled := &entity.TwoFactor{UserID: userID, Enabled: true}
+
+	testCases := []struct {
+		name        string
+		mockSetup   func(*mock.MockTwoFactorRepository, *mock.MockLoginAttemptRepository)
+		expectedErr error
+	}{
+		{
+			name: "Код восстановления выпускает новые коды и сбрасывает счетчик",
+			mockSetup: func(tfRepo *mock.MockTwoFactorRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), attemptKey).Return(time.Duration(0), nil)
+				tfRepo.EXPECT().Get(gomock.Any(), userID).Return(enabled, nil)
+				tfRepo.EXPECT().UseRecoveryCode(gomock.Any(), userID, entity.HashRecoveryCode("abcd-efgh")).Return(true, nil)
+				attempts.EXPECT().Reset(gomock.Any(), attemptKey).Return(nil)
+				tfRepo.EXPECT().ReplaceRecoveryCodes(gomock.Any(), userID, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "Использованный код восстановления учитывается и блокирует на пороге",
+			mockSetup: func(tfRepo *mock.MockTwoFactorRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), attemptKey).Return(time.Duration(0), nil)
+				tfRepo.EXPECT().Get(gomock.Any(), userID).Return(enabled, nil)
+				tfRepo.EXPECT().UseRecoveryCode(gomock.Any(), userID, entity.HashRecoveryCode("abcd-efgh")).Return(false, nil)
+				attempts.EXPECT().RegisterFailure(gomock.Any(), attemptKey, testTwoFactorCfg.CodeLockout).
+					Return(testTwoFactorCfg.ChallengeMaxAttempts, nil)
+				attempts.EXPECT().Lock(gomock.Any(), attemptKey, testTwoFactorCfg.CodeLockout).Return(nil)
+			},
+			expectedErr: entity.ErrUnauthorized,
+		},
+		{
+			name: "Выключенная 2FA не считается неверным кодом",
+			mockSetup: func(tfRepo *mock.MockTwoFactorRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), attemptKey).Return(time.Duration(0), nil)
+				tfRepo.EXPECT().Get(gomock.Any(), userID).Return(&entity.TwoFactor{UserID: userID}, nil)
+			},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name: "Действующая блокировка",
+			mockSetup: func(_ *mock.MockTwoFactorRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), attemptKey).Return(time.Minute, nil)
+			},
+			expectedErr: entity.ErrTooManyRequests,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tfRepo := mock.NewMockTwoFactorRepository(ctrl)
+			attempts := mock.NewMockLoginAttemptRepository(ctrl)
+			service := NewTwoFactorService(nil, tfRepo, nil, attempts, testTwoFactorCfg, entity.Pepper{})
+
+			tc.mockSetup(tfRepo, attempts)
+
+			codes, err := service.RegenerateRecoveryCodes(context.Background(), userID, "abcd-efgh")
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, codes.Codes)
+		})
+	}
+}
@@ -2,24 +2,46 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/utils"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/logger"
 	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/sanitizer"
+	"github.com/sirupsen/logrus"
 )
 
 type UserService struct {
-	userRepo repository.UserRepository
+	userRepo         repository.UserRepository
+	sessionRepo      repository.SessionRepository
+	loginAttemptRepo repository.LoginAttemptRepository
+	notifications    usecase.NotificationUsecase
+	protectionCfg    config.LoginProtectionConfig
+	pepper           entity.Pepper
 }
 
 func NewUserService(
 	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	loginAttemptRepo repository.LoginAttemptRepository,
+	notifications usecase.NotificationUsecase,
+	protectionCfg config.LoginProtectionConfig,
+	pepper entity.Pepper,
 ) usecase.UserUsecase {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		sessionRepo:      sessionRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		notifications:    notifications,
+		protectionCfg:    protectionCfg,
+		pepper:           pepper,
 	}
 }
 
@@ -34,14 +56,14 @@ func (e *UserService) Register(ctx context.Context, registerDTO *dto.UserRegiste
 		return nil, err
 	}
 
-	salt, hash, err := entity.HashPassword(registerDTO.Password)
+	hash, err := entity.HashPassword(registerDTO.Password, e.pepper)
 	if err != nil {
 		return nil, err
 	}
 
 	sanitizedName := sanitizer.StrictPolicy.Sanitize(registerDTO.Name)
 	sanitizedSurname := sanitizer.StrictPolicy.Sanitize(registerDTO.Surname)
-	user, err = e.userRepo.Create(ctx, registerDTO.Login, sanitizedName, sanitizedSurname, hash, salt)
+	user, err = e.userRepo.Create(ctx, registerDTO.Login, sanitizedName, sanitizedSurname, hash)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +79,7 @@ func (e *UserService) Register(ctx context.Context, registerDTO *dto.UserRegiste
 	return response, nil
 }
 
-func (e *UserService) Login(ctx context.Context, loginDTO *dto.Login) (int, error) {
+func (e *UserService) Login(ctx context.Context, loginDTO *dto.Login, clientIP string) (int, error) {
 	if err := entity.ValidateLogin(loginDTO.Login); err != nil {
 		return 0, entity.NewError(entity.ErrBadRequest, err)
 	}
@@ -65,36 +87,264 @@ func (e *UserService) Login(ctx context.Context, loginDTO *dto.Login) (int, erro
 		return 0, entity.NewError(entity.ErrBadRequest, err)
 	}
 
+	loginKey := loginAttemptKey(loginDTO.Login)
+	ipKey := "ip:" + clientIP
+
+	if err := e.checkLockout(ctx, loginKey, ipKey); err != nil {
+		return 0, err
+	}
+
 	employer, err := e.userRepo.GetByLogin(ctx, loginDTO.Login)
 	if err != nil {
+		if !isNotFound(err) {
+			return 0, err
+		}
+		// Несуществующий логин проверяется так же долго, как и существующий.
+		entity.CheckDummyPassword(loginDTO.Password, e.pepper)
+		return 0, e.registerFailure(ctx, loginKey, ipKey)
+	}
+
+	ok, needsRehash := entity.CheckPassword(loginDTO.Password, employer.PasswordHash, e.pepper)
+	if !ok {
+		return 0, e.registerFailure(ctx, loginKey, ipKey)
+	}
+
+	if err := e.loginAttemptRepo.Reset(ctx, loginKey); err != nil {
 		return 0, err
 	}
 
-	if !entity.CheckPassword(loginDTO.Password, employer.PasswordHash, employer.PasswordSalt) {
-		return 0, entity.NewError(entity.ErrUnauthorized, fmt.Errorf("неверные учетные данные"))
+	if needsRehash {
+		e.rehashPassword(ctx, employer.ID, loginDTO.Password)
 	}
 
 	return employer.ID, nil
 }
 
-func (e *UserService) GetUser(ctx context.Context, employerID int) (*dto.UserProfileResponse, error) {
+// CompleteLogin вызывается перед созданием сессии, когда пройдены все шаги входа,
+// включая второй фактор: отменяет запланированное удаление аккаунта и уведомляет о входе.
+func (e *UserService) CompleteLogin(ctx context.Context, userID int, clientIP string) error {
+	user, err := e.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Вход в течение срока ожидания отменяет удаление аккаунта.
+	if user.DeleteAfter != nil {
+		if err := e.userRepo.CancelDeletion(ctx, userID); err != nil {
+			return err
+		}
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"userID":    userID,
+		}).Info("Удаление аккаунта отменено входом пользователя")
+	}
+
+	e.notifications.Notify(ctx, userID, entity.NotificationNewLogin, dto.NewLoginNotification{IP: clientIP}, "")
+	return nil
+}
+
+// rehashPassword пересчитывает хеш с текущими параметрами и pepper.
+// Ошибка не мешает входу: хеш обновится при следующей попытке.
+func (e *UserService) rehashPassword(ctx context.Context, userID int, password string) {
+	hash, err := entity.HashPassword(password, e.pepper)
+	if err == nil {
+		err = e.userRepo.UpdatePasswordHash(ctx, userID, hash)
+	}
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"userID":    userID,
+			"error":     err,
+		}).Error("Не удалось обновить хеш пароля")
+		return
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+	}).Info("Хеш пароля пересчитан с актуальными параметрами")
+}
+
+func isNotFound(err error) bool {
+	var appErr entity.Error
+	return errors.As(err, &appErr) && appErr.ClientErr() == entity.ErrNotFound
+}
+
+func loginAttemptKey(login string) string {
+	return "login:" + strings.ToLower(login)
+}
+
+func (e *UserService) checkLockout(ctx context.Context, keys ...string) error {
+	var retryAfter time.Duration
+	for _, key := range keys {
+		remaining, err := e.loginAttemptRepo.GetLockout(ctx, key)
+		if err != nil {
+			return err
+		}
+		retryAfter = max(retryAfter, remaining)
+	}
+
+	if retryAfter > 0 {
+		return entity.NewError(entity.ErrTooManyRequests, entity.LockoutError{RetryAfter: retryAfter})
+	}
+	return nil
+}
+
+// registerFailure учитывает неудачную попытку входа и при превышении порога блокирует ключ.
+// Всегда возвращает ошибку: либо неверные учетные данные, либо ошибку хранилища.
+func (e *UserService) registerFailure(ctx context.Context, loginKey, ipKey string) error {
+	limits := map[string]int{
+		loginKey: e.protectionCfg.MaxAttempts,
+		ipKey:    e.protectionCfg.IPMaxAttempts,
+	}
+
+	for key, maxAttempts := range limits {
+		failures, err := e.loginAttemptRepo.RegisterFailure(ctx, key, e.protectionCfg.Window)
+		if err != nil {
+			return err
+		}
+
+		lockout := entity.LoginLockoutDuration(
+			failures,
+			maxAttempts,
+			e.protectionCfg.BaseLockout,
+			e.protectionCfg.MaxLockout,
+		)
+		if lockout == 0 {
+			continue
+		}
+
+		logger.Log.WithFields(logrus.Fields{
+			"requestID": utils.GetRequestID(ctx),
+			"key":       key,
+			"failures":  failures,
+		}).Warn("Превышено количество неудачных попыток входа")
+
+		if err := e.loginAttemptRepo.Lock(ctx, key, lockout); err != nil {
+			return err
+		}
+	}
+
+	return entity.NewError(entity.ErrUnauthorized, fmt.Errorf("неверные учетные данные"))
+}
+
+func (e *UserService) GetUser(ctx context.Context, viewerID, employerID int) (*dto.UserProfileResponse, error) {
 	employer, err := e.userRepo.GetByID(ctx, employerID)
 	if err != nil {
 		return nil, err
 	}
-	return e.employerEntityToDTO(ctx, employer)
+	return employerEntityToDTO(employer, viewerID == employerID), nil
 }
 
-func (e *UserService) employerEntityToDTO(ctx context.Context, employer *entity.User) (*dto.UserProfileResponse, error) {
+// employerEntityToDTO показывает неподтвержденные контакты только владельцу профиля.
+func employerEntityToDTO(employer *entity.User, isOwner bool) *dto.UserProfileResponse {
 	profile := &dto.UserProfileResponse{
-		ID:        employer.ID,
-		Name:      employer.Name,
-		Surname:   employer.Surname,
-		Login:     employer.Login,
-		CreatedAt: employer.CreatedAt,
-		UpdatedAt: employer.UpdatedAt,
-	}
-	return profile, nil
+		ID:            employer.ID,
+		Name:          employer.Name,
+		Surname:       employer.Surname,
+		Login:         employer.Login,
+		EmailVerified: employer.EmailVerified,
+		PhoneVerified: employer.PhoneVerified,
+		Verified:      employer.IsVerified(),
+		Bio:           employer.Bio,
+		City:          employer.City,
+		AvatarURL:     employer.AvatarURL,
+		Rating:        employer.Rating,
+		ReviewsCount:  employer.RatingCount,
+		CreatedAt:     employer.CreatedAt,
+		UpdatedAt:     employer.UpdatedAt,
+	}
+	if isOwner || employer.EmailVerified {
+		profile.Email = employer.Email
+	}
+	if isOwner || employer.PhoneVerified {
+		profile.Phone = employer.Phone
+	}
+	if isOwner {
+		profile.Language = employer.Language
+	}
+	return profile
+}
+
+// GetCurrentUser возвращает профиль вошедшего пользователя вместе с ролями,
+// способом входа и сроком действия сессии.
+func (e *UserService) GetCurrentUser(ctx context.Context, principal *entity.Principal) (*dto.CurrentUserResponse, error) {
+	user, err := e.userRepo.GetByID(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := &dto.CurrentUserResponse{
+		UserProfileResponse: *employerEntityToDTO(user, true),
+		Roles:               user.Roles(),
+		AuthMethod:          string(principal.Method),
+		DeleteAfter:         user.DeleteAfter,
+	}
+
+	unread, err := e.notifications.CountUnread(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	current.UnreadNotifications = unread.Unread
+	if principal.Method == entity.AuthMethodAPIKey {
+		current.Scopes = principal.Scopes
+	}
+
+	if principal.IsSession() {
+		expiresAt, err := e.sessionRepo.GetSessionExpiry(ctx, principal.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		current.SessionExpiresAt = &expiresAt
+	}
+
+	return current, nil
+}
+
+func (e *UserService) UpdateProfile(ctx context.Context, userID int, req *dto.UpdateProfileRequest) (*dto.UserProfileResponse, error) {
+	logger.Log.WithFields(logrus.Fields{
+		"requestID": utils.GetRequestID(ctx),
+		"userID":    userID,
+	}).Info("Изменение профиля пользователя")
+
+	sanitize := func(v *string) *string {
+		if v == nil {
+			return nil
+		}
+		sanitized := sanitizer.StrictPolicy.Sanitize(*v)
+		return &sanitized
+	}
+
+	update := &entity.ProfileUpdate{
+		Name:      sanitize(req.Name),
+		Surname:   sanitize(req.Surname),
+		Bio:       sanitize(req.Bio),
+		City:      sanitize(req.City),
+		AvatarURL: sanitize(req.AvatarURL),
+		Language:  req.Language,
+	}
+	if update.IsEmpty() {
+		return nil, entity.NewError(entity.ErrBadRequest, fmt.Errorf("не указано ни одного поля для изменения"))
+	}
+	if err := update.Normalize(); err != nil {
+		return nil, err
+	}
+
+	user, err := e.userRepo.UpdateProfile(ctx, userID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	return employerEntityToDTO(user, true), nil
+}
+
+// GetLanguage возвращает выбранный пользователем язык сообщений или пустую строку, если он не выбран.
+func (e *UserService) GetLanguage(ctx context.Context, userID int) (string, error) {
+	user, err := e.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return user.Language, nil
 }
 
 func (e *UserService) LoginExists(ctx context.Context, email string) (*dto.LoginExistsResponse, error) {
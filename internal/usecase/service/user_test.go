@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/repository/mock"
+	usecaseMock "github.com/AlexSamarskii/marketplace_vk_intern/internal/usecase/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testProtectionCfg = config.LoginProtectionConfig{
+	MaxAttempts:   5,
+	IPMaxAttempts: 20,
+	Window:        15 * time.Minute,
+	BaseLockout:   time.Minute,
+	MaxLockout:    10 * time.Minute,
+}
+
+func TestUserService_LoginLockout(t *testing.T) {
+	t.Parallel()
+
+	const (
+		login    = "user.test"
+		loginKey = "login:user.test"
+		ipKey    = "ip:192.0.2.1"
+	)
+
+	hash, err := entity.HashPassword("Correct123", entity.Pepper{})
+	require.NoError(t, err)
+	user := &entity.User{ID: 7, Login: login, PasswordHash: hash}
+	// Отмена удаления и уведомление о входе ждут второго фактора, Login их не выполняет.
+	deleteAfter := time.Now().Add(time.Hour)
+	deletingUser := &entity.User{ID: 7, Login: login, PasswordHash: hash, DeleteAfter: &deleteAfter}
+
+	testCases := []struct {
+		name        string
+		password    string
+		mockSetup   func(*mock.MockUserRepository, *mock.MockLoginAttemptRepository)
+		expectedErr error
+		retryAfter  time.Duration
+	}{
+		{
+			name:     "Действующая блокировка не доходит до проверки пароля",
+			password: "Correct123",
+			mockSetup: func(userRepo *mock.MockUserRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), loginKey).Return(30*time.Second, nil)
+				attempts.EXPECT().GetLockout(gomock.Any(), ipKey).Return(90*time.Second, nil)
+			},
+			expectedErr: entity.ErrTooManyRequests,
+			retryAfter:  90 * time.Second,
+		},
+		{
+			name:     "Успешный вход сбрасывает счетчик и не трогает удаление аккаунта",
+			password: "Correct123",
+			mockSetup: func(userRepo *mock.MockUserRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+				userRepo.EXPECT().GetByLogin(gomock.Any(), login).Return(deletingUser, nil)
+				attempts.EXPECT().Reset(gomock.Any(), loginKey).Return(nil)
+			},
+		},
+		{
+			name:     "Неверный пароль до порога не блокирует",
+			password: "Wrong12345",
+			mockSetup: func(userRepo *mock.MockUserRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+				userRepo.EXPECT().GetByLogin(gomock.Any(), login).Return(user, nil)
+				attempts.EXPECT().RegisterFailure(gomock.Any(), loginKey, testProtectionCfg.Window).Return(4, nil)
+				attempts.EXPECT().RegisterFailure(gomock.Any(), ipKey, testProtectionCfg.Window).Return(4, nil)
+			},
+			expectedErr: entity.ErrUnauthorized,
+		},
+		{
+			name:     "Достижение порога блокирует логин на базовое время",
+			password: "Wrong12345",
+			mockSetup: func(userRepo *mock.MockUserRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+				userRepo.EXPECT().GetByLogin(gomock.Any(), login).Return(user, nil)
+				attempts.EXPECT().RegisterFailure(gomock.Any(), loginKey, testProtectionCfg.Window).Return(5, nil)
+				attempts.EXPECT().RegisterFailure(gomock.Any(), ipKey, testProtectionCfg.Window).Return(5, nil)
+				attempts.EXPECT().Lock(gomock.Any(), loginKey, time.Minute).Return(nil)
+			},
+			expectedErr: entity.ErrUnauthorized,
+		},
+		{
+			name:     "Каждая следующая попытка удваивает блокировку",
+			password: "Wrong12345",
+			mockSetup: func(userRepo *mock.MockUserRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+				userRepo.EXPECT().GetByLogin(gomock.Any(), login).Return(user, nil)
+				attempts.EXPECT().RegisterFailure(gomock.Any(), loginKey, testProtectionCfg.Window).Return(7, nil)
+				attempts.EXPECT().RegisterFailure(gomock.Any(), ipKey, testProtectionCfg.Window).Return(7, nil)
+				attempts.EXPECT().Lock(gomock.Any(), loginKey, 4*time.Minute).Return(nil)
+			},
+			expectedErr: entity.ErrUnauthorized,
+		},
+		{
+			name:     "Блокировка не превышает максимум, IP блокируется по своему порогу",
+			password: "Wrong12345",
+			mockSetup: func(userRepo *mock.MockUserRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+				userRepo.EXPECT().GetByLogin(gomock.Any(), login).Return(user, nil)
+				attempts.EXPECT().RegisterFailure(gomock.Any(), loginKey, testProtectionCfg.Window).Return(12, nil)
+				attempts.EXPECT().RegisterFailure(gomock.Any(), ipKey, testProtectionCfg.Window).Return(20, nil)
+				attempts.EXPECT().Lock(gomock.Any(), loginKey, 10*time.Minute).Return(nil)
+				attempts.EXPECT().Lock(gomock.Any(), ipKey, time.Minute).Return(nil)
+			},
+			expectedErr: entity.ErrUnauthorized,
+		},
+		{
+			name:     "Несуществующий логин считается неудачной попыткой",
+			password: "Wrong12345",
+			mockSetup: func(userRepo *mock.MockUserRepository, attempts *mock.MockLoginAttemptRepository) {
+				attempts.EXPECT().GetLockout(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+				userRepo.EXPECT().GetByLogin(gomock.Any(), login).
+					Return(nil, entity.NewError(entity.ErrNotFound, nil))
+				attempts.EXPECT().RegisterFailure(gomock.Any(), loginKey, testProtectionCfg.Window).Return(1, nil)
+				attempts.EXPECT().RegisterFailure(gomock.Any(), ipKey, testProtectionCfg.Window).Return(1, nil)
+			},
+			expectedErr: entity.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			attempts := mock.NewMockLoginAttemptRepository(ctrl)
+			service := NewUserService(userRepo, nil, attempts, nil, testProtectionCfg, entity.Pepper{})
+
+			tc.mockSetup(userRepo, attempts)
+
+			userID, err := service.Login(context.Background(), &dto.Login{Login: login, Password: tc.password}, "192.0.2.1")
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				require.Equal(t, 7, userID)
+				return
+			}
+
+			require.ErrorIs(t, err, tc.expectedErr)
+			if tc.retryAfter > 0 {
+				var lockoutErr entity.LockoutError
+				require.ErrorAs(t, err, &lockoutErr)
+				require.Equal(t, tc.retryAfter, lockoutErr.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestUserService_CompleteLogin(t *testing.T) {
+	t.Parallel()
+
+	deleteAfter := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		name        string
+		mockSetup   func(*mock.MockUserRepository, *usecaseMock.MockNotificationUsecase)
+		expectedErr error
+	}{
+		{
+			name: "Вход отменяет запланированное удаление и уведомляет",
+			mockSetup: func(userRepo *mock.MockUserRepository, notifications *usecaseMock.MockNotificationUsecase) {
+				userRepo.EXPECT().GetByID(gomock.Any(), 7).Return(&entity.User{ID: 7, DeleteAfter: &deleteAfter}, nil)
+				userRepo.EXPECT().CancelDeletion(gomock.Any(), 7).Return(nil)
+				notifications.EXPECT().Notify(gomock.Any(), 7, entity.NotificationNewLogin,
+					dto.NewLoginNotification{IP: "192.0.2.1"}, "")
+			},
+		},
+		{
+			name: "Без запланированного удаления только уведомление",
+			mockSetup: func(userRepo *mock.MockUserRepository, notifications *usecaseMock.MockNotificationUsecase) {
+				userRepo.EXPECT().GetByID(gomock.Any(), 7).Return(&entity.User{ID: 7}, nil)
+				notifications.EXPECT().Notify(gomock.Any(), 7, entity.NotificationNewLogin,
+					dto.NewLoginNotification{IP: "192.0.2.1"}, "")
+			},
+		},
+		{
+			name: "Ошибка отмены удаления прерывает вход без уведомления",
+			mockSetup: func(userRepo *mock.MockUserRepository, notifications *usecaseMock.MockNotificationUsecase) {
+				userRepo.EXPECT().GetByID(gomock.Any(), 7).Return(&entity.User{ID: 7, DeleteAfter: &deleteAfter}, nil)
+				userRepo.EXPECT().CancelDeletion(gomock.Any(), 7).
+					Return(entity.NewError(entity.ErrInternal, fmt.Errorf("соединение потеряно")))
+			},
+			expectedErr: entity.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			notifications := usecaseMock.NewMockNotificationUsecase(ctrl)
+			service := NewUserService(userRepo, nil, nil, notifications, testProtectionCfg, entity.Pepper{})
+
+			tc.mockSetup(userRepo, notifications)
+
+			err := service.CompleteLogin(context.Background(), 7, "192.0.2.1")
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	t.Parallel()
+
+	strPtr := func(s string) *string { return &s }
+
+	testCases := []struct {
+		name           string
+		req            dto.UpdateProfileRequest
+		expectedUpdate *entity.ProfileUpdate
+		expectedErr    error
+	}{
+		{
+			name: "Разметка вырезается, пробелы обрезаются",
+			req: dto.UpdateProfileRequest{
+				Bio:  strPtr(" <b>Продаю</b> технику "),
+				City: strPtr(" Москва"),
+			},
+			expectedUpdate: &entity.ProfileUpdate{Bio: strPtr("Продаю технику"), City: strPtr("Москва")},
+		},
+		{
+			name:           "Новый аватар",
+			req:            dto.UpdateProfileRequest{AvatarURL: strPtr("https://cdn.example.com/avatar.png")},
+			expectedUpdate: &entity.ProfileUpdate{AvatarURL: strPtr("https://cdn.example.com/avatar.png")},
+		},
+		{
+			name:           "Пустая строка удаляет аватар",
+			req:            dto.UpdateProfileRequest{AvatarURL: strPtr("")},
+			expectedUpdate: &entity.ProfileUpdate{AvatarURL: strPtr("")},
+		},
+		{
+			name:        "Аватар не картинка",
+			req:         dto.UpdateProfileRequest{AvatarURL: strPtr("https://example.com/avatar.exe")},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Слишком длинное описание",
+			req:         dto.UpdateProfileRequest{Bio: strPtr(strings.Repeat("а", entity.UserBioMaxLen+1))},
+			expectedErr: entity.ErrBadRequest,
+		},
+		{
+			name:        "Пустой запрос",
+			req:         dto.UpdateProfileRequest{},
+			expectedErr: entity.ErrBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			service := NewUserService(userRepo, nil, nil, nil, testProtectionCfg, entity.Pepper{})
+
+			if tc.expectedUpdate != nil {
+				userRepo.EXPECT().UpdateProfile(gomock.Any(), 7, tc.expectedUpdate).
+					Return(&entity.User{ID: 7, Login: "user.test", Email: "user@example.com"}, nil)
+			}
+
+			profile, err := service.UpdateProfile(context.Background(), 7, &tc.req)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			// Владелец видит свой контакт, даже неподтвержденный.
+			require.Equal(t, "user@example.com", profile.Email)
+		})
+	}
+}
+
+func TestUserService_GetUser(t *testing.T) {
+	t.Parallel()
+
+	user := &entity.User{
+		ID:            7,
+		Email:         "user@example.com",
+		Phone:         "+79990000000",
+		PhoneVerified: true,
+		Language:      "en",
+	}
+
+	testCases := []struct {
+		name          string
+		viewerID      int
+		expectedEmail string
+		expectedLang  string
+	}{
+		{
+			name:          "Владелец видит неподтвержденный email и язык",
+			viewerID:      7,
+			expectedEmail: "user@example.com",
+			expectedLang:  "en",
+		},
+		{
+			name:     "Другой пользователь видит только подтвержденные контакты",
+			viewerID: 8,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			userRepo.EXPECT().GetByID(gomock.Any(), 7).Return(user, nil)
+			service := NewUserService(userRepo, nil, nil, nil, testProtectionCfg, entity.Pepper{})
+
+			profile, err := service.GetUser(context.Background(), tc.viewerID, 7)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedEmail, profile.Email)
+			require.Equal(t, "+79990000000", profile.Phone)
+			require.Equal(t, tc.expectedLang, profile.Language)
+		})
+	}
+}
+
+func TestUserService_GetCurrentUser(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	moderator := &entity.User{ID: 1, Login: "moder", Email: "moder@example.com", EmailVerified: true, IsModerator: true}
+
+	testCases := []struct {
+		name        string
+		principal   *entity.Principal
+		mockSetup   func(*mock.MockSessionRepository)
+		expected    func(*dto.CurrentUserResponse)
+		expectedErr error
+	}{
+		{
+			name:      "Сессия: роли, непрочитанные и срок действия",
+			principal: &entity.Principal{UserID: 1, Method: entity.AuthMethodSession, SessionID: "session"},
+			mockSetup: func(sessionRepo *mock.MockSessionRepository) {
+				sessionRepo.EXPECT().GetSessionExpiry(gomock.Any(), "session").Return(expiresAt, nil)
+			},
+			expected: func(current *dto.CurrentUserResponse) {
+				require.Equal(t, []string{entity.RoleUser, entity.RoleVerifiedSeller, entity.RoleModerator}, current.Roles)
+				require.Equal(t, string(entity.AuthMethodSession), current.AuthMethod)
+				require.Equal(t, 4, current.UnreadNotifications)
+				require.Equal(t, &expiresAt, current.SessionExpiresAt)
+				require.Empty(t, current.Scopes)
+			},
+		},
+		{
+			name:      "API-ключ: права ключа без срока сессии",
+			principal: &entity.Principal{UserID: 1, Method: entity.AuthMethodAPIKey, Scopes: []string{entity.ScopeAdsRead}},
+			mockSetup: func(*mock.MockSessionRepository) {},
+			expected: func(current *dto.CurrentUserResponse) {
+				require.Equal(t, string(entity.AuthMethodAPIKey), current.AuthMethod)
+				require.Equal(t, []string{entity.ScopeAdsRead}, current.Scopes)
+				require.Nil(t, current.SessionExpiresAt)
+			},
+		},
+		{
+			name:      "Сессия истекла между проверкой и запросом",
+			principal: &entity.Principal{UserID: 1, Method: entity.AuthMethodBearer, SessionID: "expired"},
+			mockSetup: func(sessionRepo *mock.MockSessionRepository) {
+				sessionRepo.EXPECT().GetSessionExpiry(gomock.Any(), "expired").
+					Return(time.Time{}, entity.NewError(entity.ErrNotFound, fmt.Errorf("сессия не найдена")))
+			},
+			expectedErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			sessionRepo := mock.NewMockSessionRepository(ctrl)
+			notifications := usecaseMock.NewMockNotificationUsecase(ctrl)
+			service := NewUserService(userRepo, sessionRepo, nil, notifications, testProtectionCfg, entity.Pepper{})
+
+			userRepo.EXPECT().GetByID(gomock.Any(), 1).Return(moderator, nil)
+			notifications.EXPECT().CountUnread(gomock.Any(), 1).Return(&dto.UnreadCountResponse{Unread: 4}, nil)
+			tc.mockSetup(sessionRepo)
+
+			current, err := service.GetCurrentUser(context.Background(), tc.principal)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "moder@example.com", current.Email)
+			tc.expected(current)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
+)
+
+type TwoFactorUsecase interface {
+	Enroll(ctx context.Context, userID int) (*dto.TOTPEnrollResponse, error)
+	Confirm(ctx context.Context, userID int, code string) (*dto.RecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID int, req *dto.TOTPDisableRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*dto.RecoveryCodesResponse, error)
+	StartChallenge(ctx context.Context, userID int) (token string, required bool, err error)
+	VerifyChallenge(ctx context.Context, req *dto.LoginTwoFactorRequest) (int, error)
+}
@@ -3,12 +3,17 @@ package usecase
 import (
 	"context"
 
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
 	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity/dto"
 )
 
 type UserUsecase interface {
 	Register(ctx context.Context, registerDTO *dto.UserRegister) (*dto.UserProfileResponse, error)
-	Login(ctx context.Context, loginDTO *dto.Login) (int, error)
-	GetUser(ctx context.Context, employerID int) (*dto.UserProfileResponse, error)
+	Login(ctx context.Context, loginDTO *dto.Login, clientIP string) (int, error)
+	CompleteLogin(ctx context.Context, userID int, clientIP string) error
+	GetUser(ctx context.Context, viewerID, employerID int) (*dto.UserProfileResponse, error)
 	LoginExists(ctx context.Context, email string) (*dto.LoginExistsResponse, error)
+	UpdateProfile(ctx context.Context, userID int, req *dto.UpdateProfileRequest) (*dto.UserProfileResponse, error)
+	GetCurrentUser(ctx context.Context, principal *entity.Principal) (*dto.CurrentUserResponse, error)
+	GetLanguage(ctx context.Context, userID int) (string, error)
 }
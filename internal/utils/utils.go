@@ -1,9 +1,30 @@
 package utils
 
-import "context"
+import (
+	"context"
+	"sync"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/entity"
+	"github.com/AlexSamarskii/marketplace_vk_intern/pkg/i18n"
+)
 
 type ctxKeyRequestID struct{}
 
+type ctxKeyPrincipal struct{}
+
+type ctxKeyAuthError struct{}
+
+type ctxKeyLanguage struct{}
+
+// languageResolver определяет язык при первом обращении и запоминает результат.
+type languageResolver struct {
+	once    sync.Once
+	resolve func() i18n.Lang
+	lang    i18n.Lang
+}
+
+type ctxKeyClientIP struct{}
+
 func SetRequestID(ctx context.Context, requestID string) context.Context {
 	return context.WithValue(ctx, ctxKeyRequestID{}, requestID)
 }
@@ -14,3 +35,59 @@ func GetRequestID(ctx context.Context) string {
 	}
 	return ""
 }
+
+func SetPrincipal(ctx context.Context, principal *entity.Principal) context.Context {
+	return context.WithValue(ctx, ctxKeyPrincipal{}, principal)
+}
+
+// GetPrincipal возвращает субъект запроса, если он был аутентифицирован.
+func GetPrincipal(ctx context.Context) (*entity.Principal, bool) {
+	principal, ok := ctx.Value(ctxKeyPrincipal{}).(*entity.Principal)
+	return principal, ok && principal != nil
+}
+
+// SetAuthError сохраняет причину, по которой явно переданные учетные данные не прошли проверку.
+func SetAuthError(ctx context.Context, err error) context.Context {
+	return context.WithValue(ctx, ctxKeyAuthError{}, err)
+}
+
+func GetAuthError(ctx context.Context) error {
+	if err, ok := ctx.Value(ctxKeyAuthError{}).(error); ok {
+		return err
+	}
+	return nil
+}
+
+// SetLanguage сохраняет язык, на котором клиенту отправляются сообщения.
+func SetLanguage(ctx context.Context, lang i18n.Lang) context.Context {
+	return context.WithValue(ctx, ctxKeyLanguage{}, lang)
+}
+
+// SetLanguageResolver откладывает определение языка до первого обращения: resolve
+// вызывается не больше одного раза и только если ответу понадобился перевод.
+func SetLanguageResolver(ctx context.Context, resolve func() i18n.Lang) context.Context {
+	return context.WithValue(ctx, ctxKeyLanguage{}, &languageResolver{resolve: resolve})
+}
+
+// GetLanguage возвращает язык запроса, если его уже определил LanguageMiddleware.
+func GetLanguage(ctx context.Context) (i18n.Lang, bool) {
+	switch v := ctx.Value(ctxKeyLanguage{}).(type) {
+	case i18n.Lang:
+		return v, true
+	case *languageResolver:
+		v.once.Do(func() { v.lang = v.resolve() })
+		return v.lang, true
+	}
+	return "", false
+}
+
+// SetClientIP сохраняет адрес клиента, определенный с учетом доверенных прокси.
+func SetClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ctxKeyClientIP{}, ip)
+}
+
+// GetClientIP возвращает адрес клиента, если его уже определил ClientIPMiddleware.
+func GetClientIP(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(ctxKeyClientIP{}).(string)
+	return ip, ok && ip != ""
+}
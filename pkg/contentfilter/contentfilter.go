@@ -0,0 +1,201 @@
+// Package contentfilter проверяет пользовательский текст по правилам: спискам ключевых слов,
+// регулярным выражениям, детекторам ссылок, телефонов и нецензурной лексики.
+package contentfilter
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+)
+
+type Severity string
+
+const (
+	// SeverityBlock — текст недопустим.
+	SeverityBlock Severity = "block"
+	// SeverityReview — текст подозрителен и должен проверить модератор.
+	SeverityReview Severity = "review"
+)
+
+// Violation — сработавшее правило.
+type Violation struct {
+	Field    string
+	Rule     string
+	Reason   string
+	Severity Severity
+}
+
+type rule struct {
+	name     string
+	reason   string
+	severity Severity
+	keywords []keyword
+	patterns []*regexp.Regexp
+	// detect — встроенный детектор, срабатывает, если вернул true.
+	detect func(text string) bool
+}
+
+type Filter struct {
+	rules []rule
+}
+
+//go:embed profanity/*.txt
+var profanityLists embed.FS
+
+// New собирает правила из конфигурации. Ошибка означает неверную конфигурацию:
+// неизвестную важность, язык или регулярное выражение.
+func New(cfg config.ContentFilterConfig) (*Filter, error) {
+	f := &Filter{}
+	if !cfg.Enabled {
+		return f, nil
+	}
+
+	if cfg.Links.Severity != "" {
+		severity, err := parseSeverity(cfg.Links.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("links: %w", err)
+		}
+		f.rules = append(f.rules, rule{
+			name:     "links",
+			reason:   "ссылки на сторонние сайты запрещены",
+			severity: severity,
+			detect:   newLinkDetector(cfg.Links.AllowedDomains),
+		})
+	}
+
+	if cfg.Phones.Severity != "" {
+		severity, err := parseSeverity(cfg.Phones.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("phones: %w", err)
+		}
+		f.rules = append(f.rules, rule{
+			name:     "phones",
+			reason:   "номера телефонов в тексте запрещены, используйте контакты профиля",
+			severity: severity,
+			detect:   phonePattern.MatchString,
+		})
+	}
+
+	if cfg.Profanity.Severity != "" {
+		severity, err := parseSeverity(cfg.Profanity.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("profanity: %w", err)
+		}
+		var keywords []keyword
+		for _, lang := range cfg.Profanity.Languages {
+			words, err := loadProfanity(lang)
+			if err != nil {
+				return nil, err
+			}
+			keywords = append(keywords, words...)
+		}
+		f.rules = append(f.rules, rule{
+			name:     "profanity",
+			reason:   "нецензурная лексика запрещена",
+			severity: severity,
+			keywords: keywords,
+		})
+	}
+
+	for _, rc := range cfg.Rules {
+		severity, err := parseSeverity(rc.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("правило %s: %w", rc.Name, err)
+		}
+		r := rule{
+			name:     rc.Name,
+			reason:   rc.Reason,
+			severity: severity,
+		}
+		for _, kw := range rc.Keywords {
+			if parsed, ok := parseKeyword(kw); ok {
+				r.keywords = append(r.keywords, parsed)
+			}
+		}
+		for _, pattern := range rc.Patterns {
+			re, err := regexp.Compile("(?i)" + pattern)
+			if err != nil {
+				return nil, fmt.Errorf("правило %s: неверное выражение %q: %w", rc.Name, pattern, err)
+			}
+			r.patterns = append(r.patterns, re)
+		}
+		f.rules = append(f.rules, r)
+	}
+
+	return f, nil
+}
+
+// Check проверяет значение поля и возвращает сработавшие правила в порядке конфигурации.
+func (f *Filter) Check(field, text string) []Violation {
+	if f == nil || len(f.rules) == 0 || strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	tokens := tokenize(text)
+	var violations []Violation
+	for _, r := range f.rules {
+		if r.matches(text, tokens) {
+			violations = append(violations, Violation{
+				Field:    field,
+				Rule:     r.name,
+				Reason:   r.reason,
+				Severity: r.severity,
+			})
+		}
+	}
+	return violations
+}
+
+func (r *rule) matches(text string, tokens []string) bool {
+	if r.detect != nil && r.detect(text) {
+		return true
+	}
+	for _, kw := range r.keywords {
+		if kw.matches(tokens) {
+			return true
+		}
+	}
+	for _, re := range r.patterns {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseSeverity(s string) (Severity, error) {
+	switch Severity(s) {
+	case SeverityBlock, SeverityReview:
+		return Severity(s), nil
+	default:
+		return "", fmt.Errorf("неизвестная важность %q, допустимо: %s, %s", s, SeverityBlock, SeverityReview)
+	}
+}
+
+func loadProfanity(lang string) ([]keyword, error) {
+	file, err := profanityLists.Open("profanity/" + lang + ".txt")
+	if err != nil {
+		return nil, fmt.Errorf("profanity: нет списка для языка %q", lang)
+	}
+	defer file.Close()
+
+	var keywords []keyword
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if kw, ok := parseKeyword(line); ok {
+			keywords = append(keywords, kw)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("profanity: не удалось прочитать список %q: %w", lang, err)
+	}
+	return keywords, nil
+}
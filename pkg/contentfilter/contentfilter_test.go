@@ -0,0 +1,97 @@
+package contentfilter
+
+import (
+	"testing"
+
+	"github.com/AlexSamarskii/marketplace_vk_intern/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFilter(t *testing.T) *Filter {
+	t.Helper()
+
+	f, err := New(config.ContentFilterConfig{
+		Enabled:   true,
+		Links:     config.LinkFilterConfig{Severity: "review", AllowedDomains: []string{"example.com"}},
+		Phones:    config.PhoneFilterConfig{Severity: "block"},
+		Profanity: config.ProfanityFilterConfig{Severity: "block", Languages: []string{"ru", "en"}},
+		Rules: []config.ContentFilterRuleConfig{
+			{
+				Name:     "documents",
+				Severity: "block",
+				Reason:   "продажа документов запрещена",
+				Keywords: []string{"поддельн* паспорт*"},
+			},
+			{
+				Name:     "external_contacts",
+				Severity: "review",
+				Reason:   "контакты вне сервиса",
+				Keywords: []string{"телеграм*"},
+				Patterns: []string{`@[a-z0-9_]{5,32}\b`},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return f
+}
+
+func TestFilterCheck(t *testing.T) {
+	t.Parallel()
+
+	f := newTestFilter(t)
+
+	tests := []struct {
+		name string
+		text string
+		rule string
+	}{
+		{"clean", "Продаю велосипед в отличном состоянии, цена 15 000 руб.", ""},
+		{"phone", "Звоните +7 (912) 345-67-89", "phones"},
+		{"phone with 8", "тел. 8 912 345 67 89", "phones"},
+		{"link", "подробнее на shop-bikes.ru/sale", "links"},
+		{"allowed link", "инструкция: https://docs.example.com/bike", ""},
+		{"profanity ru", "Ёбаный сарай", "profanity"},
+		{"profanity homoglyph", "какая-то xуйня", "profanity"},
+		{"profanity en", "this is fucking great", "profanity"},
+		{"no false positive", "Сумка для рубля и оскорбление не мат", ""},
+		{"phrase", "Поддельные паспорта любой страны", "documents"},
+		{"keyword prefix", "пишите в телеграме", "external_contacts"},
+		{"pattern", "пишите @seller_bike", "external_contacts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			violations := f.Check("description", tt.text)
+			if tt.rule == "" {
+				require.Empty(t, violations)
+				return
+			}
+			require.NotEmpty(t, violations)
+			require.Equal(t, tt.rule, violations[0].Rule)
+			require.Equal(t, "description", violations[0].Field)
+		})
+	}
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(config.ContentFilterConfig{Enabled: true, Phones: config.PhoneFilterConfig{Severity: "warn"}})
+	require.Error(t, err)
+
+	_, err = New(config.ContentFilterConfig{Enabled: true, Profanity: config.ProfanityFilterConfig{Severity: "block", Languages: []string{"de"}}})
+	require.Error(t, err)
+
+	_, err = New(config.ContentFilterConfig{Enabled: true, Rules: []config.ContentFilterRuleConfig{{Name: "bad", Severity: "block", Patterns: []string{"("}}}})
+	require.Error(t, err)
+}
+
+func TestDisabledFilterAllowsEverything(t *testing.T) {
+	t.Parallel()
+
+	f, err := New(config.ContentFilterConfig{Phones: config.PhoneFilterConfig{Severity: "block"}})
+	require.NoError(t, err)
+	require.Empty(t, f.Check("title", "+7 912 345 67 89"))
+}
@@ -0,0 +1,145 @@
+package contentfilter
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// keyword — последовательность слов; слово со звездочкой совпадает по началу.
+type keyword []wordMatcher
+
+type wordMatcher struct {
+	word   string
+	prefix bool
+}
+
+func parseKeyword(s string) (keyword, bool) {
+	var kw keyword
+	for _, word := range strings.Fields(s) {
+		prefix := strings.HasSuffix(word, "*")
+		for _, token := range tokenize(strings.TrimSuffix(word, "*")) {
+			kw = append(kw, wordMatcher{word: token})
+		}
+		if prefix && len(kw) > 0 {
+			kw[len(kw)-1].prefix = true
+		}
+	}
+	return kw, len(kw) > 0
+}
+
+func (kw keyword) matches(tokens []string) bool {
+	for start := 0; start+len(kw) <= len(tokens); start++ {
+		matched := true
+		for i, m := range kw {
+			if !m.matches(tokens[start+i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (m wordMatcher) matches(token string) bool {
+	if m.prefix {
+		return strings.HasPrefix(token, m.word)
+	}
+	return token == m.word
+}
+
+// homoglyphs — латинские буквы, которыми подменяют кириллицу, чтобы обойти фильтр.
+var homoglyphs = map[rune]rune{
+	'a': 'а', 'b': 'в', 'c': 'с', 'e': 'е', 'h': 'н', 'k': 'к', 'm': 'м',
+	'o': 'о', 'p': 'р', 't': 'т', 'x': 'х', 'y': 'у',
+}
+
+// tokenize разбивает текст на слова в нижнем регистре, заменяет ё на е, а в словах,
+// смешивающих кириллицу и латиницу, латинские двойники букв на кириллические.
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		var cyrillic, latin bool
+		for _, r := range word {
+			switch {
+			case unicode.Is(unicode.Cyrillic, r):
+				cyrillic = true
+			case r < unicode.MaxASCII && unicode.IsLetter(r):
+				latin = true
+			}
+		}
+
+		words[i] = strings.Map(func(r rune) rune {
+			if r == 'ё' {
+				return 'е'
+			}
+			if cyrillic && latin {
+				if c, ok := homoglyphs[r]; ok {
+					return c
+				}
+			}
+			return r
+		}, word)
+	}
+	return words
+}
+
+// phonePattern ловит российские номера (+7, 7 или 8 и десять цифр) и международные с +,
+// в том числе записанные через пробелы, дефисы, точки и скобки.
+var phonePattern = regexp.MustCompile(
+	`(?:\+7|(?:^|[^\d])[78])[\s\-().]*\d(?:[\s\-().]*\d){9}(?:[^\d]|$)|\+\d(?:[\s\-().]*\d){9,13}`,
+)
+
+var linkPattern = regexp.MustCompile(
+	`(?i)(?:https?://|www\.)[^\s]+|[\p{L}\p{N}][\p{L}\p{N}-]*(?:\.[\p{L}\p{N}-]+)*\.(?:ru|com|net|org|su|io|info|biz|me|рф|shop|store|online|site)(?:/[^\s]*)?(?:[^\p{L}\p{N}]|$)`,
+)
+
+// newLinkDetector находит ссылки и домены, кроме allowed и их поддоменов.
+func newLinkDetector(allowed []string) func(text string) bool {
+	normalized := make([]string, 0, len(allowed))
+	for _, domain := range allowed {
+		normalized = append(normalized, strings.TrimPrefix(strings.ToLower(domain), "www."))
+	}
+
+	return func(text string) bool {
+		for _, match := range linkPattern.FindAllString(text, -1) {
+			if !isAllowedHost(linkHost(match), normalized) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func linkHost(match string) string {
+	raw := strings.TrimRightFunc(strings.ToLower(match), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '/'
+	})
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
+
+func isAllowedHost(host string, allowed []string) bool {
+	if host == "" {
+		return false
+	}
+	for _, domain := range allowed {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+// Package i18n переводит коды сообщений на языки, которые поддерживает сервис,
+// и определяет язык клиента по заголовку Accept-Language.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Lang string
+
+const (
+	Russian Lang = "ru"
+	English Lang = "en"
+
+	// Default используется, когда клиент не назвал ни одного поддерживаемого языка.
+	Default = Russian
+)
+
+// Supported — поддерживаемые языки в порядке предпочтения по умолчанию.
+var Supported = []Lang{Russian, English}
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var catalogue = mustLoad()
+
+func mustLoad() map[Lang]map[string]string {
+	c := make(map[Lang]map[string]string, len(Supported))
+	for _, lang := range Supported {
+		raw, err := locales.ReadFile("locales/" + string(lang) + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: нет каталога %s: %v", lang, err))
+		}
+		messages := map[string]string{}
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: неверный каталог %s: %v", lang, err))
+		}
+		c[lang] = messages
+	}
+	return c
+}
+
+// ParseLang проверяет, что язык поддерживается. Регион и регистр не важны: en-GB — это en.
+func ParseLang(s string) (Lang, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, "-_"); i >= 0 {
+		s = s[:i]
+	}
+	for _, lang := range Supported {
+		if string(lang) == s {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// FromAcceptLanguage выбирает поддерживаемый язык с наибольшим весом q.
+// При равных весах выигрывает указанный раньше.
+func FromAcceptLanguage(header string) Lang {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, q := part, 1.0
+		if i := strings.Index(part, ";"); i >= 0 {
+			tag = part[:i]
+			param := strings.TrimSpace(part[i+1:])
+			if v, ok := strings.CutPrefix(param, "q="); ok {
+				parsed, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					continue
+				}
+				q = parsed
+			}
+		}
+		lang, ok := ParseLang(tag)
+		if !ok || q <= bestQ {
+			continue
+		}
+		best, bestQ = lang, q
+	}
+	return best
+}
+
+// Translate возвращает сообщение для кода на языке lang и подставляет параметры
+// вместо {name}. Если перевода нет, ищется родительский код (content.rule.drugs → content.rule),
+// затем язык по умолчанию. Неизвестный код возвращается как есть.
+func Translate(lang Lang, code string, params map[string]any) string {
+	template, ok := lookup(lang, code)
+	if !ok {
+		template, ok = lookup(Default, code)
+	}
+	if !ok {
+		return code
+	}
+	if len(params) == 0 {
+		return template
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(params)*2)
+	for _, k := range keys {
+		pairs = append(pairs, "{"+k+"}", FormatParam(params[k]))
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+func lookup(lang Lang, code string) (string, bool) {
+	messages := catalogue[lang]
+	for {
+		if m, ok := messages[code]; ok {
+			return m, true
+		}
+		i := strings.LastIndex(code, ".")
+		if i < 0 {
+			return "", false
+		}
+		code = code[:i]
+	}
+}
+
+// FormatParam печатает параметр сообщения. Дробные числа печатаются без экспоненты.
+func FormatParam(v any) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
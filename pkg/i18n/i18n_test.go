@@ -0,0 +1,67 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogues_SameCodes(t *testing.T) {
+	t.Parallel()
+
+	for _, lang := range Supported {
+		require.NotEmpty(t, catalogue[lang], lang)
+		for code := range catalogue[Default] {
+			require.Contains(t, catalogue[lang], code, "нет перевода %s на %s", code, lang)
+		}
+		for code := range catalogue[lang] {
+			require.Contains(t, catalogue[Default], code, "лишний код %s в каталоге %s", code, lang)
+		}
+	}
+}
+
+func TestFromAcceptLanguage(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		header   string
+		expected Lang
+	}{
+		{"", Default},
+		{"en", English},
+		{"en-US,en;q=0.9", English},
+		{"ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7", Russian},
+		{"de-DE,de;q=0.9,en;q=0.5", English},
+		{"fr, de", Default},
+		{"ru;q=0.3, en;q=0.8", English},
+		{"en;q=0", Default},
+		{"EN_gb", English},
+		{"en;q=abc, ru;q=0.1", Russian},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.header, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, FromAcceptLanguage(tc.header))
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+
+	params := map[string]any{"min": 3, "max": 50}
+	require.Equal(t, "Длина должна быть от 3 до 50", Translate(Russian, "validation.length_between", params))
+	require.Equal(t, "Length must be between 3 and 50", Translate(English, "validation.length_between", params))
+
+	require.Equal(t, "Price must not exceed 1000000000",
+		Translate(English, "validation.price_max", map[string]any{"max": float64(1e9)}))
+
+	// Правило без своего перевода получает общий текст родительского кода.
+	require.Equal(t, "Текст нарушает правила площадки: только для взрослых",
+		Translate(Russian, "content.rule.adult", map[string]any{"reason": "только для взрослых"}))
+
+	// Неизвестный код возвращается как есть.
+	require.Equal(t, "no.such.code", Translate(English, "no.such.code", nil))
+}